@@ -18,8 +18,10 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/versiondata"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
@@ -83,6 +85,12 @@ func (l *listener) Start(ctx context.Context) error {
 	// Start pac config syncer
 	go params.StartConfigSync(ctx, l.run)
 
+	// the concurrency and kueue settings of the namespaces are read from a
+	// cache on every event
+	if err := params.StartNamespaceSync(ctx, l.run); err != nil {
+		return err
+	}
+
 	l.logger.Infof("Starting Pipelines as Code version: %s", strings.TrimSpace(versiondata.Version))
 	mux := http.NewServeMux()
 
@@ -268,6 +276,18 @@ func (l listener) detectProvider(req *http.Request, reqBody string) (provider.In
 		return l.processRes(processReq, gitLab, logger, reason, err)
 	}
 
+	azureDevOps := &azuredevops.Provider{}
+	isAzureDevOps, processReq, logger, reason, err := azureDevOps.Detect(req, reqBody, &log)
+	if isAzureDevOps {
+		return l.processRes(processReq, azureDevOps, logger, reason, err)
+	}
+
+	gerritProvider := &gerrit.Provider{}
+	isGerrit, processReq, logger, reason, err := gerritProvider.Detect(req, reqBody, &log)
+	if isGerrit {
+		return l.processRes(processReq, gerritProvider, logger, reason, err)
+	}
+
 	bitCloud := &bitbucketcloud.Provider{}
 
 	isBitCloud, processReq, logger, reason, err := bitCloud.Detect(req, reqBody, &log)
@@ -15,8 +15,10 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github/app"
@@ -237,6 +239,10 @@ func (l *listener) processIncoming(targetRepo *v1alpha1.Repository) (provider.In
 			provider = &bitbucketcloud.Provider{}
 		case "bitbucket-datacenter":
 			provider = &bitbucketdatacenter.Provider{}
+		case "azure-devops":
+			provider = &azuredevops.Provider{}
+		case "gerrit":
+			provider = &gerrit.Provider{}
 		default:
 			return l.processRes(false, nil, l.logger.With("namespace", targetRepo.Namespace), "", fmt.Errorf("no supported Git provider has been detected"))
 		}
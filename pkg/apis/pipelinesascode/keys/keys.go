@@ -51,16 +51,34 @@ const (
 	OnEvent                = pipelinesascode.GroupName + "/on-event"
 	OnComment              = pipelinesascode.GroupName + "/on-comment"
 	OnTargetBranch         = pipelinesascode.GroupName + "/on-target-branch"
+	OnTargetBranchIgnore   = pipelinesascode.GroupName + "/on-target-branch-ignore"
 	OnPathChange           = pipelinesascode.GroupName + "/on-path-change"
 	OnLabel                = pipelinesascode.GroupName + "/on-label"
+	OnLabelIgnore          = pipelinesascode.GroupName + "/on-label-ignore"
 	OnPathChangeIgnore     = pipelinesascode.GroupName + "/on-path-change-ignore"
 	OnCelExpression        = pipelinesascode.GroupName + "/on-cel-expression"
+	OnSchedule             = pipelinesascode.GroupName + "/on-schedule"
+	OnScheduleTimeZone     = pipelinesascode.GroupName + "/on-schedule-timezone"
 	TargetNamespace        = pipelinesascode.GroupName + "/target-namespace"
 	MaxKeepRuns            = pipelinesascode.GroupName + "/max-keep-runs"
+	MaxKeepFailedRuns      = pipelinesascode.GroupName + "/max-keep-failed-runs"
+	MaxKeepSuccessfulRuns  = pipelinesascode.GroupName + "/max-keep-successful-runs"
+	KeepFor                = pipelinesascode.GroupName + "/keep-for"
 	CancelInProgress       = pipelinesascode.GroupName + "/cancel-in-progress"
 	LogURL                 = pipelinesascode.GroupName + "/log-url"
 	ExecutionOrder         = pipelinesascode.GroupName + "/execution-order"
+	ConcurrencyGroup       = pipelinesascode.GroupName + "/concurrency-group"
+	Priority               = pipelinesascode.GroupName + "/priority"
+	ConcurrencyLimit       = pipelinesascode.GroupName + "/concurrency-limit"
+	SupersededBy           = pipelinesascode.GroupName + "/superseded-by"
+	KueueQueueName         = pipelinesascode.GroupName + "/kueue-queue-name"
+	QueueTimeout           = pipelinesascode.GroupName + "/queue-timeout"
+	MaxRetries             = pipelinesascode.GroupName + "/max-retries"
+	RetryOn                = pipelinesascode.GroupName + "/retry-on"
+	RetryAttempts          = pipelinesascode.GroupName + "/retry-attempts"
+	RetriedAs              = pipelinesascode.GroupName + "/retried-as"
 	SCMReportingPLRStarted = pipelinesascode.GroupName + "/scm-reporting-plr-started"
+	AIAnalysis             = pipelinesascode.GroupName + "/ai-analysis"
 	// PublicGithubAPIURL default is "https://api.github.com" but it can be overridden by X-GitHub-Enterprise-Host header.
 	PublicGithubAPIURL   = "https://api.github.com"
 	GithubApplicationID  = "github-application-id"
@@ -1,6 +1,9 @@
 package v1alpha1
 
 import (
+	"path"
+	"slices"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
@@ -23,6 +26,52 @@ type Repository struct {
 
 	Spec   RepositorySpec        `json:"spec"`
 	Status []RepositoryRunStatus `json:"pipelinerun_status,omitempty"`
+
+	// ScheduleStatus are the PipelineRuns of the default branch running on a
+	// schedule and when they have been run last.
+	// +optional
+	ScheduleStatus []ScheduleStatus `json:"schedule_status,omitempty"`
+
+	// PollStatus are the commits the references of a polled repository
+	// pointed to on the last poll.
+	// +optional
+	PollStatus *PollStatus `json:"poll_status,omitempty"`
+
+	// AIAnalysisUsage is the number of LLM tokens used by the AI analyses of
+	// the PipelineRuns of the Repository.
+	// +optional
+	AIAnalysisUsage *AIAnalysisUsage `json:"ai_analysis_usage,omitempty"`
+}
+
+// ScheduleStatus is a PipelineRun of the default branch with an on-schedule
+// annotation.
+type ScheduleStatus struct {
+	// PipelineRunName is the name of the PipelineRun in the .tekton directory
+	PipelineRunName string `json:"pipelineRunName"`
+
+	// Schedule is the cron schedule of the PipelineRun
+	Schedule string `json:"schedule"`
+
+	// TimeZone is the time zone of the schedule, UTC when not set
+	// +optional
+	TimeZone string `json:"timezone,omitempty"`
+
+	// Branch is the branch the PipelineRun is run on
+	Branch string `json:"branch"`
+
+	// LastScheduleTime is the last time the PipelineRun has been scheduled.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+}
+
+// PollStatus are the references of a polled plain git repository.
+type PollStatus struct {
+	// URL is the URL of the repository the references have been listed from
+	URL string `json:"url"`
+
+	// Refs are the commits the references pointed to on the last poll
+	// +optional
+	Refs map[string]string `json:"refs,omitempty"`
 }
 
 type RepositoryRunStatus struct {
@@ -64,8 +113,26 @@ type RepositoryRunStatus struct {
 	// +optional
 	EventType *string `json:"event_type,omitempty"`
 
+	// ConcurrencyGroup is the concurrency group of that run
+	// +optional
+	ConcurrencyGroup *string `json:"concurrency_group,omitempty"`
+
 	// CollectedTaskInfos is the information about tasks
 	CollectedTaskInfos *map[string]TaskInfos `json:"failure_reason,omitempty"`
+
+	// Attempts is the number of times the PipelineRun has been run, it is
+	// greater than 1 when it has been retried after a failure.
+	// +optional
+	Attempts int `json:"attempts,omitempty"`
+
+	// Flaky is true when the PipelineRun succeeded only after being retried.
+	// +optional
+	Flaky bool `json:"flaky,omitempty"`
+
+	// AIAnalysis is the result of the AI analysis of the PipelineRun by
+	// analysis role name, for the roles with the repository-status output.
+	// +optional
+	AIAnalysis map[string]string `json:"ai_analysis,omitempty"`
 }
 
 // TaskInfos contains information about a task.
@@ -88,6 +155,21 @@ type RepositorySpec struct {
 	// +kubebuilder:validation:Minimum=1
 	ConcurrencyLimit *int `json:"concurrency_limit,omitempty"` // move it to settings in further version of the spec
 
+	// ConcurrencyGroups defines the limits of the concurrency groups. The
+	// PipelineRuns with the same pipelinesascode.tekton.dev/concurrency-group
+	// annotation share one queue, even when they belong to different Repositories.
+	// +optional
+	ConcurrencyGroups []ConcurrencyGroup `json:"concurrency_groups,omitempty"`
+
+	// PriorityExpression is a CEL expression evaluating to the priority of the
+	// queued PipelineRuns of the repository, for example
+	// `event == "push" && target_branch == "main" ? 100 : 0`. The PipelineRuns
+	// with the highest priority leave the queue first. The
+	// pipelinesascode.tekton.dev/priority annotation of a PipelineRun takes
+	// precedence over it.
+	// +optional
+	PriorityExpression string `json:"priority_expression,omitempty"`
+
 	// URL of the repository we are building. Must be a valid HTTP/HTTPS Git repository URL
 	// that PAC will use to clone and fetch pipeline definitions from.
 	// +optional
@@ -112,12 +194,95 @@ type RepositorySpec struct {
 	// authorization policies, provider-specific configuration, and provenance settings.
 	// +optional
 	Settings *Settings `json:"settings,omitempty"`
+
+	// Poll configures the polling of a plain git repository which cannot send webhooks.
+	// When set, the watcher periodically lists the references of the repository with
+	// git ls-remote and runs the push PipelineRuns when a branch or a tag changes.
+	// +optional
+	Poll *Poll `json:"poll,omitempty"`
+}
+
+// ConcurrencyGroup defines how many PipelineRuns of a concurrency group can
+// run at the same time.
+type ConcurrencyGroup struct {
+	// Name of the group as set in the pipelinesascode.tekton.dev/concurrency-group
+	// annotation of the PipelineRuns. It can be a glob, to match the names of
+	// the groups templated with dynamic variables like {{ target_branch }}.
+	Name string `json:"name"`
+
+	// Limit is the maximum number of PipelineRuns of the group running at the same time.
+	// +kubebuilder:validation:Minimum=1
+	Limit int `json:"limit"`
+
+	// Scope of the group:
+	// - 'namespace': the group is shared by the Repositories of the namespace (default)
+	// - 'cluster': the group is shared by all the Repositories of the cluster, it can
+	// only be defined in the global repository
+	// +optional
+	// +kubebuilder:validation:Enum=namespace;cluster
+	Scope string `json:"scope,omitempty"`
+}
+
+const (
+	// ConcurrencyGroupScopeNamespace shares a concurrency group between the
+	// Repositories of a namespace.
+	ConcurrencyGroupScopeNamespace = "namespace"
+	// ConcurrencyGroupScopeCluster shares a concurrency group between all the
+	// Repositories of the cluster.
+	ConcurrencyGroupScopeCluster = "cluster"
+)
+
+// GetConcurrencyGroup returns the first concurrency group matching name or nil
+// when the group is not defined.
+func (r *RepositorySpec) GetConcurrencyGroup(name string) *ConcurrencyGroup {
+	if name == "" {
+		return nil
+	}
+	for i := range r.ConcurrencyGroups {
+		if r.ConcurrencyGroups[i].Name == name {
+			return &r.ConcurrencyGroups[i]
+		}
+		if matched, err := path.Match(r.ConcurrencyGroups[i].Name, name); err == nil && matched {
+			return &r.ConcurrencyGroups[i]
+		}
+	}
+	return nil
+}
+
+// mergeConcurrencyGroups merges the concurrency groups of the global
+// repository. A cluster scoped group is shared by all the Repositories, its
+// limit is only read from the global repository and comes first so a local
+// group with the same name cannot override it, the local cluster scoped groups
+// are ignored. The other groups of the global repository are appended after
+// the local ones, the local definition of a group wins.
+func (r *RepositorySpec) mergeConcurrencyGroups(globalGroups []ConcurrencyGroup) {
+	var groups []ConcurrencyGroup
+	for _, group := range globalGroups {
+		if group.Scope == ConcurrencyGroupScopeCluster {
+			groups = append(groups, group)
+		}
+	}
+	for _, group := range r.ConcurrencyGroups {
+		if group.Scope != ConcurrencyGroupScopeCluster {
+			groups = append(groups, group)
+		}
+	}
+	for _, group := range globalGroups {
+		if group.Scope != ConcurrencyGroupScopeCluster && !slices.ContainsFunc(groups, func(g ConcurrencyGroup) bool { return g.Name == group.Name }) {
+			groups = append(groups, group)
+		}
+	}
+	r.ConcurrencyGroups = groups
 }
 
 func (r *RepositorySpec) Merge(newRepo RepositorySpec) {
 	if newRepo.ConcurrencyLimit != nil && r.ConcurrencyLimit == nil {
 		r.ConcurrencyLimit = newRepo.ConcurrencyLimit
 	}
+	if newRepo.PriorityExpression != "" && r.PriorityExpression == "" {
+		r.PriorityExpression = newRepo.PriorityExpression
+	}
+	r.mergeConcurrencyGroups(newRepo.ConcurrencyGroups)
 	if newRepo.Settings != nil && r.Settings != nil {
 		r.Settings.Merge(newRepo.Settings)
 	}
@@ -150,6 +315,30 @@ type Settings struct {
 	// +kubebuilder:validation:Enum=source;default_branch
 	PipelineRunProvenance string `json:"pipelinerun_provenance,omitempty"`
 
+	// SkipDraftPullRequests skips the PipelineRuns of the draft pull requests, a
+	// neutral status is reported instead and the PipelineRuns are started when
+	// the pull request is marked as ready for review.
+	// +optional
+	SkipDraftPullRequests bool `json:"skip_draft_pull_requests,omitempty"`
+
+	// CoalesceQueuedPipelineRuns cancels the queued PipelineRuns which have not
+	// started yet when a newer PipelineRun with the same name is queued for
+	// the same event type and branch.
+	// +optional
+	CoalesceQueuedPipelineRuns bool `json:"coalesce_queued_pipelineruns,omitempty"`
+
+	// KueueQueueName hands the admission of the PipelineRuns over to Kueue,
+	// they are labelled with the name of this Kueue LocalQueue instead of
+	// being queued by Pipelines-as-Code.
+	// +optional
+	KueueQueueName string `json:"kueue_queue_name,omitempty"`
+
+	// MaxQueueWait is the maximum duration a PipelineRun waits in the queue
+	// before being cancelled and reported as timed out, for example `2h`. It
+	// overrides the max-queue-wait setting of the pipelines-as-code configmap.
+	// +optional
+	MaxQueueWait string `json:"max_queue_wait,omitempty"`
+
 	// Policy defines authorization policies for the repository, controlling who can
 	// trigger PipelineRuns under different conditions.
 	// +optional
@@ -191,6 +380,18 @@ func (s *Settings) Merge(newSettings *Settings) {
 	if newSettings.Policy != nil && s.Policy == nil {
 		s.Policy = newSettings.Policy
 	}
+	if newSettings.SkipDraftPullRequests {
+		s.SkipDraftPullRequests = true
+	}
+	if newSettings.CoalesceQueuedPipelineRuns {
+		s.CoalesceQueuedPipelineRuns = true
+	}
+	if newSettings.KueueQueueName != "" && s.KueueQueueName == "" {
+		s.KueueQueueName = newSettings.KueueQueueName
+	}
+	if newSettings.MaxQueueWait != "" && s.MaxQueueWait == "" {
+		s.MaxQueueWait = newSettings.MaxQueueWait
+	}
 	if newSettings.GithubAppTokenScopeRepos != nil && s.GithubAppTokenScopeRepos == nil {
 		s.GithubAppTokenScopeRepos = newSettings.GithubAppTokenScopeRepos
 	}
@@ -259,6 +460,19 @@ type Incoming struct {
 	Targets []string `json:"targets,omitempty"`
 }
 
+type Poll struct {
+	// Interval between two polls of the repository, as a duration (e.g. '5m', '1h').
+	// Defaults to 5m, intervals shorter than 1m are rounded up to 1m.
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Refs is the list of references to watch, glob patterns are supported
+	// (e.g. 'refs/heads/main', 'refs/heads/release-*', 'refs/tags/*').
+	// Defaults to all the branches and tags of the repository.
+	// +optional
+	Refs []string `json:"refs,omitempty"`
+}
+
 type GitProvider struct {
 	// URL of the git provider API endpoint. This is the base URL for API requests to the
 	// Git provider (e.g., 'https://api.github.com' for GitHub or a custom GitLab instance URL).
@@ -287,8 +501,10 @@ type GitProvider struct {
 	// - 'bitbucket-datacenter': Bitbucket Data Center (self-hosted)
 	// - 'bitbucket-cloud': Bitbucket Cloud (bitbucket.org)
 	// - 'gitea': Gitea instances
+	// - 'azure-devops': Azure DevOps Services or Azure DevOps Server
+	// - 'gerrit': Gerrit Code Review
 	// +optional
-	// +kubebuilder:validation:Enum=github;gitlab;bitbucket-datacenter;bitbucket-cloud;gitea
+	// +kubebuilder:validation:Enum=github;gitlab;bitbucket-datacenter;bitbucket-cloud;gitea;azure-devops;gerrit
 	Type string `json:"type,omitempty"`
 }
 
@@ -1,10 +1,40 @@
 package v1alpha1
 
+import (
+	"slices"
+	"time"
+)
+
+// The destinations of the results of an analysis role.
+const (
+	AnalysisOutputPRComment             = "pr-comment"
+	AnalysisOutputCommitComment         = "commit-comment"
+	AnalysisOutputCheckRun              = "check-run"
+	AnalysisOutputRepositoryStatus      = "repository-status"
+	AnalysisOutputPipelineRunAnnotation = "pipelinerun-annotation"
+)
+
+// AnalysisOutputs are all the supported destinations of the results of an
+// analysis role.
+var AnalysisOutputs = []string{
+	AnalysisOutputPRComment,
+	AnalysisOutputCommitComment,
+	AnalysisOutputCheckRun,
+	AnalysisOutputRepositoryStatus,
+	AnalysisOutputPipelineRunAnnotation,
+}
+
 const (
 	// defaultContainerLogsMaxLines is the default maximum number of log lines to fetch per container.
 	defaultContainerLogsMaxLines = 50
+	// defaultSourceSnippetsContextLines is the default number of lines included before and after an error location.
+	defaultSourceSnippetsContextLines = 10
+	// defaultSourceSnippetsMaxSize is the default maximum number of characters of all the source snippets.
+	defaultSourceSnippetsMaxSize = 8000
 	defaultOpenAIURL             = "https://api.openai.com/v1"
 	defaultGeminiURL             = "https://generativelanguage.googleapis.com/v1beta"
+	defaultAnthropicURL          = "https://api.anthropic.com/v1"
+	defaultOllamaURL             = "http://localhost:11434"
 )
 
 // AIAnalysisConfig defines configuration for AI/LLM-powered analysis of CI/CD pipeline events.
@@ -15,20 +45,23 @@ type AIAnalysisConfig struct {
 
 	// Provider specifies which LLM provider to use for analysis
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=openai;gemini
+	// +kubebuilder:validation:Enum=openai;gemini;anthropic;ollama
 	Provider string `json:"provider"`
 
 	// APIURL is an optional base URL to override the default API endpoint of the LLM provider.
 	// If not specified, provider-specific defaults are used:
 	// - OpenAI: https://api.openai.com/v1
 	// - Gemini: https://generativelanguage.googleapis.com/v1beta
+	// - Anthropic: https://api.anthropic.com/v1
+	// - Ollama: http://localhost:11434
 	// Use this to configure self-hosted LLM instances, proxy services, or alternative endpoints.
 	// +optional
 	APIURL string `json:"api_url,omitempty"`
 
-	// TokenSecretRef references the Kubernetes secret containing the LLM provider API token
-	// +kubebuilder:validation:Required
-	TokenSecretRef *Secret `json:"secret_ref"`
+	// TokenSecretRef references the Kubernetes secret containing the LLM provider API token.
+	// It is required for all the providers but Ollama.
+	// +optional
+	TokenSecretRef *Secret `json:"secret_ref,omitempty"`
 
 	// TimeoutSeconds sets the maximum time to wait for LLM analysis (default: 30)
 	// +optional
@@ -42,6 +75,11 @@ type AIAnalysisConfig struct {
 	// +kubebuilder:validation:Maximum=4000
 	MaxTokens int `json:"max_tokens,omitempty"`
 
+	// TokenBudget limits the number of LLM tokens used by the analyses, the
+	// roles are skipped once a budget is exhausted
+	// +optional
+	TokenBudget *TokenBudget `json:"token_budget,omitempty"`
+
 	// Roles defines different analysis scenarios and their configurations
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinItems=1
@@ -50,6 +88,84 @@ type AIAnalysisConfig struct {
 	Roles []AnalysisRole `json:"roles"`
 }
 
+// TokenBudget limits the number of LLM tokens used by the analyses of the
+// Repository and of all the Repositories of its namespace, per day and per
+// month in UTC. A budget of 0 is no limit.
+type TokenBudget struct {
+	// Daily is the maximum number of tokens used per day by the analyses of the Repository
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Daily int `json:"daily,omitempty"`
+
+	// Monthly is the maximum number of tokens used per month by the analyses of the Repository
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Monthly int `json:"monthly,omitempty"`
+
+	// NamespaceDaily is the maximum number of tokens used per day by the
+	// analyses of all the Repositories of the namespace
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	NamespaceDaily int `json:"namespace_daily,omitempty"`
+
+	// NamespaceMonthly is the maximum number of tokens used per month by the
+	// analyses of all the Repositories of the namespace
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	NamespaceMonthly int `json:"namespace_monthly,omitempty"`
+}
+
+// AIAnalysisUsage is the number of LLM tokens used by the analyses of a
+// Repository in the current day and month, in UTC.
+type AIAnalysisUsage struct {
+	// Day is the day of DailyTokens, as YYYY-MM-DD
+	// +optional
+	Day string `json:"day,omitempty"`
+
+	// DailyTokens is the number of tokens used on Day
+	// +optional
+	DailyTokens int `json:"daily_tokens,omitempty"`
+
+	// Month is the month of MonthlyTokens, as YYYY-MM
+	// +optional
+	Month string `json:"month,omitempty"`
+
+	// MonthlyTokens is the number of tokens used in Month
+	// +optional
+	MonthlyTokens int `json:"monthly_tokens,omitempty"`
+}
+
+const (
+	usageDayLayout   = "2006-01-02"
+	usageMonthLayout = "2006-01"
+)
+
+// Tokens returns the number of tokens used on the day and in the month of now.
+func (u *AIAnalysisUsage) Tokens(now time.Time) (daily, monthly int) {
+	if u == nil {
+		return 0, 0
+	}
+	now = now.UTC()
+	if u.Day == now.Format(usageDayLayout) {
+		daily = u.DailyTokens
+	}
+	if u.Month == now.Format(usageMonthLayout) {
+		monthly = u.MonthlyTokens
+	}
+	return daily, monthly
+}
+
+// Add adds the tokens used at now, the tokens used on a previous day or in a
+// previous month are not counted anymore.
+func (u *AIAnalysisUsage) Add(now time.Time, tokens int) {
+	u.DailyTokens, u.MonthlyTokens = u.Tokens(now)
+	now = now.UTC()
+	u.Day = now.Format(usageDayLayout)
+	u.Month = now.Format(usageMonthLayout)
+	u.DailyTokens += tokens
+	u.MonthlyTokens += tokens
+}
+
 // AnalysisRole defines a specific analysis scenario with its prompt, conditions, and output configuration.
 type AnalysisRole struct {
 	// Name is a unique identifier for this analysis role
@@ -65,6 +181,8 @@ type AnalysisRole struct {
 	// If not specified, provider-specific defaults are used:
 	// - OpenAI: gpt-5-mini
 	// - Gemini: gemini-2.5-flash-lite
+	// - Anthropic: claude-haiku-4-5
+	// - Ollama: llama3.2
 	// +optional
 	Model string `json:"model,omitempty"`
 
@@ -72,12 +190,22 @@ type AnalysisRole struct {
 	// +optional
 	OnCEL string `json:"on_cel,omitempty"`
 
-	// Output specifies where the analysis results should be sent (default: pr-comment)
+	// Output specifies where the analysis results should be sent (default: pr-comment):
+	// - pr-comment: a comment on the pull request
+	// - commit-comment: a comment on the commit, for push events
+	// - check-run: the text of the check run, or commit status, of the PipelineRun
+	// - repository-status: the run status of the PipelineRun in the Repository
+	// - pipelinerun-annotation: an annotation of the PipelineRun, shown by tkn pac describe
 	// +optional
-	// +kubebuilder:default=pr-comment
-	// +kubebuilder:validation:Enum=pr-comment
+	// +kubebuilder:validation:Enum=pr-comment;commit-comment;check-run;repository-status;pipelinerun-annotation
 	Output string `json:"output,omitempty"`
 
+	// Outputs specifies more destinations the analysis results are sent to,
+	// in addition to Output.
+	// +optional
+	// +kubebuilder:validation:items:Enum=pr-comment;commit-comment;check-run;repository-status;pipelinerun-annotation
+	Outputs []string `json:"outputs,omitempty"`
+
 	// ContextItems defines what context data to include in the analysis
 	// +optional
 	ContextItems *ContextConfig `json:"context_items,omitempty"`
@@ -100,6 +228,11 @@ type ContextConfig struct {
 	// ContainerLogs configures inclusion of container/task logs
 	// +optional
 	ContainerLogs *ContainerLogsConfig `json:"container_logs,omitempty"`
+
+	// SourceSnippets configures inclusion of the source code around the error
+	// locations detected in the logs of the failed tasks
+	// +optional
+	SourceSnippets *SourceSnippetsConfig `json:"source_snippets,omitempty"`
 }
 
 // ContainerLogsConfig defines how container logs should be included in analysis.
@@ -121,12 +254,54 @@ func (c *ContainerLogsConfig) GetMaxLines() int {
 	return c.MaxLines
 }
 
-// GetOutput returns the output destination with a default value if not specified.
-func (r *AnalysisRole) GetOutput() string {
-	if r.Output == "" {
-		return "pr-comment"
+// SourceSnippetsConfig defines how the source code around the error locations
+// should be included in analysis.
+type SourceSnippetsConfig struct {
+	// Enabled controls whether source snippets are included
+	Enabled bool `json:"enabled"`
+
+	// ContextLines is the number of lines included before and after each
+	// error location (default: 10)
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	ContextLines int `json:"context_lines,omitempty"`
+
+	// MaxSize limits the number of characters of all the snippets included
+	// (default: 8000)
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100000
+	MaxSize int `json:"max_size,omitempty"`
+}
+
+func (c *SourceSnippetsConfig) GetContextLines() int {
+	if c == nil || c.ContextLines == 0 {
+		return defaultSourceSnippetsContextLines
+	}
+	return c.ContextLines
+}
+
+func (c *SourceSnippetsConfig) GetMaxSize() int {
+	if c == nil || c.MaxSize == 0 {
+		return defaultSourceSnippetsMaxSize
+	}
+	return c.MaxSize
+}
+
+// GetOutputs returns the destinations of Output and Outputs without
+// duplicates, pr-comment when none is specified.
+func (r *AnalysisRole) GetOutputs() []string {
+	outputs := []string{}
+	for _, output := range append([]string{r.Output}, r.Outputs...) {
+		if output != "" && !slices.Contains(outputs, output) {
+			outputs = append(outputs, output)
+		}
+	}
+	if len(outputs) == 0 {
+		return []string{AnalysisOutputPRComment}
 	}
-	return r.Output
+	return outputs
 }
 
 // GetModel returns the configured model or an empty string to use provider default.
@@ -149,6 +324,10 @@ func GetProviderDefaultAPIURL(provider string) string {
 		return defaultOpenAIURL
 	case "gemini":
 		return defaultGeminiURL
+	case "anthropic":
+		return defaultAnthropicURL
+	case "ollama":
+		return defaultOllamaURL
 	default:
 		return ""
 	}
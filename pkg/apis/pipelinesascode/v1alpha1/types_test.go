@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 )
@@ -117,6 +118,78 @@ func TestMergeSpecs(t *testing.T) {
 				ConcurrencyLimit: &two,
 			},
 		},
+		{
+			name: "namespace concurrency groups are appended unless defined locally",
+			local: &RepositorySpec{
+				ConcurrencyGroups: []ConcurrencyGroup{{Name: "deploy", Limit: 2}},
+			},
+			global: RepositorySpec{
+				ConcurrencyGroups: []ConcurrencyGroup{
+					{Name: "deploy", Limit: 1},
+					{Name: "e2e-*", Limit: 3},
+				},
+			},
+			expected: &RepositorySpec{
+				ConcurrencyGroups: []ConcurrencyGroup{
+					{Name: "deploy", Limit: 2},
+					{Name: "e2e-*", Limit: 3},
+				},
+			},
+		},
+		{
+			name: "cluster concurrency groups only come from the global repository",
+			local: &RepositorySpec{
+				ConcurrencyGroups: []ConcurrencyGroup{
+					{Name: "deploy", Limit: 2},
+					{Name: "release", Limit: 5, Scope: ConcurrencyGroupScopeCluster},
+				},
+			},
+			global: RepositorySpec{
+				ConcurrencyGroups: []ConcurrencyGroup{
+					{Name: "deploy", Limit: 1, Scope: ConcurrencyGroupScopeCluster},
+				},
+			},
+			expected: &RepositorySpec{
+				ConcurrencyGroups: []ConcurrencyGroup{
+					{Name: "deploy", Limit: 1, Scope: ConcurrencyGroupScopeCluster},
+					{Name: "deploy", Limit: 2},
+				},
+			},
+		},
+		{
+			name:  "global priority expression",
+			local: &RepositorySpec{},
+			global: RepositorySpec{
+				PriorityExpression: `event == "push" ? 10 : 0`,
+			},
+			expected: &RepositorySpec{
+				PriorityExpression: `event == "push" ? 10 : 0`,
+			},
+		},
+		{
+			name: "global kueue queue",
+			local: &RepositorySpec{
+				Settings: &Settings{},
+			},
+			global: RepositorySpec{
+				Settings: &Settings{KueueQueueName: "pipelines"},
+			},
+			expected: &RepositorySpec{
+				Settings: &Settings{KueueQueueName: "pipelines"},
+			},
+		},
+		{
+			name: "local max queue wait takes precedence",
+			local: &RepositorySpec{
+				Settings: &Settings{MaxQueueWait: "30m"},
+			},
+			global: RepositorySpec{
+				Settings: &Settings{MaxQueueWait: "2h"},
+			},
+			expected: &RepositorySpec{
+				Settings: &Settings{MaxQueueWait: "30m"},
+			},
+		},
 		{
 			name: "different git providers",
 			local: &RepositorySpec{
@@ -142,3 +215,65 @@ func TestMergeSpecs(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConcurrencyGroup(t *testing.T) {
+	spec := RepositorySpec{
+		ConcurrencyGroups: []ConcurrencyGroup{
+			{Name: "deploy-prod", Limit: 1},
+			{Name: "deploy-*", Limit: 2},
+		},
+	}
+	tests := []struct {
+		name      string
+		group     string
+		wantLimit int
+		wantNil   bool
+	}{
+		{name: "exact name", group: "deploy-prod", wantLimit: 1},
+		{name: "glob", group: "deploy-main", wantLimit: 2},
+		{name: "undefined group", group: "e2e", wantNil: true},
+		{name: "no group", group: "", wantNil: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := spec.GetConcurrencyGroup(tt.group)
+			if tt.wantNil {
+				assert.Assert(t, group == nil)
+				return
+			}
+			assert.Assert(t, group != nil)
+			assert.Equal(t, group.Limit, tt.wantLimit)
+		})
+	}
+}
+
+func TestAIAnalysisUsage(t *testing.T) {
+	day := time.Date(2026, 10, 17, 23, 30, 0, 0, time.UTC)
+	usage := &AIAnalysisUsage{}
+	usage.Add(day, 100)
+	usage.Add(day, 50)
+	daily, monthly := usage.Tokens(day)
+	assert.Equal(t, daily, 150)
+	assert.Equal(t, monthly, 150)
+	assert.Equal(t, usage.Day, "2026-10-17")
+	assert.Equal(t, usage.Month, "2026-10")
+
+	nextDay := day.Add(time.Hour)
+	daily, monthly = usage.Tokens(nextDay)
+	assert.Equal(t, daily, 0)
+	assert.Equal(t, monthly, 150)
+	usage.Add(nextDay, 10)
+	daily, monthly = usage.Tokens(nextDay)
+	assert.Equal(t, daily, 10)
+	assert.Equal(t, monthly, 160)
+
+	nextMonth := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	daily, monthly = usage.Tokens(nextMonth)
+	assert.Equal(t, daily, 0)
+	assert.Equal(t, monthly, 0)
+
+	var noUsage *AIAnalysisUsage
+	daily, monthly = noUsage.Tokens(day)
+	assert.Equal(t, daily, 0)
+	assert.Equal(t, monthly, 0)
+}
@@ -24,12 +24,321 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AIAnalysisConfig) DeepCopyInto(out *AIAnalysisConfig) {
+	*out = *in
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(Secret)
+		**out = **in
+	}
+	if in.TokenBudget != nil {
+		in, out := &in.TokenBudget, &out.TokenBudget
+		*out = new(TokenBudget)
+		**out = **in
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]AnalysisRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIAnalysisConfig.
+func (in *AIAnalysisConfig) DeepCopy() *AIAnalysisConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AIAnalysisConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AIAnalysisUsage) DeepCopyInto(out *AIAnalysisUsage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIAnalysisUsage.
+func (in *AIAnalysisUsage) DeepCopy() *AIAnalysisUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(AIAnalysisUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisRole) DeepCopyInto(out *AnalysisRole) {
+	*out = *in
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContextItems != nil {
+		in, out := &in.ContextItems, &out.ContextItems
+		*out = new(ContextConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisRole.
+func (in *AnalysisRole) DeepCopy() *AnalysisRole {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConcurrencyGroup) DeepCopyInto(out *ConcurrencyGroup) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConcurrencyGroup.
+func (in *ConcurrencyGroup) DeepCopy() *ConcurrencyGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ConcurrencyGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerLogsConfig) DeepCopyInto(out *ContainerLogsConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerLogsConfig.
+func (in *ContainerLogsConfig) DeepCopy() *ContainerLogsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerLogsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContextConfig) DeepCopyInto(out *ContextConfig) {
+	*out = *in
+	if in.ContainerLogs != nil {
+		in, out := &in.ContainerLogs, &out.ContainerLogs
+		*out = new(ContainerLogsConfig)
+		**out = **in
+	}
+	if in.SourceSnippets != nil {
+		in, out := &in.SourceSnippets, &out.SourceSnippets
+		*out = new(SourceSnippetsConfig)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContextConfig.
+func (in *ContextConfig) DeepCopy() *ContextConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ContextConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitProvider) DeepCopyInto(out *GitProvider) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(Secret)
+		**out = **in
+	}
+	if in.WebhookSecret != nil {
+		in, out := &in.WebhookSecret, &out.WebhookSecret
+		*out = new(Secret)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitProvider.
+func (in *GitProvider) DeepCopy() *GitProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(GitProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GithubSettings) DeepCopyInto(out *GithubSettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubSettings.
+func (in *GithubSettings) DeepCopy() *GithubSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(GithubSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitlabSettings) DeepCopyInto(out *GitlabSettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitlabSettings.
+func (in *GitlabSettings) DeepCopy() *GitlabSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(GitlabSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Incoming) DeepCopyInto(out *Incoming) {
+	*out = *in
+	out.Secret = in.Secret
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Incoming.
+func (in *Incoming) DeepCopy() *Incoming {
+	if in == nil {
+		return nil
+	}
+	out := new(Incoming)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Params) DeepCopyInto(out *Params) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(Secret)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Params.
+func (in *Params) DeepCopy() *Params {
+	if in == nil {
+		return nil
+	}
+	out := new(Params)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Policy) DeepCopyInto(out *Policy) {
+	*out = *in
+	if in.OkToTest != nil {
+		in, out := &in.OkToTest, &out.OkToTest
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PullRequest != nil {
+		in, out := &in.PullRequest, &out.PullRequest
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Policy.
+func (in *Policy) DeepCopy() *Policy {
+	if in == nil {
+		return nil
+	}
+	out := new(Policy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Poll) DeepCopyInto(out *Poll) {
+	*out = *in
+	if in.Refs != nil {
+		in, out := &in.Refs, &out.Refs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Poll.
+func (in *Poll) DeepCopy() *Poll {
+	if in == nil {
+		return nil
+	}
+	out := new(Poll)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PollStatus) DeepCopyInto(out *PollStatus) {
+	*out = *in
+	if in.Refs != nil {
+		in, out := &in.Refs, &out.Refs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PollStatus.
+func (in *PollStatus) DeepCopy() *PollStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PollStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Repository) DeepCopyInto(out *Repository) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = make([]RepositoryRunStatus, len(*in))
@@ -37,6 +346,23 @@ func (in *Repository) DeepCopyInto(out *Repository) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ScheduleStatus != nil {
+		in, out := &in.ScheduleStatus, &out.ScheduleStatus
+		*out = make([]ScheduleStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PollStatus != nil {
+		in, out := &in.PollStatus, &out.PollStatus
+		*out = new(PollStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AIAnalysisUsage != nil {
+		in, out := &in.AIAnalysisUsage, &out.AIAnalysisUsage
+		*out = new(AIAnalysisUsage)
+		**out = **in
+	}
 	return
 }
 
@@ -103,6 +429,59 @@ func (in *RepositoryRunStatus) DeepCopyInto(out *RepositoryRunStatus) {
 		in, out := &in.CompletionTime, &out.CompletionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.SHA != nil {
+		in, out := &in.SHA, &out.SHA
+		*out = new(string)
+		**out = **in
+	}
+	if in.SHAURL != nil {
+		in, out := &in.SHAURL, &out.SHAURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.Title != nil {
+		in, out := &in.Title, &out.Title
+		*out = new(string)
+		**out = **in
+	}
+	if in.LogURL != nil {
+		in, out := &in.LogURL, &out.LogURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.TargetBranch != nil {
+		in, out := &in.TargetBranch, &out.TargetBranch
+		*out = new(string)
+		**out = **in
+	}
+	if in.EventType != nil {
+		in, out := &in.EventType, &out.EventType
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConcurrencyGroup != nil {
+		in, out := &in.ConcurrencyGroup, &out.ConcurrencyGroup
+		*out = new(string)
+		**out = **in
+	}
+	if in.CollectedTaskInfos != nil {
+		in, out := &in.CollectedTaskInfos, &out.CollectedTaskInfos
+		*out = new(map[string]TaskInfos)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make(map[string]TaskInfos, len(*in))
+			for key, val := range *in {
+				(*out)[key] = *val.DeepCopy()
+			}
+		}
+	}
+	if in.AIAnalysis != nil {
+		in, out := &in.AIAnalysis, &out.AIAnalysis
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -119,6 +498,53 @@ func (in *RepositoryRunStatus) DeepCopy() *RepositoryRunStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepositorySpec) DeepCopyInto(out *RepositorySpec) {
 	*out = *in
+	if in.ConcurrencyLimit != nil {
+		in, out := &in.ConcurrencyLimit, &out.ConcurrencyLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConcurrencyGroups != nil {
+		in, out := &in.ConcurrencyGroups, &out.ConcurrencyGroups
+		*out = make([]ConcurrencyGroup, len(*in))
+		copy(*out, *in)
+	}
+	if in.GitProvider != nil {
+		in, out := &in.GitProvider, &out.GitProvider
+		*out = new(GitProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Incomings != nil {
+		in, out := &in.Incomings, &out.Incomings
+		*out = new([]Incoming)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]Incoming, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = new([]Params)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]Params, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Poll != nil {
+		in, out := &in.Poll, &out.Poll
+		*out = new(Poll)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -131,3 +557,132 @@ func (in *RepositorySpec) DeepCopy() *RepositorySpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleStatus) DeepCopyInto(out *ScheduleStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleStatus.
+func (in *ScheduleStatus) DeepCopy() *ScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Secret) DeepCopyInto(out *Secret) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Secret.
+func (in *Secret) DeepCopy() *Secret {
+	if in == nil {
+		return nil
+	}
+	out := new(Secret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Settings) DeepCopyInto(out *Settings) {
+	*out = *in
+	if in.GithubAppTokenScopeRepos != nil {
+		in, out := &in.GithubAppTokenScopeRepos, &out.GithubAppTokenScopeRepos
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(Policy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gitlab != nil {
+		in, out := &in.Gitlab, &out.Gitlab
+		*out = new(GitlabSettings)
+		**out = **in
+	}
+	if in.Github != nil {
+		in, out := &in.Github, &out.Github
+		*out = new(GithubSettings)
+		**out = **in
+	}
+	if in.AIAnalysis != nil {
+		in, out := &in.AIAnalysis, &out.AIAnalysis
+		*out = new(AIAnalysisConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Settings.
+func (in *Settings) DeepCopy() *Settings {
+	if in == nil {
+		return nil
+	}
+	out := new(Settings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceSnippetsConfig) DeepCopyInto(out *SourceSnippetsConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceSnippetsConfig.
+func (in *SourceSnippetsConfig) DeepCopy() *SourceSnippetsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceSnippetsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskInfos) DeepCopyInto(out *TaskInfos) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskInfos.
+func (in *TaskInfos) DeepCopy() *TaskInfos {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskInfos)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenBudget) DeepCopyInto(out *TokenBudget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenBudget.
+func (in *TokenBudget) DeepCopy() *TokenBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenBudget)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,54 @@
+package cel
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/decls"
+	"github.com/google/cel-go/common/types"
+)
+
+// FailedTask is a failed task of a PipelineRun as seen by the retry-on
+// expression.
+type FailedTask struct {
+	Name       string
+	Reason     string
+	Message    string
+	LogSnippet string
+}
+
+// RetryOn evaluates the retry-on expression of a failed PipelineRun, the
+// expression has the reason of the PipelineRun failure and its failed tasks
+// with their reason, message and log snippet, and must return a boolean.
+func RetryOn(expr, reason string, failedTasks []FailedTask) (bool, error) {
+	tasks := make([]map[string]string, 0, len(failedTasks))
+	for _, task := range failedTasks {
+		tasks = append(tasks, map[string]string{
+			"name":        task.Name,
+			"reason":      task.Reason,
+			"message":     task.Message,
+			"log_snippet": task.LogSnippet,
+		})
+	}
+
+	env, err := cel.NewEnv(
+		cel.VariableDecls(
+			decls.NewVariable("reason", types.StringType),
+			decls.NewVariable("failed_tasks", types.NewListType(types.NewMapType(types.StringType, types.StringType))),
+		))
+	if err != nil {
+		return false, err
+	}
+	out, err := evaluate(expr, env, map[string]any{
+		"reason":       reason,
+		"failed_tasks": tasks,
+	})
+	if err != nil {
+		return false, err
+	}
+	retry, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %#v returned %v, it must return a boolean", expr, out.Value())
+	}
+	return retry, nil
+}
@@ -0,0 +1,56 @@
+package cel
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRetryOn(t *testing.T) {
+	failedTasks := []FailedTask{
+		{Name: "build", Reason: "Failed", Message: "step build failed", LogSnippet: "error pulling image: 503 Service Unavailable"},
+		{Name: "lint", Reason: "TaskRunImagePullFailed"},
+	}
+	tests := []struct {
+		name      string
+		expr      string
+		want      bool
+		wantError string
+	}{
+		{
+			name: "match a log snippet",
+			expr: `failed_tasks.exists(t, t.log_snippet.contains("503"))`,
+			want: true,
+		},
+		{
+			name: "match a task reason",
+			expr: `failed_tasks.exists(t, t.reason == "TaskRunImagePullFailed")`,
+			want: true,
+		},
+		{
+			name: "match the pipelinerun reason",
+			expr: `reason == "PipelineRunTimeout"`,
+		},
+		{
+			name:      "not a boolean",
+			expr:      `failed_tasks.size()`,
+			wantError: "expression \"failed_tasks.size()\" returned 2, it must return a boolean",
+		},
+		{
+			name:      "unknown variable",
+			expr:      `body.foo == "bar"`,
+			wantError: "undeclared reference to 'body'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RetryOn(tt.expr, "Failed", failedTasks)
+			if tt.wantError != "" {
+				assert.ErrorContains(t, err, tt.wantError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package status
 
 import (
 	"context"
+	"encoding/json"
 	"regexp"
 
 	"github.com/google/go-github/v81/github"
@@ -40,6 +41,10 @@ func convertPrStatusToRepositoryStatus(ctx context.Context, cs *params.Run, pr t
 	kinteract, _ := kubeinteraction.NewKubernetesInteraction(cs)
 	failurereasons := kstatus.CollectFailedTasksLogSnippet(ctx, cs, kinteract, &pr, defaultNumLinesOfLogsInContainersToGrabForErr)
 	prSHA := pr.GetAnnotations()[keys.SHA]
+	var concurrencyGroup *string
+	if group := pr.GetAnnotations()[keys.ConcurrencyGroup]; group != "" {
+		concurrencyGroup = github.Ptr(group)
+	}
 	return pacv1alpha1.RepositoryRunStatus{
 		Status:             pr.Status.Status,
 		LogURL:             &logurl,
@@ -51,7 +56,19 @@ func convertPrStatusToRepositoryStatus(ctx context.Context, cs *params.Run, pr t
 		Title:              github.Ptr(pr.GetAnnotations()[keys.ShaTitle]),
 		TargetBranch:       github.Ptr(pr.GetAnnotations()[keys.Branch]),
 		EventType:          github.Ptr(pr.GetAnnotations()[keys.EventType]),
+		ConcurrencyGroup:   concurrencyGroup,
+		AIAnalysis:         aiAnalysis(pr),
+	}
+}
+
+// aiAnalysis returns the AI analysis by role name stored in the ai-analysis
+// annotation of the PipelineRun, nil when there is none.
+func aiAnalysis(pr tektonv1.PipelineRun) map[string]string {
+	analysis := map[string]string{}
+	if err := json.Unmarshal([]byte(pr.GetAnnotations()[keys.AIAnalysis]), &analysis); err != nil || len(analysis) == 0 {
+		return nil
 	}
+	return analysis
 }
 
 func MixLivePRandRepoStatus(ctx context.Context, cs *params.Run, repository pacv1alpha1.Repository) []pacv1alpha1.RepositoryRunStatus {
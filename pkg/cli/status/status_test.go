@@ -95,3 +95,32 @@ func getPipelineRun(prName, sha string, repo1 v1alpha1.Repository) *tektonv1.Pip
 		},
 	}
 }
+
+func TestAIAnalysis(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "analysis by role",
+			annotations: map[string]string{keys.AIAnalysis: `{"failure-analysis":"the analysis"}`},
+			want:        map[string]string{"failure-analysis": "the analysis"},
+		},
+		{
+			name:        "invalid annotation",
+			annotations: map[string]string{keys.AIAnalysis: `not json`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if d := cmp.Diff(aiAnalysis(pr), tt.want); d != "" {
+				t.Errorf("Diff %s:", d)
+			}
+		})
+	}
+}
@@ -23,7 +23,9 @@ func generateManifest(opts *bootstrapOpts) ([]byte, error) {
 			"check_suite",
 			"issue_comment",
 			"commit_comment",
+			"merge_group",
 			triggertype.PullRequest.String(),
+			triggertype.PullRequestReview.String(),
 			"push",
 		},
 		DefaultPermissions: &github.InstallationPermissions{
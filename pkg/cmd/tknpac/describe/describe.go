@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 	"text/tabwriter"
 	"text/template"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
 	"github.com/spf13/cobra"
@@ -47,6 +49,11 @@ func formatError(cs *cli.ColorScheme, log string) string {
 	return n
 }
 
+// indent adds two spaces at the beginning of every non empty line of the text.
+func indent(text string) string {
+	return regexp.MustCompile(`(?m)^(.)`).ReplaceAllString(strings.TrimSpace(text), "  $1")
+}
+
 func formatStatus(status v1alpha1.RepositoryRunStatus, cs *cli.ColorScheme, c clockwork.Clock) string {
 	return fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s",
 		cs.ColorStatus(status.Status.Conditions[0].Reason),
@@ -229,6 +236,7 @@ func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *
 	colorScheme := ioStreams.ColorScheme()
 	funcMap := template.FuncMap{
 		"formatError":     formatError,
+		"indent":          indent,
 		"formatStatus":    formatStatus,
 		"formatEventType": formatting.CamelCasit,
 		"formatDuration":  formatting.PRDuration,
@@ -246,20 +254,26 @@ func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *
 		}
 	}
 
+	// the namespace budgets cannot be shown without the permission to list
+	// the repositories of the namespace, so just skip them
+	tokenBudgets, _ := llm.TokenBudgetStatuses(ctx, cs.Clients.PipelineAsCode, repository, clock.Now())
+
 	data := struct {
-		Repository  *v1alpha1.Repository
-		Statuses    []v1alpha1.RepositoryRunStatus
-		ColorScheme *cli.ColorScheme
-		Clock       clockwork.Clock
-		Opts        *describeOpts
-		EventList   []corev1.Event
+		Repository   *v1alpha1.Repository
+		TokenBudgets []llm.TokenBudgetStatus
+		Statuses     []v1alpha1.RepositoryRunStatus
+		ColorScheme  *cli.ColorScheme
+		Clock        clockwork.Clock
+		Opts         *describeOpts
+		EventList    []corev1.Event
 	}{
-		Repository:  repository,
-		Statuses:    statuses,
-		ColorScheme: colorScheme,
-		Clock:       clock,
-		EventList:   eventList,
-		Opts:        opts,
+		Repository:   repository,
+		TokenBudgets: tokenBudgets,
+		Statuses:     statuses,
+		ColorScheme:  colorScheme,
+		Clock:        clock,
+		EventList:    eventList,
+		Opts:         opts,
 	}
 	w := ansiterm.NewTabWriter(ioStreams.Out, 0, 5, 3, ' ', tabwriter.TabIndent)
 	t := template.Must(template.New("Describe Repository").Funcs(funcMap).Parse(describeTemplate))
@@ -33,12 +33,15 @@ func TestDescribe(t *testing.T) {
 	ns := "ns"
 	running := tektonv1.PipelineRunReasonRunning.String()
 	type args struct {
-		currentNamespace string
-		repoName         string
-		statuses         []v1alpha1.RepositoryRunStatus
-		opts             *describeOpts
-		pruns            []*tektonv1.PipelineRun
-		events           []*corev1.Event
+		currentNamespace  string
+		repoName          string
+		statuses          []v1alpha1.RepositoryRunStatus
+		opts              *describeOpts
+		pruns             []*tektonv1.PipelineRun
+		events            []*corev1.Event
+		concurrencyGroups []v1alpha1.ConcurrencyGroup
+		tokenBudget       *v1alpha1.TokenBudget
+		aiAnalysisUsage   *v1alpha1.AIAnalysisUsage
 	}
 	tests := []struct {
 		name    string
@@ -83,6 +86,28 @@ func TestDescribe(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "concurrency group",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: ns,
+				opts:             &describeOpts{},
+				concurrencyGroups: []v1alpha1.ConcurrencyGroup{
+					{Name: "deploy-*", Limit: 1, Scope: v1alpha1.ConcurrencyGroupScopeCluster},
+					{Name: "e2e", Limit: 2},
+				},
+				pruns: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(cw, "running", ns, running, map[string]string{
+						keys.Branch:           "main",
+						keys.ConcurrencyGroup: "deploy-main",
+					}, map[string]string{
+						keys.Repository: "test-run",
+					}, 30),
+				},
+				statuses: []v1alpha1.RepositoryRunStatus{},
+			},
+			wantErr: false,
+		},
 		{
 			name: "one live run",
 			args: args{
@@ -188,6 +213,62 @@ func TestDescribe(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "ai analysis",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: "namespace",
+				opts: &describeOpts{
+					PacCliOpts: cli.PacCliOpts{
+						Namespace: "optnamespace",
+					},
+				},
+				statuses: []v1alpha1.RepositoryRunStatus{
+					{
+						Status: knativeduckv1.Status{
+							Conditions: []knativeapis.Condition{
+								{
+									Reason: "Failed",
+								},
+							},
+						},
+						AIAnalysis: map[string]string{
+							"failure-analysis": "## Root Cause\nThe unit tests failed.\n\n## Fix\nUpdate the test.",
+							"security":         "No security issue found.",
+						},
+						PipelineRunName: "pipelinerun1",
+						LogURL:          github.Ptr("https://everywhere.anwywhere"),
+						StartTime:       &metav1.Time{Time: cw.Now().Add(-16 * time.Minute)},
+						CompletionTime:  &metav1.Time{Time: cw.Now().Add(-15 * time.Minute)},
+						SHA:             github.Ptr("SHA"),
+						SHAURL:          github.Ptr("https://anurl.com/commit/SHA"),
+						Title:           github.Ptr("A title"),
+						TargetBranch:    github.Ptr("TargetBranch"),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "token budget",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: "namespace",
+				opts: &describeOpts{
+					PacCliOpts: cli.PacCliOpts{
+						Namespace: "optnamespace",
+					},
+				},
+				tokenBudget: &v1alpha1.TokenBudget{Daily: 10000, Monthly: 100000, NamespaceDaily: 50000},
+				aiAnalysisUsage: &v1alpha1.AIAnalysisUsage{
+					Day:           cw.Now().UTC().Format("2006-01-02"),
+					DailyTokens:   1200,
+					Month:         cw.Now().UTC().Format("2006-01"),
+					MonthlyTokens: 45000,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "use real time",
 			args: args{
@@ -460,12 +541,20 @@ func TestDescribe(t *testing.T) {
 						Namespace: ns,
 					},
 					Spec: v1alpha1.RepositorySpec{
-						URL: "https://anurl.com",
+						URL:               "https://anurl.com",
+						ConcurrencyGroups: tt.args.concurrencyGroups,
 					},
-					Status: tt.args.statuses,
+					Status:          tt.args.statuses,
+					AIAnalysisUsage: tt.args.aiAnalysisUsage,
 				},
 			}
 
+			if tt.args.tokenBudget != nil {
+				repositories[0].Spec.Settings = &v1alpha1.Settings{
+					AIAnalysis: &v1alpha1.AIAnalysisConfig{TokenBudget: tt.args.tokenBudget},
+				}
+			}
+
 			tdata := testclient.Data{
 				Events: tt.args.events,
 				Namespaces: []*corev1.Namespace{
@@ -0,0 +1,197 @@
+package explain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/decisiontrace"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const longhelp = `
+
+explain - explain why the PipelineRuns of a Repository did or did not match an event
+
+tkn pac explain shows for each PipelineRun of the .tekton directory which
+annotation or CEL expression matched or rejected it, and the outcome of the
+policy and ACL checks.
+
+The decision traces of the latest events are stored in the decision traces
+ConfigMap of the Repository, the oldest ones are dropped once there are more
+than 20 of them. The controller only stores them in the namespaces where it
+has been allowed to by a RoleBinding to the
+pipelines-as-code-decision-traces ClusterRole.`
+
+const (
+	namespaceFlag = "namespace"
+	shaFlag       = "sha"
+)
+
+type explainOpts struct {
+	cli.PacCliOpts
+	SHA string
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &explainOpts{PacCliOpts: *cli.NewCliOptions()}
+	cmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Explain why PipelineRuns did or did not match an event",
+		Long:  longhelp,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion("repositories", args)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			var repoName string
+			if len(args) > 0 {
+				repoName = args[0]
+			}
+			ctx := context.Background()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return explain(ctx, run, opts, ioStreams, repoName)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Namespace, namespaceFlag, "n", "", "If present, the namespace scope for this CLI request")
+	_ = cmd.RegisterFlagCompletionFunc(namespaceFlag,
+		func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion(namespaceFlag, args)
+		},
+	)
+	cmd.Flags().StringVarP(&opts.SHA, shaFlag, "s", "", "The SHA of the commit to explain, the latest event is explained when not set")
+	return cmd
+}
+
+func explain(ctx context.Context, cs *params.Run, opts *explainOpts, ioStreams *cli.IOStreams, repoName string) error {
+	var repository *v1alpha1.Repository
+	var err error
+
+	if opts.Namespace != "" {
+		cs.Info.Kube.Namespace = opts.Namespace
+	}
+
+	if repoName != "" {
+		repository, err = cs.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(cs.Info.Kube.Namespace).Get(ctx,
+			repoName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+	} else {
+		repository, err = prompt.SelectRepo(ctx, cs, cs.Info.Kube.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	traces, err := getTraces(ctx, cs, repository, opts.SHA)
+	if err != nil {
+		return err
+	}
+	if len(traces) == 0 {
+		if opts.SHA != "" {
+			return fmt.Errorf("cannot find a decision trace for the sha %s in repository %s, only the latest %d decision traces are kept", opts.SHA, repository.GetName(), decisiontrace.MaxStoredTraces)
+		}
+		return fmt.Errorf("cannot find any decision trace in repository %s", repository.GetName())
+	}
+
+	for i, trace := range traces {
+		if i > 0 {
+			fmt.Fprintln(ioStreams.Out)
+		}
+		render(ioStreams, trace)
+	}
+	return nil
+}
+
+// getTraces returns the decision traces of the repository for the sha sorted
+// from the oldest to the newest, only the newest one when sha is empty.
+func getTraces(ctx context.Context, cs *params.Run, repository *v1alpha1.Repository, sha string) ([]*decisiontrace.Trace, error) {
+	stored, err := decisiontrace.List(ctx, cs.Clients.Kube, repository)
+	if errors.IsNotFound(err) {
+		return nil, fmt.Errorf("cannot find the decision traces ConfigMap %s of repository %s, the controller must be allowed to store it in namespace %s",
+			decisiontrace.ConfigMapName(repository), repository.GetName(), repository.GetNamespace())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	traces := []*decisiontrace.Trace{}
+	for _, trace := range stored {
+		if sha != "" && !strings.HasPrefix(trace.SHA, sha) {
+			continue
+		}
+		traces = append(traces, trace)
+	}
+	sort.SliceStable(traces, func(i, j int) bool {
+		return traces[i].Time.Before(traces[j].Time)
+	})
+	if sha == "" && len(traces) > 0 {
+		return traces[len(traces)-1:], nil
+	}
+	return traces, nil
+}
+
+func render(ioStreams *cli.IOStreams, trace *decisiontrace.Trace) {
+	cs := ioStreams.ColorScheme()
+	out := ioStreams.Out
+
+	fmt.Fprintf(out, "%s %s\n", cs.Bold("Repository:"), trace.Repository)
+	fmt.Fprintf(out, "%s %s\n", cs.Bold("SHA:"), trace.SHA)
+	fmt.Fprintf(out, "%s %s (%s)\n", cs.Bold("Event:"), trace.EventType, trace.TriggerTarget)
+	if trace.PullRequestNumber != 0 {
+		fmt.Fprintf(out, "%s %d\n", cs.Bold("Pull request:"), trace.PullRequestNumber)
+	}
+	if trace.TargetBranch != "" {
+		fmt.Fprintf(out, "%s %s\n", cs.Bold("Target branch:"), formatting.SanitizeBranch(trace.TargetBranch))
+	}
+	if trace.SourceBranch != "" {
+		fmt.Fprintf(out, "%s %s\n", cs.Bold("Source branch:"), formatting.SanitizeBranch(trace.SourceBranch))
+	}
+	if trace.Sender != "" {
+		fmt.Fprintf(out, "%s %s\n", cs.Bold("Sender:"), trace.Sender)
+	}
+
+	if len(trace.Access) > 0 {
+		fmt.Fprintf(out, "\n%s\n", cs.Bold("Access checks:"))
+		for _, access := range trace.Access {
+			icon := cs.SuccessIcon()
+			if !access.Allowed {
+				icon = cs.FailureIcon()
+			}
+			fmt.Fprintf(out, "%s %s: %s\n", icon, access.Check, access.Reason)
+		}
+	}
+
+	fmt.Fprintf(out, "\n%s\n", cs.Bold("PipelineRuns:"))
+	if len(trace.PipelineRuns) == 0 {
+		fmt.Fprintln(out, cs.Dimmed("no PipelineRun has been evaluated"))
+		return
+	}
+	for _, decision := range trace.PipelineRuns {
+		icon := cs.SuccessIcon()
+		if !decision.Matched {
+			icon = cs.FailureIcon()
+		}
+		reason := decision.Reason
+		if decision.Annotation != "" {
+			reason = fmt.Sprintf("%s: %s", strings.TrimPrefix(decision.Annotation, pipelinesascode.GroupName+"/"), reason)
+		}
+		fmt.Fprintf(out, "%s %s %s\n", icon, cs.Bold(decision.Name), reason)
+	}
+}
@@ -0,0 +1,127 @@
+package explain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/decisiontrace"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestExplain(t *testing.T) {
+	ns := "namespace"
+	repoName := "repo"
+	now := time.Now()
+
+	pullRequestTrace := &decisiontrace.Trace{
+		Repository:        repoName,
+		SHA:               "0123456789abcdef",
+		EventType:         "pull_request",
+		TriggerTarget:     "pull_request",
+		Sender:            "contributor",
+		TargetBranch:      "main",
+		SourceBranch:      "feature",
+		PullRequestNumber: 42,
+		Time:              now.Add(-time.Hour),
+		Access: []decisiontrace.AccessDecision{
+			{Check: decisiontrace.AccessCheckACL, Allowed: true, Reason: "user contributor is allowed to trigger CI via pull_request"},
+		},
+		PipelineRuns: []decisiontrace.PipelineRunDecision{
+			{Name: "pr-lint", Matched: true, Annotation: keys.OnEvent, Reason: "the annotations match: target-branch=[main], target-event=[pull_request]"},
+			{Name: "pr-docs", Annotation: keys.OnPathChange, Reason: "no changed file matches [docs/***]"},
+		},
+	}
+	pushTrace := &decisiontrace.Trace{
+		Repository:    repoName,
+		SHA:           "fedcba9876543210",
+		EventType:     "push",
+		TriggerTarget: "push",
+		TargetBranch:  "refs/heads/main",
+		Time:          now,
+		PipelineRuns: []decisiontrace.PipelineRunDecision{
+			{Name: "pr-lint", Annotation: keys.OnCelExpression, Reason: "the CEL expression evaluated to false"},
+		},
+	}
+	stored := []*decisiontrace.Trace{pullRequestTrace, pushTrace}
+
+	tests := []struct {
+		name      string
+		sha       string
+		stored    []*decisiontrace.Trace
+		wantError string
+	}{
+		{
+			name:   "latest trace",
+			stored: stored,
+		},
+		{
+			name:   "trace by sha",
+			sha:    "0123456789abcdef",
+			stored: stored,
+		},
+		{
+			name:   "trace by short sha",
+			sha:    "0123456",
+			stored: stored,
+		},
+		{
+			name:      "no trace for sha",
+			sha:       "notfound",
+			stored:    stored,
+			wantError: "cannot find a decision trace for the sha notfound in repository repo, only the latest 20 decision traces are kept",
+		},
+		{
+			name:      "no stored trace",
+			wantError: "cannot find the decision traces ConfigMap repo-decision-traces of repository repo, the controller must be allowed to store it in namespace namespace",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: repoName, Namespace: ns},
+				Spec:       v1alpha1.RepositorySpec{URL: "https://anurl.com"},
+			}
+			tdata := testclient.Data{
+				Namespaces: []*corev1.Namespace{
+					{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+				},
+				Repositories: []*v1alpha1.Repository{repo},
+			}
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			for _, trace := range tt.stored {
+				assert.NilError(t, decisiontrace.Store(ctx, stdata.Kube, repo, trace))
+			}
+			cs := &params.Run{
+				Clients: clients.Clients{
+					PipelineAsCode: stdata.PipelineAsCode,
+					Kube:           stdata.Kube,
+				},
+				Info: info.Info{Kube: &info.KubeOpts{Namespace: ns}},
+			}
+			io, out := tcli.NewIOStream()
+			opts := &explainOpts{PacCliOpts: *cli.NewCliOptions(), SHA: tt.sha}
+			err := explain(ctx, cs, opts, io, repoName)
+			if tt.wantError != "" {
+				assert.Error(t, err, tt.wantError)
+				return
+			}
+			assert.NilError(t, err)
+			golden.Assert(t, out.String(), strings.ReplaceAll(fmt.Sprintf("%s.golden", t.Name()), "/", "-"))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/juju/ansiterm"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	queuepkg "github.com/openshift-pipelines/pipelines-as-code/pkg/queue"
+	"github.com/spf13/cobra"
+)
+
+func listCommand(run *params.Run, opts *cli.PacCliOpts, ioStreams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list [repository]",
+		Aliases: []string{"ls"},
+		Short:   "List the running and queued PipelineRuns of a Repository",
+		Long: `List the running and queued PipelineRuns of a Repository with their position
+in the queue, their age and the concurrency limit of the queue.`,
+		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion("repositories", args)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			var repoName string
+			if len(args) > 0 {
+				repoName = args[0]
+			}
+			ctx := context.Background()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return list(ctx, run, opts, ioStreams, clockwork.NewRealClock(), repoName)
+		},
+	}
+	return cmd
+}
+
+func list(ctx context.Context, cs *params.Run, opts *cli.PacCliOpts, ioStreams *cli.IOStreams, clock clockwork.Clock, repoName string) error {
+	repo, err := getRepository(ctx, cs, opts, repoName)
+	if err != nil {
+		return err
+	}
+	queues, err := getQueues(ctx, cs, repo)
+	if err != nil {
+		return err
+	}
+	if len(queues) == 0 {
+		fmt.Fprintf(ioStreams.Out, "no running or queued PipelineRun in repository %s\n", repo.GetName())
+		return nil
+	}
+
+	colorScheme := ioStreams.ColorScheme()
+	w := ansiterm.NewTabWriter(ioStreams.Out, 0, 5, 3, ' ', tabwriter.TabIndent)
+	for i, q := range queues {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		limit := "unlimited"
+		if q.Limit != nil && *q.Limit > 0 {
+			limit = strconv.Itoa(*q.Limit)
+		}
+		fmt.Fprintf(w, "%s %s\t%s %s\n", colorScheme.Bold("Queue:"), q.Key, colorScheme.Bold("Limit:"), limit)
+		fmt.Fprintln(w, "POSITION\tNAME\tPRIORITY\tAGE")
+		for _, pr := range q.Running {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", colorScheme.Dimmed("running"), runName(repo, pr),
+				queuepkg.Priority(pr), formatting.Age(&pr.CreationTimestamp, clock))
+		}
+		for position, pr := range q.Pending {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", position+1, runName(repo, pr),
+				queuepkg.Priority(pr), formatting.Age(&pr.CreationTimestamp, clock))
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,77 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	queuepkg "github.com/openshift-pipelines/pipelines-as-code/pkg/queue"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func promoteCommand(run *params.Run, opts *cli.PacCliOpts, ioStreams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote repository pipelinerun",
+		Short: "Move a queued PipelineRun to the front of its queue",
+		Long: `Move a queued PipelineRun to the front of its queue.
+
+The priority annotation of the PipelineRun is raised above the priority of the
+other queued PipelineRuns, the watcher starts it as soon as the queue has room.`,
+		Args: cobra.ExactArgs(2),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion("repositories", args)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return promote(ctx, run, opts, ioStreams, args[0], args[1])
+		},
+	}
+	return cmd
+}
+
+func promote(ctx context.Context, cs *params.Run, opts *cli.PacCliOpts, ioStreams *cli.IOStreams, repoName, prName string) error {
+	repo, err := getRepository(ctx, cs, opts, repoName)
+	if err != nil {
+		return err
+	}
+	queues, err := getQueues(ctx, cs, repo)
+	if err != nil {
+		return err
+	}
+	q, position, err := findPending(queues, repo.GetNamespace(), prName)
+	if err != nil {
+		return err
+	}
+	if position == 0 {
+		return fmt.Errorf("pipelinerun %s is already at the front of the queue %s", prName, q.Key)
+	}
+
+	priority := queuepkg.Priority(q.Pending[0]) + 1
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{
+				keys.Priority: strconv.Itoa(priority),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := cs.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).Patch(ctx, prName,
+		types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(ioStreams.Out, "%s PipelineRun %s has been moved to the front of the queue %s\n",
+		ioStreams.ColorScheme().SuccessIcon(), prName, q.Key)
+	return nil
+}
@@ -0,0 +1,196 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	queuepkg "github.com/openshift-pipelines/pipelines-as-code/pkg/queue"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// runQueue is a queue of the Repository as seen by the watcher: the queue of
+// the Repository or of one of its concurrency groups.
+type runQueue struct {
+	Key     string
+	Limit   *int
+	Running []*tektonv1.PipelineRun
+	// Pending is sorted in the order the PipelineRuns are started.
+	Pending []*tektonv1.PipelineRun
+}
+
+func getRepository(ctx context.Context, cs *params.Run, opts *cli.PacCliOpts, repoName string) (*v1alpha1.Repository, error) {
+	if opts.Namespace != "" {
+		cs.Info.Kube.Namespace = opts.Namespace
+	}
+	if repoName == "" {
+		return prompt.SelectRepo(ctx, cs, cs.Info.Kube.Namespace)
+	}
+	return cs.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(cs.Info.Kube.Namespace).Get(ctx, repoName, metav1.GetOptions{})
+}
+
+// getGlobalRepository returns the global repository of the installation of
+// Pipelines-as-Code, nil when there is none.
+func getGlobalRepository(ctx context.Context, cs *params.Run) *v1alpha1.Repository {
+	installNamespace, _, err := params.GetInstallLocation(ctx, cs)
+	if err != nil {
+		return nil
+	}
+	repo, err := cs.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(installNamespace).Get(ctx,
+		info.GetControllerInfoFromEnvOrDefault().GlobalRepository, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return repo
+}
+
+// mergeGlobalRepository returns the Repository as seen by the watcher, with the
+// settings of the global repository merged in.
+func mergeGlobalRepository(repo, globalRepo *v1alpha1.Repository) *v1alpha1.Repository {
+	if globalRepo == nil || (globalRepo.GetNamespace() == repo.GetNamespace() && globalRepo.GetName() == repo.GetName()) {
+		return repo
+	}
+	repo = repo.DeepCopy()
+	repo.Spec.Merge(globalRepo.Spec)
+	return repo
+}
+
+func activeRunsSelector() string {
+	return fmt.Sprintf("%s in (%s,%s)", keys.State, kubeinteraction.StateStarted, kubeinteraction.StateQueued)
+}
+
+// getQueues returns the queues of the running and queued PipelineRuns of the
+// Repository sorted by key. The queues of the concurrency groups include the
+// PipelineRuns of the other Repositories sharing the group.
+func getQueues(ctx context.Context, cs *params.Run, repo *v1alpha1.Repository) ([]*runQueue, error) {
+	globalRepo := getGlobalRepository(ctx, cs)
+	repo = mergeGlobalRepository(repo, globalRepo)
+
+	prs, err := cs.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s", keys.Repository, formatting.CleanValueKubernetes(repo.GetName()), activeRunsSelector()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	queues := map[string]*runQueue{}
+	add := func(q *runQueue, pr *tektonv1.PipelineRun) {
+		if pr.Spec.Status == tektonv1.PipelineRunSpecStatusPending {
+			q.Pending = append(q.Pending, pr)
+		} else {
+			q.Running = append(q.Running, pr)
+		}
+	}
+
+	own := map[string]bool{}
+	// namespaces of the concurrency groups, the empty namespace for the
+	// cluster scoped groups.
+	groupNamespaces := map[string]bool{}
+	for i := range prs.Items {
+		pr := &prs.Items[i]
+		if pr.IsDone() || pr.IsCancelled() {
+			continue
+		}
+		own[queuepkg.PrKey(pr)] = true
+		key, limit := queuepkg.QueueKey(repo, pr)
+		q, ok := queues[key]
+		if !ok {
+			q = &runQueue{Key: key, Limit: limit}
+			queues[key] = q
+		}
+		add(q, pr)
+		if group := repo.Spec.GetConcurrencyGroup(pr.GetAnnotations()[keys.ConcurrencyGroup]); group != nil {
+			if group.Scope == v1alpha1.ConcurrencyGroupScopeCluster {
+				groupNamespaces[metav1.NamespaceAll] = true
+			} else {
+				groupNamespaces[repo.GetNamespace()] = true
+			}
+		}
+	}
+
+	if groupNamespaces[metav1.NamespaceAll] {
+		groupNamespaces = map[string]bool{metav1.NamespaceAll: true}
+	}
+	repos := map[string]*v1alpha1.Repository{}
+	for _, namespace := range slices.Sorted(maps.Keys(groupNamespaces)) {
+		groupPrs, err := cs.Clients.Tekton.TektonV1().PipelineRuns(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: activeRunsSelector(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range groupPrs.Items {
+			pr := &groupPrs.Items[i]
+			if own[queuepkg.PrKey(pr)] || pr.IsDone() || pr.IsCancelled() {
+				continue
+			}
+			repoName := pr.GetAnnotations()[keys.Repository]
+			if repoName == "" {
+				continue
+			}
+			repoKey := fmt.Sprintf("%s/%s", pr.GetNamespace(), repoName)
+			prRepo, ok := repos[repoKey]
+			if !ok {
+				if r, err := cs.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(pr.GetNamespace()).Get(ctx, repoName, metav1.GetOptions{}); err == nil {
+					prRepo = mergeGlobalRepository(r, globalRepo)
+				}
+				repos[repoKey] = prRepo
+			}
+			if prRepo == nil {
+				continue
+			}
+			key, _ := queuepkg.QueueKey(prRepo, pr)
+			if q, ok := queues[key]; ok {
+				add(q, pr)
+			}
+		}
+	}
+
+	sorted := []*runQueue{}
+	for _, key := range slices.Sorted(maps.Keys(queues)) {
+		q := queues[key]
+		queuepkg.SortPending(q.Pending)
+		slices.SortStableFunc(q.Running, func(a, b *tektonv1.PipelineRun) int {
+			return a.GetCreationTimestamp().Compare(b.GetCreationTimestamp().Time)
+		})
+		sorted = append(sorted, q)
+	}
+	return sorted, nil
+}
+
+// runName returns the name of the PipelineRun, prefixed by its namespace when
+// it is not in the namespace of the Repository.
+func runName(repo *v1alpha1.Repository, pr *tektonv1.PipelineRun) string {
+	if pr.GetNamespace() != repo.GetNamespace() {
+		return queuepkg.PrKey(pr)
+	}
+	return pr.GetName()
+}
+
+// findPending returns the queue and the position of the queued PipelineRun
+// name of the namespace.
+func findPending(queues []*runQueue, namespace, name string) (*runQueue, int, error) {
+	for _, q := range queues {
+		for i, pr := range q.Pending {
+			if pr.GetNamespace() == namespace && pr.GetName() == name {
+				return q, i, nil
+			}
+		}
+		for _, pr := range q.Running {
+			if pr.GetNamespace() == namespace && pr.GetName() == name {
+				return nil, 0, fmt.Errorf("pipelinerun %s is already running", name)
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("cannot find the queued pipelinerun %s", name)
+}
@@ -0,0 +1,288 @@
+package queue
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+const (
+	ns        = "namespace"
+	otherNs   = "other-namespace"
+	installNs = "pipelines-as-code"
+	repoName  = "repo"
+)
+
+func newPR(name, state string, pending bool, age time.Duration, annotations map[string]string, now time.Time) *tektonv1.PipelineRun {
+	return newRepoPR(ns, repoName, name, state, pending, age, annotations, now)
+}
+
+func newRepoPR(namespace, repository, name, state string, pending bool, age time.Duration, annotations map[string]string, now time.Time) *tektonv1.PipelineRun {
+	prAnnotations := map[string]string{keys.Repository: repository}
+	maps.Copy(prAnnotations, annotations)
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.Time{Time: now.Add(-age)},
+			Labels: map[string]string{
+				keys.Repository: formatting.CleanValueKubernetes(repository),
+				keys.State:      state,
+			},
+			Annotations: prAnnotations,
+		},
+	}
+	if pending {
+		pr.Spec.Status = tektonv1.PipelineRunSpecStatusPending
+	}
+	return pr
+}
+
+func seed(t *testing.T, now time.Time) (*params.Run, testclient.Clients) {
+	t.Helper()
+	limit := 1
+	tdata := testclient.Data{
+		Namespaces: []*corev1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+			{ObjectMeta: metav1.ObjectMeta{Name: otherNs}},
+			{ObjectMeta: metav1.ObjectMeta{Name: installNs}},
+		},
+		Deployments: []*appsv1.Deployment{
+			{ObjectMeta: metav1.ObjectMeta{Name: "pipelines-as-code-controller", Namespace: installNs}},
+		},
+		Repositories: []*v1alpha1.Repository{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: repoName, Namespace: ns},
+				Spec: v1alpha1.RepositorySpec{
+					URL:               "https://anurl.com",
+					ConcurrencyLimit:  &limit,
+					ConcurrencyGroups: []v1alpha1.ConcurrencyGroup{{Name: "deploy", Limit: 2}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: ns},
+				Spec: v1alpha1.RepositorySpec{
+					URL:               "https://anurl.com/other",
+					ConcurrencyGroups: []v1alpha1.ConcurrencyGroup{{Name: "deploy", Limit: 2}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: otherNs},
+				Spec: v1alpha1.RepositorySpec{
+					URL:               "https://anurl.com/other",
+					ConcurrencyGroups: []v1alpha1.ConcurrencyGroup{{Name: "deploy", Limit: 2}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: info.DefaultGlobalRepoName, Namespace: installNs},
+				Spec: v1alpha1.RepositorySpec{
+					ConcurrencyGroups: []v1alpha1.ConcurrencyGroup{
+						{Name: "release", Limit: 1, Scope: v1alpha1.ConcurrencyGroupScopeCluster},
+					},
+				},
+			},
+		},
+		PipelineRuns: []*tektonv1.PipelineRun{
+			newPR("running", kubeinteraction.StateStarted, false, 10*time.Minute, nil, now),
+			newPR("queued-old", kubeinteraction.StateQueued, true, 8*time.Minute, nil, now),
+			newPR("queued-new", kubeinteraction.StateQueued, true, 5*time.Minute, nil, now),
+			newPR("queued-urgent", kubeinteraction.StateQueued, true, 2*time.Minute,
+				map[string]string{keys.Priority: "10"}, now),
+			newPR("deploy", kubeinteraction.StateQueued, true, time.Minute,
+				map[string]string{keys.ConcurrencyGroup: "deploy"}, now),
+			newPR("release", kubeinteraction.StateQueued, true, time.Minute,
+				map[string]string{keys.ConcurrencyGroup: "release"}, now),
+			newPR("completed", kubeinteraction.StateCompleted, false, time.Hour, nil, now),
+			// the group deploy of the namespace is shared with the other
+			// Repository of the namespace, not with the one of otherNs.
+			newRepoPR(ns, "other", "other-deploy", kubeinteraction.StateQueued, true, 3*time.Minute,
+				map[string]string{keys.ConcurrencyGroup: "deploy", keys.Priority: "5"}, now),
+			newRepoPR(otherNs, "other", "other-ns-deploy", kubeinteraction.StateQueued, true, 3*time.Minute,
+				map[string]string{keys.ConcurrencyGroup: "deploy", keys.Priority: "7"}, now),
+			newRepoPR(otherNs, "other", "other-ns-release", kubeinteraction.StateStarted, false, 3*time.Minute,
+				map[string]string{keys.ConcurrencyGroup: "release"}, now),
+		},
+	}
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+	cs := &params.Run{
+		Clients: clients.Clients{
+			PipelineAsCode: stdata.PipelineAsCode,
+			Tekton:         stdata.Pipeline,
+			Kube:           stdata.Kube,
+		},
+		Info: info.Info{Kube: &info.KubeOpts{Namespace: ns}},
+	}
+	return cs, stdata
+}
+
+func TestList(t *testing.T) {
+	now := time.Now()
+	cs, _ := seed(t, now)
+	ctx, _ := rtesting.SetupFakeContext(t)
+	io, out := tcli.NewIOStream()
+	err := list(ctx, cs, cli.NewCliOptions(), io, clockwork.NewFakeClockAt(now), repoName)
+	assert.NilError(t, err)
+	golden.Assert(t, out.String(), strings.ReplaceAll(fmt.Sprintf("%s.golden", t.Name()), "/", "-"))
+}
+
+func TestPromote(t *testing.T) {
+	tests := []struct {
+		name         string
+		pipelinerun  string
+		wantError    string
+		wantQueue    string
+		wantPriority string
+	}{
+		{
+			name:         "promote queued pipelinerun",
+			pipelinerun:  "queued-new",
+			wantQueue:    "namespace/repo",
+			wantPriority: "11",
+		},
+		{
+			name:         "promote above the pipelinerun of another repository",
+			pipelinerun:  "deploy",
+			wantQueue:    "group/namespace/namespace/deploy",
+			wantPriority: "6",
+		},
+		{
+			name:        "already at the front",
+			pipelinerun: "queued-urgent",
+			wantError:   "pipelinerun queued-urgent is already at the front of the queue namespace/repo",
+		},
+		{
+			name:        "running pipelinerun",
+			pipelinerun: "running",
+			wantError:   "pipelinerun running is already running",
+		},
+		{
+			name:        "unknown pipelinerun",
+			pipelinerun: "completed",
+			wantError:   "cannot find the queued pipelinerun completed",
+		},
+		{
+			name:        "pipelinerun of another namespace",
+			pipelinerun: "other-ns-deploy",
+			wantError:   "cannot find the queued pipelinerun other-ns-deploy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			cs, stdata := seed(t, now)
+			ctx, _ := rtesting.SetupFakeContext(t)
+			io, out := tcli.NewIOStream()
+			err := promote(ctx, cs, cli.NewCliOptions(), io, repoName, tt.pipelinerun)
+			if tt.wantError != "" {
+				assert.Error(t, err, tt.wantError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(out.String(), "has been moved to the front of the queue "+tt.wantQueue))
+
+			pr, err := stdata.Pipeline.TektonV1().PipelineRuns(ns).Get(ctx, tt.pipelinerun, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, pr.GetAnnotations()[keys.Priority], tt.wantPriority)
+
+			repo, err := getRepository(ctx, cs, cli.NewCliOptions(), repoName)
+			assert.NilError(t, err)
+			queues, err := getQueues(ctx, cs, repo)
+			assert.NilError(t, err)
+			q, position, err := findPending(queues, ns, tt.pipelinerun)
+			assert.NilError(t, err)
+			assert.Equal(t, q.Key, tt.wantQueue)
+			assert.Equal(t, position, 0)
+		})
+	}
+}
+
+func TestGetQueuesLongRepositoryName(t *testing.T) {
+	now := time.Now()
+	longName := strings.Repeat("a", 70)
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Namespaces: []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: ns}}},
+		Repositories: []*v1alpha1.Repository{
+			{ObjectMeta: metav1.ObjectMeta{Name: longName, Namespace: ns}, Spec: v1alpha1.RepositorySpec{URL: "https://anurl.com"}},
+		},
+		PipelineRuns: []*tektonv1.PipelineRun{
+			newRepoPR(ns, longName, "queued", kubeinteraction.StateQueued, true, time.Minute, nil, now),
+		},
+	})
+	cs := &params.Run{
+		Clients: clients.Clients{
+			PipelineAsCode: stdata.PipelineAsCode,
+			Tekton:         stdata.Pipeline,
+			Kube:           stdata.Kube,
+		},
+		Info: info.Info{Kube: &info.KubeOpts{Namespace: ns}},
+	}
+	repo, err := getRepository(ctx, cs, cli.NewCliOptions(), longName)
+	assert.NilError(t, err)
+	queues, err := getQueues(ctx, cs, repo)
+	assert.NilError(t, err)
+	assert.Equal(t, len(queues), 1)
+	assert.Equal(t, queues[0].Pending[0].GetName(), "queued")
+}
+
+func TestRemove(t *testing.T) {
+	tests := []struct {
+		name        string
+		pipelinerun string
+		wantError   string
+	}{
+		{
+			name:        "remove queued pipelinerun",
+			pipelinerun: "queued-old",
+		},
+		{
+			name:        "remove from a concurrency group",
+			pipelinerun: "deploy",
+		},
+		{
+			name:        "running pipelinerun",
+			pipelinerun: "running",
+			wantError:   "pipelinerun running is already running",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			cs, stdata := seed(t, now)
+			ctx, _ := rtesting.SetupFakeContext(t)
+			io, _ := tcli.NewIOStream()
+			err := remove(ctx, cs, cli.NewCliOptions(), io, repoName, tt.pipelinerun)
+			if tt.wantError != "" {
+				assert.Error(t, err, tt.wantError)
+				return
+			}
+			assert.NilError(t, err)
+
+			pr, err := stdata.Pipeline.TektonV1().PipelineRuns(ns).Get(ctx, tt.pipelinerun, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, pr.Spec.Status, tektonv1.PipelineRunSpecStatus(tektonv1.PipelineRunSpecStatusCancelled))
+		})
+	}
+}
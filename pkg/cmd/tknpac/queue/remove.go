@@ -0,0 +1,75 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func removeCommand(run *params.Run, opts *cli.PacCliOpts, ioStreams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove repository pipelinerun",
+		Aliases: []string{"rm"},
+		Short:   "Remove a queued PipelineRun from its queue",
+		Long: `Remove a queued PipelineRun from its queue.
+
+The PipelineRun is cancelled before it has been started, the watcher reports
+it as cancelled and removes it from the queue.`,
+		Args: cobra.ExactArgs(2),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion("repositories", args)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return remove(ctx, run, opts, ioStreams, args[0], args[1])
+		},
+	}
+	return cmd
+}
+
+// cancelPendingPatch cancels a PipelineRun only if it has not been started
+// in the meantime.
+func cancelPendingPatch() ([]byte, error) {
+	return json.Marshal([]map[string]any{
+		{"op": "test", "path": "/spec/status", "value": tektonv1.PipelineRunSpecStatusPending},
+		{"op": "replace", "path": "/spec/status", "value": tektonv1.PipelineRunSpecStatusCancelled},
+	})
+}
+
+func remove(ctx context.Context, cs *params.Run, opts *cli.PacCliOpts, ioStreams *cli.IOStreams, repoName, prName string) error {
+	repo, err := getRepository(ctx, cs, opts, repoName)
+	if err != nil {
+		return err
+	}
+	queues, err := getQueues(ctx, cs, repo)
+	if err != nil {
+		return err
+	}
+	q, _, err := findPending(queues, repo.GetNamespace(), prName)
+	if err != nil {
+		return err
+	}
+
+	patch, err := cancelPendingPatch()
+	if err != nil {
+		return err
+	}
+	if _, err := cs.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).Patch(ctx, prName,
+		types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("cannot remove pipelinerun %s from the queue %s: %w", prName, q.Key, err)
+	}
+	fmt.Fprintf(ioStreams.Out, "%s PipelineRun %s has been removed from the queue %s\n",
+		ioStreams.ColorScheme().SuccessIcon(), prName, q.Key)
+	return nil
+}
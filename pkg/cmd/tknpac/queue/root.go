@@ -0,0 +1,35 @@
+package queue
+
+import (
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+)
+
+const namespaceFlag = "namespace"
+
+func Root(clients *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := cli.NewCliOptions()
+	cmd := &cobra.Command{
+		Use:          "queue",
+		Short:        "Inspect and manage the queued PipelineRuns of a Repository",
+		Long:         `Inspect and manage the running and queued PipelineRuns of a Repository with a concurrency limit`,
+		SilenceUsage: true,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&opts.Namespace, namespaceFlag, "n", "", "If present, the namespace scope for this CLI request")
+	_ = cmd.RegisterFlagCompletionFunc(namespaceFlag,
+		func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion(namespaceFlag, args)
+		},
+	)
+
+	cmd.AddCommand(listCommand(clients, opts, ioStreams))
+	cmd.AddCommand(promoteCommand(clients, opts, ioStreams))
+	cmd.AddCommand(removeCommand(clients, opts, ioStreams))
+	return cmd
+}
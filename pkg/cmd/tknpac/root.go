@@ -8,11 +8,14 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/create"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/deleterepo"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/describe"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/explain"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/generate"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/info"
 	list "github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/listcmd"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/logs"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/queue"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/resolve"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/stats"
 	versioncmd "github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/versioncmd"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/webhook"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -39,8 +42,11 @@ func Root(clients *params.Run) *cobra.Command {
 	cmd.AddCommand(list.Root(clients, ioStreams))
 	cmd.AddCommand(deleterepo.Root(clients, ioStreams))
 	cmd.AddCommand(describe.Root(clients, ioStreams))
+	cmd.AddCommand(explain.Command(clients, ioStreams))
 	cmd.AddCommand(logs.Command(clients, ioStreams))
+	cmd.AddCommand(queue.Root(clients, ioStreams))
 	cmd.AddCommand(resolve.Command(clients, ioStreams))
+	cmd.AddCommand(stats.Command(clients, ioStreams))
 	cmd.AddCommand(completion.Command())
 	cmd.AddCommand(bootstrap.Command(clients, ioStreams))
 	cmd.AddCommand(generate.Command(clients, ioStreams))
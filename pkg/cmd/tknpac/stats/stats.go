@@ -0,0 +1,130 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/juju/ansiterm"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/flaky"
+	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const namespaceFlag = "namespace"
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := cli.NewCliOptions()
+	cmd := &cobra.Command{
+		Use:   "stats [repository]",
+		Short: "Show the flaky and failing tasks of a Repository",
+		Long: `Show the failure rate and the flake rate of the tasks of the PipelineRuns of a
+Repository.
+
+The flake rate of a task is the ratio of the commits the task both failed and
+succeeded on. The statistics are computed from the PipelineRuns of the
+Repository on the cluster and from the runs recorded in the Repository status.`,
+		Args: cobra.MaximumNArgs(1),
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion("repositories", args)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			var repoName string
+			if len(args) > 0 {
+				repoName = args[0]
+			}
+			ctx := context.Background()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return stats(ctx, run, opts, ioStreams, repoName)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Namespace, namespaceFlag, "n", "", "If present, the namespace scope for this CLI request")
+	_ = cmd.RegisterFlagCompletionFunc(namespaceFlag,
+		func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion(namespaceFlag, args)
+		},
+	)
+	return cmd
+}
+
+// getRuns returns the completed runs of the Repository from its PipelineRuns
+// and, for the PipelineRuns which have been deleted, from its status.
+func getRuns(ctx context.Context, cs *params.Run, repo *v1alpha1.Repository) ([]flaky.Run, error) {
+	prs, err := cs.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", keys.Repository, repo.GetName()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []flaky.Run{}
+	live := map[string]bool{}
+	for i := range prs.Items {
+		pr := &prs.Items[i]
+		live[pr.GetName()] = true
+		if run, ok := flaky.NewRun(pr, kstatus.GetStatusFromTaskStatusOrFromAsking(ctx, pr, cs)); ok {
+			runs = append(runs, run)
+		}
+	}
+	for _, status := range repo.Status {
+		if live[status.PipelineRunName] {
+			continue
+		}
+		if run, ok := flaky.NewRunFromStatus(status); ok {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func stats(ctx context.Context, cs *params.Run, opts *cli.PacCliOpts, ioStreams *cli.IOStreams, repoName string) error {
+	if opts.Namespace != "" {
+		cs.Info.Kube.Namespace = opts.Namespace
+	}
+	var repo *v1alpha1.Repository
+	var err error
+	if repoName == "" {
+		repo, err = prompt.SelectRepo(ctx, cs, cs.Info.Kube.Namespace)
+	} else {
+		repo, err = cs.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(cs.Info.Kube.Namespace).Get(ctx, repoName, metav1.GetOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	runs, err := getRuns(ctx, cs, repo)
+	if err != nil {
+		return err
+	}
+	taskStats := flaky.Analyze(runs)
+	if len(taskStats) == 0 {
+		fmt.Fprintf(ioStreams.Out, "no completed PipelineRun in repository %s\n", repo.GetName())
+		return nil
+	}
+
+	colorScheme := ioStreams.ColorScheme()
+	w := ansiterm.NewTabWriter(ioStreams.Out, 0, 5, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "PIPELINE\tTASK\tRUNS\tFAILURES\tFAILURE-RATE\tCOMMITS\tFLAKY-COMMITS\tFLAKE-RATE")
+	for _, s := range taskStats {
+		flakeRate := fmt.Sprintf("%.0f%%", s.FlakeRate()*100)
+		if s.FlakyCommits > 0 {
+			flakeRate = colorScheme.Yellow(flakeRate)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.0f%%\t%d\t%d\t%s\n", s.Pipeline, s.Task, s.Runs, s.Failures,
+			s.FailureRate()*100, s.Commits, s.FlakyCommits, flakeRate)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,133 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+const (
+	ns       = "namespace"
+	repoName = "repo"
+)
+
+func succeeded(status corev1.ConditionStatus) duckv1.Status {
+	return duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status}}}
+}
+
+func newPR(name, sha string, status corev1.ConditionStatus, tasks map[string]corev1.ConditionStatus) (*tektonv1.PipelineRun, []*tektonv1.TaskRun) {
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels:    map[string]string{keys.Repository: repoName},
+			Annotations: map[string]string{
+				keys.OriginalPRName: "pull-request",
+				keys.SHA:            sha,
+			},
+		},
+		Status: tektonv1.PipelineRunStatus{Status: succeeded(status)},
+	}
+	taskruns := []*tektonv1.TaskRun{}
+	for task, taskStatus := range tasks {
+		trName := fmt.Sprintf("%s-%s", name, task)
+		pr.Status.ChildReferences = append(pr.Status.ChildReferences, tektonv1.ChildStatusReference{
+			TypeMeta:         runtime.TypeMeta{Kind: "TaskRun"},
+			Name:             trName,
+			PipelineTaskName: task,
+		})
+		taskruns = append(taskruns, &tektonv1.TaskRun{
+			ObjectMeta: metav1.ObjectMeta{Name: trName, Namespace: ns},
+			Status:     tektonv1.TaskRunStatus{Status: succeeded(taskStatus)},
+		})
+	}
+	return pr, taskruns
+}
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		withRuns bool
+	}{
+		{
+			name:     "flaky tasks",
+			withRuns: true,
+		},
+		{
+			name: "no runs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sha := "sha2"
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: repoName, Namespace: ns},
+				Spec:       v1alpha1.RepositorySpec{URL: "https://anurl.com"},
+			}
+			tdata := testclient.Data{
+				Namespaces:   []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: ns}}},
+				Repositories: []*v1alpha1.Repository{repo},
+			}
+			if tt.withRuns {
+				for _, run := range []struct {
+					name, sha string
+					status    corev1.ConditionStatus
+					tasks     map[string]corev1.ConditionStatus
+				}{
+					{"pull-request-aaaaa", "sha1", corev1.ConditionFalse, map[string]corev1.ConditionStatus{"lint": corev1.ConditionTrue, "test": corev1.ConditionFalse}},
+					{"pull-request-bbbbb", "sha1", corev1.ConditionTrue, map[string]corev1.ConditionStatus{"lint": corev1.ConditionTrue, "test": corev1.ConditionTrue}},
+					{"pull-request-ccccc", "sha3", corev1.ConditionUnknown, map[string]corev1.ConditionStatus{"lint": corev1.ConditionUnknown}},
+				} {
+					pr, taskruns := newPR(run.name, run.sha, run.status, run.tasks)
+					tdata.PipelineRuns = append(tdata.PipelineRuns, pr)
+					tdata.TaskRuns = append(tdata.TaskRuns, taskruns...)
+				}
+				// a run whose PipelineRun has been deleted
+				repo.Status = []v1alpha1.RepositoryRunStatus{
+					{
+						Status: duckv1.Status{
+							Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: "Failed"}},
+						},
+						PipelineRunName:    "pull-request-ddddd",
+						SHA:                &sha,
+						CollectedTaskInfos: &map[string]v1alpha1.TaskInfos{"lint": {Name: "pull-request-ddddd-lint"}},
+					},
+				}
+			}
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			cs := &params.Run{
+				Clients: clients.Clients{
+					PipelineAsCode: stdata.PipelineAsCode,
+					Tekton:         stdata.Pipeline,
+					Kube:           stdata.Kube,
+					Log:            zap.NewNop().Sugar(),
+				},
+				Info: info.Info{Kube: &info.KubeOpts{Namespace: ns}},
+			}
+			io, out := tcli.NewIOStream()
+			err := stats(ctx, cs, cli.NewCliOptions(), io, repoName)
+			assert.NilError(t, err)
+			golden.Assert(t, out.String(), strings.ReplaceAll(fmt.Sprintf("%s.golden", t.Name()), "/", "-"))
+		})
+	}
+}
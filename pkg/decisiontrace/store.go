@@ -0,0 +1,142 @@
+package decisiontrace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	// MaxStoredTraces is the number of decision traces kept for a Repository,
+	// the oldest ones are dropped first.
+	MaxStoredTraces = 20
+
+	// tracesKey is the key of the ConfigMap data holding the traces.
+	tracesKey = "traces"
+	// maxStoredSize keeps the ConfigMap well below the 1MiB limit of the
+	// Kubernetes objects.
+	maxStoredSize = 512 * 1024
+)
+
+// ConfigMapName returns the name of the ConfigMap holding the decision traces
+// of the Repository.
+func ConfigMapName(repo *v1alpha1.Repository) string {
+	return kmeta.ChildName(repo.GetName(), "-decision-traces")
+}
+
+// Store adds the trace to the ConfigMap of the decision traces of the
+// Repository, keeping only the MaxStoredTraces latest ones. The ConfigMap is
+// owned by the Repository and deleted with it.
+func Store(ctx context.Context, kube kubernetes.Interface, repo *v1alpha1.Repository, trace *Trace) error {
+	configMaps := kube.CoreV1().ConfigMaps(repo.GetNamespace())
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, ConfigMapName(repo), metav1.GetOptions{})
+		found := err == nil
+		if errors.IsNotFound(err) {
+			cm = newConfigMap(repo)
+		} else if err != nil {
+			return err
+		}
+
+		traces, err := decodeTraces(cm)
+		if err != nil {
+			// a corrupted ConfigMap should not prevent recording the new traces
+			traces = nil
+		}
+		traces = append(traces, trace)
+		data, err := encodeTraces(traces)
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[tracesKey] = data
+
+		if !found {
+			_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+			if errors.IsAlreadyExists(err) {
+				// created concurrently, retry on the existing one
+				return errors.NewConflict(corev1.Resource("configmaps"), cm.GetName(), err)
+			}
+			return err
+		}
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// List returns the decision traces stored for the Repository, sorted from the
+// oldest to the newest. It returns a not found error when no trace has been
+// stored.
+func List(ctx context.Context, kube kubernetes.Interface, repo *v1alpha1.Repository) ([]*Trace, error) {
+	cm, err := kube.CoreV1().ConfigMaps(repo.GetNamespace()).Get(ctx, ConfigMapName(repo), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return decodeTraces(cm)
+}
+
+func newConfigMap(repo *v1alpha1.Repository) *corev1.ConfigMap {
+	controllerOwned := false
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName(repo),
+			Namespace: repo.GetNamespace(),
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": pipelinesascode.GroupName,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         v1alpha1.SchemeGroupVersion.String(),
+				Kind:               pipelinesascode.RepositoryKind,
+				Name:               repo.GetName(),
+				UID:                repo.GetUID(),
+				BlockOwnerDeletion: &controllerOwned,
+				Controller:         &controllerOwned,
+			}},
+		},
+	}
+}
+
+func decodeTraces(cm *corev1.ConfigMap) ([]*Trace, error) {
+	data, ok := cm.Data[tracesKey]
+	if !ok || data == "" {
+		return []*Trace{}, nil
+	}
+	traces := []*Trace{}
+	if err := json.Unmarshal([]byte(data), &traces); err != nil {
+		return nil, fmt.Errorf("cannot decode the decision traces of configmap %s/%s: %w", cm.GetNamespace(), cm.GetName(), err)
+	}
+	return traces, nil
+}
+
+// encodeTraces encodes the latest traces, dropping the oldest ones above
+// MaxStoredTraces or when the encoding gets too large.
+func encodeTraces(traces []*Trace) (string, error) {
+	sort.SliceStable(traces, func(i, j int) bool {
+		return traces[i].Time.Before(traces[j].Time)
+	})
+	if len(traces) > MaxStoredTraces {
+		traces = traces[len(traces)-MaxStoredTraces:]
+	}
+	for {
+		b, err := json.Marshal(traces)
+		if err != nil {
+			return "", err
+		}
+		if len(b) <= maxStoredSize || len(traces) == 1 {
+			return string(b), nil
+		}
+		traces = traces[1:]
+	}
+}
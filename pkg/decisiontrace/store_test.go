@@ -0,0 +1,79 @@
+package decisiontrace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns", UID: "uid"}}
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		existing *corev1.ConfigMap
+		stored   int
+		wantSHAs []string
+	}{
+		{
+			name:     "first trace",
+			stored:   1,
+			wantSHAs: []string{"sha0"},
+		},
+		{
+			name:     "keeps the latest traces",
+			stored:   MaxStoredTraces + 2,
+			wantSHAs: []string{"sha2", "sha3"},
+		},
+		{
+			name: "replaces corrupted traces",
+			existing: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName(repo), Namespace: "ns"},
+				Data:       map[string]string{tracesKey: "not json"},
+			},
+			stored:   1,
+			wantSHAs: []string{"sha0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kube := fake.NewSimpleClientset()
+			if tt.existing != nil {
+				_, err := kube.CoreV1().ConfigMaps("ns").Create(ctx, tt.existing, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+
+			_, err := List(ctx, kube, repo)
+			assert.Assert(t, tt.existing != nil || errors.IsNotFound(err))
+
+			for i := range tt.stored {
+				trace := &Trace{Repository: repo.GetName(), SHA: fmt.Sprintf("sha%d", i), Time: now.Add(time.Duration(i) * time.Second)}
+				assert.NilError(t, Store(ctx, kube, repo, trace))
+			}
+
+			traces, err := List(ctx, kube, repo)
+			assert.NilError(t, err)
+			assert.Equal(t, len(traces), min(tt.stored, MaxStoredTraces))
+			for i, sha := range tt.wantSHAs {
+				assert.Equal(t, traces[i].SHA, sha)
+			}
+
+			if tt.existing == nil {
+				cm, err := kube.CoreV1().ConfigMaps("ns").Get(ctx, ConfigMapName(repo), metav1.GetOptions{})
+				assert.NilError(t, err)
+				assert.Equal(t, len(cm.OwnerReferences), 1)
+				assert.Equal(t, cm.OwnerReferences[0].UID, repo.GetUID())
+			}
+		})
+	}
+}
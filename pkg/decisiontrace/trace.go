@@ -0,0 +1,168 @@
+package decisiontrace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+const (
+	// Reason is the reason of the Kubernetes event summarizing a decision
+	// trace.
+	Reason = "RepositoryDecisionTrace"
+
+	// AccessCheckPolicy is the access check of the policy settings of a Repository.
+	AccessCheckPolicy = "policy"
+	// AccessCheckACL is the access check of the sender of the event.
+	AccessCheckACL = "acl"
+)
+
+type contextKey struct{}
+
+// Trace records why the PipelineRuns of the .tekton directory did or did not
+// match an event. All the methods can be called on a nil Trace, which is what
+// FromContext returns when no trace is recorded.
+type Trace struct {
+	Repository        string                `json:"repository"`
+	SHA               string                `json:"sha"`
+	EventType         string                `json:"event_type"`
+	TriggerTarget     string                `json:"trigger_target"`
+	Sender            string                `json:"sender,omitempty"`
+	TargetBranch      string                `json:"target_branch,omitempty"`
+	SourceBranch      string                `json:"source_branch,omitempty"`
+	PullRequestNumber int                   `json:"pull_request_number,omitempty"`
+	Time              time.Time             `json:"time"`
+	Access            []AccessDecision      `json:"access,omitempty"`
+	PipelineRuns      []PipelineRunDecision `json:"pipelineruns,omitempty"`
+
+	repo *v1alpha1.Repository
+}
+
+// AccessDecision is the outcome of a policy or ACL check.
+type AccessDecision struct {
+	Check   string `json:"check"`
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// PipelineRunDecision is the outcome of the matching of a PipelineRun, with
+// the annotation or CEL expression which decided of it.
+type PipelineRunDecision struct {
+	Name       string `json:"name"`
+	Matched    bool   `json:"matched"`
+	Annotation string `json:"annotation,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+// New returns an empty trace started now.
+func New() *Trace {
+	return &Trace{Time: time.Now()}
+}
+
+// NewContext returns a copy of ctx carrying the trace.
+func NewContext(ctx context.Context, t *Trace) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the trace carried by ctx or nil.
+func FromContext(ctx context.Context) *Trace {
+	t, _ := ctx.Value(contextKey{}).(*Trace)
+	return t
+}
+
+// SetRepository records the Repository matching the event.
+func (t *Trace) SetRepository(repo *v1alpha1.Repository) {
+	if t == nil || repo == nil {
+		return
+	}
+	t.repo = repo
+	t.Repository = repo.GetName()
+}
+
+// GetRepository returns the Repository matching the event or nil when there
+// is none.
+func (t *Trace) GetRepository() *v1alpha1.Repository {
+	if t == nil {
+		return nil
+	}
+	return t.repo
+}
+
+// SetEvent records the event the PipelineRuns have been matched against.
+func (t *Trace) SetEvent(event *info.Event) {
+	if t == nil || event == nil {
+		return
+	}
+	t.SHA = event.SHA
+	t.EventType = event.EventType
+	t.TriggerTarget = event.TriggerTarget.String()
+	t.Sender = event.Sender
+	t.TargetBranch = event.BaseBranch
+	t.SourceBranch = event.HeadBranch
+	t.PullRequestNumber = event.PullRequestNumber
+}
+
+// ResetPipelineRuns forgets the previous matching decisions, the PipelineRuns
+// are matched a few times while being resolved and only the last matching is
+// relevant.
+func (t *Trace) ResetPipelineRuns() {
+	if t == nil {
+		return
+	}
+	t.PipelineRuns = nil
+}
+
+// Matched records that the PipelineRun name has been matched.
+func (t *Trace) Matched(name, annotation, reason string) {
+	t.record(PipelineRunDecision{Name: name, Matched: true, Annotation: annotation, Reason: reason})
+}
+
+// Rejected records that the PipelineRun name has not been matched.
+func (t *Trace) Rejected(name, annotation, reason string) {
+	t.record(PipelineRunDecision{Name: name, Annotation: annotation, Reason: reason})
+}
+
+func (t *Trace) record(decision PipelineRunDecision) {
+	if t == nil {
+		return
+	}
+	for i := range t.PipelineRuns {
+		if t.PipelineRuns[i].Name == decision.Name {
+			t.PipelineRuns[i] = decision
+			return
+		}
+	}
+	t.PipelineRuns = append(t.PipelineRuns, decision)
+}
+
+// AccessChecked records the outcome of an access check.
+func (t *Trace) AccessChecked(check string, allowed bool, reason string) {
+	if t == nil {
+		return
+	}
+	t.Access = append(t.Access, AccessDecision{Check: check, Allowed: allowed, Reason: reason})
+}
+
+// Summary returns a one line summary of the trace.
+func (t *Trace) Summary() string {
+	if t == nil {
+		return ""
+	}
+	matched := 0
+	for _, decision := range t.PipelineRuns {
+		if decision.Matched {
+			matched++
+		}
+	}
+	summary := fmt.Sprintf("decision trace for %s event on sha %s: %d/%d PipelineRun(s) matched", t.EventType, t.SHA, matched, len(t.PipelineRuns))
+	for _, access := range t.Access {
+		if !access.Allowed {
+			summary += fmt.Sprintf(", denied by %s check", access.Check)
+		}
+	}
+	return summary
+}
+
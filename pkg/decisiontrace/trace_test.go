@@ -0,0 +1,92 @@
+package decisiontrace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNilTrace(t *testing.T) {
+	trace := FromContext(context.Background())
+	assert.Assert(t, trace == nil)
+	// none of those should panic
+	trace.ResetPipelineRuns()
+	trace.Matched("pr", "", "reason")
+	trace.Rejected("pr", "", "reason")
+	trace.AccessChecked(AccessCheckACL, false, "reason")
+	trace.SetEvent(info.NewEvent())
+	trace.SetRepository(&v1alpha1.Repository{})
+	assert.Assert(t, trace.GetRepository() == nil)
+	assert.Equal(t, trace.Summary(), "")
+}
+
+func TestTrace(t *testing.T) {
+	tests := []struct {
+		name        string
+		record      func(*Trace)
+		wantMatched []string
+		wantSummary string
+	}{
+		{
+			name: "matched and rejected",
+			record: func(trace *Trace) {
+				trace.Matched("pr-push", "", "push")
+				trace.Rejected("pr-pull", "", "not a pull request")
+			},
+			wantMatched: []string{"pr-push"},
+			wantSummary: "decision trace for push event on sha abc: 1/2 PipelineRun(s) matched",
+		},
+		{
+			name: "last decision wins",
+			record: func(trace *Trace) {
+				trace.Matched("pr-push", "", "push")
+				trace.Rejected("pr-push", "", "already succeeded")
+			},
+			wantSummary: "decision trace for push event on sha abc: 0/1 PipelineRun(s) matched",
+		},
+		{
+			name: "reset forgets previous matching",
+			record: func(trace *Trace) {
+				trace.Rejected("pr-push", "", "first pass")
+				trace.ResetPipelineRuns()
+				trace.Matched("pr-push", "", "second pass")
+			},
+			wantMatched: []string{"pr-push"},
+			wantSummary: "decision trace for push event on sha abc: 1/1 PipelineRun(s) matched",
+		},
+		{
+			name: "access denied",
+			record: func(trace *Trace) {
+				trace.AccessChecked(AccessCheckPolicy, false, "not in the allowed groups")
+			},
+			wantSummary: "decision trace for push event on sha abc: 0/0 PipelineRun(s) matched, denied by policy check",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace := New()
+			ctx := NewContext(context.Background(), trace)
+			tt.record(FromContext(ctx))
+			trace.SetRepository(&v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo"}})
+			trace.SetEvent(&info.Event{SHA: "abc", EventType: "push", TriggerTarget: triggertype.Push})
+
+			matched := []string{}
+			for _, decision := range trace.PipelineRuns {
+				if decision.Matched {
+					matched = append(matched, decision.Name)
+				}
+			}
+			if tt.wantMatched == nil {
+				tt.wantMatched = []string{}
+			}
+			assert.DeepEqual(t, matched, tt.wantMatched)
+			assert.Equal(t, trace.Summary(), tt.wantSummary)
+			assert.Equal(t, trace.GetRepository().GetName(), "repo")
+		})
+	}
+}
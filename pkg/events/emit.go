@@ -31,8 +31,18 @@ func (e *EventEmitter) SetLogger(logger *zap.SugaredLogger) {
 }
 
 func (e *EventEmitter) EmitMessage(repo *v1alpha1.Repository, loggerLevel zapcore.Level, reason, message string) {
+	e.EmitMessageWithAnnotations(repo, loggerLevel, reason, message, nil)
+}
+
+// EmitMessageWithAnnotations emits a message like EmitMessage and adds
+// annotations to the Kubernetes event, to attach data which would not fit in
+// the message.
+func (e *EventEmitter) EmitMessageWithAnnotations(repo *v1alpha1.Repository, loggerLevel zapcore.Level, reason, message string, annotations map[string]string) {
 	if repo != nil && e.client != nil {
 		event := makeEvent(repo, loggerLevel, reason, message)
+		for k, v := range annotations {
+			event.Annotations[k] = v
+		}
 		if _, err := e.client.CoreV1().Events(event.Namespace).Create(context.Background(), event, metav1.CreateOptions{}); err != nil {
 			if e.logger != nil {
 				e.logger.Infof("Cannot create event: %s", err.Error())
@@ -0,0 +1,226 @@
+// Package flaky detects the tasks whose result flips between success and
+// failure on the same commit.
+package flaky
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+// Run is the result of the tasks of a completed PipelineRun.
+type Run struct {
+	// Pipeline is the name of the PipelineRun in the .tekton directory.
+	Pipeline     string
+	SHA          string
+	CreationTime time.Time
+	// Tasks are the results of the tasks by name, true when the task
+	// succeeded.
+	Tasks map[string]bool
+	// Succeeded is true when the PipelineRun succeeded.
+	Succeeded bool
+	// Partial is true when only the failed tasks of the run are known, the
+	// other tasks of the pipeline are assumed to have succeeded when the run
+	// succeeded.
+	Partial bool
+}
+
+// generatedSuffixLength is the length of the random suffix added by the API
+// server to the names of the objects created with generateName.
+const generatedSuffixLength = 5
+
+// TaskStats are the statistics of a task of a pipeline.
+type TaskStats struct {
+	Pipeline string
+	Task     string
+	Runs     int
+	Failures int
+	// Commits is the number of commits the task ran on.
+	Commits int
+	// FlakyCommits is the number of commits the task both failed and
+	// succeeded on.
+	FlakyCommits int
+}
+
+// FailureRate is the ratio of the runs of the task which failed.
+func (s TaskStats) FailureRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Runs)
+}
+
+// FlakeRate is the ratio of the commits the task both failed and succeeded
+// on.
+func (s TaskStats) FlakeRate() float64 {
+	if s.Commits == 0 {
+		return 0
+	}
+	return float64(s.FlakyCommits) / float64(s.Commits)
+}
+
+// NewRun returns the result of the tasks of a completed PipelineRun from the
+// statuses of its TaskRuns. It returns false when the PipelineRun is not done
+// or has been cancelled.
+func NewRun(pr *tektonv1.PipelineRun, trStatus map[string]*tektonv1.PipelineRunTaskRunStatus) (Run, bool) {
+	if !pr.IsDone() || pr.IsCancelled() {
+		return Run{}, false
+	}
+	pipeline := pr.GetAnnotations()[keys.OriginalPRName]
+	if pipeline == "" {
+		pipeline = pr.GetName()
+	}
+	run := Run{
+		Pipeline:     pipeline,
+		SHA:          pr.GetAnnotations()[keys.SHA],
+		CreationTime: pr.GetCreationTimestamp().Time,
+		Succeeded:    pr.Status.GetCondition(apis.ConditionSucceeded).IsTrue(),
+		Tasks:        map[string]bool{},
+	}
+	for _, taskrun := range trStatus {
+		if taskrun.Status == nil {
+			continue
+		}
+		cond := taskrun.Status.GetCondition(apis.ConditionSucceeded)
+		if cond == nil || cond.Status == corev1.ConditionUnknown {
+			continue
+		}
+		run.Tasks[taskrun.PipelineTaskName] = cond.IsTrue()
+	}
+	return run, true
+}
+
+// NewRunFromStatus returns the result of the tasks of a run recorded in the
+// status of the Repository, for the runs whose PipelineRun has been deleted.
+// Only the failed tasks of the run are known from the CollectedTaskInfos.
+func NewRunFromStatus(status v1alpha1.RepositoryRunStatus) (Run, bool) {
+	if status.SHA == nil || len(status.Conditions) == 0 {
+		return Run{}, false
+	}
+	cond := status.Conditions[0]
+	if cond.Status == corev1.ConditionUnknown || cond.Reason == string(tektonv1.PipelineRunReasonCancelled) {
+		return Run{}, false
+	}
+	run := Run{
+		Pipeline:  pipelineName(status.PipelineRunName),
+		SHA:       *status.SHA,
+		Succeeded: cond.Status == corev1.ConditionTrue,
+		Partial:   true,
+		Tasks:     map[string]bool{},
+	}
+	if status.StartTime != nil {
+		run.CreationTime = status.StartTime.Time
+	}
+	if status.CollectedTaskInfos != nil {
+		for name := range *status.CollectedTaskInfos {
+			run.Tasks[name] = false
+		}
+	}
+	return run, true
+}
+
+// pipelineName returns the name of the PipelineRun in the .tekton directory
+// from the name of the PipelineRun, without the suffix added by generateName.
+func pipelineName(name string) string {
+	if i := strings.LastIndex(name, "-"); i > 0 && len(name)-i-1 == generatedSuffixLength {
+		return name[:i]
+	}
+	return name
+}
+
+type taskKey struct {
+	pipeline, task string
+}
+
+// Analyze returns the statistics of the tasks of the runs, sorted from the
+// flakiest to the least flaky task and then by failure rate.
+func Analyze(runs []Run) []TaskStats {
+	known := map[string][]string{}
+	for _, run := range runs {
+		for task := range run.Tasks {
+			if !slices.Contains(known[run.Pipeline], task) {
+				known[run.Pipeline] = append(known[run.Pipeline], task)
+			}
+		}
+	}
+
+	stats := map[taskKey]*TaskStats{}
+	// the results of a task on each commit, bit 1 when it succeeded and bit 2
+	// when it failed
+	results := map[taskKey]map[string]int{}
+	for _, run := range runs {
+		tasks := run.Tasks
+		if run.Partial && run.Succeeded {
+			tasks = map[string]bool{}
+			for _, task := range known[run.Pipeline] {
+				tasks[task] = true
+			}
+		}
+		for task, succeeded := range tasks {
+			key := taskKey{run.Pipeline, task}
+			if _, ok := stats[key]; !ok {
+				stats[key] = &TaskStats{Pipeline: run.Pipeline, Task: task}
+				results[key] = map[string]int{}
+			}
+			stats[key].Runs++
+			if succeeded {
+				results[key][run.SHA] |= 1
+			} else {
+				stats[key].Failures++
+				results[key][run.SHA] |= 2
+			}
+		}
+	}
+
+	analyzed := make([]TaskStats, 0, len(stats))
+	for key, stat := range stats {
+		stat.Commits = len(results[key])
+		for _, result := range results[key] {
+			if result == 3 {
+				stat.FlakyCommits++
+			}
+		}
+		analyzed = append(analyzed, *stat)
+	}
+	slices.SortFunc(analyzed, func(a, b TaskStats) int {
+		return cmp.Or(
+			cmp.Compare(b.FlakeRate(), a.FlakeRate()),
+			cmp.Compare(b.FailureRate(), a.FailureRate()),
+			cmp.Compare(a.Pipeline, b.Pipeline),
+			cmp.Compare(a.Task, b.Task),
+		)
+	})
+	return analyzed
+}
+
+// Flips returns the tasks of the run whose result differs from their result
+// in the latest previous run of the same pipeline on the same commit.
+func Flips(run Run, previous []Run) []string {
+	latest := map[string]Run{}
+	for _, prev := range previous {
+		if prev.Pipeline != run.Pipeline || prev.SHA != run.SHA || !prev.CreationTime.Before(run.CreationTime) {
+			continue
+		}
+		for task := range prev.Tasks {
+			if last, ok := latest[task]; !ok || last.CreationTime.Before(prev.CreationTime) {
+				latest[task] = prev
+			}
+		}
+	}
+
+	flips := []string{}
+	for task, succeeded := range run.Tasks {
+		if last, ok := latest[task]; ok && last.Tasks[task] != succeeded {
+			flips = append(flips, task)
+		}
+	}
+	slices.Sort(flips)
+	return flips
+}
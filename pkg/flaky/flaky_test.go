@@ -0,0 +1,175 @@
+package flaky
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func taskRunStatus(task string, status corev1.ConditionStatus) *tektonv1.PipelineRunTaskRunStatus {
+	return &tektonv1.PipelineRunTaskRunStatus{
+		PipelineTaskName: task,
+		Status: &tektonv1.TaskRunStatus{
+			Status: duckv1.Status{
+				Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status}},
+			},
+		},
+	}
+}
+
+func TestNewRun(t *testing.T) {
+	now := time.Now()
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pull-request-abcde",
+			CreationTimestamp: metav1.Time{Time: now},
+			Annotations: map[string]string{
+				keys.OriginalPRName: "pull-request",
+				keys.SHA:            "sha1",
+			},
+		},
+		Status: tektonv1.PipelineRunStatus{
+			Status: duckv1.Status{
+				Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse}},
+			},
+		},
+	}
+	trStatus := map[string]*tektonv1.PipelineRunTaskRunStatus{
+		"pull-request-abcde-lint": taskRunStatus("lint", corev1.ConditionTrue),
+		"pull-request-abcde-test": taskRunStatus("test", corev1.ConditionFalse),
+		"pull-request-abcde-e2e":  taskRunStatus("e2e", corev1.ConditionUnknown),
+	}
+
+	run, ok := NewRun(pr, trStatus)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, run, Run{
+		Pipeline:     "pull-request",
+		SHA:          "sha1",
+		CreationTime: now,
+		Tasks:        map[string]bool{"lint": true, "test": false},
+	})
+
+	pr.Status.Conditions[0].Status = corev1.ConditionUnknown
+	_, ok = NewRun(pr, trStatus)
+	assert.Assert(t, !ok, "a running pipelinerun should not be analyzed")
+}
+
+func TestNewRunFromStatus(t *testing.T) {
+	sha := "sha1"
+	tests := []struct {
+		name   string
+		status v1alpha1.RepositoryRunStatus
+		want   Run
+		wantOK bool
+	}{
+		{
+			name: "failed run",
+			status: v1alpha1.RepositoryRunStatus{
+				Status: duckv1.Status{
+					Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: "Failed"}},
+				},
+				PipelineRunName:    "pull-request-abcde",
+				SHA:                &sha,
+				CollectedTaskInfos: &map[string]v1alpha1.TaskInfos{"test": {Name: "pull-request-abcde-test"}},
+			},
+			want: Run{
+				Pipeline: "pull-request",
+				SHA:      sha,
+				Partial:  true,
+				Tasks:    map[string]bool{"test": false},
+			},
+			wantOK: true,
+		},
+		{
+			name: "succeeded run",
+			status: v1alpha1.RepositoryRunStatus{
+				Status: duckv1.Status{
+					Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue, Reason: "Succeeded"}},
+				},
+				PipelineRunName: "push",
+				SHA:             &sha,
+			},
+			want: Run{
+				Pipeline:  "push",
+				SHA:       sha,
+				Succeeded: true,
+				Partial:   true,
+				Tasks:     map[string]bool{},
+			},
+			wantOK: true,
+		},
+		{
+			name: "cancelled run",
+			status: v1alpha1.RepositoryRunStatus{
+				Status: duckv1.Status{
+					Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: "Cancelled"}},
+				},
+				PipelineRunName: "push-abcde",
+				SHA:             &sha,
+			},
+		},
+		{
+			name: "no sha",
+			status: v1alpha1.RepositoryRunStatus{
+				Status: duckv1.Status{
+					Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run, ok := NewRunFromStatus(tt.status)
+			assert.Equal(t, ok, tt.wantOK)
+			if ok {
+				assert.DeepEqual(t, run, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	runs := []Run{
+		{Pipeline: "pr", SHA: "sha1", Tasks: map[string]bool{"lint": true, "test": false}},
+		{Pipeline: "pr", SHA: "sha1", Tasks: map[string]bool{"lint": true, "test": true}},
+		{Pipeline: "pr", SHA: "sha2", Tasks: map[string]bool{"lint": false, "test": true}},
+		{Pipeline: "pr", SHA: "sha3", Tasks: map[string]bool{"lint": false}, Partial: true},
+		{Pipeline: "pr", SHA: "sha3", Succeeded: true, Partial: true, Tasks: map[string]bool{}},
+		{Pipeline: "push", SHA: "sha1", Tasks: map[string]bool{"build": true}},
+	}
+
+	assert.DeepEqual(t, Analyze(runs), []TaskStats{
+		{Pipeline: "pr", Task: "lint", Runs: 5, Failures: 2, Commits: 3, FlakyCommits: 1},
+		{Pipeline: "pr", Task: "test", Runs: 4, Failures: 1, Commits: 3, FlakyCommits: 1},
+		{Pipeline: "push", Task: "build", Runs: 1, Commits: 1},
+	})
+}
+
+func TestTaskStatsRates(t *testing.T) {
+	stats := TaskStats{Runs: 4, Failures: 1, Commits: 2, FlakyCommits: 1}
+	assert.Equal(t, stats.FailureRate(), 0.25)
+	assert.Equal(t, stats.FlakeRate(), 0.5)
+	assert.Equal(t, TaskStats{}.FailureRate(), 0.0)
+	assert.Equal(t, TaskStats{}.FlakeRate(), 0.0)
+}
+
+func TestFlips(t *testing.T) {
+	now := time.Now()
+	run := Run{Pipeline: "pr", SHA: "sha1", CreationTime: now, Tasks: map[string]bool{"lint": true, "test": true, "e2e": false}}
+	previous := []Run{
+		{Pipeline: "pr", SHA: "sha1", CreationTime: now.Add(-2 * time.Hour), Tasks: map[string]bool{"lint": false, "test": true}},
+		{Pipeline: "pr", SHA: "sha1", CreationTime: now.Add(-time.Hour), Tasks: map[string]bool{"test": false}},
+		{Pipeline: "pr", SHA: "sha2", CreationTime: now.Add(-time.Hour), Tasks: map[string]bool{"e2e": true}},
+		{Pipeline: "push", SHA: "sha1", CreationTime: now.Add(-time.Hour), Tasks: map[string]bool{"e2e": true}},
+		run,
+	}
+	assert.DeepEqual(t, Flips(run, previous), []string{"lint", "test"})
+}
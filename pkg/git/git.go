@@ -22,28 +22,51 @@ func RunGit(dir string, args ...string) (string, error) {
 		//nolint: nilerr
 		return "", nil
 	}
+	c := gitCommand(context.Background(), gitPath, dir, nil, args...)
+	var output bytes.Buffer
+	c.Stderr = &output
+	c.Stdout = &output
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("error running, %s, output: %s error: %w", c.Args[1:], output.String(), err)
+	}
+	return output.String(), nil
+}
+
+// RunGitContext runs git like RunGit until ctx is done, with env added to its
+// environment. Only the standard output is returned, the standard error is in
+// the error.
+func RunGitContext(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		return "", err
+	}
+	c := gitCommand(ctx, gitPath, dir, env, args...)
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("error running, %s, output: %s error: %w", c.Args[1:], stderr.String(), err)
+	}
+	return stdout.String(), nil
+}
+
+func gitCommand(ctx context.Context, gitPath, dir string, env []string, args ...string) *exec.Cmd {
 	// insert in args "-c", "gitcommit.gpgsign=false" at the beginning gpg sign when set in user
 	args = append([]string{"-c", "commit.gpgsign=false"}, args...)
 
-	c := exec.CommandContext(context.Background(), gitPath, args...)
-	c.Env = []string{
+	c := exec.CommandContext(ctx, gitPath, args...)
+	c.Env = append([]string{
 		"PATH=" + os.Getenv("PATH"),
 		"HOME=" + os.Getenv("HOME"),
 		"LC_ALL=C",
 		"LANG=C",
-	}
-	var output bytes.Buffer
-	c.Stderr = &output
-	c.Stdout = &output
+	}, env...)
 	// This is the optional working directory. If not set, it defaults to the current
 	// working directory of the process.
 	if dir != "" {
 		c.Dir = dir
 	}
-	if err := c.Run(); err != nil {
-		return "", fmt.Errorf("error running, %s, output: %s error: %w", args, output.String(), err)
-	}
-	return output.String(), nil
+	return c
 }
 
 // GetGitInfo try to detect the current remote for this URL return the origin url transformed and the topdir.
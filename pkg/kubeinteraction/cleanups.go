@@ -3,6 +3,7 @@ package kubeinteraction
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
@@ -14,7 +15,58 @@ import (
 	"knative.dev/pkg/apis"
 )
 
-func (k Interaction) CleanupPipelines(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun, maxKeep int) error {
+// Retention defines which completed PipelineRuns of a pipeline are kept, a
+// PipelineRun is deleted as soon as one of the rules does not keep it.
+type Retention struct {
+	// MaxKeep is the number of the latest PipelineRuns kept.
+	MaxKeep int
+	// MaxKeepFailed is the number of the latest failed PipelineRuns kept,
+	// it replaces MaxKeep for the failed PipelineRuns when set.
+	MaxKeepFailed int
+	// MaxKeepSuccessful is the number of the latest successful PipelineRuns
+	// kept, it replaces MaxKeep for the successful PipelineRuns when set.
+	MaxKeepSuccessful int
+	// KeepFor is how long the PipelineRuns are kept after their completion.
+	KeepFor time.Duration
+	// KeepNone deletes the PipelineRuns not kept by MaxKeepFailed or
+	// MaxKeepSuccessful, as an explicit max-keep-runs of 0 always did.
+	KeepNone bool
+}
+
+// MaxKeepRetention returns the retention keeping the maxKeep latest
+// PipelineRuns, none of them when maxKeep is 0.
+func MaxKeepRetention(maxKeep int) Retention {
+	return Retention{MaxKeep: maxKeep, KeepNone: maxKeep == 0}
+}
+
+// IsZero returns true when the retention keeps all the PipelineRuns.
+func (r Retention) IsZero() bool {
+	return r == Retention{}
+}
+
+// expired returns true when the PipelineRun is not kept by the retention, c
+// is its position in the completed PipelineRuns of the pipeline and
+// statusPosition its position in the ones with the same status.
+func (r Retention) expired(prun *tektonv1.PipelineRun, c, statusPosition int, now time.Time) bool {
+	succeeded := prun.Status.GetCondition(apis.ConditionSucceeded).IsTrue()
+	switch {
+	case succeeded && r.MaxKeepSuccessful > 0:
+		if statusPosition >= r.MaxKeepSuccessful {
+			return true
+		}
+	case !succeeded && r.MaxKeepFailed > 0:
+		if statusPosition >= r.MaxKeepFailed {
+			return true
+		}
+	case r.MaxKeep > 0 || r.KeepNone:
+		if c >= r.MaxKeep {
+			return true
+		}
+	}
+	return r.KeepFor > 0 && prun.Status.CompletionTime != nil && now.Sub(prun.Status.CompletionTime.Time) > r.KeepFor
+}
+
+func (k Interaction) CleanupPipelines(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun, retention Retention) error {
 	if _, ok := pr.GetAnnotations()[keys.OriginalPRName]; !ok {
 		return fmt.Errorf("generated pipelinerun should have had the %s label for selection set but we could not find it", keys.OriginalPRName)
 	}
@@ -32,6 +84,9 @@ func (k Interaction) CleanupPipelines(ctx context.Context, logger *zap.SugaredLo
 		return err
 	}
 
+	now := time.Now()
+	// the number of successful and failed PipelineRuns seen so far
+	byStatus := map[bool]int{}
 	for c, prun := range psort.PipelineRunSortByCompletionTime(pruns.Items) {
 		prReason := prun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).GetReason()
 		if prReason == tektonv1.PipelineRunReasonRunning.String() || prReason == tektonv1.PipelineRunReasonPending.String() {
@@ -39,7 +94,10 @@ func (k Interaction) CleanupPipelines(ctx context.Context, logger *zap.SugaredLo
 			continue
 		}
 
-		if c >= maxKeep {
+		succeeded := prun.Status.GetCondition(apis.ConditionSucceeded).IsTrue()
+		statusPosition := byStatus[succeeded]
+		byStatus[succeeded]++
+		if retention.expired(&prun, c, statusPosition, now) {
 			logger.Infof("cleaning old PipelineRun: %s", prun.GetName())
 			err := k.Run.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).Delete(
 				ctx, prun.GetName(), metav1.DeleteOptions{})
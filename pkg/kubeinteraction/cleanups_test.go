@@ -2,6 +2,7 @@ package kubeinteraction
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
@@ -33,12 +34,14 @@ func TestCleanupPipelines(t *testing.T) {
 	cleanupAnnotations := maps.Clone(cleanupLabels)
 
 	clock := clockwork.NewFakeClock()
+	recentClock := clockwork.NewFakeClockAt(time.Now())
 
 	type args struct {
 		logSnippet       string
 		namespace        string
 		repositoryName   string
 		maxKeep          int
+		retention        Retention
 		pruns            []*tektonv1.PipelineRun
 		prunCurrent      *tektonv1.PipelineRun
 		kept             int
@@ -100,6 +103,39 @@ func TestCleanupPipelines(t *testing.T) {
 				prunLatestInList: "pipeline-pending",
 			},
 		},
+		{
+			name: "cleanup by status",
+			args: args{
+				namespace:      ns,
+				repositoryName: cleanupRepoName,
+				retention:      Retention{MaxKeepFailed: 2, MaxKeepSuccessful: 1},
+				kept:           3,
+				prunCurrent:    &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Labels: cleanupLabels, Annotations: cleanupAnnotations}},
+				pruns: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(clock, "pipeline-success-newest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 10),
+					tektontest.MakePRCompletion(clock, "pipeline-failed-newest", ns, tektonv1.PipelineRunReasonFailed.String(), nil, cleanupLabels, 20),
+					tektontest.MakePRCompletion(clock, "pipeline-success-oldest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 30),
+					tektontest.MakePRCompletion(clock, "pipeline-failed-middest", ns, tektonv1.PipelineRunReasonFailed.String(), nil, cleanupLabels, 40),
+					tektontest.MakePRCompletion(clock, "pipeline-failed-oldest", ns, tektonv1.PipelineRunReasonFailed.String(), nil, cleanupLabels, 50),
+				},
+				prunLatestInList: "pipeline-failed-middest",
+			},
+		},
+		{
+			name: "cleanup by age",
+			args: args{
+				namespace:      ns,
+				repositoryName: cleanupRepoName,
+				retention:      Retention{MaxKeep: 10, KeepFor: time.Hour},
+				kept:           1,
+				prunCurrent:    &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Labels: cleanupLabels, Annotations: cleanupAnnotations}},
+				pruns: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(recentClock, "pipeline-recent", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 10),
+					tektontest.MakePRCompletion(recentClock, "pipeline-old", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 120),
+				},
+				prunLatestInList: "pipeline-recent",
+			},
+		},
 		{
 			name: "cleanup with secrets",
 			args: args{
@@ -228,7 +264,11 @@ func TestCleanupPipelines(t *testing.T) {
 				},
 			}
 
-			err := kint.CleanupPipelines(ctx, fakelogger, repo, tt.args.prunCurrent, tt.args.maxKeep)
+			retention := tt.args.retention
+			if retention.IsZero() {
+				retention = MaxKeepRetention(tt.args.maxKeep)
+			}
+			err := kint.CleanupPipelines(ctx, fakelogger, repo, tt.args.prunCurrent, retention)
 			if tt.wantErr {
 				assert.Assert(t, err != nil)
 			}
@@ -0,0 +1,155 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// QueueNameLabel is the label of the PipelineRuns admitted by Kueue, it
+	// holds the name of the Kueue LocalQueue.
+	QueueNameLabel = "kueue.x-k8s.io/queue-name"
+	// JobUIDLabel is the label set by Kueue on a Workload with the UID of the
+	// job it admits.
+	JobUIDLabel = "kueue.x-k8s.io/job-uid"
+
+	workloadGroup     = "kueue.x-k8s.io"
+	workloadVersion   = "v1beta1"
+	workloadResource  = "workloads"
+	conditionAdmitted = "Admitted"
+	conditionReserved = "QuotaReserved"
+	jobUIDIndex       = "job-uid"
+)
+
+// WorkloadGVR is the resource of the Kueue Workloads.
+var WorkloadGVR = schema.GroupVersionResource{Group: workloadGroup, Version: workloadVersion, Resource: workloadResource}
+
+// Admission is the admission state of the Kueue Workload of a PipelineRun.
+type Admission struct {
+	// Found is false when Kueue has not created the Workload yet.
+	Found    bool
+	Admitted bool
+	// Message is the message of the admission condition, why the Workload is
+	// not admitted yet.
+	Message string
+}
+
+// QueueName returns the Kueue queue of the PipelineRun or an empty string when
+// its admission is not handed over to Kueue.
+func QueueName(pr *tektonv1.PipelineRun) string {
+	return pr.GetLabels()[QueueNameLabel]
+}
+
+// GetQueueName returns the Kueue queue of the PipelineRuns of the Repository,
+// from its settings or from the pipelinesascode.tekton.dev/kueue-queue-name
+// annotation of its namespace, an empty string when Kueue is not used.
+func GetQueueName(namespaces corelisters.NamespaceLister, repo *v1alpha1.Repository) (string, error) {
+	if repo.Spec.Settings != nil && repo.Spec.Settings.KueueQueueName != "" {
+		return repo.Spec.Settings.KueueQueueName, nil
+	}
+	if namespaces == nil {
+		return "", nil
+	}
+	ns, err := namespaces.Get(repo.GetNamespace())
+	if err != nil {
+		return "", fmt.Errorf("cannot get the kueue queue of namespace %s: %w", repo.GetNamespace(), err)
+	}
+	return ns.GetAnnotations()[keys.KueueQueueName], nil
+}
+
+// IsInstalled returns true when the Kueue Workloads are served by the cluster.
+func IsInstalled(kube kubernetes.Interface) bool {
+	_, err := kube.Discovery().ServerResourcesForGroupVersion(WorkloadGVR.GroupVersion().String())
+	return err == nil
+}
+
+// NewWorkloadInformer returns an informer of the Kueue Workloads admitting a
+// job, indexed by the UID of their job so the Workload of a PipelineRun is
+// found without listing all the Workloads of its namespace.
+func NewWorkloadInformer(ctx context.Context, kdyn dynamic.Interface) cache.SharedIndexInformer {
+	workloads := kdyn.Resource(WorkloadGVR)
+	lw := &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.LabelSelector = JobUIDLabel
+			return workloads.List(ctx, opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.LabelSelector = JobUIDLabel
+			return workloads.Watch(ctx, opts)
+		},
+	}
+	return cache.NewSharedIndexInformer(lw, &unstructured.Unstructured{}, 0, cache.Indexers{
+		jobUIDIndex: func(obj any) ([]string, error) {
+			workload, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				return nil, nil
+			}
+			return []string{workload.GetLabels()[JobUIDLabel]}, nil
+		},
+	})
+}
+
+// OwnerPipelineRun returns the PipelineRun admitted by the Workload, false
+// when the Workload admits another kind of job.
+func OwnerPipelineRun(obj any) (types.NamespacedName, bool) {
+	workload, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return types.NamespacedName{}, false
+	}
+	for _, owner := range workload.GetOwnerReferences() {
+		if owner.Kind == "PipelineRun" {
+			return types.NamespacedName{Namespace: workload.GetNamespace(), Name: owner.Name}, true
+		}
+	}
+	return types.NamespacedName{}, false
+}
+
+// GetAdmission returns the admission state of the Workload created by Kueue
+// for the PipelineRun from the indexer of the Workload informer.
+func GetAdmission(workloads cache.Indexer, pr *tektonv1.PipelineRun) (*Admission, error) {
+	objs, err := workloads.ByIndex(jobUIDIndex, string(pr.GetUID()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the kueue workload of pipelinerun %s/%s: %w", pr.GetNamespace(), pr.GetName(), err)
+	}
+	for _, obj := range objs {
+		workload, ok := obj.(*unstructured.Unstructured)
+		if !ok || workload.GetNamespace() != pr.GetNamespace() {
+			continue
+		}
+		admission := &Admission{Found: true}
+		conditions, _, _ := unstructured.NestedSlice(workload.Object, "status", "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			switch condition["type"] {
+			case conditionAdmitted:
+				admission.Admitted = condition["status"] == string(metav1.ConditionTrue)
+				if message, ok := condition["message"].(string); ok && message != "" {
+					admission.Message = message
+				}
+			case conditionReserved:
+				if message, ok := condition["message"].(string); ok && admission.Message == "" {
+					admission.Message = message
+				}
+			}
+		}
+		return admission, nil
+	}
+	return &Admission{}, nil
+}
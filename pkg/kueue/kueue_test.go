@@ -0,0 +1,151 @@
+package kueue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestGetQueueName(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    *v1alpha1.Settings
+		annotations map[string]string
+		want        string
+	}{
+		{name: "no kueue"},
+		{
+			name:     "repository setting",
+			settings: &v1alpha1.Settings{KueueQueueName: "repo-queue"},
+			want:     "repo-queue",
+		},
+		{
+			name:        "namespace annotation",
+			annotations: map[string]string{keys.KueueQueueName: "ns-queue"},
+			want:        "ns-queue",
+		},
+		{
+			name:        "repository setting over namespace annotation",
+			settings:    &v1alpha1.Settings{KueueQueueName: "repo-queue"},
+			annotations: map[string]string{keys.KueueQueueName: "ns-queue"},
+			want:        "repo-queue",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			assert.NilError(t, indexer.Add(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ns", Annotations: tt.annotations},
+			}))
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec:       v1alpha1.RepositorySpec{Settings: tt.settings},
+			}
+			got, err := GetQueueName(corelisters.NewNamespaceLister(indexer), repo)
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func newWorkload(name string, owner *tektonv1.PipelineRun, conditions ...map[string]any) *unstructured.Unstructured {
+	workload := &unstructured.Unstructured{}
+	items := []any{}
+	for _, c := range conditions {
+		items = append(items, c)
+	}
+	workload.SetUnstructuredContent(map[string]any{
+		"apiVersion": "kueue.x-k8s.io/v1beta1",
+		"kind":       "Workload",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "ns",
+			"labels":    map[string]any{JobUIDLabel: string(owner.GetUID())},
+		},
+		"status": map[string]any{
+			"conditions": items,
+		},
+	})
+	workload.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: "tekton.dev/v1",
+		Kind:       "PipelineRun",
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+	}})
+	return workload
+}
+
+func TestGetAdmission(t *testing.T) {
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "ns", UID: types.UID("uid")}}
+	otherPR := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "ns", UID: types.UID("other-uid")}}
+
+	tests := []struct {
+		name      string
+		workloads []runtime.Object
+		want      Admission
+	}{
+		{
+			name: "no workload",
+			want: Admission{},
+		},
+		{
+			name:      "workload of a previous pipelinerun with the same name",
+			workloads: []runtime.Object{newWorkload("other", otherPR, map[string]any{"type": "Admitted", "status": "True"})},
+			want:      Admission{},
+		},
+		{
+			name: "quota not reserved",
+			workloads: []runtime.Object{newWorkload("wl", pr, map[string]any{
+				"type": "QuotaReserved", "status": "False", "message": "couldn't assign flavors to pod set main: insufficient quota",
+			})},
+			want: Admission{Found: true, Message: "couldn't assign flavors to pod set main: insufficient quota"},
+		},
+		{
+			name: "admitted",
+			workloads: []runtime.Object{newWorkload("wl", pr,
+				map[string]any{"type": "QuotaReserved", "status": "True", "message": "Quota reserved in ClusterQueue cluster-queue"},
+				map[string]any{"type": "Admitted", "status": "True", "message": "The workload is admitted"},
+			)},
+			want: Admission{Found: true, Admitted: true, Message: "The workload is admitted"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			kdyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{WorkloadGVR: "WorkloadList"}, tt.workloads...)
+			informer := NewWorkloadInformer(ctx, kdyn)
+			go informer.Run(ctx.Done())
+			assert.Assert(t, cache.WaitForCacheSync(ctx.Done(), informer.HasSynced))
+			got, err := GetAdmission(informer.GetIndexer(), pr)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, *got, tt.want)
+		})
+	}
+}
+
+func TestOwnerPipelineRun(t *testing.T) {
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "ns", UID: types.UID("uid")}}
+	key, ok := OwnerPipelineRun(newWorkload("wl", pr))
+	assert.Assert(t, ok)
+	assert.Equal(t, key, types.NamespacedName{Namespace: "ns", Name: "pr"})
+
+	job := newWorkload("job", pr)
+	job.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Job", Name: "job"}})
+	_, ok = OwnerPipelineRun(job)
+	assert.Assert(t, !ok)
+}
@@ -1,17 +1,22 @@
 package llm
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cel"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
 	llmcontext "github.com/openshift-pipelines/pipelines-as-code/pkg/llm/context"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/ltypes"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	prmetrics "github.com/openshift-pipelines/pipelines-as-code/pkg/pipelinerunmetrics"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"go.uber.org/zap"
@@ -30,16 +35,19 @@ type Analyzer struct {
 	kinteract kubeinteraction.Interface
 	factory   *Factory
 	assembler *llmcontext.Assembler
+	cache     *AnalysisCache
 	logger    *zap.SugaredLogger
 }
 
-// NewAnalyzer creates a new LLM analyzer.
-func NewAnalyzer(run *params.Run, kinteract kubeinteraction.Interface, logger *zap.SugaredLogger) *Analyzer {
+// NewAnalyzer creates a new LLM analyzer, the analyses of the failures are
+// reused from the cache when it is not nil.
+func NewAnalyzer(run *params.Run, kinteract kubeinteraction.Interface, cache *AnalysisCache, logger *zap.SugaredLogger) *Analyzer {
 	return &Analyzer{
 		run:       run,
 		kinteract: kinteract,
 		factory:   NewFactory(run, kinteract),
 		assembler: llmcontext.NewAssembler(run, kinteract, logger),
+		cache:     cache,
 		logger:    logger,
 	}
 }
@@ -103,6 +111,18 @@ func (a *Analyzer) Analyze(ctx context.Context, request *AnalyzeRequest) ([]Anal
 		return nil, fmt.Errorf("failed to build CEL context: %w", err)
 	}
 
+	// The roles are skipped once a token budget is exhausted
+	budgets, err := TokenBudgetStatuses(ctx, a.run.Clients.PipelineAsCode, request.Repository, time.Now())
+	if err != nil {
+		analysisLogger.With("error", err).Error("Failed to get the token budgets")
+		return nil, fmt.Errorf("failed to get the token budgets: %w", err)
+	}
+
+	// The failed tasks fingerprint the failure to reuse its previous analysis,
+	// with the same lines of logs as sent for the role
+	cacheEnabled := a.configureCache()
+	failedTasksByLines := map[int64]map[string]v1alpha1.TaskInfos{}
+
 	// Process each role
 	results := []AnalysisResult{}
 	contextCache := make(map[string]map[string]any)
@@ -125,6 +145,50 @@ func (a *Analyzer) Analyze(ctx context.Context, request *AnalyzeRequest) ([]Anal
 			continue
 		}
 
+		var failedTasks map[string]v1alpha1.TaskInfos
+		if cacheEnabled {
+			lines := a.assembler.LogLines(role.ContextItems)
+			if failedTasks = failedTasksByLines[lines]; failedTasks == nil {
+				failedTasks = kstatus.CollectFailedTasksLogSnippet(ctx, a.run, a.kinteract, request.PipelineRun, lines)
+				failedTasksByLines[lines] = failedTasks
+			}
+		}
+		var key string
+		if fp := fingerprint(&role, request.PipelineRun.GetName(), failedTasks); fp != "" {
+			key = cacheKey(request.Repository, fp)
+			if previous, ok := a.cache.Get(key); ok {
+				roleLogger.With(
+					"fingerprint", fp,
+					"previous_pipeline_run", previous.pipelineRun,
+				).Info("Reusing the previous LLM analysis of the same failure")
+				a.recordCacheMetrics(request.Repository, "hit")
+				results = append(results, AnalysisResult{
+					Role:     role.Name,
+					Response: previous.reusedResponse(),
+				})
+				continue
+			}
+			a.recordCacheMetrics(request.Repository, "miss")
+		}
+
+		if budget := exhaustedBudget(budgets); budget != nil {
+			roleLogger.With(
+				"budget_scope", budget.Scope,
+				"budget_period", budget.Period,
+				"budget_limit", budget.Limit,
+				"budget_used", budget.Used,
+			).Warn("Token budget exhausted, skipping analysis role")
+			results = append(results, AnalysisResult{
+				Role: role.Name,
+				Response: &ltypes.AnalysisResponse{
+					Content:   budget.notice(),
+					Provider:  config.Provider,
+					Timestamp: time.Now(),
+				},
+			})
+			continue
+		}
+
 		roleLogger.Info("Executing analysis role")
 
 		contextKey := getContextCacheKey(role.ContextItems)
@@ -239,6 +303,16 @@ func (a *Analyzer) Analyze(ctx context.Context, request *AnalyzeRequest) ([]Anal
 			"response_length", len(response.Content),
 		).Info("LLM analysis completed successfully")
 
+		a.recordTokenUsage(ctx, request.Repository, config, &role, response.TokensUsed)
+		for i := range budgets {
+			budgets[i].Used += response.TokensUsed
+		}
+
+		if key != "" {
+			a.cache.Add(key, request.PipelineRun.GetName(), response)
+			a.recordCacheMetrics(request.Repository, "")
+		}
+
 		results = append(results, AnalysisResult{
 			Role:     role.Name,
 			Response: response,
@@ -254,6 +328,65 @@ func (a *Analyzer) Analyze(ctx context.Context, request *AnalyzeRequest) ([]Anal
 	return results, nil
 }
 
+// configureCache applies the llm-analysis-cache-size and
+// llm-analysis-cache-ttl settings to the cache and returns true when the
+// cache is enabled.
+func (a *Analyzer) configureCache() bool {
+	if a.cache == nil {
+		return false
+	}
+	pacInfo := a.run.Info.GetPacOpts()
+	var ttl time.Duration
+	if pacInfo.LLMAnalysisCacheTTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(pacInfo.LLMAnalysisCacheTTL); err != nil {
+			a.logger.Warnf("invalid llm-analysis-cache-ttl setting, the analyses are kept in the cache until they are evicted: %v", err)
+		}
+	}
+	a.cache.Configure(pacInfo.LLMAnalysisCacheSize, ttl)
+	return a.cache.Enabled()
+}
+
+// recordCacheMetrics reports the result of the lookup of an analysis of the
+// repository in the cache, when result is not empty, and the size of the
+// cache.
+func (a *Analyzer) recordCacheMetrics(repo *v1alpha1.Repository, result string) {
+	recorder, err := prmetrics.NewRecorder()
+	if err != nil {
+		a.logger.Errorf("Error initializing metrics recorder: %v", err)
+		return
+	}
+	if result != "" {
+		if err := recorder.CountLLMAnalysisCache(repo.GetNamespace(), repo.GetName(), result); err != nil {
+			a.logger.Errorf("Error reporting llm analysis cache metrics: %v", err)
+		}
+	}
+	if err := recorder.LLMAnalysisCache(float64(a.cache.Len()), a.cache.TTL()); err != nil {
+		a.logger.Errorf("Error reporting llm analysis cache metrics: %v", err)
+	}
+}
+
+// recordTokenUsage accounts the tokens used by the analysis of a role in the
+// usage of the Repository and in the metrics.
+func (a *Analyzer) recordTokenUsage(ctx context.Context, repo *v1alpha1.Repository, config *v1alpha1.AIAnalysisConfig, role *v1alpha1.AnalysisRole, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+	if err := addTokenUsage(ctx, a.run.Clients.PipelineAsCode, repo, time.Now(), tokens); err != nil {
+		a.logger.Warnf("cannot record the token usage of repository %s/%s: %v", repo.GetNamespace(), repo.GetName(), err)
+	}
+
+	recorder, err := prmetrics.NewRecorder()
+	if err != nil {
+		a.logger.Errorf("Error initializing metrics recorder: %v", err)
+		return
+	}
+	model := cmp.Or(role.GetModel(), getDefaultModel(ltypes.AIProvider(config.Provider)))
+	if err := recorder.CountLLMAnalysisTokens(repo.GetNamespace(), repo.GetName(), role.Name, model, tokens); err != nil {
+		a.logger.Errorf("Error reporting llm analysis token metrics: %v", err)
+	}
+}
+
 // getContextCacheKey generates a unique key for a context configuration.
 func getContextCacheKey(config *v1alpha1.ContextConfig) string {
 	if config == nil {
@@ -263,13 +396,21 @@ func getContextCacheKey(config *v1alpha1.ContextConfig) string {
 	if config.ContainerLogs != nil {
 		maxLines = config.ContainerLogs.GetMaxLines()
 	}
+	contextLines, maxSize := 0, 0
+	if config.SourceSnippets != nil {
+		contextLines = config.SourceSnippets.GetContextLines()
+		maxSize = config.SourceSnippets.GetMaxSize()
+	}
 
-	return fmt.Sprintf("commit:%t-pr:%t-error:%t-logs:%t-%d",
+	return fmt.Sprintf("commit:%t-pr:%t-error:%t-logs:%t-%d-snippets:%t-%d-%d",
 		config.CommitContent,
 		config.PRContent,
 		config.ErrorContent,
 		config.ContainerLogs != nil && config.ContainerLogs.Enabled,
 		maxLines,
+		config.SourceSnippets != nil && config.SourceSnippets.Enabled,
+		contextLines,
+		maxSize,
 	)
 }
 
@@ -322,7 +463,7 @@ func (a *Analyzer) validateConfig(config *v1alpha1.AIAnalysisConfig) error {
 		return fmt.Errorf("provider is required")
 	}
 
-	if config.TokenSecretRef == nil {
+	if config.TokenSecretRef == nil && ltypes.AIProvider(config.Provider).RequiresToken() {
 		return fmt.Errorf("token secret reference is required")
 	}
 
@@ -339,9 +480,10 @@ func (a *Analyzer) validateConfig(config *v1alpha1.AIAnalysisConfig) error {
 			return fmt.Errorf("role[%d]: prompt is required", i)
 		}
 
-		output := role.GetOutput()
-		if output != "pr-comment" {
-			return fmt.Errorf("role[%d]: invalid output destination '%s' (only 'pr-comment' is currently supported)", i, output)
+		for _, output := range role.GetOutputs() {
+			if !slices.Contains(v1alpha1.AnalysisOutputs, output) {
+				return fmt.Errorf("role[%d]: invalid output destination '%s' (supported: %s)", i, output, strings.Join(v1alpha1.AnalysisOutputs, ", "))
+			}
 		}
 	}
 
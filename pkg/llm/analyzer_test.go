@@ -31,7 +31,7 @@ func TestAnalyzer_Analyze(t *testing.T) {
 	// Create mock kubeinteraction
 	kinteract := &kubeinteraction.Interaction{}
 
-	analyzer := NewAnalyzer(run, kinteract, logger)
+	analyzer := NewAnalyzer(run, kinteract, nil, logger)
 
 	tests := []struct {
 		name        string
@@ -117,7 +117,7 @@ func TestAnalyzer_ValidateConfig(t *testing.T) {
 	logger, _ := logger.GetLogger()
 	run := &params.Run{}
 	kinteract := &kubeinteraction.Interaction{}
-	analyzer := NewAnalyzer(run, kinteract, logger)
+	analyzer := NewAnalyzer(run, kinteract, nil, logger)
 
 	tests := []struct {
 		name      string
@@ -173,6 +173,20 @@ func TestAnalyzer_ValidateConfig(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "ollama without token secret ref",
+			config: &v1alpha1.AIAnalysisConfig{
+				Provider: "ollama",
+				Roles: []v1alpha1.AnalysisRole{
+					{
+						Name:   "test-role",
+						Prompt: "test prompt",
+						Output: "pr-comment",
+					},
+				},
+			},
+			wantError: false,
+		},
 		{
 			name: "no roles",
 			config: &v1alpha1.AIAnalysisConfig{
@@ -237,6 +251,43 @@ func TestAnalyzer_ValidateConfig(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "multiple outputs",
+			config: &v1alpha1.AIAnalysisConfig{
+				Provider: "openai",
+				TokenSecretRef: &v1alpha1.Secret{
+					Name: "test-secret",
+					Key:  "token",
+				},
+				Roles: []v1alpha1.AnalysisRole{
+					{
+						Name:    "test-role",
+						Prompt:  "test prompt",
+						Output:  "commit-comment",
+						Outputs: []string{"check-run", "repository-status", "pipelinerun-annotation"},
+					},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid role - invalid outputs",
+			config: &v1alpha1.AIAnalysisConfig{
+				Provider: "openai",
+				TokenSecretRef: &v1alpha1.Secret{
+					Name: "test-secret",
+					Key:  "token",
+				},
+				Roles: []v1alpha1.AnalysisRole{
+					{
+						Name:    "test-role",
+						Prompt:  "test prompt",
+						Outputs: []string{"pr-comment", "annotation"},
+					},
+				},
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -256,7 +307,7 @@ func TestAnalyzer_ValidateConfig_WithModels(t *testing.T) {
 	logger, _ := logger.GetLogger()
 	run := &params.Run{}
 	kinteract := &kubeinteraction.Interaction{}
-	analyzer := NewAnalyzer(run, kinteract, logger)
+	analyzer := NewAnalyzer(run, kinteract, nil, logger)
 
 	tests := []struct {
 		name      string
@@ -354,7 +405,7 @@ func TestGetContextCacheKey(t *testing.T) {
 		{
 			name:     "config without container logs",
 			config:   &v1alpha1.ContextConfig{},
-			expected: "commit:false-pr:false-error:false-logs:false-0",
+			expected: "commit:false-pr:false-error:false-logs:false-0-snippets:false-0-0",
 		},
 		{
 			name: "container logs enabled with explicit max lines",
@@ -367,7 +418,7 @@ func TestGetContextCacheKey(t *testing.T) {
 					MaxLines: 25,
 				},
 			},
-			expected: "commit:true-pr:true-error:true-logs:true-25",
+			expected: "commit:true-pr:true-error:true-logs:true-25-snippets:false-0-0",
 		},
 		{
 			name: "container logs enabled with default max lines",
@@ -376,7 +427,16 @@ func TestGetContextCacheKey(t *testing.T) {
 					Enabled: true,
 				},
 			},
-			expected: "commit:false-pr:false-error:false-logs:true-50",
+			expected: "commit:false-pr:false-error:false-logs:true-50-snippets:false-0-0",
+		},
+		{
+			name: "source snippets enabled with default sizes",
+			config: &v1alpha1.ContextConfig{
+				SourceSnippets: &v1alpha1.SourceSnippetsConfig{
+					Enabled: true,
+				},
+			},
+			expected: "commit:false-pr:false-error:false-logs:false-0-snippets:true-10-8000",
 		},
 	}
 
@@ -391,7 +451,7 @@ func TestAnalyzer_ShouldTriggerRoleEvaluations(t *testing.T) {
 	logger, _ := logger.GetLogger()
 	run := &params.Run{}
 	kinteract := &kubeinteraction.Interaction{}
-	analyzer := NewAnalyzer(run, kinteract, logger)
+	analyzer := NewAnalyzer(run, kinteract, nil, logger)
 
 	celContext := map[string]any{
 		"body": map[string]any{
@@ -457,7 +517,7 @@ func TestAnalyzer_ShouldTriggerRole(t *testing.T) {
 	logger, _ := logger.GetLogger()
 	run := &params.Run{}
 	kinteract := &kubeinteraction.Interaction{}
-	analyzer := NewAnalyzer(run, kinteract, logger)
+	analyzer := NewAnalyzer(run, kinteract, nil, logger)
 
 	tests := []struct {
 		name        string
@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// The scopes and periods of the token budgets.
+const (
+	BudgetScopeRepository = "repository"
+	BudgetScopeNamespace  = "namespace"
+	BudgetPeriodDaily     = "daily"
+	BudgetPeriodMonthly   = "monthly"
+)
+
+// TokenBudgetStatus is the number of tokens used out of a token budget.
+type TokenBudgetStatus struct {
+	Scope  string
+	Period string
+	Limit  int
+	Used   int
+}
+
+// Remaining returns the number of tokens left in the budget.
+func (s TokenBudgetStatus) Remaining() int {
+	return max(s.Limit-s.Used, 0)
+}
+
+// Exhausted returns true when no token is left in the budget.
+func (s TokenBudgetStatus) Exhausted() bool {
+	return s.Used >= s.Limit
+}
+
+// notice returns the notice posted in place of an analysis skipped because
+// the budget is exhausted.
+func (s TokenBudgetStatus) notice() string {
+	resume := "tomorrow"
+	if s.Period == BudgetPeriodMonthly {
+		resume = "next month"
+	}
+	return fmt.Sprintf("AI analysis skipped: the %s token budget of the %s (%d tokens) is exhausted, the analyses resume %s (UTC).",
+		s.Period, s.Scope, s.Limit, resume)
+}
+
+// TokenBudgetStatuses returns the usage of the token budgets of the AI
+// analysis of the Repository at now, for the budgets with a limit. The usage
+// of the namespace budgets is the sum of the usages of the Repositories of the
+// namespace.
+func TokenBudgetStatuses(ctx context.Context, pac versioned.Interface, repo *v1alpha1.Repository, now time.Time) ([]TokenBudgetStatus, error) {
+	if repo.Spec.Settings == nil || repo.Spec.Settings.AIAnalysis == nil || repo.Spec.Settings.AIAnalysis.TokenBudget == nil {
+		return nil, nil
+	}
+	budget := repo.Spec.Settings.AIAnalysis.TokenBudget
+
+	statuses := []TokenBudgetStatus{}
+	daily, monthly := repo.AIAnalysisUsage.Tokens(now)
+	if budget.Daily > 0 {
+		statuses = append(statuses, TokenBudgetStatus{Scope: BudgetScopeRepository, Period: BudgetPeriodDaily, Limit: budget.Daily, Used: daily})
+	}
+	if budget.Monthly > 0 {
+		statuses = append(statuses, TokenBudgetStatus{Scope: BudgetScopeRepository, Period: BudgetPeriodMonthly, Limit: budget.Monthly, Used: monthly})
+	}
+
+	if budget.NamespaceDaily > 0 || budget.NamespaceMonthly > 0 {
+		repos, err := pac.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("cannot list the repositories of namespace %s: %w", repo.GetNamespace(), err)
+		}
+		namespaceDaily, namespaceMonthly := 0, 0
+		for i := range repos.Items {
+			daily, monthly := repos.Items[i].AIAnalysisUsage.Tokens(now)
+			namespaceDaily += daily
+			namespaceMonthly += monthly
+		}
+		if budget.NamespaceDaily > 0 {
+			statuses = append(statuses, TokenBudgetStatus{Scope: BudgetScopeNamespace, Period: BudgetPeriodDaily, Limit: budget.NamespaceDaily, Used: namespaceDaily})
+		}
+		if budget.NamespaceMonthly > 0 {
+			statuses = append(statuses, TokenBudgetStatus{Scope: BudgetScopeNamespace, Period: BudgetPeriodMonthly, Limit: budget.NamespaceMonthly, Used: namespaceMonthly})
+		}
+	}
+	return statuses, nil
+}
+
+// exhaustedBudget returns the first exhausted budget, nil when tokens are
+// left in all of them.
+func exhaustedBudget(statuses []TokenBudgetStatus) *TokenBudgetStatus {
+	for i := range statuses {
+		if statuses[i].Exhausted() {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// addTokenUsage adds the tokens used at now by an analysis to the usage of
+// the Repository.
+func addTokenUsage(ctx context.Context, pac versioned.Interface, repo *v1alpha1.Repository, now time.Time, tokens int) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lastrepo, err := pac.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Get(ctx, repo.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if lastrepo.AIAnalysisUsage == nil {
+			lastrepo.AIAnalysisUsage = &v1alpha1.AIAnalysisUsage{}
+		}
+		lastrepo.AIAnalysisUsage.Add(now, tokens)
+		_, err = pac.PipelinesascodeV1alpha1().Repositories(lastrepo.GetNamespace()).Update(ctx, lastrepo, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,192 @@
+package llm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	paramclients "github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/metricstest"
+	tprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.opencensus.io/stats/view"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	_ "knative.dev/pkg/metrics/testing"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestTokenBudgetStatuses(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	usage := func(daily, monthly int) *v1alpha1.AIAnalysisUsage {
+		return &v1alpha1.AIAnalysisUsage{Day: "2026-10-17", DailyTokens: daily, Month: "2026-10", MonthlyTokens: monthly}
+	}
+	repository := func(name string, budget *v1alpha1.TokenBudget, usage *v1alpha1.AIAnalysisUsage) *v1alpha1.Repository {
+		return &v1alpha1.Repository{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec: v1alpha1.RepositorySpec{
+				Settings: &v1alpha1.Settings{
+					AIAnalysis: &v1alpha1.AIAnalysisConfig{TokenBudget: budget},
+				},
+			},
+			AIAnalysisUsage: usage,
+		}
+	}
+
+	tests := []struct {
+		name string
+		repo *v1alpha1.Repository
+		want []TokenBudgetStatus
+	}{
+		{
+			name: "no budget",
+			repo: repository("repo", nil, usage(10, 20)),
+		},
+		{
+			name: "repository budgets",
+			repo: repository("repo", &v1alpha1.TokenBudget{Daily: 100, Monthly: 1000}, usage(10, 20)),
+			want: []TokenBudgetStatus{
+				{Scope: BudgetScopeRepository, Period: BudgetPeriodDaily, Limit: 100, Used: 10},
+				{Scope: BudgetScopeRepository, Period: BudgetPeriodMonthly, Limit: 1000, Used: 20},
+			},
+		},
+		{
+			name: "usage of a previous day",
+			repo: repository("repo", &v1alpha1.TokenBudget{Daily: 100}, &v1alpha1.AIAnalysisUsage{Day: "2026-10-16", DailyTokens: 100}),
+			want: []TokenBudgetStatus{
+				{Scope: BudgetScopeRepository, Period: BudgetPeriodDaily, Limit: 100, Used: 0},
+			},
+		},
+		{
+			name: "namespace budgets",
+			repo: repository("repo", &v1alpha1.TokenBudget{NamespaceDaily: 100, NamespaceMonthly: 1000}, usage(10, 20)),
+			want: []TokenBudgetStatus{
+				{Scope: BudgetScopeNamespace, Period: BudgetPeriodDaily, Limit: 100, Used: 40},
+				{Scope: BudgetScopeNamespace, Period: BudgetPeriodMonthly, Limit: 1000, Used: 320},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces: []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}},
+				Repositories: []*v1alpha1.Repository{
+					tt.repo,
+					repository("other", nil, usage(30, 300)),
+					repository("no-usage", nil, nil),
+				},
+			})
+			got, err := TokenBudgetStatuses(ctx, stdata.PipelineAsCode, tt.repo, now)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want, cmpopts.EquateEmpty())
+		})
+	}
+}
+
+func TestTokenBudgetStatus(t *testing.T) {
+	status := TokenBudgetStatus{Scope: BudgetScopeNamespace, Period: BudgetPeriodMonthly, Limit: 100, Used: 80}
+	assert.Equal(t, status.Remaining(), 20)
+	assert.Assert(t, !status.Exhausted())
+	assert.Assert(t, exhaustedBudget([]TokenBudgetStatus{status}) == nil)
+
+	status.Used = 120
+	assert.Equal(t, status.Remaining(), 0)
+	assert.Assert(t, status.Exhausted())
+	assert.Equal(t, exhaustedBudget([]TokenBudgetStatus{status}).Scope, BudgetScopeNamespace)
+	assert.Equal(t, status.notice(), "AI analysis skipped: the monthly token budget of the namespace (100 tokens) is exhausted, the analyses resume next month (UTC).")
+}
+
+func TestAnalyzeTokenBudget(t *testing.T) {
+	metricstest.ResetMetrics()
+	logger, _ := logger.GetLogger()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"model":             "llama3.2",
+			"message":           map[string]string{"role": "assistant", "content": "the analysis"},
+			"done":              true,
+			"prompt_eval_count": 60,
+			"eval_count":        40,
+		})
+	}))
+	defer server.Close()
+
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec: v1alpha1.RepositorySpec{
+			Settings: &v1alpha1.Settings{
+				AIAnalysis: &v1alpha1.AIAnalysisConfig{
+					Enabled:     true,
+					Provider:    "ollama",
+					APIURL:      server.URL,
+					TokenBudget: &v1alpha1.TokenBudget{Daily: 150},
+					Roles: []v1alpha1.AnalysisRole{
+						{Name: "failure-analysis", Prompt: "Why did it fail?"},
+						{Name: "security", Prompt: "Is it a security issue?", Model: "llama3.3"},
+						{Name: "summary", Prompt: "Summarize the run"},
+					},
+				},
+			},
+		},
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Namespaces:   []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}},
+		Repositories: []*v1alpha1.Repository{repo},
+	})
+	run := &params.Run{
+		Clients: paramclients.Clients{PipelineAsCode: stdata.PipelineAsCode, Log: logger},
+	}
+	analyzer := NewAnalyzer(run, &kubernetestint.KinterfaceTest{}, nil, logger)
+
+	results, err := analyzer.Analyze(ctx, &AnalyzeRequest{
+		PipelineRun: &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "ns"}},
+		Event:       &info.Event{},
+		Repository:  repo,
+		Provider:    &tprovider.TestProviderImp{},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, calls, 2)
+	assert.Equal(t, len(results), 3)
+	assert.Equal(t, results[0].Response.Content, "the analysis")
+	assert.Equal(t, results[1].Response.Content, "the analysis")
+	assert.Assert(t, strings.HasPrefix(results[2].Response.Content, "AI analysis skipped: the daily token budget of the repository (150 tokens) is exhausted"))
+
+	got, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	daily, monthly := got.AIAnalysisUsage.Tokens(time.Now())
+	assert.Equal(t, daily, 200)
+	assert.Equal(t, monthly, 200)
+
+	rows, err := view.RetrieveData("pipelines_as_code_llm_analysis_token_count")
+	assert.NilError(t, err)
+	tokens := map[string]float64{}
+	for _, row := range rows {
+		tags := map[string]string{}
+		for _, tag := range row.Tags {
+			tags[tag.Key.Name()] = tag.Value
+		}
+		sum, ok := row.Data.(*view.SumData)
+		assert.Assert(t, ok)
+		tokens[tags["namespace"]+"/"+tags["repository"]+"/"+tags["role"]+"/"+tags["model"]] = sum.Value
+	}
+	assert.DeepEqual(t, tokens, map[string]float64{
+		"ns/repo/failure-analysis/llama3.2": 100,
+		"ns/repo/security/llama3.3":         100,
+	})
+}
@@ -0,0 +1,231 @@
+package llm
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/ltypes"
+)
+
+var (
+	timestampRegexp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+	uuidRegexp      = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	hexIDRegexp     = regexp.MustCompile(`(?i)\b[0-9a-f]{7,}\b`)
+	durationRegexp  = regexp.MustCompile(`\b\d+(\.\d+)?(ns|us|µs|ms|s|m|h)\b`)
+)
+
+// normalizeLog removes from a log snippet what changes from one run to
+// another for the same failure: the name of the PipelineRun, the timestamps,
+// the hexadecimal identifiers, the durations and the trailing spaces.
+func normalizeLog(snippet, pipelineRunName string) string {
+	if pipelineRunName != "" {
+		snippet = strings.ReplaceAll(snippet, pipelineRunName, "<pipelinerun>")
+	}
+	snippet = timestampRegexp.ReplaceAllString(snippet, "<timestamp>")
+	snippet = uuidRegexp.ReplaceAllString(snippet, "<uuid>")
+	snippet = hexIDRegexp.ReplaceAllStringFunc(snippet, func(id string) string {
+		// words like "effaced" are made of hexadecimal digits too, and the
+		// plain numbers like line numbers or exit codes are part of the failure
+		if !strings.ContainsAny(id, "0123456789") || !strings.ContainsAny(id, "abcdefABCDEF") {
+			return id
+		}
+		return "<id>"
+	})
+	snippet = durationRegexp.ReplaceAllString(snippet, "<duration>")
+
+	lines := strings.Split(strings.TrimSpace(snippet), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fingerprint returns the fingerprint of the failure of a PipelineRun for an
+// analysis role: the same tasks failing with the same logs analysed with the
+// same prompt and model have the same fingerprint. It returns an empty string
+// when no task failed, there is no failure to fingerprint then.
+func fingerprint(role *v1alpha1.AnalysisRole, pipelineRunName string, failedTasks map[string]v1alpha1.TaskInfos) string {
+	if len(failedTasks) == 0 {
+		return ""
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "prompt:%s\nmodel:%s\n", role.Prompt, role.GetModel())
+	for _, name := range slices.Sorted(maps.Keys(failedTasks)) {
+		task := failedTasks[name]
+		fmt.Fprintf(h, "task:%s\nreason:%s\nlog:%s\n", name, task.Reason, normalizeLog(task.LogSnippet, pipelineRunName))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedAnalysis is an analysis response kept in the AnalysisCache.
+type cachedAnalysis struct {
+	key         string
+	response    *ltypes.AnalysisResponse
+	pipelineRun string
+	created     time.Time
+}
+
+// AnalysisCache is an in-memory LRU cache of the analysis responses by
+// failure fingerprint, to not pay again for the analysis of a failure already
+// analysed, for example the same test failing on several pushes. It is
+// disabled until Configure sets a size.
+type AnalysisCache struct {
+	mu      sync.Mutex
+	clock   clockwork.Clock
+	size    int
+	ttl     time.Duration
+	entries *list.List
+	index   map[string]*list.Element
+}
+
+// NewAnalysisCache creates a new, disabled, analysis cache.
+func NewAnalysisCache(clock clockwork.Clock) *AnalysisCache {
+	return &AnalysisCache{
+		clock:   clock,
+		entries: list.New(),
+		index:   map[string]*list.Element{},
+	}
+}
+
+// cacheKey returns the key of the analysis of a fingerprint in the cache, the
+// analyses are not shared between Repositories.
+func cacheKey(repo *v1alpha1.Repository, fingerprint string) string {
+	return fmt.Sprintf("%s/%s/%s", repo.GetNamespace(), repo.GetName(), fingerprint)
+}
+
+// Configure sets the maximum number of analyses kept in the cache and how
+// long they are kept, a size of 0 disables the cache and a ttl of 0 keeps them
+// until they are evicted. The least recently used analyses are evicted when
+// the cache gets smaller.
+func (c *AnalysisCache) Configure(size int, ttl time.Duration) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size = max(size, 0)
+	c.ttl = ttl
+	c.evict()
+}
+
+// Enabled returns true when the cache keeps analyses.
+func (c *AnalysisCache) Enabled() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size > 0
+}
+
+// Len returns the number of analyses in the cache.
+func (c *AnalysisCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+// TTL returns how long the analyses are kept in the cache.
+func (c *AnalysisCache) TTL() time.Duration {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ttl
+}
+
+// Get returns the analysis cached for the key, the expired analyses are
+// removed from the cache and not returned.
+func (c *AnalysisCache) Get(key string) (*cachedAnalysis, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.index[key]
+	if !ok {
+		return nil, false
+	}
+	entry, _ := elem.Value.(*cachedAnalysis)
+	if c.expired(entry) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.entries.MoveToFront(elem)
+	return entry, true
+}
+
+// Add caches the analysis response of a PipelineRun for the key.
+func (c *AnalysisCache) Add(key, pipelineRun string, response *ltypes.AnalysisResponse) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size == 0 {
+		return
+	}
+	entry := &cachedAnalysis{
+		key:         key,
+		response:    response,
+		pipelineRun: pipelineRun,
+		created:     c.clock.Now(),
+	}
+	if elem, ok := c.index[key]; ok {
+		elem.Value = entry
+		c.entries.MoveToFront(elem)
+		return
+	}
+	c.index[key] = c.entries.PushFront(entry)
+	c.evict()
+}
+
+func (c *AnalysisCache) expired(entry *cachedAnalysis) bool {
+	return c.ttl > 0 && c.clock.Since(entry.created) > c.ttl
+}
+
+// evict removes the expired analyses and the least recently used ones above
+// the size of the cache.
+func (c *AnalysisCache) evict() {
+	for elem := c.entries.Back(); elem != nil; {
+		prev := elem.Prev()
+		if entry, _ := elem.Value.(*cachedAnalysis); c.expired(entry) {
+			c.remove(elem)
+		}
+		elem = prev
+	}
+	for c.entries.Len() > c.size {
+		c.remove(c.entries.Back())
+	}
+}
+
+func (c *AnalysisCache) remove(elem *list.Element) {
+	entry, _ := elem.Value.(*cachedAnalysis)
+	delete(c.index, entry.key)
+	c.entries.Remove(elem)
+}
+
+// reusedResponse returns the cached analysis as the response of a new
+// analysis, with a note that it was analysed before.
+func (e *cachedAnalysis) reusedResponse() *ltypes.AnalysisResponse {
+	response := *e.response
+	response.Content = fmt.Sprintf("_Previously analysed on %s for PipelineRun %s, the same failure was found again._\n\n%s",
+		e.created.UTC().Format(time.RFC3339), e.pipelineRun, e.response.Content)
+	response.TokensUsed = 0
+	return &response
+}
@@ -0,0 +1,278 @@
+package llm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/ltypes"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	paramclients "github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	tprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	knativeapi "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestNormalizeLog(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{
+			name:    "pipelinerun name",
+			snippet: "cannot push image for pr-build-abcde",
+			want:    "cannot push image for <pipelinerun>",
+		},
+		{
+			name:    "timestamps and durations",
+			snippet: "2026-10-17T06:16:38.123Z --- FAIL: TestFoo (0.52s)",
+			want:    "<timestamp> --- FAIL: TestFoo (<duration>)",
+		},
+		{
+			name:    "identifiers",
+			snippet: "request 123e4567-e89b-12d3-a456-426614174000 failed on commit 0b4993c1",
+			want:    "request <uuid> failed on commit <id>",
+		},
+		{
+			name:    "decimal numbers",
+			snippet: "exit status 1234567 at line 10000001",
+			want:    "exit status 1234567 at line 10000001",
+		},
+		{
+			name:    "hexadecimal words",
+			snippet: "the record was effaced",
+			want:    "the record was effaced",
+		},
+		{
+			name:    "spaces",
+			snippet: "  first line  \n\tsecond line \n",
+			want:    "first line\nsecond line",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, normalizeLog(tt.snippet, "pr-build-abcde"), tt.want)
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	role := &v1alpha1.AnalysisRole{Name: "failure-analysis", Prompt: "Why did it fail?"}
+	failedTasks := map[string]v1alpha1.TaskInfos{
+		"unit": {Name: "unit", Reason: "Failed", LogSnippet: "2026-10-17T06:16:38Z --- FAIL: TestFoo (0.52s)"},
+		"lint": {Name: "lint", Reason: "Failed", LogSnippet: "pr-build-abcde: main.go:10: unused variable"},
+	}
+	fp := fingerprint(role, "pr-build-abcde", failedTasks)
+	assert.Assert(t, fp != "")
+
+	sameFailure := map[string]v1alpha1.TaskInfos{
+		"lint": {Name: "lint", Reason: "Failed", LogSnippet: "pr-build-fghij: main.go:10: unused variable"},
+		"unit": {Name: "unit", Reason: "Failed", LogSnippet: "2026-10-18T08:00:00Z --- FAIL: TestFoo (0.61s)"},
+	}
+	assert.Equal(t, fingerprint(role, "pr-build-fghij", sameFailure), fp)
+
+	otherFailure := map[string]v1alpha1.TaskInfos{
+		"lint": {Name: "lint", Reason: "Failed", LogSnippet: "pr-build-fghij: main.go:12: unused import"},
+		"unit": {Name: "unit", Reason: "Failed", LogSnippet: "2026-10-18T08:00:00Z --- FAIL: TestFoo (0.61s)"},
+	}
+	assert.Assert(t, fingerprint(role, "pr-build-fghij", otherFailure) != fp)
+
+	otherRole := &v1alpha1.AnalysisRole{Name: "security", Prompt: "Is it a security issue?"}
+	assert.Assert(t, fingerprint(otherRole, "pr-build-abcde", failedTasks) != fp)
+
+	assert.Equal(t, fingerprint(role, "pr-build-abcde", nil), "")
+}
+
+func TestAnalysisCache(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	response := func(content string) *ltypes.AnalysisResponse {
+		return &ltypes.AnalysisResponse{Content: content, TokensUsed: 42}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		cache := NewAnalysisCache(clock)
+		assert.Assert(t, !cache.Enabled())
+		cache.Add("a", "pr-a", response("a"))
+		_, ok := cache.Get("a")
+		assert.Assert(t, !ok)
+		assert.Equal(t, cache.Len(), 0)
+
+		var nilCache *AnalysisCache
+		assert.Assert(t, !nilCache.Enabled())
+		nilCache.Add("a", "pr-a", response("a"))
+		_, ok = nilCache.Get("a")
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("least recently used evicted", func(t *testing.T) {
+		cache := NewAnalysisCache(clock)
+		cache.Configure(2, 0)
+		cache.Add("a", "pr-a", response("a"))
+		cache.Add("b", "pr-b", response("b"))
+		_, ok := cache.Get("a")
+		assert.Assert(t, ok)
+		cache.Add("c", "pr-c", response("c"))
+		assert.Equal(t, cache.Len(), 2)
+		_, ok = cache.Get("b")
+		assert.Assert(t, !ok)
+		_, ok = cache.Get("a")
+		assert.Assert(t, ok)
+
+		cache.Configure(1, 0)
+		assert.Equal(t, cache.Len(), 1)
+		_, ok = cache.Get("a")
+		assert.Assert(t, ok)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		cache := NewAnalysisCache(clock)
+		cache.Configure(10, time.Hour)
+		cache.Add("a", "pr-a", response("a"))
+		clock.Advance(30 * time.Minute)
+		cache.Add("b", "pr-b", response("b"))
+		clock.Advance(31 * time.Minute)
+		_, ok := cache.Get("a")
+		assert.Assert(t, !ok)
+		entry, ok := cache.Get("b")
+		assert.Assert(t, ok)
+		assert.Equal(t, entry.pipelineRun, "pr-b")
+		assert.Equal(t, cache.Len(), 1)
+	})
+
+	t.Run("reused response", func(t *testing.T) {
+		cache := NewAnalysisCache(clock)
+		cache.Configure(10, 0)
+		cache.Add("a", "pr-a", response("the analysis"))
+		entry, ok := cache.Get("a")
+		assert.Assert(t, ok)
+		reused := entry.reusedResponse()
+		assert.Assert(t, strings.HasPrefix(reused.Content, "_Previously analysed on "))
+		assert.Assert(t, strings.Contains(reused.Content, "for PipelineRun pr-a"))
+		assert.Assert(t, strings.HasSuffix(reused.Content, "\n\nthe analysis"))
+		assert.Equal(t, reused.TokensUsed, 0)
+		assert.Equal(t, entry.response.Content, "the analysis")
+	})
+}
+
+func TestAnalyzeReusesCachedAnalysis(t *testing.T) {
+	logger, _ := logger.GetLogger()
+	clock := clockwork.NewFakeClock()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"model":   "llama3.2",
+			"message": map[string]string{"role": "assistant", "content": "the analysis"},
+			"done":    true,
+		})
+	}))
+	defer server.Close()
+
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec: v1alpha1.RepositorySpec{
+			Settings: &v1alpha1.Settings{
+				AIAnalysis: &v1alpha1.AIAnalysisConfig{
+					Enabled:  true,
+					Provider: "ollama",
+					APIURL:   server.URL,
+					Roles:    []v1alpha1.AnalysisRole{{Name: "failure-analysis", Prompt: "Why did it fail?"}},
+				},
+			},
+		},
+	}
+
+	failedPipelineRun := func(name, log string) (*tektonv1.PipelineRun, *tektonv1.TaskRun, string) {
+		pr := tektontest.MakePRCompletion(clock, name, "ns", tektonv1.PipelineRunReasonFailed.String(), nil, map[string]string{}, 10)
+		pr.Status.ChildReferences = []tektonv1.ChildStatusReference{
+			{
+				TypeMeta:         runtime.TypeMeta{Kind: "TaskRun"},
+				Name:             name + "-unit",
+				PipelineTaskName: "unit",
+			},
+		}
+		taskStatus := tektonv1.TaskRunStatusFields{
+			PodName: name + "-unit-pod",
+			Steps: []tektonv1.StepState{
+				{
+					Name: "test",
+					ContainerState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 1},
+					},
+				},
+			},
+		}
+		tr := tektontest.MakeTaskRunCompletion(clock, name+"-unit", "ns", name, map[string]string{}, taskStatus, knativeduckv1.Conditions{
+			{
+				Type:   knativeapi.ConditionSucceeded,
+				Status: corev1.ConditionFalse,
+				Reason: tektonv1.PipelineRunReasonFailed.String(),
+			},
+		}, 10)
+		return pr, tr, log
+	}
+
+	first, firstTaskRun, firstLog := failedPipelineRun("pr-build-abcde", "2026-10-17T06:16:38Z --- FAIL: TestFoo (0.52s)")
+	second, secondTaskRun, secondLog := failedPipelineRun("pr-build-fghij", "2026-10-17T07:20:00Z --- FAIL: TestFoo (0.48s)")
+	third, thirdTaskRun, thirdLog := failedPipelineRun("pr-build-klmno", "2026-10-17T08:00:00Z --- FAIL: TestBar (0.10s)")
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		TaskRuns: []*tektonv1.TaskRun{firstTaskRun, secondTaskRun, thirdTaskRun},
+	})
+	run := &params.Run{
+		Clients: paramclients.Clients{Tekton: stdata.Pipeline, Log: logger},
+		Info: info.Info{Pac: &info.PacOpts{Settings: settings.Settings{
+			LLMAnalysisCacheSize: 10,
+			LLMAnalysisCacheTTL:  "24h",
+		}}},
+	}
+	kinteract := &kubernetestint.KinterfaceTest{GetPodLogsOutput: map[string]string{
+		"pr-build-abcde-unit-pod": firstLog,
+		"pr-build-fghij-unit-pod": secondLog,
+		"pr-build-klmno-unit-pod": thirdLog,
+	}}
+	analyzer := NewAnalyzer(run, kinteract, NewAnalysisCache(clock), logger)
+
+	for _, tt := range []struct {
+		pr        *tektonv1.PipelineRun
+		wantCalls int
+		wantReuse bool
+	}{
+		{pr: first, wantCalls: 1},
+		{pr: second, wantCalls: 1, wantReuse: true},
+		{pr: third, wantCalls: 2},
+	} {
+		results, err := analyzer.Analyze(ctx, &AnalyzeRequest{
+			PipelineRun: tt.pr,
+			Event:       &info.Event{},
+			Repository:  repo,
+			Provider:    &tprovider.TestProviderImp{},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, len(results), 1)
+		assert.NilError(t, results[0].Error)
+		assert.Equal(t, calls, tt.wantCalls)
+		assert.Equal(t, strings.Contains(results[0].Response.Content, "Previously analysed"), tt.wantReuse, results[0].Response.Content)
+		assert.Assert(t, strings.HasSuffix(results[0].Response.Content, "the analysis"))
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
@@ -11,6 +13,7 @@ import (
 	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
@@ -20,6 +23,14 @@ import (
 const (
 	// DefaultMaxLogLines is the default maximum number of log lines to include in context.
 	DefaultMaxLogLines = 50
+
+	// errorContentLogLines is the number of lines of the logs of each failed
+	// task included in the error content.
+	errorContentLogLines = 3
+
+	// maxSourceSnippetFiles is the maximum number of distinct files fetched
+	// from the provider for the source snippets.
+	maxSourceSnippetFiles = 10
 )
 
 // Assembler builds context data for LLM analysis from pipeline and event information.
@@ -68,8 +79,19 @@ func (a *Assembler) BuildContext(
 		}
 	}
 
+	sourceSnippetsEnabled := contextConfig.SourceSnippets != nil && contextConfig.SourceSnippets.Enabled
+
+	// the error content and the source snippets share the logs of the failed
+	// tasks, collect them only once with the lines the source snippets need
+	var taskInfos map[string]v1alpha1.TaskInfos
+	if sourceSnippetsEnabled {
+		taskInfos = kstatus.CollectFailedTasksLogSnippet(ctx, a.run, a.kinteract, pipelineRun, max(a.errorLogLines(), errorContentLogLines))
+	} else if contextConfig.ErrorContent {
+		taskInfos = kstatus.CollectFailedTasksLogSnippet(ctx, a.run, a.kinteract, pipelineRun, errorContentLogLines)
+	}
+
 	if contextConfig.ErrorContent {
-		if errorData := a.buildErrorContent(ctx, pipelineRun); errorData != nil {
+		if errorData := a.buildErrorContent(pipelineRun, taskInfos); errorData != nil {
 			contextData["errors"] = errorData
 		}
 	}
@@ -84,12 +106,47 @@ func (a *Assembler) BuildContext(
 		}
 	}
 
+	if sourceSnippetsEnabled {
+		if snippetData := a.buildSourceSnippets(ctx, taskInfos, event, provider, contextConfig.SourceSnippets); snippetData != nil {
+			contextData["source_snippets"] = snippetData
+		}
+	}
+
 	// Always include basic pipeline information
 	contextData["pipeline"] = a.buildBasicPipelineContext(pipelineRun, event)
 
 	return contextData, nil
 }
 
+// LogLines returns the number of lines of the logs of each failed task the
+// context of contextConfig is built from: the most of the lines of the error
+// content, of the lines the source snippets are detected in and of the lines
+// of the container logs.
+func (a *Assembler) LogLines(contextConfig *v1alpha1.ContextConfig) int64 {
+	lines := int64(errorContentLogLines)
+	if contextConfig == nil {
+		return lines
+	}
+	if contextConfig.SourceSnippets != nil && contextConfig.SourceSnippets.Enabled {
+		lines = max(lines, a.errorLogLines())
+	}
+	if contextConfig.ContainerLogs != nil && contextConfig.ContainerLogs.Enabled {
+		lines = max(lines, int64(contextConfig.ContainerLogs.GetMaxLines()))
+	}
+	return lines
+}
+
+// errorLogLines returns the number of lines of the logs of each failed task
+// the error locations of the source snippets are detected in, as many as the
+// error detection of the settings.
+func (a *Assembler) errorLogLines() int64 {
+	lines := settings.DefaultSettings().ErrorDetectionNumberOfLines
+	if a.run.Info.Pac != nil && a.run.Info.GetPacOpts().ErrorDetectionNumberOfLines > 0 {
+		lines = a.run.Info.GetPacOpts().ErrorDetectionNumberOfLines
+	}
+	return int64(lines)
+}
+
 // buildBasicPipelineContext creates basic pipeline context information.
 func (a *Assembler) buildBasicPipelineContext(pipelineRun *tektonv1.PipelineRun, event *info.Event) map[string]any {
 	pipelineData := map[string]any{
@@ -198,8 +255,10 @@ func (a *Assembler) buildPRContent(_ context.Context, event *info.Event, _ provi
 	return prData, nil
 }
 
-// buildErrorContent builds error and failure context information.
-func (a *Assembler) buildErrorContent(ctx context.Context, pipelineRun *tektonv1.PipelineRun) map[string]any {
+// buildErrorContent builds error and failure context information from the
+// condition of the PipelineRun and the infos of its failed tasks, with the last
+// errorContentLogLines lines of their logs.
+func (a *Assembler) buildErrorContent(pipelineRun *tektonv1.PipelineRun, taskInfos map[string]v1alpha1.TaskInfos) map[string]any {
 	if len(pipelineRun.Status.Conditions) == 0 {
 		return nil
 	}
@@ -214,8 +273,6 @@ func (a *Assembler) buildErrorContent(ctx context.Context, pipelineRun *tektonv1
 		"condition_message": condition.Message,
 	}
 
-	// Get detailed task failure information
-	taskInfos := kstatus.CollectFailedTasksLogSnippet(ctx, a.run, a.kinteract, pipelineRun, 3)
 	if len(taskInfos) > 0 {
 		sortedTaskInfos := sort.TaskInfos(taskInfos)
 
@@ -225,7 +282,7 @@ func (a *Assembler) buildErrorContent(ctx context.Context, pipelineRun *tektonv1
 				"name":        taskInfo.Name,
 				"reason":      taskInfo.Reason,
 				"message":     taskInfo.Message,
-				"log_snippet": taskInfo.LogSnippet,
+				"log_snippet": lastLines(taskInfo.LogSnippet, errorContentLogLines),
 			}
 
 			if taskInfo.DisplayName != "" {
@@ -245,6 +302,15 @@ func (a *Assembler) buildErrorContent(ctx context.Context, pipelineRun *tektonv1
 	return errorData
 }
 
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) <= n {
+		return s
+	}
+	return strings.Join(lines[len(lines)-n:], "\n")
+}
+
 // buildContainerLogs builds container logs context information.
 func (a *Assembler) buildContainerLogs(ctx context.Context, pipelineRun *tektonv1.PipelineRun, maxLines int) map[string]any {
 	// Get detailed task information with logs
@@ -273,6 +339,165 @@ func (a *Assembler) buildContainerLogs(ctx context.Context, pipelineRun *tektonv
 	}
 }
 
+// errorLocation is a location of an error in the source code of the
+// repository, detected in the logs of a failed task.
+type errorLocation struct {
+	taskName string
+	file     string
+	line     int
+	message  string
+}
+
+// buildSourceSnippets builds the source code context information around the
+// error locations detected in the logs of the failed tasks.
+func (a *Assembler) buildSourceSnippets(
+	ctx context.Context,
+	taskInfos map[string]v1alpha1.TaskInfos,
+	event *info.Event,
+	provider provider.Interface,
+	config *v1alpha1.SourceSnippetsConfig,
+) map[string]any {
+	if event == nil || event.SHA == "" || provider == nil {
+		return nil
+	}
+
+	pacopts := settings.DefaultSettings()
+	if a.run.Info.Pac != nil {
+		pacopts = a.run.Info.GetPacOpts().Settings
+	}
+	r, err := regexp.Compile(pacopts.ErrorDetectionSimpleRegexp)
+	if err != nil {
+		a.logger.Warnf("invalid regexp for detecting the error locations: %v", pacopts.ErrorDetectionSimpleRegexp)
+		return nil
+	}
+	if r.SubexpIndex("filename") == -1 || r.SubexpIndex("line") == -1 {
+		a.logger.Warnf("regexp for detecting the error locations does not contain a filename and a line regexp group: %v", pacopts.ErrorDetectionSimpleRegexp)
+		return nil
+	}
+
+	locations := errorLocations(sort.TaskInfos(taskInfos), r)
+	if len(locations) == 0 {
+		return nil
+	}
+	return a.sourceSnippets(ctx, event, provider, locations, config.GetContextLines(), config.GetMaxSize())
+}
+
+// errorLocations returns the error locations matched by the error detection
+// regexp in the logs of the failed tasks, without duplicates. The absolute
+// paths are skipped, they are not inside the repository.
+func errorLocations(taskInfos []v1alpha1.TaskInfos, r *regexp.Regexp) []errorLocation {
+	locations := []errorLocation{}
+	seen := map[string]bool{}
+	for _, taskInfo := range taskInfos {
+		for _, logLine := range strings.Split(taskInfo.LogSnippet, "\n") {
+			matches := r.FindStringSubmatch(logLine)
+			if matches == nil {
+				continue
+			}
+			file := strings.TrimPrefix(matches[r.SubexpIndex("filename")], "./")
+			if file == "" || strings.HasPrefix(file, "/") {
+				continue
+			}
+			line, err := strconv.Atoi(matches[r.SubexpIndex("line")])
+			if err != nil || line < 1 {
+				continue
+			}
+			key := fmt.Sprintf("%s:%d", file, line)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			location := errorLocation{taskName: taskInfo.Name, file: file, line: line}
+			if i := r.SubexpIndex("error"); i != -1 {
+				location.message = strings.TrimSpace(matches[i])
+			}
+			locations = append(locations, location)
+		}
+	}
+	return locations
+}
+
+// sourceSnippets fetches the files of the error locations at the SHA of the
+// event and returns the lines around each location. The snippets are added
+// until their total size reaches maxSize, to fit the context of the model, and
+// at most maxSourceSnippetFiles distinct files are fetched.
+func (a *Assembler) sourceSnippets(
+	ctx context.Context,
+	event *info.Event,
+	provider provider.Interface,
+	locations []errorLocation,
+	contextLines, maxSize int,
+) map[string]any {
+	// without a branch, some providers read the files from the head branch or,
+	// with the default_branch provenance, from the default branch, both are
+	// pinned on the SHA the snippets are reported for
+	shaEvent := *event
+	if event.SHA != "" {
+		shaEvent.HeadBranch = event.SHA
+		shaEvent.DefaultBranch = event.SHA
+	}
+
+	files := map[string][]string{}
+	snippets := []map[string]any{}
+	size := 0
+	truncated := false
+	for _, location := range locations {
+		lines, ok := files[location.file]
+		if !ok {
+			if len(files) >= maxSourceSnippetFiles {
+				truncated = true
+				continue
+			}
+			content, err := provider.GetFileInsideRepo(ctx, &shaEvent, location.file, "")
+			if err != nil {
+				// the file may come from a dependency or be generated during the run
+				a.logger.Debugf("cannot get file %s for the source snippets: %v", location.file, err)
+			} else {
+				lines = strings.Split(content, "\n")
+			}
+			files[location.file] = lines
+		}
+		if location.line > len(lines) {
+			continue
+		}
+
+		start := max(location.line-contextLines, 1)
+		end := min(location.line+contextLines, len(lines))
+		var code strings.Builder
+		for i := start; i <= end; i++ {
+			fmt.Fprintf(&code, "%d: %s\n", i, lines[i-1])
+		}
+		if size+code.Len() > maxSize {
+			truncated = true
+			break
+		}
+		size += code.Len()
+
+		snippet := map[string]any{
+			"task_name":  location.taskName,
+			"file":       location.file,
+			"line":       location.line,
+			"start_line": start,
+			"end_line":   end,
+			"code":       code.String(),
+		}
+		if location.message != "" {
+			snippet["error"] = location.message
+		}
+		snippets = append(snippets, snippet)
+	}
+	if len(snippets) == 0 {
+		return nil
+	}
+
+	return map[string]any{
+		"snippets":  snippets,
+		"sha":       event.SHA,
+		"truncated": truncated,
+	}
+}
+
 // BuildCELContext builds context data for CEL expression evaluation.
 //
 // This function exposes a carefully curated subset of event data to CEL expressions.
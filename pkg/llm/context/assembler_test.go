@@ -1,16 +1,23 @@
 package context
 
 import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"gotest.tools/v3/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -473,3 +480,209 @@ func TestBuildCommitContent(t *testing.T) {
 		assert.ErrorContains(t, err, "event is nil")
 	})
 }
+
+func TestErrorLocations(t *testing.T) {
+	r := regexp.MustCompile(settings.DefaultSettings().ErrorDetectionSimpleRegexp)
+	taskInfos := []v1alpha1.TaskInfos{
+		{
+			Name: "lint",
+			LogSnippet: strings.Join([]string{
+				"Running golangci-lint",
+				"./pkg/foo/foo.go:12: ineffectual assignment to err",
+				"pkg/foo/foo.go:12: ineffectual assignment to err",
+				"/usr/local/go/src/runtime/panic.go:770: panic",
+				"pkg/bar/bar.go:0: invalid line",
+			}, "\n"),
+		},
+		{
+			Name:       "unit",
+			LogSnippet: "pkg/bar/bar_test.go:42: expected 1, got 2",
+		},
+	}
+
+	locations := errorLocations(taskInfos, r)
+	assert.DeepEqual(t, locations, []errorLocation{
+		{taskName: "lint", file: "pkg/foo/foo.go", line: 12, message: "ineffectual assignment to err"},
+		{taskName: "unit", file: "pkg/bar/bar_test.go", line: 42, message: "expected 1, got 2"},
+	}, cmp.AllowUnexported(errorLocation{}))
+}
+
+func TestSourceSnippets(t *testing.T) {
+	logger, _ := logger.GetLogger()
+	assembler := NewAssembler(&params.Run{}, &kubeinteraction.Interaction{}, logger)
+	ctx, _ := rtesting.SetupFakeContext(t)
+	event := &info.Event{SHA: "abc123"}
+
+	lines := []string{}
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, "line "+strconv.Itoa(i))
+	}
+	provider := &testprovider.TestProviderImp{FilesInsideRepo: map[string]string{
+		"main.go":  strings.Join(lines, "\n"),
+		"short.go": "package short\nvar x = y\n",
+	}}
+	locations := []errorLocation{
+		{taskName: "build", file: "main.go", line: 3, message: "undefined: y"},
+		{taskName: "build", file: "missing.go", line: 1},
+		{taskName: "build", file: "short.go", line: 2},
+		{taskName: "build", file: "short.go", line: 10},
+	}
+
+	t.Run("snippets around the error locations", func(t *testing.T) {
+		data := assembler.sourceSnippets(ctx, event, provider, locations, 2, 1000)
+		assert.Equal(t, data["sha"], "abc123")
+		assert.Equal(t, data["truncated"], false)
+		snippets, ok := data["snippets"].([]map[string]any)
+		assert.Assert(t, ok)
+		assert.Equal(t, len(snippets), 2)
+
+		assert.Equal(t, snippets[0]["file"], "main.go")
+		assert.Equal(t, snippets[0]["error"], "undefined: y")
+		assert.Equal(t, snippets[0]["start_line"], 1)
+		assert.Equal(t, snippets[0]["end_line"], 5)
+		assert.Equal(t, snippets[0]["code"], "1: line 1\n2: line 2\n3: line 3\n4: line 4\n5: line 5\n")
+
+		assert.Equal(t, snippets[1]["file"], "short.go")
+		_, hasError := snippets[1]["error"]
+		assert.Assert(t, !hasError)
+		assert.Equal(t, snippets[1]["code"], "1: package short\n2: var x = y\n3: \n")
+	})
+
+	t.Run("total size capped", func(t *testing.T) {
+		data := assembler.sourceSnippets(ctx, event, provider, locations, 2, 60)
+		assert.Equal(t, data["truncated"], true)
+		snippets, ok := data["snippets"].([]map[string]any)
+		assert.Assert(t, ok)
+		assert.Equal(t, len(snippets), 1)
+		assert.Equal(t, snippets[0]["file"], "main.go")
+	})
+
+	t.Run("no snippet", func(t *testing.T) {
+		assert.Assert(t, assembler.sourceSnippets(ctx, event, provider, locations[1:2], 2, 1000) == nil)
+	})
+
+	t.Run("files fetched capped", func(t *testing.T) {
+		counting := &countingProvider{TestProviderImp: &testprovider.TestProviderImp{FilesInsideRepo: map[string]string{}}}
+		many := []errorLocation{}
+		for i := range maxSourceSnippetFiles + 2 {
+			file := fmt.Sprintf("file%d.go", i)
+			counting.FilesInsideRepo[file] = "package file"
+			many = append(many, errorLocation{taskName: "build", file: file, line: 1})
+		}
+		data := assembler.sourceSnippets(ctx, event, counting, many, 2, 1000)
+		assert.Equal(t, counting.fetched, maxSourceSnippetFiles)
+		assert.Equal(t, data["truncated"], true)
+		snippets, ok := data["snippets"].([]map[string]any)
+		assert.Assert(t, ok)
+		assert.Equal(t, len(snippets), maxSourceSnippetFiles)
+	})
+
+	t.Run("files fetched at the sha", func(t *testing.T) {
+		counting := &countingProvider{TestProviderImp: provider}
+		branchEvent := &info.Event{SHA: "abc123", HeadBranch: "feature", BaseBranch: "main", DefaultBranch: "main"}
+		data := assembler.sourceSnippets(ctx, branchEvent, counting, locations[:1], 2, 1000)
+		assert.Equal(t, data["sha"], "abc123")
+		assert.Equal(t, counting.event.SHA, "abc123")
+		assert.Equal(t, counting.event.HeadBranch, "abc123")
+		assert.Equal(t, counting.event.DefaultBranch, "abc123")
+		assert.Equal(t, counting.target, "")
+		assert.Equal(t, branchEvent.HeadBranch, "feature")
+		assert.Equal(t, branchEvent.DefaultBranch, "main")
+	})
+}
+
+// countingProvider counts the files fetched from the repository and records
+// the event and the target of the last fetch.
+type countingProvider struct {
+	*testprovider.TestProviderImp
+	fetched int
+	event   info.Event
+	target  string
+}
+
+func (c *countingProvider) GetFileInsideRepo(ctx context.Context, event *info.Event, file, target string) (string, error) {
+	c.fetched++
+	c.event, c.target = *event, target
+	return c.TestProviderImp.GetFileInsideRepo(ctx, event, file, target)
+}
+
+func TestLogLines(t *testing.T) {
+	logger, _ := logger.GetLogger()
+	tests := []struct {
+		name   string
+		pac    *info.PacOpts
+		config *v1alpha1.ContextConfig
+		want   int64
+	}{
+		{name: "no context", want: errorContentLogLines},
+		{
+			name:   "error content lines",
+			pac:    &info.PacOpts{Settings: settings.Settings{ErrorDetectionNumberOfLines: 10}},
+			config: &v1alpha1.ContextConfig{ErrorContent: true},
+			want:   errorContentLogLines,
+		},
+		{
+			name:   "source snippets error detection lines",
+			pac:    &info.PacOpts{Settings: settings.Settings{ErrorDetectionNumberOfLines: 10}},
+			config: &v1alpha1.ContextConfig{ErrorContent: true, SourceSnippets: &v1alpha1.SourceSnippetsConfig{Enabled: true}},
+			want:   10,
+		},
+		{
+			name: "source snippets default error detection lines",
+			config: &v1alpha1.ContextConfig{
+				SourceSnippets: &v1alpha1.SourceSnippetsConfig{Enabled: true},
+			},
+			want: int64(settings.DefaultSettings().ErrorDetectionNumberOfLines),
+		},
+		{
+			name:   "container logs",
+			pac:    &info.PacOpts{Settings: settings.Settings{ErrorDetectionNumberOfLines: 10}},
+			config: &v1alpha1.ContextConfig{ContainerLogs: &v1alpha1.ContainerLogsConfig{Enabled: true, MaxLines: 100}},
+			want:   100,
+		},
+		{
+			name: "source snippets with more lines than the container logs",
+			pac:  &info.PacOpts{Settings: settings.Settings{ErrorDetectionNumberOfLines: 100}},
+			config: &v1alpha1.ContextConfig{
+				ContainerLogs:  &v1alpha1.ContainerLogsConfig{Enabled: true, MaxLines: 20},
+				SourceSnippets: &v1alpha1.SourceSnippetsConfig{Enabled: true},
+			},
+			want: 100,
+		},
+		{
+			name:   "container logs default lines",
+			config: &v1alpha1.ContextConfig{ContainerLogs: &v1alpha1.ContainerLogsConfig{Enabled: true}},
+			want:   DefaultMaxLogLines,
+		},
+		{
+			name:   "disabled container logs",
+			pac:    &info.PacOpts{Settings: settings.Settings{ErrorDetectionNumberOfLines: 10}},
+			config: &v1alpha1.ContextConfig{ContainerLogs: &v1alpha1.ContainerLogsConfig{MaxLines: 100}},
+			want:   errorContentLogLines,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssembler(&params.Run{Info: info.Info{Pac: tt.pac}}, &kubeinteraction.Interaction{}, logger)
+			assert.Equal(t, assembler.LogLines(tt.config), tt.want)
+		})
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "empty", s: "", want: ""},
+		{name: "fewer lines", s: "a\nb", want: "a\nb"},
+		{name: "as many lines", s: "a\nb\nc", want: "a\nb\nc"},
+		{name: "more lines", s: "a\nb\nc\nd\ne", want: "c\nd\ne"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, lastLines(tt.s, errorContentLogLines), tt.want)
+		})
+	}
+}
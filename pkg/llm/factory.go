@@ -8,7 +8,9 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	llmtypes "github.com/openshift-pipelines/pipelines-as-code/pkg/llm/ltypes"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/providers/anthropic"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/providers/gemini"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/providers/ollama"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/providers/openai"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
@@ -48,9 +50,14 @@ func (f *Factory) CreateClient(ctx context.Context, config *ClientConfig, namesp
 		return nil, fmt.Errorf("invalid client configuration: %w", err)
 	}
 
-	token, err := f.getTokenFromSecret(ctx, config.TokenSecretRef, namespace)
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve LLM token: %w", err)
+	// the token is optional for the providers which do not require one
+	token := ""
+	if config.TokenSecretRef != nil {
+		var err error
+		token, err = f.getTokenFromSecret(ctx, config.TokenSecretRef, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve LLM token: %w", err)
+		}
 	}
 
 	timeoutSeconds, maxTokens := f.applyDefaults(config.TimeoutSeconds, config.MaxTokens)
@@ -78,10 +85,10 @@ func (f *Factory) ValidateConfig(config *ClientConfig) error {
 		return fmt.Errorf("LLM provider is required")
 	}
 
-	if config.TokenSecretRef == nil {
+	if config.TokenSecretRef == nil && config.Provider.RequiresToken() {
 		return fmt.Errorf("token secret reference is required")
 	}
-	if config.TokenSecretRef.Name == "" {
+	if config.TokenSecretRef != nil && config.TokenSecretRef.Name == "" {
 		return fmt.Errorf("token secret name is required")
 	}
 
@@ -111,6 +118,8 @@ func (f *Factory) GetSupportedProviders() []llmtypes.AIProvider {
 	return []llmtypes.AIProvider{
 		llmtypes.LLMProviderOpenAI,
 		llmtypes.LLMProviderGemini,
+		llmtypes.LLMProviderAnthropic,
+		llmtypes.LLMProviderOllama,
 	}
 }
 
@@ -211,6 +220,28 @@ func (f *Factory) createProviderClient(provider llmtypes.AIProvider, token, base
 			config.BaseURL = baseURL
 		}
 		return gemini.NewClient(config)
+	case llmtypes.LLMProviderAnthropic:
+		config := &anthropic.Config{
+			APIKey:         token,
+			Model:          model,
+			TimeoutSeconds: timeoutSeconds,
+			MaxTokens:      maxTokens,
+		}
+		if baseURL != "" {
+			config.BaseURL = baseURL
+		}
+		return anthropic.NewClient(config)
+	case llmtypes.LLMProviderOllama:
+		config := &ollama.Config{
+			APIKey:         token,
+			Model:          model,
+			TimeoutSeconds: timeoutSeconds,
+			MaxTokens:      maxTokens,
+		}
+		if baseURL != "" {
+			config.BaseURL = baseURL
+		}
+		return ollama.NewClient(config)
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", provider)
 	}
@@ -243,6 +274,10 @@ func getDefaultModel(provider llmtypes.AIProvider) string {
 		return "gpt-5-mini"
 	case llmtypes.LLMProviderGemini:
 		return "gemini-2.5-flash-lite"
+	case llmtypes.LLMProviderAnthropic:
+		return "claude-haiku-4-5"
+	case llmtypes.LLMProviderOllama:
+		return "llama3.2"
 	default:
 		return ""
 	}
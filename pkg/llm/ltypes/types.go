@@ -45,10 +45,18 @@ func (e *AnalysisError) Error() string {
 type AIProvider string
 
 const (
-	LLMProviderOpenAI AIProvider = "openai"
-	LLMProviderGemini AIProvider = "gemini"
+	LLMProviderOpenAI    AIProvider = "openai"
+	LLMProviderGemini    AIProvider = "gemini"
+	LLMProviderAnthropic AIProvider = "anthropic"
+	LLMProviderOllama    AIProvider = "ollama"
 )
 
+// RequiresToken returns true when the provider needs an API token, a local
+// Ollama server does not.
+func (p AIProvider) RequiresToken() bool {
+	return p != LLMProviderOllama
+}
+
 // Config holds the default configuration values.
 type Config struct {
 	TimeoutSeconds int
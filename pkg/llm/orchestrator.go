@@ -17,27 +17,33 @@ import (
 type Orchestrator struct {
 	run           *params.Run
 	kinteract     kubeinteraction.Interface
+	cache         *AnalysisCache
 	logger        *zap.SugaredLogger
 	outputHandler *OutputHandler
 }
 
-// NewOrchestrator creates a new LLM analysis orchestrator.
-func NewOrchestrator(run *params.Run, kinteract kubeinteraction.Interface, logger *zap.SugaredLogger) *Orchestrator {
+// NewOrchestrator creates a new LLM analysis orchestrator, the analyses of
+// the failures are reused from the cache when it is not nil.
+func NewOrchestrator(run *params.Run, kinteract kubeinteraction.Interface, cache *AnalysisCache, logger *zap.SugaredLogger) *Orchestrator {
 	return &Orchestrator{
 		run:           run,
 		kinteract:     kinteract,
+		cache:         cache,
 		logger:        logger,
 		outputHandler: NewOutputHandler(run, logger),
 	}
 }
 
-// ExecuteAnalysis performs the complete LLM analysis workflow.
+// ExecuteAnalysis performs the complete LLM analysis workflow, status is the
+// final status reported for the PipelineRun which the check-run output
+// updates.
 func (o *Orchestrator) ExecuteAnalysis(
 	ctx context.Context,
 	repo *v1alpha1.Repository,
 	pr *tektonv1.PipelineRun,
 	event *info.Event,
 	prov provider.Interface,
+	status *provider.StatusOpts,
 ) error {
 	if repo.Spec.Settings == nil || repo.Spec.Settings.AIAnalysis == nil || !repo.Spec.Settings.AIAnalysis.Enabled {
 		o.logger.Debug("AI analysis not configured or disabled, skipping")
@@ -47,7 +53,7 @@ func (o *Orchestrator) ExecuteAnalysis(
 	o.logger.Infof("Starting LLM analysis for pipeline %s/%s", pr.Namespace, pr.Name)
 
 	// Create LLM analyzer
-	analyzer := NewAnalyzer(o.run, o.kinteract, o.logger)
+	analyzer := NewAnalyzer(o.run, o.kinteract, o.cache, o.logger)
 
 	// Create analysis request
 	request := &AnalyzeRequest{
@@ -82,7 +88,7 @@ func (o *Orchestrator) ExecuteAnalysis(
 
 		o.logger.Infof("Processing LLM analysis result for role %s, tokens used: %d", result.Role, result.Response.TokensUsed)
 
-		if err := o.outputHandler.HandleOutput(ctx, repo, pr, result, event, prov); err != nil {
+		if err := o.outputHandler.HandleOutput(ctx, repo, pr, result, event, prov, status); err != nil {
 			o.logger.Warnf("Failed to handle output for role %s: %v", result.Role, err)
 			// Continue processing other results even if one fails
 		}
@@ -2,16 +2,27 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 )
 
+// maxStatusDescriptionLength is the length of the description of a commit
+// status accepted by all the git providers.
+const maxStatusDescriptionLength = 140
+
 // OutputHandler handles the output of LLM analysis results to various destinations.
 type OutputHandler struct {
 	run    *params.Run
@@ -26,8 +37,11 @@ func NewOutputHandler(run *params.Run, logger *zap.SugaredLogger) *OutputHandler
 	}
 }
 
-// HandleOutput processes the LLM analysis output according to the role configuration.
-func (h *OutputHandler) HandleOutput(ctx context.Context, repo *v1alpha1.Repository, _ *tektonv1.PipelineRun, result AnalysisResult, event *info.Event, prov provider.Interface) error {
+// HandleOutput processes the LLM analysis output according to the role
+// configuration, the result is sent to every output destination of the role
+// even when sending it to one of them fails. status is the final status
+// reported for the PipelineRun, nil when it was not reported.
+func (h *OutputHandler) HandleOutput(ctx context.Context, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun, result AnalysisResult, event *info.Event, prov provider.Interface, status *provider.StatusOpts) error {
 	if repo.Spec.Settings == nil || repo.Spec.Settings.AIAnalysis == nil {
 		return fmt.Errorf("AI analysis configuration is nil")
 	}
@@ -45,12 +59,34 @@ func (h *OutputHandler) HandleOutput(ctx context.Context, repo *v1alpha1.Reposit
 		return fmt.Errorf("role configuration not found for %s", result.Role)
 	}
 
-	output := roleConfig.GetOutput()
-	if output != "pr-comment" {
-		return fmt.Errorf("unsupported output destination: %s (only 'pr-comment' is currently supported)", output)
+	var errs []error
+	for _, output := range roleConfig.GetOutputs() {
+		var err error
+		switch output {
+		case v1alpha1.AnalysisOutputPRComment:
+			err = h.postPRComment(ctx, result, event, prov)
+		case v1alpha1.AnalysisOutputCommitComment:
+			err = h.postCommitComment(ctx, result, event, prov)
+		case v1alpha1.AnalysisOutputCheckRun:
+			err = h.updateCheckRun(ctx, result, event, prov, status)
+		case v1alpha1.AnalysisOutputRepositoryStatus:
+			err = h.updateRepositoryStatus(ctx, repo, pr, result)
+		case v1alpha1.AnalysisOutputPipelineRunAnnotation:
+			err = h.annotatePipelineRun(ctx, pr, result)
+		default:
+			err = fmt.Errorf("unsupported output destination: %s", output)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", output, err))
+		}
 	}
+	return errors.Join(errs...)
+}
 
-	return h.postPRComment(ctx, result, event, prov)
+// formatComment formats the LLM analysis as a comment.
+func formatComment(result AnalysisResult) string {
+	return fmt.Sprintf("## 🤖 AI Analysis - %s\n\n%s\n\n---\n*Generated by Pipelines-as-Code LLM Analysis*",
+		result.Role, result.Response.Content)
 }
 
 // postPRComment posts LLM analysis as a PR comment.
@@ -60,17 +96,129 @@ func (h *OutputHandler) postPRComment(ctx context.Context, result AnalysisResult
 		return nil
 	}
 
-	// Format the comment with LLM analysis
-	comment := fmt.Sprintf("## 🤖 AI Analysis - %s\n\n%s\n\n---\n*Generated by Pipelines-as-Code LLM Analysis*",
-		result.Role, result.Response.Content)
-
 	// Create a unique marker for this analysis role to allow updates
 	updateMarker := fmt.Sprintf("llm-analysis-%s", result.Role)
 
-	if err := prov.CreateComment(ctx, event, comment, updateMarker); err != nil {
+	if err := prov.CreateComment(ctx, event, formatComment(result), updateMarker); err != nil {
 		return fmt.Errorf("failed to create PR comment: %w", err)
 	}
 
 	h.logger.Infof("Posted LLM analysis as PR comment for role %s", result.Role)
 	return nil
 }
+
+// postCommitComment posts LLM analysis as a comment on the commit of the
+// event, for the push events which have no pull request to comment on.
+func (h *OutputHandler) postCommitComment(ctx context.Context, result AnalysisResult, event *info.Event, prov provider.Interface) error {
+	if event.SHA == "" {
+		h.logger.Debug("No commit associated with this event, skipping commit comment")
+		return nil
+	}
+
+	if err := prov.CreateCommitComment(ctx, event, formatComment(result)); err != nil {
+		return fmt.Errorf("failed to create commit comment: %w", err)
+	}
+
+	h.logger.Infof("Posted LLM analysis as commit comment on %s for role %s", event.SHA, result.Role)
+	return nil
+}
+
+// updateCheckRun adds LLM analysis to the text of the check run of the
+// PipelineRun, or to the description of its commit status on the providers
+// without check runs. The conclusion of the PipelineRun is left alone and
+// nothing is commented on the pull request, status is the final status
+// reported for the PipelineRun.
+func (h *OutputHandler) updateCheckRun(ctx context.Context, result AnalysisResult, event *info.Event, prov provider.Interface, status *provider.StatusOpts) error {
+	if status == nil {
+		h.logger.Debug("No final status reported for this pipelinerun, skipping check run")
+		return nil
+	}
+
+	if err := prov.UpdateStatusDetails(ctx, event, *status, formatComment(result), statusDescription(result)); err != nil {
+		return fmt.Errorf("failed to update check run: %w", err)
+	}
+
+	h.logger.Infof("Added LLM analysis to the check run of pipelinerun %s for role %s", status.PipelineRunName, result.Role)
+	return nil
+}
+
+// statusDescription formats the LLM analysis as the description of a commit
+// status, on a single line and shortened to what the git providers accept.
+func statusDescription(result AnalysisResult) string {
+	description := fmt.Sprintf("AI Analysis - %s: %s", result.Role, strings.Join(strings.Fields(result.Response.Content), " "))
+	if runes := []rune(description); len(runes) > maxStatusDescriptionLength {
+		description = string(runes[:maxStatusDescriptionLength-1]) + "…"
+	}
+	return description
+}
+
+// updateRepositoryStatus stores LLM analysis in the run status of the
+// PipelineRun in the Repository.
+func (h *OutputHandler) updateRepositoryStatus(ctx context.Context, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun, result AnalysisResult) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lastrepo, err := h.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Get(ctx, repo.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		index := -1
+		for i := range lastrepo.Status {
+			if lastrepo.Status[i].PipelineRunName == pr.GetName() {
+				index = i
+			}
+		}
+		if index == -1 {
+			return fmt.Errorf("no run status for pipelinerun %s in repository %s/%s", pr.GetName(), repo.GetNamespace(), repo.GetName())
+		}
+
+		if lastrepo.Status[index].AIAnalysis == nil {
+			lastrepo.Status[index].AIAnalysis = map[string]string{}
+		}
+		lastrepo.Status[index].AIAnalysis[result.Role] = result.Response.Content
+		_, err = h.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(lastrepo.GetNamespace()).Update(ctx, lastrepo, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update repository status: %w", err)
+	}
+
+	h.logger.Infof("Stored LLM analysis in the status of repository %s/%s for role %s", repo.GetNamespace(), repo.GetName(), result.Role)
+	return nil
+}
+
+// annotatePipelineRun stores LLM analysis in the ai-analysis annotation of
+// the PipelineRun, a JSON object of the analysis by role name.
+func (h *OutputHandler) annotatePipelineRun(ctx context.Context, pr *tektonv1.PipelineRun, result AnalysisResult) error {
+	// get the PipelineRun again to keep the analysis of the other roles
+	lastpr, err := h.run.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).Get(ctx, pr.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get pipelinerun: %w", err)
+	}
+
+	analysis := map[string]string{}
+	if value := lastpr.GetAnnotations()[keys.AIAnalysis]; value != "" {
+		if err := json.Unmarshal([]byte(value), &analysis); err != nil {
+			h.logger.Warnf("Overwriting invalid %s annotation of pipelinerun %s: %v", keys.AIAnalysis, pr.GetName(), err)
+			analysis = map[string]string{}
+		}
+	}
+	analysis[result.Role] = result.Response.Content
+	value, err := json.Marshal(analysis)
+	if err != nil {
+		return err
+	}
+
+	mergePatch := map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{
+				keys.AIAnalysis: string(value),
+			},
+		},
+	}
+	if _, err := action.PatchPipelineRun(ctx, h.logger, "ai analysis", h.run.Clients.Tekton, lastpr, mergePatch); err != nil {
+		return fmt.Errorf("failed to annotate pipelinerun: %w", err)
+	}
+
+	h.logger.Infof("Stored LLM analysis in the annotations of pipelinerun %s for role %s", pr.GetName(), result.Role)
+	return nil
+}
@@ -1,4 +1,256 @@
 package llm
 
-// Tests for output handler removed - only pr-comment output is currently supported
-// Annotation and check-run outputs were removed and will be reintroduced in the future
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/ltypes"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestHandleOutput(t *testing.T) {
+	tests := []struct {
+		name                  string
+		role                  v1alpha1.AnalysisRole
+		event                 *info.Event
+		annotations           map[string]string
+		wantErr               string
+		wantComments          int
+		wantCommitComments    int
+		wantCheckRun          bool
+		wantRepositoryStatus  map[string]string
+		wantAnnotation        map[string]string
+		withoutRepositoryRuns bool
+	}{
+		{
+			name:         "default pr comment",
+			role:         v1alpha1.AnalysisRole{Name: "failure-analysis"},
+			event:        &info.Event{SHA: "sha", PullRequestNumber: 1},
+			wantComments: 1,
+		},
+		{
+			name:  "pr comment skipped on push",
+			role:  v1alpha1.AnalysisRole{Name: "failure-analysis", Output: v1alpha1.AnalysisOutputPRComment},
+			event: &info.Event{SHA: "sha"},
+		},
+		{
+			name:               "commit comment",
+			role:               v1alpha1.AnalysisRole{Name: "failure-analysis", Output: v1alpha1.AnalysisOutputCommitComment},
+			event:              &info.Event{SHA: "sha"},
+			wantCommitComments: 1,
+		},
+		{
+			name:         "check run",
+			role:         v1alpha1.AnalysisRole{Name: "failure-analysis", Output: v1alpha1.AnalysisOutputCheckRun},
+			event:        &info.Event{SHA: "sha"},
+			wantCheckRun: true,
+		},
+		{
+			name:                 "repository status",
+			role:                 v1alpha1.AnalysisRole{Name: "failure-analysis", Output: v1alpha1.AnalysisOutputRepositoryStatus},
+			event:                &info.Event{SHA: "sha"},
+			wantRepositoryStatus: map[string]string{"failure-analysis": "the analysis"},
+		},
+		{
+			name:                  "repository status without run status",
+			role:                  v1alpha1.AnalysisRole{Name: "failure-analysis", Output: v1alpha1.AnalysisOutputRepositoryStatus},
+			event:                 &info.Event{SHA: "sha"},
+			withoutRepositoryRuns: true,
+			wantErr:               "no run status for pipelinerun pr-build-aaaaa",
+		},
+		{
+			name:           "pipelinerun annotation",
+			role:           v1alpha1.AnalysisRole{Name: "failure-analysis", Output: v1alpha1.AnalysisOutputPipelineRunAnnotation},
+			event:          &info.Event{SHA: "sha"},
+			wantAnnotation: map[string]string{"failure-analysis": "the analysis"},
+		},
+		{
+			name:           "pipelinerun annotation keeps the other roles",
+			role:           v1alpha1.AnalysisRole{Name: "failure-analysis", Output: v1alpha1.AnalysisOutputPipelineRunAnnotation},
+			event:          &info.Event{SHA: "sha"},
+			annotations:    map[string]string{keys.AIAnalysis: `{"security":"no issue"}`},
+			wantAnnotation: map[string]string{"failure-analysis": "the analysis", "security": "no issue"},
+		},
+		{
+			name: "multiple outputs",
+			role: v1alpha1.AnalysisRole{
+				Name:    "failure-analysis",
+				Output:  v1alpha1.AnalysisOutputPRComment,
+				Outputs: []string{v1alpha1.AnalysisOutputPRComment, v1alpha1.AnalysisOutputCommitComment, v1alpha1.AnalysisOutputCheckRun, v1alpha1.AnalysisOutputPipelineRunAnnotation},
+			},
+			event:              &info.Event{SHA: "sha", PullRequestNumber: 1},
+			wantComments:       1,
+			wantCommitComments: 1,
+			wantCheckRun:       true,
+			wantAnnotation:     map[string]string{"failure-analysis": "the analysis"},
+		},
+		{
+			name:         "unsupported output does not stop the other outputs",
+			role:         v1alpha1.AnalysisRole{Name: "failure-analysis", Outputs: []string{"annotation", v1alpha1.AnalysisOutputPRComment}},
+			event:        &info.Event{SHA: "sha", PullRequestNumber: 1},
+			wantErr:      "unsupported output destination: annotation",
+			wantComments: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+
+			pr := &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pr-build-aaaaa",
+					Namespace:   "ns",
+					Annotations: map[string]string{keys.OriginalPRName: "pr-build"},
+				},
+			}
+			for k, v := range tt.annotations {
+				pr.Annotations[k] = v
+			}
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec: v1alpha1.RepositorySpec{
+					Settings: &v1alpha1.Settings{
+						AIAnalysis: &v1alpha1.AIAnalysisConfig{
+							Enabled:  true,
+							Provider: "openai",
+							Roles:    []v1alpha1.AnalysisRole{tt.role},
+						},
+					},
+				},
+			}
+			if !tt.withoutRepositoryRuns {
+				repo.Status = []v1alpha1.RepositoryRunStatus{
+					{PipelineRunName: "pr-build-zzzzz"},
+					{PipelineRunName: pr.GetName()},
+				}
+			}
+
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces:   []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}},
+				Repositories: []*v1alpha1.Repository{repo},
+				PipelineRuns: []*tektonv1.PipelineRun{pr},
+			})
+			run := &params.Run{
+				Clients: clients.Clients{
+					PipelineAsCode: stdata.PipelineAsCode,
+					Tekton:         stdata.Pipeline,
+					Kube:           stdata.Kube,
+				},
+			}
+			run.Clients.SetConsoleUI(consoleui.FallBackConsole{})
+			prov := &testprovider.TestProviderImp{}
+
+			result := AnalysisResult{
+				Role:     "failure-analysis",
+				Response: &ltypes.AnalysisResponse{Content: "the analysis"},
+			}
+			status := &provider.StatusOpts{
+				Status:                  "completed",
+				Conclusion:              "failure",
+				Text:                    "the task failed",
+				PipelineRunName:         pr.GetName(),
+				OriginalPipelineRunName: "pr-build",
+			}
+			err := NewOutputHandler(run, logger).HandleOutput(ctx, repo, pr, result, tt.event, prov, status)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+			}
+
+			assert.Equal(t, len(prov.CreatedComments), tt.wantComments)
+			assert.Equal(t, len(prov.CreatedCommitComments), tt.wantCommitComments)
+			for _, comment := range append(prov.CreatedComments, prov.CreatedCommitComments...) {
+				assert.Assert(t, comment != "")
+			}
+
+			// the status is never reported again, it would be commented again
+			assert.Assert(t, prov.CreatedStatus == nil)
+			if tt.wantCheckRun {
+				assert.Assert(t, prov.UpdatedStatus != nil)
+				// the details of the status of the PipelineRun are updated
+				assert.Equal(t, prov.UpdatedStatus.Conclusion, "failure")
+				assert.Equal(t, prov.UpdatedStatus.PipelineRunName, "pr-build-aaaaa")
+				assert.Equal(t, prov.UpdatedStatus.OriginalPipelineRunName, "pr-build")
+				assert.DeepEqual(t, prov.StatusTexts, []string{formatComment(result)})
+				assert.Equal(t, prov.StatusDescription, "AI Analysis - failure-analysis: the analysis")
+			} else {
+				assert.Assert(t, prov.UpdatedStatus == nil)
+			}
+
+			if !tt.withoutRepositoryRuns {
+				got, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+				assert.NilError(t, err)
+				assert.Assert(t, got.Status[0].AIAnalysis == nil)
+				assert.DeepEqual(t, got.Status[1].AIAnalysis, tt.wantRepositoryStatus)
+			}
+
+			got, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").Get(ctx, pr.GetName(), metav1.GetOptions{})
+			assert.NilError(t, err)
+			if tt.wantAnnotation == nil {
+				assert.Equal(t, got.GetAnnotations()[keys.AIAnalysis], tt.annotations[keys.AIAnalysis])
+			} else {
+				annotation := map[string]string{}
+				assert.NilError(t, json.Unmarshal([]byte(got.GetAnnotations()[keys.AIAnalysis]), &annotation))
+				assert.DeepEqual(t, annotation, tt.wantAnnotation)
+			}
+		})
+	}
+}
+
+func TestHandleOutputCheckRunRoles(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr-build-aaaaa", Namespace: "ns"}}
+	roles := []v1alpha1.AnalysisRole{
+		{Name: "failure-analysis", Output: v1alpha1.AnalysisOutputCheckRun},
+		{Name: "security", Output: v1alpha1.AnalysisOutputCheckRun},
+	}
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec: v1alpha1.RepositorySpec{
+			Settings: &v1alpha1.Settings{AIAnalysis: &v1alpha1.AIAnalysisConfig{Enabled: true, Provider: "openai", Roles: roles}},
+		},
+	}
+	handler := NewOutputHandler(&params.Run{}, logger)
+	prov := &testprovider.TestProviderImp{}
+	event := &info.Event{SHA: "sha"}
+
+	// the check run is left alone when the final status was not reported
+	result := AnalysisResult{Role: "failure-analysis", Response: &ltypes.AnalysisResponse{Content: "the analysis"}}
+	assert.NilError(t, handler.HandleOutput(ctx, repo, pr, result, event, prov, nil))
+	assert.Assert(t, prov.UpdatedStatus == nil)
+
+	// the analysis of each role is added once to the text of the check run
+	status := &provider.StatusOpts{Conclusion: "failure", Text: "the task failed", PipelineRunName: pr.GetName()}
+	assert.NilError(t, handler.HandleOutput(ctx, repo, pr, result, event, prov, status))
+	security := AnalysisResult{Role: "security", Response: &ltypes.AnalysisResponse{Content: "no issue\n\n" + strings.Repeat("a long explanation ", 20)}}
+	assert.NilError(t, handler.HandleOutput(ctx, repo, pr, security, event, prov, status))
+	assert.Assert(t, prov.CreatedStatus == nil)
+	assert.DeepEqual(t, prov.StatusTexts, []string{formatComment(result), formatComment(security)})
+
+	// the description of the commit status is on a single line and shortened
+	assert.Equal(t, len([]rune(prov.StatusDescription)), maxStatusDescriptionLength)
+	assert.Assert(t, strings.HasPrefix(prov.StatusDescription, "AI Analysis - security: no issue a long explanation"))
+	assert.Assert(t, strings.HasSuffix(prov.StatusDescription, "…"))
+}
@@ -0,0 +1,274 @@
+// Package anthropic is the Client implementation for the Anthropic Messages
+// API and the services compatible with it.
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/ltypes"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/providers"
+)
+
+const (
+	defaultBaseURL = "https://api.anthropic.com/v1"
+	defaultModel   = "claude-haiku-4-5"
+
+	// apiVersion is the version of the Messages API sent in the
+	// anthropic-version header.
+	apiVersion = "2023-06-01"
+)
+
+// Config holds the configuration for Anthropic client.
+type Config struct {
+	APIKey         string
+	BaseURL        string
+	Model          string
+	TimeoutSeconds int
+	MaxTokens      int
+	HTTPClient     *http.Client
+}
+
+// Client implements the LLM interface for Anthropic.
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+}
+
+// NewClient creates a new Anthropic client.
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+
+	commonCfg := &providers.CommonConfig{
+		APIKey:         config.APIKey,
+		TimeoutSeconds: config.TimeoutSeconds,
+		MaxTokens:      config.MaxTokens,
+	}
+	if err := providers.ApplyDefaults(commonCfg); err != nil {
+		return nil, err
+	}
+
+	config.TimeoutSeconds = commonCfg.TimeoutSeconds
+	config.MaxTokens = commonCfg.MaxTokens
+
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if config.Model == "" {
+		config.Model = defaultModel
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	if config.TimeoutSeconds > 0 && httpClient.Timeout == 0 {
+		httpClient.Timeout = time.Duration(config.TimeoutSeconds) * time.Second
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+	}, nil
+}
+
+// Analyze sends an analysis request to the Messages API and returns the
+// response.
+func (c *Client) Analyze(ctx context.Context, request *ltypes.AnalysisRequest) (*ltypes.AnalysisResponse, error) {
+	startTime := time.Now()
+
+	fullPrompt, err := providers.BuildPrompt(request)
+	if err != nil {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "prompt_build_error",
+			Message:   fmt.Sprintf("failed to build prompt: %v", err),
+			Retryable: false,
+		}
+	}
+
+	// max_tokens is required by the Messages API
+	maxTokens := request.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = c.config.MaxTokens
+	}
+	apiRequest := &anthropicRequest{
+		Model:     c.config.Model,
+		MaxTokens: maxTokens,
+		Messages: []anthropicMessage{
+			{
+				Role:    "user",
+				Content: fullPrompt,
+			},
+		},
+	}
+
+	requestBody, err := json.Marshal(apiRequest)
+	if err != nil {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "request_marshal_error",
+			Message:   fmt.Sprintf("failed to marshal request: %v", err),
+			Retryable: false,
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+"/messages", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "http_request_error",
+			Message:   fmt.Sprintf("failed to create HTTP request: %v", err),
+			Retryable: false,
+		}
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", c.config.APIKey)
+	httpReq.Header.Set("anthropic-version", apiVersion)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "http_error",
+			Message:   fmt.Sprintf("HTTP request failed: %v", err),
+			Retryable: true,
+		}
+	}
+	defer resp.Body.Close()
+
+	var apiResponse anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "response_parse_error",
+			Message:   fmt.Sprintf("failed to parse response: %v", err),
+			Retryable: false,
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errorType := "api_error"
+		retryable := false
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			errorType = "rate_limit_exceeded"
+			retryable = true
+		case resp.StatusCode == http.StatusUnauthorized:
+			errorType = "invalid_api_key"
+			retryable = false
+		case resp.StatusCode >= 500:
+			// 529 is returned when the API is overloaded
+			errorType = "server_error"
+			retryable = true
+		}
+
+		errorMsg := fmt.Sprintf("Anthropic API error (status %d)", resp.StatusCode)
+		if apiResponse.Error != nil {
+			errorMsg = fmt.Sprintf("Anthropic API error: %s", apiResponse.Error.Message)
+		}
+
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      errorType,
+			Message:   errorMsg,
+			Retryable: retryable,
+		}
+	}
+
+	var content strings.Builder
+	for _, block := range apiResponse.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+	if content.Len() == 0 {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "empty_response",
+			Message:   "no text content in API response",
+			Retryable: false,
+		}
+	}
+
+	return &ltypes.AnalysisResponse{
+		Content:    content.String(),
+		TokensUsed: apiResponse.Usage.InputTokens + apiResponse.Usage.OutputTokens,
+		Provider:   c.GetProviderName(),
+		Timestamp:  time.Now(),
+		Duration:   time.Since(startTime),
+	}, nil
+}
+
+// GetProviderName returns the provider name.
+func (c *Client) GetProviderName() string {
+	return string(ltypes.LLMProviderAnthropic)
+}
+
+// ValidateConfig validates the client configuration.
+func (c *Client) ValidateConfig() error {
+	commonCfg := &providers.CommonConfig{
+		APIKey:         c.config.APIKey,
+		TimeoutSeconds: c.config.TimeoutSeconds,
+		MaxTokens:      c.config.MaxTokens,
+	}
+	if err := providers.ValidateCommonConfig(commonCfg); err != nil {
+		return err
+	}
+
+	return providers.ValidateBaseURL(c.config.BaseURL)
+}
+
+// Anthropic Messages API request/response structures
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	Messages  []anthropicMessage `json:"messages"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	ID         string             `json:"id"`
+	Type       string             `json:"type"`
+	Role       string             `json:"role"`
+	Model      string             `json:"model"`
+	Content    []anthropicContent `json:"content"`
+	StopReason string             `json:"stop_reason"`
+	Usage      anthropicUsage     `json:"usage"`
+	Error      *anthropicError    `json:"error,omitempty"`
+}
+
+type anthropicContent struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
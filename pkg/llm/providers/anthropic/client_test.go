@@ -0,0 +1,266 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/ltypes"
+	httptesting "github.com/openshift-pipelines/pipelines-as-code/pkg/test/http"
+	"gotest.tools/v3/assert"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *Config
+		wantError bool
+		errMsg    string
+	}{
+		{
+			name:      "nil config",
+			config:    nil,
+			wantError: true,
+			errMsg:    "config is required",
+		},
+		{
+			name: "empty api key",
+			config: &Config{
+				APIKey: "",
+			},
+			wantError: true,
+			errMsg:    "API key is required",
+		},
+		{
+			name: "valid config with defaults",
+			config: &Config{
+				APIKey: "test-key",
+			},
+			wantError: false,
+		},
+		{
+			name: "custom config",
+			config: &Config{
+				APIKey:         "test-key",
+				BaseURL:        "https://custom.url/v1/",
+				Model:          "claude-sonnet-4-5",
+				TimeoutSeconds: 60,
+				MaxTokens:      2000,
+			},
+			wantError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.config)
+
+			if tt.wantError {
+				assert.ErrorContains(t, err, tt.errMsg)
+				assert.Assert(t, client == nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, client.GetProviderName(), "anthropic")
+			if tt.config.BaseURL == "" {
+				assert.Equal(t, client.config.BaseURL, defaultBaseURL)
+			} else {
+				assert.Assert(t, !strings.HasSuffix(client.config.BaseURL, "/"))
+			}
+			if tt.config.Model == "" {
+				assert.Equal(t, client.config.Model, defaultModel)
+			}
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr string
+	}{
+		{
+			name: "valid config",
+			config: &Config{
+				APIKey:         "valid-key",
+				TimeoutSeconds: 30,
+				MaxTokens:      1000,
+			},
+		},
+		{
+			name: "negative timeout",
+			config: &Config{
+				APIKey:         "valid-key",
+				TimeoutSeconds: -1,
+				MaxTokens:      1000,
+			},
+			wantErr: "timeout seconds must be non-negative",
+		},
+		{
+			name: "negative max tokens",
+			config: &Config{
+				APIKey:         "valid-key",
+				TimeoutSeconds: 30,
+				MaxTokens:      -1,
+			},
+			wantErr: "max tokens must be non-negative",
+		},
+		{
+			name: "invalid URL",
+			config: &Config{
+				APIKey:  "valid-key",
+				BaseURL: "ftp://api.anthropic.com",
+			},
+			wantErr: "base URL must use http or https scheme",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.config)
+			assert.NilError(t, err)
+			err = client.ValidateConfig()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestAnalyzeSuccess(t *testing.T) {
+	client, err := NewClient(&Config{APIKey: "test-key", MaxTokens: 500})
+	assert.NilError(t, err)
+
+	mockResponse := anthropicResponse{
+		ID:    "msg_123",
+		Type:  "message",
+		Role:  "assistant",
+		Model: defaultModel,
+		Content: []anthropicContent{
+			{Type: "text", Text: "This is the "},
+			{Type: "text", Text: "analysis result"},
+		},
+		StopReason: "end_turn",
+		Usage: anthropicUsage{
+			InputTokens:  10,
+			OutputTokens: 5,
+		},
+	}
+
+	client.httpClient = &http.Client{
+		Transport: httptesting.RoundTripFunc(func(req *http.Request) *http.Response {
+			assert.Equal(t, req.Method, "POST")
+			assert.Equal(t, req.URL.String(), defaultBaseURL+"/messages")
+			assert.Equal(t, req.Header.Get("x-api-key"), "test-key")
+			assert.Equal(t, req.Header.Get("anthropic-version"), apiVersion)
+
+			var apiRequest anthropicRequest
+			assert.NilError(t, json.NewDecoder(req.Body).Decode(&apiRequest))
+			assert.Equal(t, apiRequest.Model, defaultModel)
+			assert.Equal(t, apiRequest.MaxTokens, 500)
+			assert.Equal(t, len(apiRequest.Messages), 1)
+			assert.Equal(t, apiRequest.Messages[0].Role, "user")
+
+			body, err := json.Marshal(mockResponse)
+			assert.NilError(t, err)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(body)),
+			}
+		}),
+	}
+
+	response, err := client.Analyze(context.Background(), &ltypes.AnalysisRequest{Prompt: "Analyze this"})
+
+	assert.NilError(t, err)
+	assert.Equal(t, response.Content, "This is the analysis result")
+	assert.Equal(t, response.Provider, "anthropic")
+	assert.Equal(t, response.TokensUsed, 15)
+}
+
+func TestAnalyze_Errors(t *testing.T) {
+	tests := []struct {
+		name            string
+		statusCode      int
+		body            string
+		expectedErrType string
+		retryable       bool
+		messageContains string
+	}{
+		{
+			name:            "empty response",
+			statusCode:      http.StatusOK,
+			body:            `{"content": []}`,
+			expectedErrType: "empty_response",
+		},
+		{
+			name:            "response parse error",
+			statusCode:      http.StatusOK,
+			body:            "invalid json",
+			expectedErrType: "response_parse_error",
+		},
+		{
+			name:            "rate limit exceeded",
+			statusCode:      http.StatusTooManyRequests,
+			body:            `{"type": "error", "error": {"type": "rate_limit_error", "message": "Rate limited"}}`,
+			expectedErrType: "rate_limit_exceeded",
+			retryable:       true,
+			messageContains: "Rate limited",
+		},
+		{
+			name:            "overloaded",
+			statusCode:      529,
+			body:            `{"type": "error", "error": {"type": "overloaded_error", "message": "Overloaded"}}`,
+			expectedErrType: "server_error",
+			retryable:       true,
+		},
+		{
+			name:            "unauthorized",
+			statusCode:      http.StatusUnauthorized,
+			body:            `{"type": "error", "error": {"type": "authentication_error", "message": "invalid x-api-key"}}`,
+			expectedErrType: "invalid_api_key",
+		},
+		{
+			name:            "api error without details",
+			statusCode:      http.StatusBadRequest,
+			body:            `{}`,
+			expectedErrType: "api_error",
+			messageContains: "status 400",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(&Config{APIKey: "test-key"})
+			assert.NilError(t, err)
+
+			client.httpClient = &http.Client{
+				Transport: httptesting.RoundTripFunc(func(_ *http.Request) *http.Response {
+					return &http.Response{
+						StatusCode: tt.statusCode,
+						Body:       io.NopCloser(strings.NewReader(tt.body)),
+					}
+				}),
+			}
+
+			response, err := client.Analyze(context.Background(), &ltypes.AnalysisRequest{Prompt: "Analyze"})
+
+			assert.Assert(t, response == nil)
+			var analysisErr *ltypes.AnalysisError
+			assert.Assert(t, errors.As(err, &analysisErr))
+			assert.Equal(t, analysisErr.Type, tt.expectedErrType)
+			assert.Equal(t, analysisErr.Retryable, tt.retryable)
+			if tt.messageContains != "" {
+				assert.ErrorContains(t, err, tt.messageContains)
+			}
+		})
+	}
+}
@@ -33,6 +33,16 @@ func ValidateCommonConfig(config any) error {
 		return fmt.Errorf("API key is required")
 	}
 
+	return ValidateLimits(commonCfg)
+}
+
+// ValidateLimits validates the numeric fields of the common configuration,
+// for the providers which do not require an API key.
+func ValidateLimits(commonCfg *CommonConfig) error {
+	if commonCfg == nil {
+		return fmt.Errorf("config is required")
+	}
+
 	if commonCfg.TimeoutSeconds < 0 {
 		return fmt.Errorf("timeout seconds must be non-negative")
 	}
@@ -226,6 +226,44 @@ func TestValidateAndApplyDefaults(t *testing.T) {
 	assert.Equal(t, config.MaxTokens, 1000)
 }
 
+func TestValidateLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *CommonConfig
+		wantErr string
+	}{
+		{
+			name:   "no api key",
+			config: &CommonConfig{TimeoutSeconds: 30, MaxTokens: 1000},
+		},
+		{
+			name:    "nil config",
+			wantErr: "config is required",
+		},
+		{
+			name:    "negative timeout",
+			config:  &CommonConfig{TimeoutSeconds: -1},
+			wantErr: "timeout seconds must be non-negative",
+		},
+		{
+			name:    "negative max tokens",
+			config:  &CommonConfig{MaxTokens: -1},
+			wantErr: "max tokens must be non-negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLimits(tt.config)
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateBaseURL(t *testing.T) {
 	tests := []struct {
 		name    string
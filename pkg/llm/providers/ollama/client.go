@@ -0,0 +1,251 @@
+// Package ollama is the Client implementation for the chat API of an Ollama
+// server, usually running in the cluster next to Pipelines-as-Code.
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/ltypes"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/providers"
+)
+
+const (
+	defaultBaseURL = "http://localhost:11434"
+	defaultModel   = "llama3.2"
+)
+
+// Config holds the configuration for Ollama client. The APIKey is optional,
+// it is sent as a bearer token when the server is behind an authenticating
+// proxy.
+type Config struct {
+	APIKey         string
+	BaseURL        string
+	Model          string
+	TimeoutSeconds int
+	MaxTokens      int
+	HTTPClient     *http.Client
+}
+
+// Client implements the LLM interface for Ollama.
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+}
+
+// NewClient creates a new Ollama client.
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+
+	commonCfg := &providers.CommonConfig{
+		APIKey:         config.APIKey,
+		TimeoutSeconds: config.TimeoutSeconds,
+		MaxTokens:      config.MaxTokens,
+	}
+	if err := providers.ApplyDefaults(commonCfg); err != nil {
+		return nil, err
+	}
+
+	config.TimeoutSeconds = commonCfg.TimeoutSeconds
+	config.MaxTokens = commonCfg.MaxTokens
+
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if config.Model == "" {
+		config.Model = defaultModel
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	if config.TimeoutSeconds > 0 && httpClient.Timeout == 0 {
+		httpClient.Timeout = time.Duration(config.TimeoutSeconds) * time.Second
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+	}, nil
+}
+
+// Analyze sends an analysis request to the Ollama chat API and returns the
+// response.
+func (c *Client) Analyze(ctx context.Context, request *ltypes.AnalysisRequest) (*ltypes.AnalysisResponse, error) {
+	startTime := time.Now()
+
+	fullPrompt, err := providers.BuildPrompt(request)
+	if err != nil {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "prompt_build_error",
+			Message:   fmt.Sprintf("failed to build prompt: %v", err),
+			Retryable: false,
+		}
+	}
+
+	maxTokens := request.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = c.config.MaxTokens
+	}
+	apiRequest := &ollamaRequest{
+		Model: c.config.Model,
+		Messages: []ollamaMessage{
+			{
+				Role:    "user",
+				Content: fullPrompt,
+			},
+		},
+		Stream: false,
+		Options: ollamaOptions{
+			NumPredict: maxTokens,
+		},
+	}
+
+	requestBody, err := json.Marshal(apiRequest)
+	if err != nil {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "request_marshal_error",
+			Message:   fmt.Sprintf("failed to marshal request: %v", err),
+			Retryable: false,
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+"/api/chat", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "http_request_error",
+			Message:   fmt.Sprintf("failed to create HTTP request: %v", err),
+			Retryable: false,
+		}
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.config.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "http_error",
+			Message:   fmt.Sprintf("HTTP request failed: %v", err),
+			Retryable: true,
+		}
+	}
+	defer resp.Body.Close()
+
+	var apiResponse ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "response_parse_error",
+			Message:   fmt.Sprintf("failed to parse response: %v", err),
+			Retryable: false,
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errorType := "api_error"
+		retryable := false
+
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			// the model has not been pulled on the server
+			errorType = "model_not_found"
+		case resp.StatusCode == http.StatusUnauthorized:
+			errorType = "invalid_api_key"
+		case resp.StatusCode >= 500:
+			errorType = "server_error"
+			retryable = true
+		}
+
+		errorMsg := fmt.Sprintf("Ollama API error (status %d)", resp.StatusCode)
+		if apiResponse.Error != "" {
+			errorMsg = fmt.Sprintf("Ollama API error: %s", apiResponse.Error)
+		}
+
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      errorType,
+			Message:   errorMsg,
+			Retryable: retryable,
+		}
+	}
+
+	if apiResponse.Message.Content == "" {
+		return nil, &ltypes.AnalysisError{
+			Provider:  c.GetProviderName(),
+			Type:      "empty_response",
+			Message:   "no content in API response",
+			Retryable: false,
+		}
+	}
+
+	return &ltypes.AnalysisResponse{
+		Content:    apiResponse.Message.Content,
+		TokensUsed: apiResponse.PromptEvalCount + apiResponse.EvalCount,
+		Provider:   c.GetProviderName(),
+		Timestamp:  time.Now(),
+		Duration:   time.Since(startTime),
+	}, nil
+}
+
+// GetProviderName returns the provider name.
+func (c *Client) GetProviderName() string {
+	return string(ltypes.LLMProviderOllama)
+}
+
+// ValidateConfig validates the client configuration.
+func (c *Client) ValidateConfig() error {
+	commonCfg := &providers.CommonConfig{
+		APIKey:         c.config.APIKey,
+		TimeoutSeconds: c.config.TimeoutSeconds,
+		MaxTokens:      c.config.MaxTokens,
+	}
+	if err := providers.ValidateLimits(commonCfg); err != nil {
+		return err
+	}
+
+	return providers.ValidateBaseURL(c.config.BaseURL)
+}
+
+// Ollama chat API request/response structures
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Options  ollamaOptions   `json:"options"`
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaOptions struct {
+	NumPredict int `json:"num_predict,omitempty"`
+}
+
+type ollamaResponse struct {
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+	Error           string        `json:"error,omitempty"`
+}
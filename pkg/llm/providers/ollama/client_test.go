@@ -0,0 +1,247 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm/ltypes"
+	httptesting "github.com/openshift-pipelines/pipelines-as-code/pkg/test/http"
+	"gotest.tools/v3/assert"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *Config
+		wantError bool
+		errMsg    string
+	}{
+		{
+			name:      "nil config",
+			config:    nil,
+			wantError: true,
+			errMsg:    "config is required",
+		},
+		{
+			name:   "no api key with defaults",
+			config: &Config{},
+		},
+		{
+			name: "custom config",
+			config: &Config{
+				APIKey:         "proxy-token",
+				BaseURL:        "http://ollama.ai-models.svc:11434/",
+				Model:          "mistral",
+				TimeoutSeconds: 120,
+				MaxTokens:      2000,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.config)
+
+			if tt.wantError {
+				assert.ErrorContains(t, err, tt.errMsg)
+				assert.Assert(t, client == nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, client.GetProviderName(), "ollama")
+			if tt.config.BaseURL == "" {
+				assert.Equal(t, client.config.BaseURL, defaultBaseURL)
+			} else {
+				assert.Assert(t, !strings.HasSuffix(client.config.BaseURL, "/"))
+			}
+			if tt.config.Model == "" {
+				assert.Equal(t, client.config.Model, defaultModel)
+			}
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr string
+	}{
+		{
+			name:   "valid config without api key",
+			config: &Config{BaseURL: "http://ollama.ai-models.svc:11434"},
+		},
+		{
+			name:    "negative timeout",
+			config:  &Config{TimeoutSeconds: -1},
+			wantErr: "timeout seconds must be non-negative",
+		},
+		{
+			name:    "negative max tokens",
+			config:  &Config{MaxTokens: -1},
+			wantErr: "max tokens must be non-negative",
+		},
+		{
+			name:    "invalid URL",
+			config:  &Config{BaseURL: "ollama:11434"},
+			wantErr: "base URL must use http or https scheme",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.config)
+			assert.NilError(t, err)
+			err = client.ValidateConfig()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestAnalyzeSuccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiKey     string
+		wantHeader string
+	}{
+		{
+			name: "without api key",
+		},
+		{
+			name:       "with api key",
+			apiKey:     "proxy-token",
+			wantHeader: "Bearer proxy-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(&Config{APIKey: tt.apiKey})
+			assert.NilError(t, err)
+
+			mockResponse := ollamaResponse{
+				Model: defaultModel,
+				Message: ollamaMessage{
+					Role:    "assistant",
+					Content: "This is the analysis result",
+				},
+				Done:            true,
+				PromptEvalCount: 10,
+				EvalCount:       5,
+			}
+
+			client.httpClient = &http.Client{
+				Transport: httptesting.RoundTripFunc(func(req *http.Request) *http.Response {
+					assert.Equal(t, req.Method, "POST")
+					assert.Equal(t, req.URL.String(), defaultBaseURL+"/api/chat")
+					assert.Equal(t, req.Header.Get("Authorization"), tt.wantHeader)
+
+					var apiRequest ollamaRequest
+					assert.NilError(t, json.NewDecoder(req.Body).Decode(&apiRequest))
+					assert.Equal(t, apiRequest.Model, defaultModel)
+					assert.Assert(t, !apiRequest.Stream)
+					assert.Equal(t, apiRequest.Options.NumPredict, 100)
+
+					body, err := json.Marshal(mockResponse)
+					assert.NilError(t, err)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(bytes.NewReader(body)),
+					}
+				}),
+			}
+
+			response, err := client.Analyze(context.Background(), &ltypes.AnalysisRequest{
+				Prompt:    "Analyze this",
+				MaxTokens: 100,
+			})
+
+			assert.NilError(t, err)
+			assert.Equal(t, response.Content, "This is the analysis result")
+			assert.Equal(t, response.Provider, "ollama")
+			assert.Equal(t, response.TokensUsed, 15)
+		})
+	}
+}
+
+func TestAnalyze_Errors(t *testing.T) {
+	tests := []struct {
+		name            string
+		statusCode      int
+		body            string
+		expectedErrType string
+		retryable       bool
+		messageContains string
+	}{
+		{
+			name:            "empty response",
+			statusCode:      http.StatusOK,
+			body:            `{"done": true}`,
+			expectedErrType: "empty_response",
+		},
+		{
+			name:            "response parse error",
+			statusCode:      http.StatusOK,
+			body:            "invalid json",
+			expectedErrType: "response_parse_error",
+		},
+		{
+			name:            "model not found",
+			statusCode:      http.StatusNotFound,
+			body:            `{"error": "model \"llama3.2\" not found, try pulling it first"}`,
+			expectedErrType: "model_not_found",
+			messageContains: "try pulling it first",
+		},
+		{
+			name:            "server error",
+			statusCode:      http.StatusInternalServerError,
+			body:            `{"error": "out of memory"}`,
+			expectedErrType: "server_error",
+			retryable:       true,
+		},
+		{
+			name:            "api error without details",
+			statusCode:      http.StatusBadRequest,
+			body:            `{}`,
+			expectedErrType: "api_error",
+			messageContains: "status 400",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(&Config{})
+			assert.NilError(t, err)
+
+			client.httpClient = &http.Client{
+				Transport: httptesting.RoundTripFunc(func(_ *http.Request) *http.Response {
+					return &http.Response{
+						StatusCode: tt.statusCode,
+						Body:       io.NopCloser(strings.NewReader(tt.body)),
+					}
+				}),
+			}
+
+			response, err := client.Analyze(context.Background(), &ltypes.AnalysisRequest{Prompt: "Analyze"})
+
+			assert.Assert(t, response == nil)
+			var analysisErr *ltypes.AnalysisError
+			assert.Assert(t, errors.As(err, &analysisErr))
+			assert.Equal(t, analysisErr.Type, tt.expectedErrType)
+			assert.Equal(t, analysisErr.Retryable, tt.retryable)
+			if tt.messageContains != "" {
+				assert.ErrorContains(t, err, tt.messageContains)
+			}
+		})
+	}
+}
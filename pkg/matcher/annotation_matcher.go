@@ -3,13 +3,16 @@ package matcher
 import (
 	"context"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/customparams"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/decisiontrace"
 	pacerrors "github.com/openshift-pipelines/pipelines-as-code/pkg/errors"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
@@ -106,9 +109,13 @@ func getTargetBranch(prun *tektonv1.PipelineRun, event *info.Event) (bool, strin
 			return false, "", "", fmt.Errorf("annotation %s is empty", keys.OnEvent)
 		}
 		targetEvents := []string{event.TriggerTarget.String()}
-		if event.EventType == triggertype.Incoming.String() {
+		switch event.EventType {
+		case triggertype.Incoming.String():
 			// if we have a incoming event, we want to match pipelineruns on both incoming and push
 			targetEvents = []string{triggertype.Incoming.String(), triggertype.Push.String()}
+		case triggertype.PullRequestReview.String():
+			// a review only matches the pipelineruns explicitly targeting it
+			targetEvents = []string{triggertype.PullRequestReview.String()}
 		}
 		matched, err := matchOnAnnotation(key, targetEvents, false)
 		targetEvent = key
@@ -133,6 +140,22 @@ func getTargetBranch(prun *tektonv1.PipelineRun, event *info.Event) (bool, strin
 			return false, "", "", nil
 		}
 	}
+	if key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnTargetBranchIgnore]; ok {
+		if key == "[]" {
+			return false, "", "", fmt.Errorf("annotation %s is empty", keys.OnTargetBranchIgnore)
+		}
+		ignored, err := matchOnAnnotation(key, []string{event.BaseBranch}, true)
+		if err != nil {
+			return false, "", "", err
+		}
+		if ignored {
+			return false, "", "", nil
+		}
+		// without an on-target-branch, all the branches but the ignored ones are targeted
+		if targetBranch == "" {
+			targetBranch = "*"
+		}
+	}
 
 	if targetEvent == "" || targetBranch == "" {
 		return false, "", "", nil
@@ -168,6 +191,8 @@ func checkPipelineRunAnnotation(prun *tektonv1.PipelineRun, eventEmitter *events
 	}{
 		{"on-event", prun.GetObjectMeta().GetAnnotations()[keys.OnEvent]},
 		{"on-target-branch", prun.GetObjectMeta().GetAnnotations()[keys.OnTargetBranch]},
+		{"on-target-branch-ignore", prun.GetObjectMeta().GetAnnotations()[keys.OnTargetBranchIgnore]},
+		{"on-label-ignore", prun.GetObjectMeta().GetAnnotations()[keys.OnLabelIgnore]},
 	}
 
 	// Preallocate the annotations slice with the exact capacity needed
@@ -206,10 +231,13 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 		infomsg += fmt.Sprintf(", labels=%s", strings.Join(event.PullRequestLabel, "|"))
 	}
 
-	if event.EventType == triggertype.Incoming.String() {
+	switch event.EventType {
+	case triggertype.Incoming.String(), triggertype.Schedule.String():
 		infomsg = fmt.Sprintf("%s, target-pipelinerun=%s", infomsg, event.TargetPipelineRun)
-	} else if event.EventType == triggertype.PullRequest.String() {
+	case triggertype.PullRequest.String():
 		infomsg = fmt.Sprintf("%s, pull-request=%d", infomsg, event.PullRequestNumber)
+	case triggertype.PullRequestReview.String():
+		infomsg = fmt.Sprintf("%s, pull-request=%d, review-state=%s, reviewer=%s", infomsg, event.PullRequestNumber, event.PullRequestReviewState, event.Sender)
 	}
 	logger.Info(infomsg)
 
@@ -221,6 +249,9 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 		logger.Debugf("resolved %d custom params from repo for CEL", len(customParams))
 	}
 
+	trace := decisiontrace.FromContext(ctx)
+	trace.ResetPipelineRuns()
+
 	celValidationErrors := []*pacerrors.PacYamlValidations{}
 	for _, prun := range pruns {
 		logger.Debugf("MatchPipelinerunByAnnotation: evaluating pipelinerun=%s annotations=%d", getName(prun), len(prun.GetObjectMeta().GetAnnotations()))
@@ -232,12 +263,14 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 		prName := getName(prun)
 		if event.TargetPipelineRun != "" && event.TargetPipelineRun == strings.TrimSuffix(prName, "-") {
 			logger.Infof("matched target pipelinerun with name: %s, target pipelinerun: %s", prName, event.TargetPipelineRun)
+			trace.Matched(prName, "", fmt.Sprintf("targeted by the %s event", event.EventType))
 			matchedPRs = append(matchedPRs, prMatch)
 			continue
 		}
 
 		if prun.GetObjectMeta().GetAnnotations() == nil {
 			logger.Debugf("PipelineRun %s does not have any annotations", prName)
+			trace.Rejected(prName, "", "the PipelineRun does not have any annotations")
 			continue
 		}
 
@@ -251,6 +284,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			prMatch.Repo, _ = MatchEventURLRepo(ctx, cs, event, targetNS)
 			if prMatch.Repo == nil {
 				logger.Warnf("could not find Repository CRD in branch %s, the pipelineRun %s has a label that explicitly targets it", targetNS, prName)
+				trace.Rejected(prName, keys.TargetNamespace, fmt.Sprintf("no Repository for this event in the target namespace %s", targetNS))
 				continue
 			}
 			logger.Debugf("PipelineRun %s: matched target namespace repo=%s/%s", prName, prMatch.Repo.GetNamespace(), prMatch.Repo.GetName())
@@ -260,6 +294,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			re, err := regexp.Compile(targetComment)
 			if err != nil {
 				logger.Warnf("could not compile regexp %s from pipelineRun %s", targetComment, prName)
+				trace.Rejected(prName, keys.OnComment, fmt.Sprintf("invalid regexp: %s", err))
 				continue
 			}
 
@@ -280,6 +315,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 					comment = comment[:maxCommentLogLength] + "..."
 				}
 				logger.Infof("matched pipelinerun with name: %s on gitops comment: %q", prName, comment)
+				trace.Matched(prName, keys.OnComment, fmt.Sprintf("the comment matches %q", targetComment))
 
 				matchedPRs = append(matchedPRs, prMatch)
 				continue
@@ -287,6 +323,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 		}
 		// if the event is a comment event, but we don't have any match from the keys.OnComment then skip the other evaluations
 		if event.EventType == opscomments.NoOpsCommentEventType.String() || event.EventType == opscomments.OnCommentEventType.String() {
+			trace.Rejected(prName, keys.OnComment, "the comment does not match any on-comment annotation")
 			continue
 		}
 
@@ -297,6 +334,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			out, err := celEvaluate(ctx, celExpr, event, vcx, customParams, eventEmitter, repo)
 			if err != nil {
 				logger.Errorf("there was an error evaluating the CEL expression, skipping: %v", err)
+				trace.Rejected(prName, keys.OnCelExpression, fmt.Sprintf("error evaluating the CEL expression: %s", err))
 				if checkIfCELEvaluateError(err) {
 					celValidationErrors = append(celValidationErrors, &pacerrors.PacYamlValidations{
 						Name: prName,
@@ -308,9 +346,11 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			logger.Debugf("PipelineRun %s: CEL result=%v", prName, out)
 			if out != types.True {
 				logger.Infof("CEL expression for PipelineRun %s is not matching, skipping", prName)
+				trace.Rejected(prName, keys.OnCelExpression, "the CEL expression evaluated to false")
 				continue
 			}
 			logger.Infof("CEL expression has been evaluated and matched")
+			trace.Matched(prName, keys.OnCelExpression, "the CEL expression evaluated to true")
 		} else {
 			// If the event is a pull_request and the event type is label_update, but the PipelineRun
 			// does not contain an 'on-label' annotation, do not match this PipelineRun, as it is not intended for this event.
@@ -318,6 +358,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			_, hasOnLabel := prun.GetObjectMeta().GetAnnotations()[keys.OnLabel]
 			if event.TriggerTarget == triggertype.PullRequest && event.EventType == string(triggertype.PullRequestLabeled) && !hasOnLabel {
 				logger.Infof("label update event, PipelineRun %s does not have a on-label for any of those labels: %s", prName, strings.Join(event.PullRequestLabel, "|"))
+				trace.Rejected(prName, keys.OnLabel, "label update event and the PipelineRun has no on-label annotation")
 				continue
 			}
 
@@ -327,6 +368,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			}
 			if !matched {
 				logger.Debugf("PipelineRun %s: target branch/event did not match", prName)
+				trace.Rejected(prName, keys.OnEvent, fmt.Sprintf("on-event/on-target-branch do not match the %s event on the %s branch", event.TriggerTarget, event.BaseBranch))
 				continue
 			}
 			prMatch.Config["target-branch"] = targetBranch
@@ -337,6 +379,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 				changedFiles, err := vcx.GetFiles(ctx, event)
 				if err != nil {
 					logger.Errorf("error getting changed files: %v", err)
+					trace.Rejected(prName, keys.OnPathChange, fmt.Sprintf("error getting the changed files: %s", err))
 					continue
 				}
 				// // TODO(chmou): we use the matchOnAnnotation function, it's
@@ -348,6 +391,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 				}
 				if !matched {
 					logger.Debugf("PipelineRun %s: path-change annotation did not match", prName)
+					trace.Rejected(prName, keys.OnPathChange, fmt.Sprintf("no changed file matches %s", key))
 					continue
 				}
 				logger.Infof("matched PipelineRun with name: %s, annotation PathChange: %q", prName, key)
@@ -361,16 +405,32 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 				}
 				if !matched {
 					logger.Debugf("PipelineRun %s: label annotation did not match", prName)
+					trace.Rejected(prName, keys.OnLabel, fmt.Sprintf("no label of the pull request matches %s", key))
 					continue
 				}
 				logger.Infof("matched PipelineRun with name: %s, annotation Label: %q", prName, key)
 				prMatch.Config["label"] = key
 			}
 
+			if key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnLabelIgnore]; ok {
+				matched, err := matchOnAnnotation(key, event.PullRequestLabel, false)
+				if err != nil {
+					return matchedPRs, err
+				}
+				if matched {
+					logger.Infof("Skipping pipelinerun with name: %s, annotation LabelIgnore: %q", prName, key)
+					trace.Rejected(prName, keys.OnLabelIgnore, fmt.Sprintf("a label of the pull request matches %s", key))
+					continue
+				}
+				prMatch.Config["label-ignore"] = key
+				logger.Debugf("PipelineRun %s: label-ignore did not match, continuing", prName)
+			}
+
 			if key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnPathChangeIgnore]; ok {
 				changedFiles, err := vcx.GetFiles(ctx, event)
 				if err != nil {
 					logger.Errorf("error getting changed files: %v", err)
+					trace.Rejected(prName, keys.OnPathChangeIgnore, fmt.Sprintf("error getting the changed files: %s", err))
 					continue
 				}
 				// // TODO(chmou): we use the matchOnAnnotation function, it's
@@ -382,6 +442,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 				}
 				if matched {
 					logger.Infof("Skipping pipelinerun with name: %s, annotation PathChangeIgnore: %q", prName, key)
+					trace.Rejected(prName, keys.OnPathChangeIgnore, fmt.Sprintf("a changed file matches %s", key))
 					continue
 				}
 				prMatch.Config["path-change-ignore"] = key
@@ -390,6 +451,9 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 		}
 
 		logger.Infof("matched pipelinerun with name: %s, annotation Config: %q", prName, prMatch.Config)
+		if _, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnCelExpression]; !ok {
+			trace.Matched(prName, keys.OnEvent, fmt.Sprintf("the annotations match: %s", formatConfig(prMatch.Config)))
+		}
 		matchedPRs = append(matchedPRs, prMatch)
 	}
 
@@ -463,6 +527,8 @@ func filterSuccessfulTemplates(ctx context.Context, logger *zap.SugaredLogger, c
 		if successfulPR, hasSuccessfulRun := successfulTemplates[templateName]; hasSuccessfulRun {
 			logger.Infof("skipping template '%s' for sha %s as it already has a successful pipelinerun '%s'",
 				templateName, event.SHA, successfulPR.Name)
+			decisiontrace.FromContext(ctx).Rejected(templateName, "",
+				fmt.Sprintf("the PipelineRun %s already succeeded for this sha", successfulPR.Name))
 		} else {
 			filteredPRs = append(filteredPRs, match)
 		}
@@ -477,7 +543,10 @@ func buildAvailableMatchingAnnotationErr(event *info.Event, pruns []*tektonv1.Pi
 	for _, prun := range pruns {
 		name := getName(prun)
 		errmsg += fmt.Sprintf(" [PipelineRun: %s, annotations:", name)
-		for annotation, value := range prun.GetAnnotations() {
+		annotations := prun.GetAnnotations()
+		// sort the annotations to have a stable message
+		for _, annotation := range slices.Sorted(maps.Keys(annotations)) {
+			value := annotations[annotation]
 			if !strings.HasPrefix(annotation, pipelinesascode.GroupName+"/on-") {
 				continue
 			}
@@ -502,6 +571,15 @@ func buildAvailableMatchingAnnotationErr(event *info.Event, pruns []*tektonv1.Pi
 	return errmsg
 }
 
+// formatConfig returns the matched annotations of a PipelineRun sorted by name.
+func formatConfig(config map[string]string) string {
+	ret := []string{}
+	for _, k := range slices.Sorted(maps.Keys(config)) {
+		ret = append(ret, fmt.Sprintf("%s=%s", k, config[k]))
+	}
+	return strings.Join(ret, ", ")
+}
+
 func matchOnAnnotation(annotations string, eventType []string, branchMatching bool) (bool, error) {
 	targets, err := getAnnotationValues(annotations)
 	if err != nil {
@@ -527,14 +605,15 @@ func matchOnAnnotation(annotations string, eventType []string, branchMatching bo
 }
 
 func MatchRunningPipelineRunForIncomingWebhook(eventType, incomingPipelineRun string, prs []*tektonv1.PipelineRun) []*tektonv1.PipelineRun {
-	// return all pipelineruns if EventType is not incoming or TargetPipelineRun is ""
-	if eventType != "incoming" || incomingPipelineRun == "" {
+	// return all pipelineruns if EventType is not incoming or schedule or TargetPipelineRun is ""
+	if (eventType != "incoming" && eventType != triggertype.Schedule.String()) || incomingPipelineRun == "" {
 		return prs
 	}
 
 	for _, pr := range prs {
 		// check incomingPipelineRun with pr name or generateName
-		if incomingPipelineRun == pr.GetName() || incomingPipelineRun == pr.GetGenerateName() {
+		if incomingPipelineRun == pr.GetName() || incomingPipelineRun == pr.GetGenerateName() ||
+			(pr.GetGenerateName() != "" && incomingPipelineRun == strings.TrimSuffix(pr.GetGenerateName(), "-")) {
 			return []*tektonv1.PipelineRun{pr}
 		}
 	}
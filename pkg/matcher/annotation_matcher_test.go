@@ -14,6 +14,7 @@ import (
 	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/decisiontrace"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -383,6 +384,338 @@ func TestMatchPipelinerunAnnotationAndRepositories(t *testing.T) {
 			},
 		},
 
+		{
+			name:       "match pull request closed with on-event",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request_closed]",
+								keys.OnTargetBranch: "[" + mainBranch + "]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     triggertype.PullRequestClosed,
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					PullRequestMerged: false,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "no match pull request on-event on pull request closed",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: "[" + mainBranch + "]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     triggertype.PullRequestClosed,
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					PullRequestMerged: true,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "cel/match merged pull request",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: "event == \"pull_request_closed\" && merged",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     triggertype.PullRequestClosed,
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					PullRequestMerged: true,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "cel/no match abandoned pull request",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: "event == \"pull_request_closed\" && merged",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     triggertype.PullRequestClosed,
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					PullRequestMerged: false,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "cel/no match draft pull request",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: "event == \"pull_request\" && !is_draft",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					IsDraft:           true,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "cel/match review state and reviewer",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: "event == \"pull_request_review\" && review_state == \"approved\" && reviewer == \"maintainer\"",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:                    targetURL,
+					TriggerTarget:          "pull_request",
+					EventType:              "pull_request_review",
+					BaseBranch:             mainBranch,
+					HeadBranch:             "unittests",
+					PullRequestNumber:      1000,
+					PullRequestReviewState: "approved",
+					Sender:                 "maintainer",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "cel/no match pull request on review",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: "event == \"pull_request\"",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:                    targetURL,
+					TriggerTarget:          "pull_request",
+					EventType:              "pull_request_review",
+					BaseBranch:             mainBranch,
+					HeadBranch:             "unittests",
+					PullRequestNumber:      1000,
+					PullRequestReviewState: "approved",
+					Sender:                 "maintainer",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match review with on-event",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request_review]",
+								keys.OnTargetBranch: "[" + mainBranch + "]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:                    targetURL,
+					TriggerTarget:          "pull_request",
+					EventType:              "pull_request_review",
+					BaseBranch:             mainBranch,
+					HeadBranch:             "unittests",
+					PullRequestNumber:      1000,
+					PullRequestReviewState: "approved",
+					Sender:                 "maintainer",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "no match pull request on-event on review",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: "[" + mainBranch + "]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:                    targetURL,
+					TriggerTarget:          "pull_request",
+					EventType:              "pull_request_review",
+					BaseBranch:             mainBranch,
+					HeadBranch:             "unittests",
+					PullRequestNumber:      1000,
+					PullRequestReviewState: "approved",
+					Sender:                 "maintainer",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
 		{
 			name:    "cel/no match path title pr",
 			wantErr: true,
@@ -1985,6 +2318,64 @@ func TestMatchPipelinerunByAnnotation(t *testing.T) {
 				"matching pipelineruns to event: URL=https://hello/moto, target-branch=main, source-branch=source, target-event=pull_request, labels=documentation, pull-request=10",
 			},
 		},
+		{
+			name: "no-match-on-label-ignore",
+			args: args{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "pipeline-label-ignore",
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: "[main]",
+								keys.OnLabelIgnore:  "[skip-ci]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               "https://hello/moto",
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					HeadBranch:        "source",
+					BaseBranch:        "main",
+					PullRequestNumber: 10,
+					PullRequestLabel:  []string{"skip-ci", "documentation"},
+				},
+			},
+			wantErr: true,
+			wantLog: []string{
+				`Skipping pipelinerun with name: pipeline-label-ignore, annotation LabelIgnore: "[skip-ci]"`,
+			},
+		},
+		{
+			name: "good-match-on-label-ignore",
+			args: args{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "pipeline-label-ignore",
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: "[main]",
+								keys.OnLabelIgnore:  "[skip-ci]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               "https://hello/moto",
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					HeadBranch:        "source",
+					BaseBranch:        "main",
+					PullRequestNumber: 10,
+					PullRequestLabel:  []string{"documentation"},
+				},
+			},
+			wantErr:    false,
+			wantPrName: "pipeline-label-ignore",
+		},
 		{
 			name: "no-on-label-annotation-on-pr",
 			args: args{
@@ -2288,6 +2679,81 @@ func TestMatchPipelinerunByAnnotation(t *testing.T) {
 	}
 }
 
+func TestMatchPipelinerunByAnnotationDecisionTrace(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	pruns := []*tektonv1.PipelineRun{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pipeline-good",
+				Annotations: map[string]string{
+					keys.OnEvent:        "[pull_request]",
+					keys.OnTargetBranch: "[main]",
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pipeline-push",
+				Annotations: map[string]string{
+					keys.OnEvent:        "[push]",
+					keys.OnTargetBranch: "[main]",
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pipeline-label-ignore",
+				Annotations: map[string]string{
+					keys.OnEvent:        "[pull_request]",
+					keys.OnTargetBranch: "[main]",
+					keys.OnLabelIgnore:  "[skip-ci]",
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pipeline-cel",
+				Annotations: map[string]string{
+					keys.OnCelExpression: `event == "push"`,
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pipeline-no-annotations",
+			},
+		},
+	}
+	event := &info.Event{
+		TriggerTarget:    triggertype.PullRequest,
+		EventType:        triggertype.PullRequest.String(),
+		BaseBranch:       "main",
+		PullRequestLabel: []string{"skip-ci"},
+		Request:          &info.Request{Header: http.Header{}},
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	trace := decisiontrace.New()
+	ctx = decisiontrace.NewContext(ctx, trace)
+	// a previous matching is forgotten
+	trace.Matched("pipeline-previous", "", "previous matching")
+	cs := &params.Run{Clients: clients.Clients{}, Info: info.Info{}}
+	eventEmitter := events.NewEventEmitter(cs.Clients.Kube, logger)
+	matches, err := MatchPipelinerunByAnnotation(ctx, logger, pruns, cs, event, &ghprovider.Provider{}, eventEmitter, nil, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(matches), 1)
+
+	want := []decisiontrace.PipelineRunDecision{
+		{Name: "pipeline-good", Matched: true, Annotation: keys.OnEvent, Reason: "the annotations match: target-branch=[main], target-event=[pull_request]"},
+		{Name: "pipeline-push", Annotation: keys.OnEvent, Reason: "on-event/on-target-branch do not match the pull_request event on the main branch"},
+		{Name: "pipeline-label-ignore", Annotation: keys.OnLabelIgnore, Reason: "a label of the pull request matches [skip-ci]"},
+		{Name: "pipeline-cel", Annotation: keys.OnCelExpression, Reason: "the CEL expression evaluated to false"},
+		{Name: "pipeline-no-annotations", Reason: "the PipelineRun does not have any annotations"},
+	}
+	assert.DeepEqual(t, trace.PipelineRuns, want)
+}
+
 func Test_getAnnotationValues(t *testing.T) {
 	type args struct {
 		annotation string
@@ -2627,6 +3093,33 @@ func TestMatchRunningPipelineRunForIncomingWebhook(t *testing.T) {
 			},
 			wantedPrunsNumber: 1,
 		},
+		{
+			name: "return matched pipelinerun for a schedule on a pipelinerun generateName",
+			runevent: info.Event{
+				EventType:         "schedule",
+				TargetPipelineRun: "pr1",
+			},
+			pruns: []*tektonv1.PipelineRun{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						GenerateName: "pr1-",
+						Annotations: map[string]string{
+							keys.OnSchedule: "0 2 * * *",
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "pr2",
+						Annotations: map[string]string{
+							keys.OnEvent:        "[push]",
+							keys.OnTargetBranch: "main",
+						},
+					},
+				},
+			},
+			wantedPrunsNumber: 1,
+		},
 		{
 			name: "return nil when failing to match with an event type or a pipelinerun name",
 			runevent: info.Event{
@@ -2689,6 +3182,27 @@ func TestBuildAvailableMatchingAnnotationErr(t *testing.T) {
 				BaseBranch: "main",
 			},
 		},
+		{
+			name: "Test with ignore annotations",
+			pruns: []*tektonv1.PipelineRun{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-pipeline",
+						Annotations: map[string]string{
+							keys.OnEvent:              "pull_request",
+							keys.OnTargetBranch:       "[main, release-*]",
+							keys.OnTargetBranchIgnore: "[release-1.0]",
+							keys.OnLabelIgnore:        "[skip-ci]",
+						},
+					},
+				},
+			},
+			event: &info.Event{
+				EventType:  "pull_request",
+				HeadBranch: "feature",
+				BaseBranch: "release-1.0",
+			},
+		},
 		// Add more test cases as needed
 	}
 
@@ -2820,6 +3334,97 @@ func TestGetTargetBranch(t *testing.T) {
 			},
 			expectedError: fmt.Sprintf("annotation %s is empty", keys.OnTargetBranch),
 		},
+		{
+			name: "Test ignored target branch",
+			prun: &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						keys.OnEvent:              "pull_request",
+						keys.OnTargetBranch:       "[main, release-*]",
+						keys.OnTargetBranchIgnore: "[release-1.0]",
+					},
+				},
+			},
+			event: &info.Event{
+				TriggerTarget: triggertype.PullRequest,
+				EventType:     "pull_request",
+				BaseBranch:    "release-1.0",
+			},
+			expectedMatch: false,
+		},
+		{
+			name: "Test target branch not ignored",
+			prun: &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						keys.OnEvent:              "pull_request",
+						keys.OnTargetBranch:       "[main, release-*]",
+						keys.OnTargetBranchIgnore: "[release-1.0]",
+					},
+				},
+			},
+			event: &info.Event{
+				TriggerTarget: triggertype.PullRequest,
+				EventType:     "pull_request",
+				BaseBranch:    "release-2.0",
+			},
+			expectedMatch:  true,
+			expectedEvent:  "pull_request",
+			expectedBranch: "[main, release-*]",
+		},
+		{
+			name: "Test ignored target branch without on-target-branch",
+			prun: &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						keys.OnEvent:              "push",
+						keys.OnTargetBranchIgnore: "[gh-pages]",
+					},
+				},
+			},
+			event: &info.Event{
+				TriggerTarget: triggertype.Push,
+				EventType:     "push",
+				BaseBranch:    "gh-pages",
+			},
+			expectedMatch: false,
+		},
+		{
+			name: "Test target branch not ignored without on-target-branch",
+			prun: &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						keys.OnEvent:              "push",
+						keys.OnTargetBranchIgnore: "[gh-pages]",
+					},
+				},
+			},
+			event: &info.Event{
+				TriggerTarget: triggertype.Push,
+				EventType:     "push",
+				BaseBranch:    "main",
+			},
+			expectedMatch:  true,
+			expectedEvent:  "push",
+			expectedBranch: "*",
+		},
+		{
+			name: "Test empty array onTargetBranchIgnore",
+			prun: &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						keys.OnEvent:              "pull_request",
+						keys.OnTargetBranchIgnore: "[]",
+					},
+				},
+			},
+			event: &info.Event{
+				TriggerTarget: triggertype.PullRequest,
+				EventType:     "pull_request",
+				BaseBranch:    "main",
+			},
+			expectedError: fmt.Sprintf("annotation %s is empty", keys.OnTargetBranchIgnore),
+		},
 	}
 
 	for _, tt := range tests {
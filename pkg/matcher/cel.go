@@ -44,6 +44,7 @@ func celEvaluate(ctx context.Context, expr string, event *info.Event, vcx provid
 		"event": true, "event_type": true, "headers": true, "body": true,
 		"event_title": true, "target_branch": true, "source_branch": true,
 		"target_url": true, "source_url": true, "files": true,
+		"review_state": true, "reviewer": true, "merged": true, "is_draft": true,
 	}
 
 	varDecls := []cel.EnvOption{
@@ -59,6 +60,10 @@ func celEvaluate(ctx context.Context, expr string, event *info.Event, vcx provid
 			decls.NewVariable("target_url", types.StringType),
 			decls.NewVariable("source_url", types.StringType),
 			decls.NewVariable("files", types.NewMapType(types.StringType, types.DynType)),
+			decls.NewVariable("review_state", types.StringType),
+			decls.NewVariable("reviewer", types.StringType),
+			decls.NewVariable("merged", types.BoolType),
+			decls.NewVariable("is_draft", types.BoolType),
 		),
 	}
 
@@ -131,8 +136,17 @@ func celEvaluate(ctx context.Context, expr string, event *info.Event, vcx provid
 		}
 	}
 
+	// A review is only matched by the expressions targeting it, not by the
+	// ones matching any pull request event.
+	targetEvent := event.TriggerTarget.String()
+	reviewer := ""
+	if event.EventType == triggertype.PullRequestReview.String() {
+		targetEvent = triggertype.PullRequestReview.String()
+		reviewer = event.Sender
+	}
+
 	data := map[string]any{
-		"event":         event.TriggerTarget.String(),
+		"event":         targetEvent,
 		"event_type":    event.EventType,
 		"event_title":   eventTitle,
 		"target_branch": event.BaseBranch,
@@ -141,6 +155,10 @@ func celEvaluate(ctx context.Context, expr string, event *info.Event, vcx provid
 		"source_url":    event.HeadURL,
 		"body":          jsonMap,
 		"headers":       headerMap,
+		"review_state":  event.PullRequestReviewState,
+		"reviewer":      reviewer,
+		"merged":        event.PullRequestMerged,
+		"is_draft":      event.IsDraft,
 		"files": map[string]any{
 			"all":      changedFiles.All,
 			"added":    changedFiles.Added,
@@ -171,6 +189,29 @@ func celEvaluate(ctx context.Context, expr string, event *info.Event, vcx provid
 	return out, nil
 }
 
+// EvaluatePriority evaluates the priority expression of a Repository, it has
+// the same variables as the on-cel-expression annotation and must return an
+// integer.
+func EvaluatePriority(ctx context.Context, expr string, event *info.Event, vcx provider.Interface, eventEmitter *events.EventEmitter, repo *apipac.Repository) (int, error) {
+	// celEvaluate strips the refs/heads/ prefix of the branches of the event
+	copied := *event
+	out, err := celEvaluate(ctx, expr, &copied, vcx, nil, eventEmitter, repo)
+	if err != nil {
+		return 0, err
+	}
+	switch value := out.Value().(type) {
+	case int64:
+		return int(value), nil
+	case bool:
+		// label events are skipped by the expressions not referencing the
+		// labels, they keep the default priority.
+		if !value && event.EventType == string(triggertype.PullRequestLabeled) {
+			return 0, nil
+		}
+	}
+	return 0, fmt.Errorf("expression %#v returned %v, the priority must be an integer", expr, out.Value())
+}
+
 type celPac struct {
 	vcx   provider.Interface
 	ctx   context.Context
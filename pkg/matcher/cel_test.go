@@ -1,12 +1,16 @@
 package matcher
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/decls"
 	"github.com/google/cel-go/common/types"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
 // parseAndCheckForLabelReferences is a test helper that parses a CEL expression
@@ -195,3 +199,67 @@ func TestWalkExprForLabelReferences(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluatePriority(t *testing.T) {
+	expr := `event == "push" && target_branch == "main" ? 100 : event == "pull_request" ? 50 : 0`
+	tests := []struct {
+		name    string
+		expr    string
+		event   *info.Event
+		want    int
+		wantErr string
+	}{
+		{
+			name:  "push to main",
+			expr:  expr,
+			event: &info.Event{TriggerTarget: triggertype.Push, EventType: "push", BaseBranch: "refs/heads/main"},
+			want:  100,
+		},
+		{
+			name:  "pull request",
+			expr:  expr,
+			event: &info.Event{TriggerTarget: triggertype.PullRequest, EventType: "pull_request", BaseBranch: "main"},
+			want:  50,
+		},
+		{
+			name:  "incoming",
+			expr:  expr,
+			event: &info.Event{TriggerTarget: triggertype.Incoming, EventType: "incoming", BaseBranch: "main"},
+			want:  0,
+		},
+		{
+			name:  "label event not referencing labels",
+			expr:  expr,
+			event: &info.Event{TriggerTarget: triggertype.PullRequest, EventType: string(triggertype.PullRequestLabeled)},
+			want:  0,
+		},
+		{
+			name:    "not an integer",
+			expr:    `event == "push"`,
+			event:   &info.Event{TriggerTarget: triggertype.Push, EventType: "push"},
+			wantErr: "the priority must be an integer",
+		},
+		{
+			name:    "invalid expression",
+			expr:    `event ==`,
+			event:   &info.Event{TriggerTarget: triggertype.Push, EventType: "push"},
+			wantErr: "failed to parse expression",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			tt.event.Request = &info.Request{Header: http.Header{}}
+			baseBranch := tt.event.BaseBranch
+			got, err := EvaluatePriority(ctx, tt.expr, tt.event, nil, nil, nil)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+			// the event is not modified
+			assert.Equal(t, tt.event.BaseBranch, baseBranch)
+		})
+	}
+}
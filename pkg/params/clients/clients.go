@@ -17,6 +17,7 @@ import (
 	"go.uber.org/zap"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -36,8 +37,11 @@ type Clients struct {
 	HTTP              http.Client
 	Log               *zap.SugaredLogger
 	Dynamic           dynamic.Interface
-	consoleUIMutex    *sync.Mutex
-	consoleUI         consoleui.Interface
+	// NamespaceLister caches the namespaces, their annotations are read on
+	// every event and must not cost a request to the API server.
+	NamespaceLister corelisters.NamespaceLister
+	consoleUIMutex  *sync.Mutex
+	consoleUI       consoleui.Interface
 }
 
 func (c *Clients) InitClients() {
@@ -49,10 +49,13 @@ type Event struct {
 	SHACommitterEmail string    // committer email
 	SHACommitterDate  time.Time // when the commit was committed
 
-	PullRequestNumber int      // Pull or Merge Request number
-	PullRequestTitle  string   // Title of the pull Request
-	PullRequestLabel  []string // Labels of the pull Request
-	TriggerComment    string   // The comment triggering the pipelinerun when using on-comment annotation
+	PullRequestNumber      int      // Pull or Merge Request number
+	PullRequestTitle       string   // Title of the pull Request
+	PullRequestLabel       []string // Labels of the pull Request
+	PullRequestReviewState string   // approved or changes_requested on pull_request_review events, the reviewer is the Sender
+	PullRequestMerged      bool     // whether the pull request has been merged on pull_request_closed events
+	IsDraft                bool     // whether the pull request is a draft
+	TriggerComment         string   // The comment triggering the pipelinerun when using on-comment annotation
 
 	// HasSkipCommand indicates whether the commit message contains a skip CI command
 	// (e.g., [skip ci], [ci skip], [skip tkn], [tkn skip]). When true, PipelineRun
@@ -0,0 +1,23 @@
+package params
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/client-go/informers"
+)
+
+// StartNamespaceSync starts caching the namespaces in the NamespaceLister of
+// the clients and waits for the cache to be filled, the settings read from the
+// annotations of the namespaces are then read from the cache.
+func StartNamespaceSync(ctx context.Context, run *Run) error {
+	informerFactory := informers.NewSharedInformerFactory(run.Clients.Kube, 0)
+	run.Clients.NamespaceLister = informerFactory.Core().V1().Namespaces().Lister()
+	informerFactory.Start(ctx.Done())
+	for _, synced := range informerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to sync the namespaces cache")
+		}
+	}
+	return nil
+}
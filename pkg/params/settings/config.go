@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/configutil"
 	hubType "github.com/openshift-pipelines/pipelines-as-code/pkg/hub/vars"
@@ -52,6 +53,11 @@ type Settings struct {
 	RemoteTasks                         bool   `default:"true"                                 json:"remote-tasks"`
 	MaxKeepRunsUpperLimit               int    `json:"max-keep-run-upper-limit"`
 	DefaultMaxKeepRuns                  int    `json:"default-max-keep-runs"`
+	DefaultMaxKeepFailedRuns            int    `json:"default-max-keep-failed-runs"`
+	DefaultMaxKeepSuccessfulRuns        int    `json:"default-max-keep-successful-runs"`
+	DefaultKeepFor                      string `json:"default-keep-for"`
+	ConcurrencyLimit                    int    `json:"concurrency-limit"`
+	MaxQueueWait                        string `json:"max-queue-wait"`
 	BitbucketCloudCheckSourceIP         bool   `default:"true"                                 json:"bitbucket-cloud-check-source-ip"`
 	BitbucketCloudAdditionalSourceIP    string `json:"bitbucket-cloud-additional-source-ip"`
 	TektonDashboardURL                  string `json:"tekton-dashboard-url"`
@@ -82,6 +88,9 @@ type Settings struct {
 
 	RememberOKToTest   bool `json:"remember-ok-to-test"`
 	RequireOkToTestSHA bool `json:"require-ok-to-test-sha"`
+
+	LLMAnalysisCacheSize int    `default:"100" json:"llm-analysis-cache-size"`
+	LLMAnalysisCacheTTL  string `default:"24h" json:"llm-analysis-cache-ttl"`
 }
 
 func (s *Settings) DeepCopy(out *Settings) {
@@ -110,6 +119,9 @@ func DefaultValidators() map[string]func(string) error {
 		"CustomConsoleURL":           isValidURL,
 		"CustomConsolePRTaskLog":     startWithHTTPorHTTPS,
 		"CustomConsolePRDetail":      startWithHTTPorHTTPS,
+		"MaxQueueWait":               isValidDuration,
+		"DefaultKeepFor":             isValidDuration,
+		"LLMAnalysisCacheTTL":        isValidDuration,
 	}
 }
 
@@ -153,6 +165,17 @@ func isValidRegex(regex string) error {
 	return nil
 }
 
+func isValidDuration(duration string) error {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+	if d < 0 {
+		return fmt.Errorf("invalid duration: %s is negative", duration)
+	}
+	return nil
+}
+
 func startWithHTTPorHTTPS(url string) error {
 	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
 		return fmt.Errorf("invalid value, must start with http:// or https://")
@@ -49,6 +49,8 @@ func TestSyncConfig(t *testing.T) {
 				CustomConsolePRTaskLog:               "",
 				CustomConsoleNamespaceURL:            "",
 				RememberOKToTest:                     false,
+				LLMAnalysisCacheSize:                 100,
+				LLMAnalysisCacheTTL:                  "24h",
 			},
 		},
 		{
@@ -79,6 +81,12 @@ func TestSyncConfig(t *testing.T) {
 				"remember-ok-to-test":                     "false",
 				"skip-push-event-for-pr-commits":          "true",
 				"require-ok-to-test-sha":                  "true",
+				"max-queue-wait":                          "2h",
+				"default-max-keep-failed-runs":            "20",
+				"default-max-keep-successful-runs":        "3",
+				"default-keep-for":                        "72h",
+				"llm-analysis-cache-size":                 "10",
+				"llm-analysis-cache-ttl":                  "1h",
 			},
 			expectedStruct: Settings{
 				ApplicationName:                      "pac-pac",
@@ -110,6 +118,12 @@ func TestSyncConfig(t *testing.T) {
 				CustomConsoleNamespaceURL:            "https://custom-console-namespace",
 				RememberOKToTest:                     false,
 				RequireOkToTestSHA:                   true,
+				MaxQueueWait:                         "2h",
+				DefaultMaxKeepFailedRuns:             20,
+				DefaultMaxKeepSuccessfulRuns:         3,
+				DefaultKeepFor:                       "72h",
+				LLMAnalysisCacheSize:                 10,
+				LLMAnalysisCacheTTL:                  "1h",
 			},
 		},
 		{
@@ -133,6 +147,27 @@ func TestSyncConfig(t *testing.T) {
 			},
 			expectedError: "custom validation failed for field ErrorDetectionSimpleRegexp: invalid regex: error parsing regexp: missing closing ]: `[`",
 		},
+		{
+			name: "invalid value duration",
+			configMap: map[string]string{
+				"max-queue-wait": "forever",
+			},
+			expectedError: "custom validation failed for field MaxQueueWait: invalid duration: time: invalid duration \"forever\"",
+		},
+		{
+			name: "negative value duration",
+			configMap: map[string]string{
+				"max-queue-wait": "-1h",
+			},
+			expectedError: "custom validation failed for field MaxQueueWait: invalid duration: -1h is negative",
+		},
+		{
+			name: "invalid value keep for",
+			configMap: map[string]string{
+				"default-keep-for": "3d",
+			},
+			expectedError: "custom validation failed for field DefaultKeepFor: invalid duration: time: unknown unit \"d\" in duration \"3d\"",
+		},
 		{
 			name: "invalid value url",
 			configMap: map[string]string{
@@ -8,7 +8,7 @@ type (
 func IsPullRequestType(s string) Trigger {
 	eventType := s
 	switch s {
-	case PullRequest.String(), OkToTest.String(), Retest.String(), Cancel.String(), PullRequestLabeled.String():
+	case PullRequest.String(), OkToTest.String(), Retest.String(), Cancel.String(), PullRequestLabeled.String(), PullRequestReview.String():
 		eventType = PullRequest.String()
 	}
 	return Trigger(eventType)
@@ -40,6 +40,14 @@ func StringToType(s string) Trigger {
 		return Comment
 	case PullRequestLabeled.String():
 		return PullRequestLabeled
+	case PullRequestReview.String():
+		return PullRequestReview
+	case PullRequestClosed.String():
+		return PullRequestClosed
+	case Schedule.String():
+		return Schedule
+	case MergeGroup.String():
+		return MergeGroup
 	}
 	return ""
 }
@@ -50,10 +58,13 @@ const (
 	CheckSuiteRerequested Trigger = "check-suite-rerequested"
 	Comment               Trigger = "comment"
 	Incoming              Trigger = "incoming"
+	MergeGroup            Trigger = "merge_group"
 	PullRequestLabeled    Trigger = "pull_request_labeled"
 	OkToTest              Trigger = "ok-to-test"
 	PullRequestClosed     Trigger = "pull_request_closed"
+	PullRequestReview     Trigger = "pull_request_review"
 	PullRequest           Trigger = "pull_request" // it's should be "pull_request_opened_updated" but let's keep it simple.
 	Push                  Trigger = "push"
 	Retest                Trigger = "retest"
+	Schedule              Trigger = "schedule"
 )
@@ -2,7 +2,9 @@ package pipelineascode
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,11 +15,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 )
@@ -44,7 +48,7 @@ func (p *PacRun) cancelAllInProgressBelongingToClosedPullRequest(ctx context.Con
 	// First, build the label selector based on the URLRepository and PullRequest fields,
 	// followed by applying filtering logic for the 'cancel-in-progress' annotation.
 	labelSelector := getLabelSelector(labelsMap, operator)
-	labelSelector += fmt.Sprintf(",%s in (pull_request, Merge_Request, %s)", keys.EventType, opscomments.AnyOpsKubeLabelInSelector())
+	labelSelector += fmt.Sprintf(",%s in (pull_request, Merge_Request, %s, %s)", keys.EventType, triggertype.PullRequestReview, opscomments.AnyOpsKubeLabelInSelector())
 
 	if cancelInProgress == "true" {
 		// When the 'cancel-in-progress' setting is enabled globally via the Pipelines-as-Code ConfigMap,
@@ -139,13 +143,13 @@ func (p *PacRun) cancelInProgressMatchingPipelineRun(ctx context.Context, matchP
 		keys.URLRepository:  formatting.CleanValueKubernetes(p.event.Repository),
 		keys.OriginalPRName: prName,
 	}
-	if p.event.TriggerTarget == triggertype.PullRequest {
+	if p.event.TriggerTarget == triggertype.PullRequest || p.event.TriggerTarget == triggertype.PullRequestClosed {
 		labelMap[keys.PullRequest] = strconv.Itoa(p.event.PullRequestNumber)
 	}
 	labelSelector := getLabelSelector(labelMap, selection.Equals)
 	if p.event.TriggerTarget == triggertype.PullRequest {
 		// "Merge_Request" included since EventType is not normalized to "Pull Request" like TriggerTarget
-		labelSelector += fmt.Sprintf(",%s in (pull_request, Merge_Request, %s)", keys.EventType, opscomments.AnyOpsKubeLabelInSelector())
+		labelSelector += fmt.Sprintf(",%s in (pull_request, Merge_Request, %s, %s)", keys.EventType, triggertype.PullRequestReview, opscomments.AnyOpsKubeLabelInSelector())
 	}
 	p.run.Clients.Log.Infof("cancel-in-progress: selecting pipelineRuns to cancel with labels: %v", labelSelector)
 	p.debugf("cancelInProgress: labelSelector=%s", labelSelector)
@@ -174,6 +178,99 @@ func (p *PacRun) cancelInProgressMatchingPipelineRun(ctx context.Context, matchP
 	return nil
 }
 
+// coalesceQueuedPipelineRuns cancels the queued PipelineRuns which have not
+// started yet and are superseded by matchPR, a newer queued PipelineRun with
+// the same original name for the same event type and branch. The superseded
+// PipelineRuns are annotated with the SHA of matchPR which is reported on
+// their status. Unlike cancel-in-progress the running PipelineRuns are left
+// alone.
+func (p *PacRun) coalesceQueuedPipelineRuns(ctx context.Context, matchPR *tektonv1.PipelineRun, repo *v1alpha1.Repository) error {
+	if matchPR == nil || matchPR.Spec.Status != tektonv1.PipelineRunSpecStatusPending {
+		return nil
+	}
+	if repo.Spec.Settings == nil || !repo.Spec.Settings.CoalesceQueuedPipelineRuns {
+		return nil
+	}
+	prName, ok := matchPR.GetLabels()[keys.OriginalPRName]
+	if !ok {
+		p.debugf("coalesceQueued: missing original PR name label for pipelinerun=%s", matchPR.GetName())
+		return nil
+	}
+
+	labelMap := map[string]string{
+		keys.URLRepository:  formatting.CleanValueKubernetes(p.event.Repository),
+		keys.OriginalPRName: prName,
+		keys.EventType:      matchPR.GetLabels()[keys.EventType],
+		keys.State:          kubeinteraction.StateQueued,
+	}
+	if p.event.TriggerTarget == triggertype.PullRequest {
+		labelMap[keys.PullRequest] = strconv.Itoa(p.event.PullRequestNumber)
+	}
+	labelSelector := getLabelSelector(labelMap, selection.Equals)
+	p.debugf("coalesceQueued: labelSelector=%s", labelSelector)
+	prs, err := p.run.Clients.Tekton.TektonV1().PipelineRuns(matchPR.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pipelineRuns : %w", err)
+	}
+
+	patch, err := supersededPatch(p.event.SHA)
+	if err != nil {
+		return err
+	}
+	for _, pr := range prs.Items {
+		if pr.GetName() == matchPR.GetName() || pr.Spec.Status != tektonv1.PipelineRunSpecStatusPending {
+			continue
+		}
+		// two webhooks can create their PipelineRuns in the same second,
+		// only the one created first by createdBefore is superseded so they
+		// never cancel each other
+		if !createdBefore(&pr, matchPR) {
+			continue
+		}
+		if strings.TrimPrefix(pr.GetAnnotations()[keys.SourceBranch], "refs/heads/") != strings.TrimPrefix(p.event.HeadBranch, "refs/heads/") {
+			continue
+		}
+		// the patch only applies if the PipelineRun is still pending, it may
+		// have been started by the watcher in the meantime
+		if _, err := p.run.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).Patch(ctx, pr.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+			p.logger.Infof("coalesce-queued: cannot cancel pipelinerun %v/%v, it may have started: %v", pr.GetNamespace(), pr.GetName(), err)
+			continue
+		}
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "QueuedPipelineRunSuperseded",
+			fmt.Sprintf("queued PipelineRun %s has been cancelled as it is superseded by %s for sha %s", pr.GetName(), matchPR.GetName(), p.event.SHA))
+	}
+	return nil
+}
+
+// createdBefore returns true when a was created before b. The creation
+// timestamps only have a resolution of one second, the ties are broken by the
+// execution order of the event which created them and then by name so two
+// PipelineRuns are always ordered the same way.
+func createdBefore(a, b *tektonv1.PipelineRun) bool {
+	if c := a.GetCreationTimestamp().Compare(b.GetCreationTimestamp().Time); c != 0 {
+		return c < 0
+	}
+	order := strings.Split(a.GetAnnotations()[keys.ExecutionOrder], ",")
+	posA := slices.Index(order, a.GetNamespace()+"/"+a.GetName())
+	posB := slices.Index(order, b.GetNamespace()+"/"+b.GetName())
+	if posA != -1 && posB != -1 && posA != posB {
+		return posA < posB
+	}
+	return a.GetName() < b.GetName()
+}
+
+// supersededPatch returns the JSON patch cancelling a pending PipelineRun and
+// annotating it with the SHA superseding it.
+func supersededPatch(sha string) ([]byte, error) {
+	return json.Marshal([]map[string]any{
+		{"op": "test", "path": "/spec/status", "value": tektonv1.PipelineRunSpecStatusPending},
+		{"op": "replace", "path": "/spec/status", "value": tektonv1.PipelineRunSpecStatusCancelled},
+		{"op": "add", "path": "/metadata/annotations/" + strings.ReplaceAll(keys.SupersededBy, "/", "~1"), "value": sha},
+	})
+}
+
 // cancelPipelineRunsOpsComment cancels all PipelineRuns associated with a given repository and pull request.
 // when the user issue a cancel comment.
 func (p *PacRun) cancelPipelineRunsOpsComment(ctx context.Context, repo *v1alpha1.Repository) error {
@@ -184,11 +281,21 @@ func (p *PacRun) cancelPipelineRunsOpsComment(ctx context.Context, repo *v1alpha
 		keys.SHA:           formatting.CleanValueKubernetes(p.event.SHA),
 	}, selection.Equals)
 
-	if p.event.TriggerTarget == triggertype.PullRequest {
+	switch p.event.TriggerTarget {
+	case triggertype.PullRequest:
 		labelSelector = getLabelSelector(map[string]string{
 			keys.URLRepository: formatting.CleanValueKubernetes(p.event.Repository),
 			keys.PullRequest:   strconv.Itoa(p.event.PullRequestNumber),
 		}, selection.Equals)
+	case triggertype.MergeGroup:
+		// a merged group ends up pushed as is to the base branch, leave the
+		// push pipelineruns of that commit alone.
+		labelSelector = getLabelSelector(map[string]string{
+			keys.URLRepository: formatting.CleanValueKubernetes(p.event.Repository),
+			keys.SHA:           formatting.CleanValueKubernetes(p.event.SHA),
+			keys.EventType:     triggertype.MergeGroup.String(),
+		}, selection.Equals)
+	default:
 	}
 
 	prs, err := p.run.Clients.Tekton.TektonV1().PipelineRuns(repo.Namespace).List(ctx, metav1.ListOptions{
@@ -2,12 +2,15 @@ package pipelineascode
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
@@ -332,6 +335,47 @@ func TestCancelPipelinerunOpsComment(t *testing.T) {
 				"pr-foo-abc-123": true,
 			},
 		},
+		{
+			name: "cancel the runs of a destroyed merge group only",
+			event: &info.Event{
+				Repository:    "foo",
+				SHA:           "foosha",
+				TriggerTarget: triggertype.MergeGroup,
+				State: info.State{
+					CancelPipelineRuns: true,
+				},
+			},
+			pipelineRuns: []*pipelinev1.PipelineRun{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pr-merge-group",
+						Namespace: "foo",
+						Labels: map[string]string{
+							keys.URLRepository: formatting.CleanValueKubernetes("foo"),
+							keys.SHA:           formatting.CleanValueKubernetes("foosha"),
+							keys.EventType:     triggertype.MergeGroup.String(),
+						},
+					},
+					Spec: pipelinev1.PipelineRunSpec{},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pr-push",
+						Namespace: "foo",
+						Labels: map[string]string{
+							keys.URLRepository: formatting.CleanValueKubernetes("foo"),
+							keys.SHA:           formatting.CleanValueKubernetes("foosha"),
+							keys.EventType:     triggertype.Push.String(),
+						},
+					},
+					Spec: pipelinev1.PipelineRunSpec{},
+				},
+			},
+			repo: fooRepo,
+			cancelledPipelineRuns: map[string]bool{
+				"pr-merge-group": true,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1318,6 +1362,148 @@ func TestCancelInProgressMatchingPipelineRun(t *testing.T) {
 	}
 }
 
+func TestCoalesceQueuedPipelineRuns(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	now := time.Now().Truncate(time.Second)
+
+	newQueuedPR := func(name, originalName, branch string, created time.Time, status pipelinev1.PipelineRunSpecStatus) *pipelinev1.PipelineRun {
+		return &pipelinev1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "foo",
+				CreationTimestamp: metav1.Time{Time: created},
+				Labels: map[string]string{
+					keys.URLRepository:  formatting.CleanValueKubernetes("foo"),
+					keys.OriginalPRName: originalName,
+					keys.EventType:      triggertype.Push.String(),
+					keys.State:          kubeinteraction.StateQueued,
+				},
+				Annotations: map[string]string{
+					keys.SourceBranch: branch,
+				},
+			},
+			Spec: pipelinev1.PipelineRunSpec{Status: status},
+		}
+	}
+	coalescingRepo := func(enabled bool) *v1alpha1.Repository {
+		return &v1alpha1.Repository{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "foo"},
+			Spec: v1alpha1.RepositorySpec{
+				URL:              "https://github.com/fooorg/foo",
+				ConcurrencyLimit: github.Ptr(1),
+				Settings:         &v1alpha1.Settings{CoalesceQueuedPipelineRuns: enabled},
+			},
+		}
+	}
+	event := &info.Event{
+		Repository:    "foo",
+		SHA:           "newsha",
+		HeadBranch:    "refs/heads/main",
+		EventType:     triggertype.Push.String(),
+		TriggerTarget: triggertype.Push,
+	}
+	pending := pipelinev1.PipelineRunSpecStatus(pipelinev1.PipelineRunSpecStatusPending)
+
+	tests := []struct {
+		name       string
+		repo       *v1alpha1.Repository
+		matchPR    *pipelinev1.PipelineRun
+		superseded []string
+	}{
+		{
+			name:    "coalescing disabled",
+			repo:    coalescingRepo(false),
+			matchPR: newQueuedPR("push-foo-new", "push-foo", "main", now, pending),
+		},
+		{
+			name:    "new pipelinerun is not queued",
+			repo:    coalescingRepo(true),
+			matchPR: newQueuedPR("push-foo-new", "push-foo", "main", now, ""),
+		},
+		{
+			name:       "older queued pipelineruns are superseded",
+			repo:       coalescingRepo(true),
+			matchPR:    newQueuedPR("push-foo-new", "push-foo", "main", now, pending),
+			superseded: []string{"push-foo-old", "push-foo-older", "push-foo-a-same-second"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			tdata := testclient.Data{
+				PipelineRuns: []*pipelinev1.PipelineRun{
+					tt.matchPR,
+					newQueuedPR("push-foo-old", "push-foo", "refs/heads/main", now.Add(-time.Minute), pending),
+					newQueuedPR("push-foo-older", "push-foo", "main", now.Add(-2*time.Minute), pending),
+					// created in the same second, the name breaks the tie
+					newQueuedPR("push-foo-a-same-second", "push-foo", "main", now, pending),
+					newQueuedPR("push-foo-z-same-second", "push-foo", "main", now, pending),
+					// started by the watcher while still labelled as queued
+					newQueuedPR("push-foo-starting", "push-foo", "main", now.Add(-3*time.Minute), ""),
+					newQueuedPR("push-foo-other-branch", "push-foo", "other", now.Add(-time.Minute), pending),
+					newQueuedPR("push-bar-old", "push-bar", "main", now.Add(-time.Minute), pending),
+				},
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			cs := &params.Run{
+				Clients: clients.Clients{
+					Log:    logger,
+					Tekton: stdata.Pipeline,
+					Kube:   stdata.Kube,
+				},
+			}
+			pac := NewPacs(event, nil, cs, &info.PacOpts{}, nil, logger, nil)
+			assert.NilError(t, pac.coalesceQueuedPipelineRuns(ctx, tt.matchPR, tt.repo))
+
+			got, err := cs.Clients.Tekton.TektonV1().PipelineRuns("foo").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			for _, pr := range got.Items {
+				if slices.Contains(tt.superseded, pr.GetName()) {
+					assert.Equal(t, string(pr.Spec.Status), pipelinev1.PipelineRunSpecStatusCancelled, pr.GetName())
+					assert.Equal(t, pr.GetAnnotations()[keys.SupersededBy], "newsha", pr.GetName())
+					continue
+				}
+				assert.Assert(t, string(pr.Spec.Status) != pipelinev1.PipelineRunSpecStatusCancelled, pr.GetName())
+				assert.Equal(t, pr.GetAnnotations()[keys.SupersededBy], "", pr.GetName())
+			}
+		})
+	}
+}
+
+func TestCreatedBefore(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	newPR := func(name string, created time.Time, order string) *pipelinev1.PipelineRun {
+		return &pipelinev1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "ns",
+				CreationTimestamp: metav1.Time{Time: created},
+				Annotations:       map[string]string{keys.ExecutionOrder: order},
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		a, b *pipelinev1.PipelineRun
+		want bool
+	}{
+		{name: "older", a: newPR("b", now.Add(-time.Second), ""), b: newPR("a", now, ""), want: true},
+		{name: "newer", a: newPR("a", now, ""), b: newPR("b", now.Add(-time.Second), ""), want: false},
+		{name: "same second by execution order", a: newPR("b", now, "ns/b,ns/a"), b: newPR("a", now, "ns/b,ns/a"), want: true},
+		{name: "same second by name", a: newPR("a", now, ""), b: newPR("b", now, ""), want: true},
+		{name: "same pipelinerun", a: newPR("a", now, ""), b: newPR("a", now, ""), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, createdBefore(tt.a, tt.b), tt.want)
+			if tt.a.GetName() != tt.b.GetName() {
+				assert.Equal(t, createdBefore(tt.b, tt.a), !tt.want)
+			}
+		})
+	}
+}
+
 func TestCancelAllInProgressBelongingToClosedPullRequest(t *testing.T) {
 	observer, _ := zapobserver.New(zap.InfoLevel)
 	logger := zap.New(observer).Sugar()
@@ -10,6 +10,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
 	"go.uber.org/zap"
@@ -68,8 +69,9 @@ func SetupAuthenticatedClient(
 	// Set up the authenticated client
 	eventEmitter := events.NewEventEmitter(run.Clients.Kube, logger)
 
-	// Validate payload with webhook secret (skip for incoming webhooks)
-	if event.EventType != "incoming" {
+	// Validate payload with webhook secret (skip for incoming webhooks and
+	// scheduled runs which don't come from the git provider)
+	if event.EventType != "incoming" && event.EventType != triggertype.Schedule.String() {
 		logger.Debugf("setupAuthenticatedClient: validating webhook payload for event_type=%s", event.EventType)
 		if err := vcx.Validate(ctx, run, event); err != nil {
 			// check that webhook secret has no /n or space into it
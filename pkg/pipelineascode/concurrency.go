@@ -1,11 +1,19 @@
 package pipelineascode
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"sync"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kueue"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/queue"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
 	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -85,3 +93,96 @@ func getOrderByName(runs []*v1.PipelineRun) string {
 	}
 	return order
 }
+
+// hasConcurrencyGroup returns true when a matched PipelineRun belongs to a
+// concurrency group defined in the repository.
+func hasConcurrencyGroup(repo *v1alpha1.Repository, matchedPRs []matcher.Match) bool {
+	for _, match := range matchedPRs {
+		if match.PipelineRun != nil && repo.Spec.GetConcurrencyGroup(match.PipelineRun.GetAnnotations()[keys.ConcurrencyGroup]) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getQueueExecutionOrder returns the execution order of the PipelineRuns of
+// runs sharing the queue of pr, the queue of its concurrency group or of the
+// repository. It returns false when pr is not queued, a pipelineRun without
+// limit is queued when hasLimits is true as a namespace or global limit applies.
+func getQueueExecutionOrder(repo *v1alpha1.Repository, pr *v1.PipelineRun, runs []*v1.PipelineRun, hasLimits bool) (string, bool) {
+	queueKey, limit := queue.QueueKey(repo, pr)
+	if (limit == nil || *limit == 0) && !hasLimits {
+		return "", false
+	}
+	sameQueue := []*v1.PipelineRun{}
+	for _, run := range runs {
+		if key, _ := queue.QueueKey(repo, run); key == queueKey {
+			sameQueue = append(sameQueue, run)
+		}
+	}
+	return getOrderByName(sameQueue), true
+}
+
+// getConcurrencyLimits returns true when the global concurrency limit of the
+// pipelines-as-code configmap or the concurrency limit annotation of the
+// namespace of the repository is set. An invalid annotation is reported by the
+// watcher when the namespace changes, it is ignored here.
+func (p *PacRun) getConcurrencyLimits(repo *v1alpha1.Repository) bool {
+	if p.pacInfo != nil && p.pacInfo.ConcurrencyLimit > 0 {
+		return true
+	}
+	if p.run.Clients.NamespaceLister == nil {
+		return false
+	}
+	ns, err := p.run.Clients.NamespaceLister.Get(repo.GetNamespace())
+	if err != nil {
+		p.logger.Warnf("cannot get the concurrency limit of namespace %s: %v", repo.GetNamespace(), err)
+		return false
+	}
+	limit, err := queue.NamespaceLimit(ns)
+	if err != nil {
+		p.debugf("getConcurrencyLimits: %v", err)
+		return false
+	}
+	return limit > 0
+}
+
+// getKueueQueueName returns the kueue queue admitting the PipelineRuns of the
+// repository, an empty string when they are not admitted by kueue.
+func (p *PacRun) getKueueQueueName(repo *v1alpha1.Repository) string {
+	queueName, err := kueue.GetQueueName(p.run.Clients.NamespaceLister, repo)
+	if err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryKueueQueue", err.Error())
+		return ""
+	}
+	return queueName
+}
+
+// setPriority sets the priority annotation of a queued PipelineRun from the
+// priority expression of the repository, the priority annotation of the
+// PipelineRun takes precedence over the expression.
+func (p *PacRun) setPriority(ctx context.Context, match matcher.Match, prName string) {
+	annotations := match.PipelineRun.GetAnnotations()
+	if value, ok := annotations[keys.Priority]; ok {
+		if _, err := strconv.Atoi(value); err != nil {
+			p.eventEmitter.EmitMessage(match.Repo, zap.WarnLevel, "RepositoryPriority",
+				fmt.Sprintf("priority %q of PipelineRun %s is not an integer, the default priority is used", value, prName))
+		}
+		return
+	}
+	if match.Repo.Spec.PriorityExpression == "" {
+		return
+	}
+	priority, err := matcher.EvaluatePriority(ctx, match.Repo.Spec.PriorityExpression, p.event, p.vcx, p.eventEmitter, match.Repo)
+	if err != nil {
+		p.eventEmitter.EmitMessage(match.Repo, zap.WarnLevel, "RepositoryPriority",
+			fmt.Sprintf("cannot compute the priority of PipelineRun %s, the default priority is used: %s", prName, err.Error()))
+		return
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[keys.Priority] = strconv.Itoa(priority)
+	match.PipelineRun.SetAnnotations(annotations)
+	p.debugf("startPR: pipelinerun=%s has priority %d", prName, priority)
+}
@@ -3,9 +3,18 @@ package pipelineascode
 import (
 	"testing"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 func TestExecutionOrder(t *testing.T) {
@@ -44,3 +53,73 @@ func TestExecutionOrder_SinglePRun(t *testing.T) {
 	assert.Equal(t, order, "test/abc")
 	assert.Equal(t, len(runs), 1)
 }
+
+func TestQueueExecutionOrder(t *testing.T) {
+	testNs := "test"
+	limit := 2
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: testNs},
+		Spec: v1alpha1.RepositorySpec{
+			ConcurrencyLimit:  &limit,
+			ConcurrencyGroups: []v1alpha1.ConcurrencyGroup{{Name: "deploy", Limit: 1}},
+		},
+	}
+	group := map[string]string{keys.ConcurrencyGroup: "deploy"}
+	undefinedGroup := map[string]string{keys.ConcurrencyGroup: "undefined"}
+	abcPR := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "abc", Namespace: testNs}}
+	defPR := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "def", Namespace: testNs, Annotations: group}}
+	mnoPR := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "mno", Namespace: testNs, Annotations: undefinedGroup}}
+	pqrPR := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pqr", Namespace: testNs, Annotations: group}}
+	runs := []*tektonv1.PipelineRun{abcPR, defPR, mnoPR, pqrPR}
+
+	order, queued := getQueueExecutionOrder(repo, abcPR, runs, false)
+	assert.Assert(t, queued)
+	assert.Equal(t, order, "test/abc,test/mno")
+
+	order, queued = getQueueExecutionOrder(repo, pqrPR, runs, false)
+	assert.Assert(t, queued)
+	assert.Equal(t, order, "test/def,test/pqr")
+
+	// without a concurrency limit only the PipelineRuns of the group are queued
+	repo.Spec.ConcurrencyLimit = nil
+	_, queued = getQueueExecutionOrder(repo, abcPR, runs, false)
+	assert.Assert(t, !queued)
+	order, queued = getQueueExecutionOrder(repo, defPR, runs, false)
+	assert.Assert(t, queued)
+	assert.Equal(t, order, "test/def,test/pqr")
+	// with a namespace or global limit all the PipelineRuns are queued
+	order, queued = getQueueExecutionOrder(repo, abcPR, runs, true)
+	assert.Assert(t, queued)
+	assert.Equal(t, order, "test/abc,test/mno")
+}
+
+func TestGetConcurrencyLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		globalLimit int
+		annotations map[string]string
+		want        bool
+	}{
+		{name: "no limit", want: false},
+		{name: "global limit", globalLimit: 5, want: true},
+		{name: "namespace limit", annotations: map[string]string{keys.ConcurrencyLimit: "2"}, want: true},
+		{name: "invalid namespace limit", annotations: map[string]string{keys.ConcurrencyLimit: "many"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			assert.NilError(t, indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns", Annotations: tt.annotations}}))
+			pacInfo := info.NewPacOpts()
+			pacInfo.ConcurrencyLimit = tt.globalLimit
+			p := &PacRun{
+				run: &params.Run{
+					Clients: clients.Clients{NamespaceLister: corelisters.NewNamespaceLister(indexer)},
+				},
+				pacInfo: pacInfo,
+				logger:  zap.NewNop().Sugar(),
+			}
+			repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}}
+			assert.Equal(t, p.getConcurrencyLimits(repo), tt.want)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package pipelineascode
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/decisiontrace"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// emitDecisionTrace stores the decision trace of the event in the decision
+// traces ConfigMap of the matched Repository, to be rendered by tkn pac
+// explain, and emits its summary as a Kubernetes event on the Repository. The
+// trace is only stored in the namespaces where the controller has been
+// allowed to manage the ConfigMap.
+func (p *PacRun) emitDecisionTrace(ctx context.Context, trace *decisiontrace.Trace) {
+	repo := trace.GetRepository()
+	if repo == nil {
+		return
+	}
+	trace.SetEvent(p.event)
+	if err := decisiontrace.Store(ctx, p.run.Clients.Kube, repo, trace); errors.IsForbidden(err) {
+		p.logger.Debugf("not allowed to store the decision trace in namespace %s: %v", repo.GetNamespace(), err)
+	} else if err != nil {
+		p.logger.Warnf("cannot store the decision trace in repository %s/%s: %v", repo.GetNamespace(), repo.GetName(), err)
+	}
+	p.eventEmitter.EmitMessageWithAnnotations(repo, zap.InfoLevel, decisiontrace.Reason, trace.Summary(), map[string]string{
+		keys.SHA: p.event.SHA,
+	})
+}
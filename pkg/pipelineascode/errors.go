@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/decisiontrace"
 	pacerrors "github.com/openshift-pipelines/pipelines-as-code/pkg/errors"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
@@ -25,8 +26,10 @@ func (p *PacRun) checkAccessOrError(ctx context.Context, repo *v1alpha1.Reposito
 	if err != nil {
 		return false, fmt.Errorf("unable to verify event authorization: %w", err)
 	}
+	trace := decisiontrace.FromContext(ctx)
 	if allowed {
 		p.debugf("checkAccessOrError: access granted for sender=%s", p.event.Sender)
+		trace.AccessChecked(decisiontrace.AccessCheckACL, true, fmt.Sprintf("user %s is allowed to trigger CI %s", p.event.Sender, viamsg))
 		return true, nil
 	}
 	msg := fmt.Sprintf("User %s is not allowed to trigger CI %s in this repo.", p.event.Sender, viamsg)
@@ -34,6 +37,7 @@ func (p *PacRun) checkAccessOrError(ctx context.Context, repo *v1alpha1.Reposito
 		msg = fmt.Sprintf("User: %s AccountID: %s is not allowed to trigger CI %s in this repo.", p.event.Sender, p.event.AccountID, viamsg)
 	}
 	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryPermissionDenied", msg)
+	trace.AccessChecked(decisiontrace.AccessCheckACL, false, msg)
 	status.Text = msg
 
 	if err := p.vcx.CreateStatus(ctx, p.event, status); err != nil {
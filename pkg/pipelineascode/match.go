@@ -9,6 +9,7 @@ import (
 
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/decisiontrace"
 	pacerrors "github.com/openshift-pipelines/pipelines-as-code/pkg/errors"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
@@ -37,6 +38,21 @@ func (p *PacRun) matchRepoPR(ctx context.Context) ([]matcher.Match, *v1alpha1.Re
 		return nil, repo, p.cancelPipelineRunsOpsComment(ctx, repo)
 	}
 
+	// The in-progress PipelineRuns of a closed pull request are cancelled
+	// before matching the PipelineRuns running on the pull_request_closed event.
+	if p.event.TriggerTarget == triggertype.PullRequestClosed {
+		p.debugf("matchRepoPR: pull request closed, cancelling in-progress pipelineRuns")
+		if err := p.cancelAllInProgressBelongingToClosedPullRequest(ctx, repo); err != nil {
+			return nil, repo, fmt.Errorf("error cancelling in progress pipelineRuns belonging to pull request %d: %w", p.event.PullRequestNumber, err)
+		}
+	}
+
+	if p.event.IsDraft && repo.Spec.Settings != nil && repo.Spec.Settings.SkipDraftPullRequests &&
+		p.event.TriggerTarget == triggertype.PullRequest && !opscomments.IsAnyOpsEventType(p.event.EventType) {
+		p.debugf("matchRepoPR: skipping draft pull request %d", p.event.PullRequestNumber)
+		return nil, repo, p.skipDraftPullRequest(ctx, repo)
+	}
+
 	p.debugf("matchRepoPR: fetching pipelineruns from repo=%s/%s", repo.GetNamespace(), repo.GetName())
 	matchedPRs, err := p.getPipelineRunsFromRepo(ctx, repo)
 	if err != nil {
@@ -63,6 +79,7 @@ func (p *PacRun) verifyRepoAndUser(ctx context.Context) (*v1alpha1.Repository, e
 		return nil, nil
 	}
 	p.debugf("verifyRepoAndUser: matched repo=%s/%s", repo.GetNamespace(), repo.GetName())
+	decisiontrace.FromContext(ctx).SetRepository(repo)
 
 	p.logger = p.logger.With("namespace", repo.Namespace)
 	p.vcx.SetLogger(p.logger)
@@ -104,10 +121,32 @@ func (p *PacRun) verifyRepoAndUser(ctx context.Context) (*v1alpha1.Repository, e
 		}
 	}
 
+	// A review from a user who is not allowed to run the CI is ignored, there
+	// is no /ok-to-test to wait for and the status of the pull request is left
+	// as is.
+	if p.event.EventType == triggertype.PullRequestReview.String() {
+		p.debugf("verifyRepoAndUser: checking access for reviewer=%s", p.event.Sender)
+		allowed, err := p.vcx.IsAllowed(ctx, p.event)
+		if err != nil {
+			return nil, fmt.Errorf("unable to verify event authorization: %w", err)
+		}
+		if !allowed {
+			msg := fmt.Sprintf("User %s is not allowed to trigger CI via %s in this repo.", p.event.Sender, triggertype.PullRequestReview)
+			p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryPermissionDenied", msg)
+			decisiontrace.FromContext(ctx).AccessChecked(decisiontrace.AccessCheckACL, false, msg)
+			return nil, nil
+		}
+		decisiontrace.FromContext(ctx).AccessChecked(decisiontrace.AccessCheckACL, true,
+			fmt.Sprintf("user %s is allowed to trigger CI via %s", p.event.Sender, triggertype.PullRequestReview))
+		return repo, nil
+	}
+
 	// Check if the submitter is allowed to run this.
-	// on push we don't need to check the policy since the user has pushed to the repo so it has access to it.
+	// on push we don't need to check the policy since the user has pushed to the repo so it has access to it,
+	// the same goes for a merge group which has been queued by a user allowed to merge.
 	// on comment we skip it for now, we are going to check later on
-	if p.event.TriggerTarget != triggertype.Push && p.event.EventType != opscomments.NoOpsCommentEventType.String() {
+	if p.event.TriggerTarget != triggertype.Push && p.event.TriggerTarget != triggertype.MergeGroup &&
+		p.event.EventType != opscomments.NoOpsCommentEventType.String() {
 		p.debugf("verifyRepoAndUser: checking access for trigger target=%s event_type=%s", p.event.TriggerTarget, p.event.EventType)
 		status := provider.StatusOpts{
 			Status:       queuedStatus,
@@ -172,6 +211,15 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 		p.debugf("getPipelineRunsFromRepo: fetched templates length=%d", len(rawTemplates))
 	}
 
+	// Most repositories have nothing to run when a pull request is closed, the
+	// in-progress PipelineRuns have already been cancelled and there is
+	// nothing to resolve or report when no template names the event.
+	if p.event.TriggerTarget == triggertype.PullRequestClosed &&
+		(err != nil || !strings.Contains(rawTemplates, triggertype.PullRequestClosed.String())) {
+		p.debugf("getPipelineRunsFromRepo: no pipelinerun for the %s event", triggertype.PullRequestClosed)
+		return nil, nil
+	}
+
 	if rawTemplates == "" && p.event.EventType == opscomments.OkToTestCommentEventType.String() {
 		err = p.createNeutralStatus(ctx, ".tekton directory not found", tektonDirMissingError)
 		if err != nil {
@@ -179,6 +227,11 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 		}
 	}
 
+	// the scheduled pipelineruns have been removed with the .tekton directory
+	if err == nil && rawTemplates == "" && p.isDefaultBranchPush() {
+		p.updateSchedules(ctx, repo, nil)
+	}
+
 	// This is for push event error logging because we can't create comment for yaml validation errors on push
 	if err != nil || rawTemplates == "" {
 		msg := ""
@@ -257,11 +310,20 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 	}
 	p.debugf("getPipelineRunsFromRepo: metadata resolved for pipelineRuns count=%d", len(pipelineRuns))
 
+	if p.isDefaultBranchPush() {
+		p.updateSchedules(ctx, repo, pipelineRuns)
+	}
+
 	// Match the PipelineRun with annotation
 	var matchedPRs []matcher.Match
 	if p.event.TargetTestPipelineRun == "" {
 		if matchedPRs, err = matcher.MatchPipelinerunByAnnotation(ctx, p.logger, pipelineRuns, p.run, p.event, p.vcx, p.eventEmitter, repo, true); err != nil {
-			// Don't fail when you don't have a match between pipeline and annotations
+			// Don't fail when you don't have a match between pipeline and annotations,
+			// a closed pull request not matching anything is expected
+			if p.event.TriggerTarget == triggertype.PullRequestClosed {
+				p.debugf("getPipelineRunsFromRepo: no match for the closed pull request: %v", err)
+				return nil, nil
+			}
 			p.eventEmitter.EmitMessage(nil, zap.WarnLevel, "RepositoryNoMatch", err.Error())
 			// In a scenario where an external user submits a pull request and the repository owner uses the
 			// GitOps command `/ok-to-test` to trigger CI, but no matching pull request is found,
@@ -477,6 +539,15 @@ func (p *PacRun) checkNeedUpdate(_ string) (string, bool) {
 	return "", false
 }
 
+// skipDraftPullRequest reports a neutral status explaining that the
+// PipelineRuns of a draft pull request are not run until it is ready for review.
+func (p *PacRun) skipDraftPullRequest(ctx context.Context, repo *v1alpha1.Repository) error {
+	msg := fmt.Sprintf("pull request %d is a draft, the PipelineRuns are skipped until it is marked as ready for review", p.event.PullRequestNumber)
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositorySkipDraftPullRequest", msg)
+	// no text, the providers would comment it on the pull request at every push
+	return p.createNeutralStatus(ctx, "Skipped draft pull request", "")
+}
+
 func (p *PacRun) createNeutralStatus(ctx context.Context, title, text string) error {
 	p.debugf("createNeutralStatus: title=%s", title)
 	status := provider.StatusOpts{
@@ -292,6 +292,17 @@ func TestGetPipelineRunsFromRepo(t *testing.T) {
 		EventType:     "ok-to-test-comment",
 		TriggerTarget: "pull_request",
 	}
+	closedPullRequestEvent := &info.Event{
+		SHA:           "principale",
+		Organization:  "organizationes",
+		Repository:    "lagaffe",
+		URL:           "https://service/documentation",
+		HeadBranch:    "main",
+		BaseBranch:    "main",
+		Sender:        "fantasio",
+		EventType:     "pull_request",
+		TriggerTarget: "pull_request_closed",
+	}
 	testExplicitNoMatchPREvent := &info.Event{
 		SHA:           "principale",
 		Organization:  "organizationes",
@@ -313,6 +324,7 @@ func TestGetPipelineRunsFromRepo(t *testing.T) {
 		expectedNumberOfPruns int
 		event                 *info.Event
 		logSnippet            string
+		unexpectedLogSnippet  string
 	}{
 		{
 			name: "more than one pipelinerun in .tekton dir",
@@ -399,6 +411,47 @@ func TestGetPipelineRunsFromRepo(t *testing.T) {
 			expectedNumberOfPruns: 0,
 			event:                 okToTestEvent,
 		},
+		{
+			name: "pipelinerun on the closed pull request",
+			repositories: &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testrepo",
+					Namespace: "test",
+				},
+				Spec: v1alpha1.RepositorySpec{},
+			},
+			tektondir:             "testdata/pull_request_closed",
+			expectedNumberOfPruns: 1,
+			event:                 closedPullRequestEvent,
+		},
+		{
+			name: "no pipelinerun on the closed pull request",
+			repositories: &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testrepo",
+					Namespace: "test",
+				},
+				Spec: v1alpha1.RepositorySpec{},
+			},
+			tektondir:             "testdata/pull_request",
+			expectedNumberOfPruns: 0,
+			event:                 closedPullRequestEvent,
+			unexpectedLogSnippet:  "matching pipelineruns to event",
+		},
+		{
+			name: "no .tekton dir in repository on the closed pull request",
+			repositories: &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testrepo",
+					Namespace: "test",
+				},
+				Spec: v1alpha1.RepositorySpec{},
+			},
+			tektondir:             "testdata/no_tekton_dir",
+			expectedNumberOfPruns: 0,
+			event:                 closedPullRequestEvent,
+			unexpectedLogSnippet:  "cannot locate templates",
+		},
 		{
 			name: "no .tekton dir in repository",
 			repositories: &v1alpha1.Repository{
@@ -462,6 +515,9 @@ func TestGetPipelineRunsFromRepo(t *testing.T) {
 			if tt.logSnippet != "" {
 				assert.Assert(t, logCatcher.FilterMessageSnippet(tt.logSnippet).Len() > 0, logCatcher.All())
 			}
+			if tt.unexpectedLogSnippet != "" {
+				assert.Equal(t, logCatcher.FilterMessageSnippet(tt.unexpectedLogSnippet).Len(), 0, logCatcher.All())
+			}
 			assert.Equal(t, len(matchedPRNames), tt.expectedNumberOfPruns)
 		})
 	}
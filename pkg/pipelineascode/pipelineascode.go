@@ -9,9 +9,11 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/customparams"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/decisiontrace"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kueue"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -19,6 +21,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/queue"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"go.uber.org/zap"
@@ -45,8 +48,14 @@ type PacRun struct {
 	logger       *zap.SugaredLogger
 	eventEmitter *events.EventEmitter
 	manager      *ConcurrencyManager
-	pacInfo      *info.PacOpts
-	globalRepo   *v1alpha1.Repository
+	// hasConcurrencyLimits is true when a namespace or global concurrency
+	// limit applies to the repository
+	hasConcurrencyLimits bool
+	// kueueQueueName is the kueue queue admitting the pipelineRuns of the
+	// repository instead of the concurrency manager
+	kueueQueueName string
+	pacInfo        *info.PacOpts
+	globalRepo     *v1alpha1.Repository
 }
 
 func NewPacs(event *info.Event, vcx provider.Interface, run *params.Run, pacInfo *info.PacOpts, k8int kubeinteraction.Interface, logger *zap.SugaredLogger, globalRepo *v1alpha1.Repository) PacRun {
@@ -67,25 +76,10 @@ func (p *PacRun) Run(ctx context.Context) error {
 		p.event.HasSkipCommand,
 		p.event.CancelPipelineRuns,
 	)
-	// For PullRequestClosed events, skip matching logic and go straight to cancellation
-	if p.event.TriggerTarget == triggertype.PullRequestClosed {
-		p.debugf("pull request closed: verifying repo and cancelling in-progress pipelineRuns")
-		repo, err := p.verifyRepoAndUser(ctx)
-		if err != nil {
-			return err
-		}
-		if repo != nil {
-			p.debugf("repo verified: name=%s namespace=%s", repo.GetName(), repo.GetNamespace())
-			if err := p.cancelAllInProgressBelongingToClosedPullRequest(ctx, repo); err != nil {
-				return fmt.Errorf("error cancelling in progress pipelineRuns belonging to pull request %d: %w", p.event.PullRequestNumber, err)
-			}
-		} else {
-			p.debugf("pull request closed: no repo match found for event url=%s", p.event.URL)
-		}
-		return nil
-	}
-
+	trace := decisiontrace.New()
+	ctx = decisiontrace.NewContext(ctx, trace)
 	matchedPRs, repo, err := p.matchRepoPR(ctx)
+	p.emitDecisionTrace(ctx, trace)
 	if err != nil {
 		createStatusErr := p.vcx.CreateStatus(ctx, p.event, provider.StatusOpts{
 			Status:     CompletedStatus,
@@ -109,16 +103,28 @@ func (p *PacRun) Run(ctx context.Context) error {
 		p.debugf("no pipelineruns matched; returning without starting any runs")
 		return nil
 	}
-	if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0 {
-		p.debugf("enabling concurrency manager with limit=%d", *repo.Spec.ConcurrencyLimit)
-		p.manager.Enable()
+	if p.kueueQueueName = p.getKueueQueueName(repo); p.kueueQueueName != "" {
+		p.debugf("handing the admission of the pipelineruns over to kueue queue=%s", p.kueueQueueName)
+	} else {
+		if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0 {
+			p.debugf("enabling concurrency manager with limit=%d", *repo.Spec.ConcurrencyLimit)
+			p.manager.Enable()
+		} else if hasConcurrencyGroup(repo, matchedPRs) {
+			p.debugf("enabling concurrency manager for concurrency groups")
+			p.manager.Enable()
+		}
+		if p.hasConcurrencyLimits = p.getConcurrencyLimits(repo); p.hasConcurrencyLimits {
+			p.debugf("enabling concurrency manager for namespace or global concurrency limit")
+			p.manager.Enable()
+		}
 	}
 
 	// Defensive skip-CI check: this is a safety net in case events bypass the early check in sinker.
 	// Primary skip detection happens in sinker.processEvent() for performance, but this ensures
 	// nothing slips through (e.g., tests that call Run() directly, or edge cases).
-	// Skip only for non-GitOps events (GitOps commands can override skip-CI).
-	if p.event.HasSkipCommand && !opscomments.IsAnyOpsEventType(p.event.EventType) {
+	// Skip only for non-GitOps events (GitOps commands can override skip-CI),
+	// scheduled runs are not about the commit and are never skipped.
+	if p.event.HasSkipCommand && !opscomments.IsAnyOpsEventType(p.event.EventType) && p.event.EventType != triggertype.Schedule.String() {
 		p.logger.Infof("CI skipped: commit contains skip command in message (secondary check)")
 		return nil
 	}
@@ -183,6 +189,9 @@ func (p *PacRun) Run(ctx context.Context) error {
 			if err := p.cancelInProgressMatchingPipelineRun(ctx, pr, repo); err != nil {
 				p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPipelineRun", fmt.Sprintf("error cancelling in progress pipelineRuns: %s", err))
 			}
+			if err := p.coalesceQueuedPipelineRuns(ctx, pr, repo); err != nil {
+				p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPipelineRun", fmt.Sprintf("error cancelling superseded queued pipelineRuns: %s", err))
+			}
 			p.debugf("finished processing pipelinerun start: name=%s", match.PipelineRun.GetGenerateName())
 		}(match, i)
 	}
@@ -192,6 +201,11 @@ func (p *PacRun) Run(ctx context.Context) error {
 	if order != "" {
 		p.debugf("patching execution order for %d pipelineruns: %s", len(prs), order)
 		for _, pr := range prs {
+			// the PipelineRuns of a concurrency group are ordered in the queue of the group
+			order, queued := getQueueExecutionOrder(repo, pr, prs, p.hasConcurrencyLimits)
+			if !queued {
+				continue
+			}
 			wg.Add(1)
 
 			go func(order string, pr tektonv1.PipelineRun) {
@@ -260,11 +274,32 @@ func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*tektonv1.Pi
 		p.debugf("startPR: added labels/annotations to pipelinerun=%s", prName)
 	}
 
-	// if concurrency is defined then start the pipelineRun in pending state
-	if match.Repo.Spec.ConcurrencyLimit != nil && *match.Repo.Spec.ConcurrencyLimit != 0 {
+	if group := match.PipelineRun.GetAnnotations()[keys.ConcurrencyGroup]; group != "" && match.Repo.Spec.GetConcurrencyGroup(group) == nil {
+		p.eventEmitter.EmitMessage(match.Repo, zap.WarnLevel, "RepositoryConcurrencyGroup",
+			fmt.Sprintf("concurrency group %s of PipelineRun %s is not defined in the Repository, the concurrency limit of the Repository is used instead", group, prName))
+	}
+
+	// if kueue admits the pipelineRuns then start the pipelineRun in pending
+	// state with the label of the kueue queue, kueue starts it once admitted.
+	// if concurrency is defined for the repository, the concurrency group
+	// of the pipelineRun, the namespace or the cluster then start the
+	// pipelineRun in pending state
+	if p.kueueQueueName != "" {
+		labels := match.PipelineRun.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		if labels[kueue.QueueNameLabel] == "" {
+			labels[kueue.QueueNameLabel] = p.kueueQueueName
+		}
+		match.PipelineRun.SetLabels(labels)
+		match.PipelineRun.Spec.Status = tektonv1.PipelineRunSpecStatusPending
+		p.debugf("startPR: marking pipelinerun=%s as pending until admitted by kueue queue=%s", prName, labels[kueue.QueueNameLabel])
+	} else if _, limit := queue.QueueKey(match.Repo, match.PipelineRun); (limit != nil && *limit != 0) || p.hasConcurrencyLimits {
 		// pending status
 		match.PipelineRun.Spec.Status = tektonv1.PipelineRunSpecStatusPending
 		p.debugf("startPR: marking pipelinerun=%s as pending due to concurrency limit", prName)
+		p.setPriority(ctx, match, prName)
 	}
 
 	// Create the actual pipelineRun
@@ -129,7 +129,9 @@ func TestRun(t *testing.T) {
 		concurrencyLimit             int
 		expectedLogSnippet           string
 		expectedPostedComment        string // TODO: multiple posted comments when we need it
-		secretCreationError          error  // Error to inject for secret creation
+		noPostedComment              bool
+		repoSettings                 *v1alpha1.Settings
+		secretCreationError          error // Error to inject for secret creation
 	}{
 		{
 			name: "pull request/fail-to-start-apps",
@@ -230,6 +232,86 @@ func TestRun(t *testing.T) {
 			finalStatus:     "neutral",
 			finalStatusText: "<th>Status</th><th>Duration</th><th>Name</th>",
 		},
+		{
+			name: "pull request/closed",
+			runevent: info.Event{
+				Event: &github.PullRequestEvent{
+					PullRequest: &github.PullRequest{
+						Number: github.Ptr(666),
+					},
+				},
+				SHA:               "fromwebhook",
+				Organization:      "owner",
+				Sender:            "owner",
+				Repository:        "repo",
+				URL:               "https://service/documentation",
+				HeadBranch:        "press",
+				BaseBranch:        "main",
+				EventType:         "pull_request",
+				TriggerTarget:     "pull_request_closed",
+				PullRequestNumber: 666,
+				PullRequestMerged: true,
+				InstallationID:    1234,
+			},
+			tektondir:   "testdata/pull_request_closed",
+			finalStatus: "neutral",
+		},
+		{
+			name: "Skipped/draft pull request",
+			runevent: info.Event{
+				Event: &github.PullRequestEvent{
+					PullRequest: &github.PullRequest{
+						Number: github.Ptr(666),
+					},
+				},
+				SHA:               "fromwebhook",
+				Organization:      "owner",
+				Sender:            "owner",
+				Repository:        "repo",
+				URL:               "https://service/documentation",
+				HeadBranch:        "press",
+				BaseBranch:        "main",
+				EventType:         "pull_request",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 666,
+				IsDraft:           true,
+			},
+			tektondir:          "testdata/pull_request",
+			finalStatus:        "neutral",
+			expectedLogSnippet: "pull request 666 is a draft",
+			repoSettings: &v1alpha1.Settings{
+				SkipDraftPullRequests: true,
+			},
+		},
+		{
+			name: "Skipped/draft pull request with webhook",
+			runevent: info.Event{
+				Event: &github.PullRequestEvent{
+					PullRequest: &github.PullRequest{
+						Number: github.Ptr(666),
+					},
+				},
+				SHA:               "fromwebhook",
+				Organization:      "owner",
+				Sender:            "owner",
+				Repository:        "repo",
+				URL:               "https://service/documentation",
+				HeadBranch:        "press",
+				BaseBranch:        "main",
+				EventType:         "pull_request",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 666,
+				IsDraft:           true,
+			},
+			tektondir:            "testdata/pull_request",
+			finalStatus:          "neutral",
+			expectedLogSnippet:   "pull request 666 is a draft",
+			noPostedComment:      true,
+			ProviderInfoFromRepo: true,
+			repoSettings: &v1alpha1.Settings{
+				SkipDraftPullRequests: true,
+			},
+		},
 		{
 			name: "pull request/with webhook",
 			runevent: info.Event{
@@ -543,6 +625,7 @@ func TestRun(t *testing.T) {
 				InstallNamespace: "namespace",
 				ProviderURL:      providerURL,
 				ConcurrencyLimit: tt.concurrencyLimit,
+				Settings:         tt.repoSettings,
 			}
 
 			if tt.ProviderInfoFromRepo {
@@ -588,6 +671,7 @@ func TestRun(t *testing.T) {
 						_, _ = fmt.Fprintf(w, `{"id": %d}`, tt.runevent.PullRequestNumber)
 						// read body and compare it
 						body, _ := io.ReadAll(req.Body)
+						assert.Assert(t, !tt.noPostedComment, "unexpected comment %s", string(body))
 						expectedRegexp := regexp.MustCompile(tt.expectedPostedComment)
 						assert.Assert(t, expectedRegexp.Match(body), "expected comment %s, got %s", tt.expectedPostedComment, string(body))
 						return
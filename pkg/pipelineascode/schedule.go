@@ -0,0 +1,64 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/schedule"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// isDefaultBranchPush returns true if the event is a push on the default
+// branch, the PipelineRuns of the default branch define the schedules.
+func (p *PacRun) isDefaultBranchPush() bool {
+	if p.event.EventType != triggertype.Push.String() || p.event.DefaultBranch == "" {
+		return false
+	}
+	return strings.TrimPrefix(p.event.HeadBranch, "refs/heads/") == p.event.DefaultBranch
+}
+
+// updateSchedules records the schedules of the PipelineRuns with an
+// on-schedule annotation in the Repository status, the watcher runs them
+// from there.
+func (p *PacRun) updateSchedules(ctx context.Context, repo *v1alpha1.Repository, prs []*tektonv1.PipelineRun) {
+	schedules, errs := schedule.FromPipelineRuns(prs, p.event.DefaultBranch, repo.ScheduleStatus, time.Now())
+	for _, err := range errs {
+		p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryInvalidSchedule", err.Error())
+	}
+	if schedule.Equal(schedules, repo.ScheduleStatus) {
+		return
+	}
+
+	updated := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lastrepo, err := p.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Get(ctx, repo.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		// keep the last schedule times the watcher may have recorded meanwhile
+		schedules, _ = schedule.FromPipelineRuns(prs, p.event.DefaultBranch, lastrepo.ScheduleStatus, time.Now())
+		if schedule.Equal(schedules, lastrepo.ScheduleStatus) {
+			updated = false
+			return nil
+		}
+		lastrepo.ScheduleStatus = schedules
+		_, err = p.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(lastrepo.GetNamespace()).Update(ctx, lastrepo, metav1.UpdateOptions{})
+		updated = err == nil
+		return err
+	})
+	if err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryScheduleUpdateFailed", fmt.Sprintf("cannot update the schedules of repository %s: %v", repo.GetName(), err))
+		return
+	}
+	if updated {
+		repo.ScheduleStatus = schedules
+		p.logger.Infof("repository %s has %d scheduled pipelineruns on branch %s", repo.GetName(), len(schedules), p.event.DefaultBranch)
+	}
+}
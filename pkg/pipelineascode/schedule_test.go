@@ -0,0 +1,88 @@
+package pipelineascode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestIsDefaultBranchPush(t *testing.T) {
+	tests := []struct {
+		name          string
+		eventType     string
+		headBranch    string
+		defaultBranch string
+		want          bool
+	}{
+		{name: "push on the default branch", eventType: "push", headBranch: "main", defaultBranch: "main", want: true},
+		{name: "push on the default branch ref", eventType: "push", headBranch: "refs/heads/main", defaultBranch: "main", want: true},
+		{name: "push on another branch", eventType: "push", headBranch: "refs/heads/feature", defaultBranch: "main"},
+		{name: "unknown default branch", eventType: "push", headBranch: "main"},
+		{name: "incoming on the default branch", eventType: "incoming", headBranch: "main", defaultBranch: "main"},
+		{name: "scheduled run", eventType: "schedule", headBranch: "main", defaultBranch: "main"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PacRun{event: &info.Event{EventType: tt.eventType, HeadBranch: tt.headBranch, DefaultBranch: tt.defaultBranch}}
+			assert.Equal(t, p.isDefaultBranchPush(), tt.want)
+		})
+	}
+}
+
+func TestUpdateSchedules(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, logs := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	last := &metav1.Time{Time: time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)}
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{URL: "https://forge.example.com/org/repo"},
+		ScheduleStatus: []v1alpha1.ScheduleStatus{
+			{PipelineRunName: "nightly", Schedule: "0 2 * * *", Branch: "main", LastScheduleTime: last},
+			{PipelineRunName: "removed", Schedule: "0 2 * * *", Branch: "main", LastScheduleTime: last},
+		},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Repositories: []*v1alpha1.Repository{repo}})
+	p := &PacRun{
+		event:        &info.Event{EventType: "push", HeadBranch: "refs/heads/main", DefaultBranch: "main"},
+		run:          &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Log: logger}},
+		logger:       logger,
+		eventEmitter: events.NewEventEmitter(nil, logger),
+	}
+	prs := []*tektonv1.PipelineRun{
+		{ObjectMeta: metav1.ObjectMeta{Name: "nightly", Annotations: map[string]string{keys.OnSchedule: "0 2 * * *"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "weekly", Annotations: map[string]string{keys.OnSchedule: "@weekly"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "invalid", Annotations: map[string]string{keys.OnSchedule: "0 2 * *"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "push", Annotations: map[string]string{keys.OnEvent: "[push]"}}},
+	}
+
+	p.updateSchedules(ctx, repo, prs)
+	assert.Equal(t, logs.FilterMessageSnippet("pipelinerun invalid: invalid schedule").Len(), 1)
+	updated, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(updated.ScheduleStatus), 2)
+	assert.Equal(t, updated.ScheduleStatus[0].PipelineRunName, "nightly")
+	assert.Assert(t, updated.ScheduleStatus[0].LastScheduleTime.Equal(last))
+	assert.Equal(t, updated.ScheduleStatus[1].PipelineRunName, "weekly")
+	assert.Equal(t, updated.ScheduleStatus[1].Branch, "main")
+	assert.Assert(t, updated.ScheduleStatus[1].LastScheduleTime != nil)
+
+	// the .tekton directory has been removed
+	p.updateSchedules(ctx, repo, nil)
+	updated, err = stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(updated.ScheduleStatus), 0)
+}
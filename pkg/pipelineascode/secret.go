@@ -32,6 +32,11 @@ type SecretFromRepository struct {
 // Get grab the secret from the repository CRD.
 func (s *SecretFromRepository) Get(ctx context.Context) error {
 	var err error
+	// polled plain git repositories can be cloned anonymously
+	if s.Repo.Spec.Poll != nil && (s.Repo.Spec.GitProvider == nil || s.Repo.Spec.GitProvider.Secret == nil) {
+		s.Logger.Debugf("secretFromRepository: repo=%s/%s is polled without credentials", s.Repo.Namespace, s.Repo.Name)
+		return nil
+	}
 	if s.Repo.Spec.GitProvider == nil {
 		return fmt.Errorf("failed to find git_provider details in repository spec: %v/%v", s.Repo.Namespace, s.Repo.Name)
 	}
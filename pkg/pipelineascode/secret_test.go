@@ -121,3 +121,27 @@ func TestSecretFromRepository(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretFromRepositoryPoll(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	k8int := &kitesthelper.KinterfaceTest{
+		GetSecretResult: map[string]string{"repo-secret": "token"},
+	}
+
+	// a polled repository without git_provider is cloned anonymously
+	event := info.NewEvent()
+	repo := &apipac.Repository{Spec: apipac.RepositorySpec{Poll: &apipac.Poll{}}}
+	sfr := SecretFromRepository{K8int: k8int, Config: &info.ProviderConfig{}, Event: event, Repo: repo, Namespace: "namespace", Logger: logger}
+	assert.NilError(t, sfr.Get(ctx))
+	assert.Equal(t, event.Provider.Token, "")
+
+	// but the credentials are used when set
+	event = info.NewEvent()
+	repo.Spec.GitProvider = &apipac.GitProvider{User: "user", Secret: &apipac.Secret{Name: "repo-secret"}}
+	sfr = SecretFromRepository{K8int: k8int, Config: &info.ProviderConfig{}, Event: event, Repo: repo, Namespace: "namespace", Logger: logger}
+	assert.NilError(t, sfr.Get(ctx))
+	assert.Equal(t, event.Provider.Token, "token")
+	assert.Equal(t, event.Provider.User, "user")
+}
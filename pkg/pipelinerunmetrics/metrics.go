@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	listers "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1"
 	"go.opencensus.io/stats"
@@ -30,6 +31,42 @@ var runningPRCount = stats.Float64("pipelines_as_code_running_pipelineruns_count
 	"number of running pipelineruns by pipelines as code",
 	stats.UnitDimensionless)
 
+var queuedPRCount = stats.Float64("pipelines_as_code_queued_pipelineruns_count",
+	"number of pipelineruns waiting in the queue of a repository",
+	stats.UnitDimensionless)
+
+var queuedPRMaxWait = stats.Float64("pipelines_as_code_queued_pipelineruns_max_wait_seconds",
+	"number of seconds the oldest queued pipelinerun of a repository has been waiting",
+	stats.UnitDimensionless)
+
+var queueTimeoutCount = stats.Int64("pipelines_as_code_queue_timeout_count",
+	"number of pipelineruns cancelled after waiting in the queue longer than the maximum queue wait",
+	stats.UnitDimensionless)
+
+var taskCount = stats.Int64("pipelines_as_code_task_count",
+	"number of completed tasks of the pipelineruns by pipelines as code",
+	stats.UnitDimensionless)
+
+var flakyTaskCount = stats.Int64("pipelines_as_code_flaky_task_count",
+	"number of tasks whose result differs from their previous result on the same commit",
+	stats.UnitDimensionless)
+
+var llmAnalysisCacheCount = stats.Int64("pipelines_as_code_llm_analysis_cache_count",
+	"number of lookups of llm analyses in the cache by result, hit or miss",
+	stats.UnitDimensionless)
+
+var llmAnalysisCacheSize = stats.Float64("pipelines_as_code_llm_analysis_cache_size",
+	"number of llm analyses in the cache",
+	stats.UnitDimensionless)
+
+var llmAnalysisCacheTTL = stats.Float64("pipelines_as_code_llm_analysis_cache_ttl_seconds",
+	"number of seconds the llm analyses are kept in the cache",
+	stats.UnitDimensionless)
+
+var llmAnalysisTokenCount = stats.Int64("pipelines_as_code_llm_analysis_token_count",
+	"number of llm tokens used by the analyses",
+	stats.UnitDimensionless)
+
 var gitProviderAPIRequestCount = stats.Int64(
 	"pipelines_as_code_git_provider_api_request_count",
 	"number of API requests from pipelines as code to git providers",
@@ -45,6 +82,11 @@ type Recorder struct {
 	repository      tag.Key
 	status          tag.Key
 	reason          tag.Key
+	pipeline        tag.Key
+	task            tag.Key
+	result          tag.Key
+	role            tag.Key
+	model           tag.Key
 	ReportingPeriod time.Duration
 }
 
@@ -107,6 +149,41 @@ func NewRecorder() (*Recorder, error) {
 		}
 		R.reason = reason
 
+		pipeline, errRegistering := tag.NewKey("pipeline")
+		if errRegistering != nil {
+			ErrRegistering = errRegistering
+			return
+		}
+		R.pipeline = pipeline
+
+		task, errRegistering := tag.NewKey("task")
+		if errRegistering != nil {
+			ErrRegistering = errRegistering
+			return
+		}
+		R.task = task
+
+		result, errRegistering := tag.NewKey("result")
+		if errRegistering != nil {
+			ErrRegistering = errRegistering
+			return
+		}
+		R.result = result
+
+		role, errRegistering := tag.NewKey("role")
+		if errRegistering != nil {
+			ErrRegistering = errRegistering
+			return
+		}
+		R.role = role
+
+		model, errRegistering := tag.NewKey("model")
+		if errRegistering != nil {
+			ErrRegistering = errRegistering
+			return
+		}
+		R.model = model
+
 		var (
 			prCountView = &view.View{
 				Description: prCount.Description(),
@@ -128,6 +205,58 @@ func NewRecorder() (*Recorder, error) {
 				Aggregation: view.LastValue(),
 				TagKeys:     []tag.Key{R.namespace, R.repository},
 			}
+			queuedPRView = &view.View{
+				Description: queuedPRCount.Description(),
+				Measure:     queuedPRCount,
+				Aggregation: view.LastValue(),
+				TagKeys:     []tag.Key{R.namespace, R.repository},
+			}
+			queuedPRMaxWaitView = &view.View{
+				Description: queuedPRMaxWait.Description(),
+				Measure:     queuedPRMaxWait,
+				Aggregation: view.LastValue(),
+				TagKeys:     []tag.Key{R.namespace, R.repository},
+			}
+			queueTimeoutView = &view.View{
+				Description: queueTimeoutCount.Description(),
+				Measure:     queueTimeoutCount,
+				Aggregation: view.Count(),
+				TagKeys:     []tag.Key{R.namespace, R.repository},
+			}
+			taskView = &view.View{
+				Description: taskCount.Description(),
+				Measure:     taskCount,
+				Aggregation: view.Count(),
+				TagKeys:     []tag.Key{R.namespace, R.repository, R.pipeline, R.task, R.status},
+			}
+			flakyTaskView = &view.View{
+				Description: flakyTaskCount.Description(),
+				Measure:     flakyTaskCount,
+				Aggregation: view.Count(),
+				TagKeys:     []tag.Key{R.namespace, R.repository, R.pipeline, R.task},
+			}
+			llmAnalysisCacheView = &view.View{
+				Description: llmAnalysisCacheCount.Description(),
+				Measure:     llmAnalysisCacheCount,
+				Aggregation: view.Count(),
+				TagKeys:     []tag.Key{R.namespace, R.repository, R.result},
+			}
+			llmAnalysisCacheSizeView = &view.View{
+				Description: llmAnalysisCacheSize.Description(),
+				Measure:     llmAnalysisCacheSize,
+				Aggregation: view.LastValue(),
+			}
+			llmAnalysisCacheTTLView = &view.View{
+				Description: llmAnalysisCacheTTL.Description(),
+				Measure:     llmAnalysisCacheTTL,
+				Aggregation: view.LastValue(),
+			}
+			llmAnalysisTokenView = &view.View{
+				Description: llmAnalysisTokenCount.Description(),
+				Measure:     llmAnalysisTokenCount,
+				Aggregation: view.Sum(),
+				TagKeys:     []tag.Key{R.namespace, R.repository, R.role, R.model},
+			}
 			gitProviderAPIRequestView = &view.View{
 				Description: gitProviderAPIRequestCount.Description(),
 				Measure:     gitProviderAPIRequestCount,
@@ -136,8 +265,8 @@ func NewRecorder() (*Recorder, error) {
 			}
 		)
 
-		view.Unregister(prCountView, prDurationView, runningPRView, gitProviderAPIRequestView)
-		errRegistering = view.Register(prCountView, prDurationView, runningPRView, gitProviderAPIRequestView)
+		view.Unregister(prCountView, prDurationView, runningPRView, queuedPRView, queuedPRMaxWaitView, queueTimeoutView, taskView, flakyTaskView, llmAnalysisCacheView, llmAnalysisCacheSizeView, llmAnalysisCacheTTLView, llmAnalysisTokenView, gitProviderAPIRequestView)
+		errRegistering = view.Register(prCountView, prDurationView, runningPRView, queuedPRView, queuedPRMaxWaitView, queueTimeoutView, taskView, flakyTaskView, llmAnalysisCacheView, llmAnalysisCacheSizeView, llmAnalysisCacheTTLView, llmAnalysisTokenView, gitProviderAPIRequestView)
 		if errRegistering != nil {
 			ErrRegistering = errRegistering
 			R.initialized = false
@@ -261,6 +390,185 @@ func (r *Recorder) EmitRunningPRsMetrics(prl []*tektonv1.PipelineRun) error {
 	return nil
 }
 
+// QueuedPipelineRuns emits the number of queued PipelineRuns of a repository
+// and the wait of the oldest one.
+func (r *Recorder) QueuedPipelineRuns(namespace, repository string, queuedPRs float64, maxWait time.Duration) error {
+	if err := r.assertInitialized(); err != nil {
+		return err
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.namespace, namespace),
+		tag.Insert(r.repository, repository),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, queuedPRCount.M(queuedPRs))
+	metrics.Record(ctx, queuedPRMaxWait.M(maxWait.Seconds()))
+	return nil
+}
+
+// EmitQueuedPRsMetrics emits the queued PipelineRuns metrics of the
+// repositories, the repositories without queued PipelineRuns anymore are
+// reported with zero.
+// The position of each queued PipelineRun is not exported: a tag per
+// PipelineRun would create a time series per PipelineRun which is never
+// removed, tkn pac queue list shows it instead.
+func (r *Recorder) EmitQueuedPRsMetrics(prl []*tektonv1.PipelineRun, now time.Time) error {
+	type queueStats struct {
+		count   int
+		maxWait time.Duration
+	}
+	queues := map[string]*queueStats{}
+	for _, pr := range prl {
+		repository, ok := pr.GetAnnotations()[keys.Repository]
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s", pr.GetNamespace(), repository)
+		if _, ok := queues[key]; !ok {
+			queues[key] = &queueStats{}
+		}
+		if pr.GetLabels()[keys.State] != kubeinteraction.StateQueued || pr.Spec.Status != tektonv1.PipelineRunSpecStatusPending {
+			continue
+		}
+		queues[key].count++
+		if wait := now.Sub(pr.GetCreationTimestamp().Time); wait > queues[key].maxWait {
+			queues[key].maxWait = wait
+		}
+	}
+
+	for key, q := range queues {
+		nsKeys := strings.Split(key, "/")
+		if err := r.QueuedPipelineRuns(nsKeys[0], nsKeys[1], float64(q.count), q.maxWait); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountQueueTimeout counts the PipelineRuns cancelled after waiting in the
+// queue longer than the maximum queue wait.
+func (r *Recorder) CountQueueTimeout(namespace, repository string) error {
+	if err := r.assertInitialized(); err != nil {
+		return err
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.namespace, namespace),
+		tag.Insert(r.repository, repository),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, queueTimeoutCount.M(1))
+	return nil
+}
+
+// CountTask counts the completed tasks of a pipeline by status.
+func (r *Recorder) CountTask(namespace, repository, pipeline, task, status string) error {
+	if err := r.assertInitialized(); err != nil {
+		return err
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.namespace, namespace),
+		tag.Insert(r.repository, repository),
+		tag.Insert(r.pipeline, pipeline),
+		tag.Insert(r.task, task),
+		tag.Insert(r.status, status),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, taskCount.M(1))
+	return nil
+}
+
+// CountFlakyTask counts the tasks of a pipeline whose result differs from
+// their previous result on the same commit.
+func (r *Recorder) CountFlakyTask(namespace, repository, pipeline, task string) error {
+	if err := r.assertInitialized(); err != nil {
+		return err
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.namespace, namespace),
+		tag.Insert(r.repository, repository),
+		tag.Insert(r.pipeline, pipeline),
+		tag.Insert(r.task, task),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, flakyTaskCount.M(1))
+	return nil
+}
+
+// CountLLMAnalysisCache counts the lookups of the llm analyses of a
+// repository in the cache by result, hit or miss.
+func (r *Recorder) CountLLMAnalysisCache(namespace, repository, result string) error {
+	if err := r.assertInitialized(); err != nil {
+		return err
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.namespace, namespace),
+		tag.Insert(r.repository, repository),
+		tag.Insert(r.result, result),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, llmAnalysisCacheCount.M(1))
+	return nil
+}
+
+// LLMAnalysisCache emits the number of llm analyses in the cache and how long
+// they are kept.
+func (r *Recorder) LLMAnalysisCache(size float64, ttl time.Duration) error {
+	if err := r.assertInitialized(); err != nil {
+		return err
+	}
+
+	metrics.Record(context.Background(), llmAnalysisCacheSize.M(size))
+	metrics.Record(context.Background(), llmAnalysisCacheTTL.M(ttl.Seconds()))
+	return nil
+}
+
+// CountLLMAnalysisTokens counts the llm tokens used by the analyses of a
+// repository by role and model.
+func (r *Recorder) CountLLMAnalysisTokens(namespace, repository, role, model string, tokens int) error {
+	if err := r.assertInitialized(); err != nil {
+		return err
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.namespace, namespace),
+		tag.Insert(r.repository, repository),
+		tag.Insert(r.role, role),
+		tag.Insert(r.model, model),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, llmAnalysisTokenCount.M(int64(tokens)))
+	return nil
+}
+
 // ReportRunningPipelineRuns reports running PipelineRuns on our configured ReportingPeriod
 // until the context is cancelled.
 func (r *Recorder) ReportRunningPipelineRuns(ctx context.Context, lister listers.PipelineRunLister) {
@@ -286,6 +594,9 @@ func (r *Recorder) ReportRunningPipelineRuns(ctx context.Context, lister listers
 			if err := r.EmitRunningPRsMetrics(prl); err != nil {
 				logger.Warnf("Failed to log the metrics : %v", err)
 			}
+			if err := r.EmitQueuedPRsMetrics(prl, time.Now()); err != nil {
+				logger.Warnf("Failed to log the queue metrics : %v", err)
+			}
 		}
 	}
 }
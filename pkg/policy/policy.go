@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/decisiontrace"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
@@ -45,10 +46,11 @@ func (p *Policy) checkAllowed(ctx context.Context, tType triggertype.Trigger) (R
 		sType = settings.Policy.OkToTest
 	// apply the same policy for PullRequest and comment
 	// we don't support comments on PRs yet but if we do on the future we will need our own policy
-	case triggertype.PullRequest, triggertype.Comment, triggertype.PullRequestLabeled, triggertype.PullRequestClosed:
+	// on a review the policy applies to the reviewer, who is the sender of the event
+	case triggertype.PullRequest, triggertype.Comment, triggertype.PullRequestLabeled, triggertype.PullRequestClosed, triggertype.PullRequestReview:
 		sType = settings.Policy.PullRequest
 	// NOTE: not supported yet, will imp if it gets requested and reasonable to implement
-	case triggertype.Push, triggertype.Cancel, triggertype.CheckSuiteRerequested, triggertype.CheckRunRerequested, triggertype.Incoming:
+	case triggertype.Push, triggertype.Cancel, triggertype.CheckSuiteRerequested, triggertype.CheckRunRerequested, triggertype.Incoming, triggertype.Schedule, triggertype.MergeGroup:
 		return ResultNotSet, ""
 	default:
 		return ResultNotSet, ""
@@ -98,21 +100,25 @@ func (p *Policy) IsAllowed(ctx context.Context, tType triggertype.Trigger) (Resu
 	case ResultAllowed:
 		reason = fmt.Sprintf("policy check: policy is set for sender %s has been allowed to run CI via policy", p.Event.Sender)
 		p.EventEmitter.EmitMessage(p.Repository, zap.InfoLevel, "PolicySetAllowed", reason)
+		decisiontrace.FromContext(ctx).AccessChecked(decisiontrace.AccessCheckPolicy, true, reason)
 		return ResultAllowed, ""
 	case ResultDisallowed:
 		allowed, err := p.VCX.IsAllowedOwnersFile(ctx, p.Event)
 		if err != nil {
+			decisiontrace.FromContext(ctx).AccessChecked(decisiontrace.AccessCheckPolicy, false, err.Error())
 			return ResultDisallowed, err.Error()
 		}
 		if allowed {
 			reason = fmt.Sprintf("policy check: policy is set, sender %s not in the allowed policy but allowed via OWNERS file", p.Event.Sender)
 			p.EventEmitter.EmitMessage(p.Repository, zap.InfoLevel, "PolicySetAllowed", reason)
+			decisiontrace.FromContext(ctx).AccessChecked(decisiontrace.AccessCheckPolicy, true, reason)
 			return ResultAllowed, ""
 		}
 		if reason == "" {
 			reason = fmt.Sprintf("policy check: policy is set but sender %s is not in the allowed groups", p.Event.Sender)
 		}
 		p.EventEmitter.EmitMessage(p.Repository, zap.InfoLevel, "PolicySetDisallowed", reason)
+		decisiontrace.FromContext(ctx).AccessChecked(decisiontrace.AccessCheckPolicy, false, reason)
 		return ResultDisallowed, ""
 	case ResultNotSet: // this is to make golangci-lint happy
 	}
@@ -0,0 +1,328 @@
+package poller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gobwas/glob"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	listers "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/plaingit"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/plaingit/types"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// DefaultInterval is the interval between two polls of a repository when
+	// the Repository CR doesn't set one.
+	DefaultInterval = 5 * time.Minute
+	// MinInterval is the shortest interval between two polls of a repository.
+	MinInterval = time.Minute
+
+	tickInterval = 30 * time.Second
+)
+
+var defaultRefs = []string{"refs/heads/*", "refs/tags/*"}
+
+// Poller polls the references of the plain git repositories which cannot send
+// webhooks and runs the push PipelineRuns when a branch or a tag changes.
+type Poller struct {
+	run          *params.Run
+	kint         kubeinteraction.Interface
+	repoLister   listers.RepositoryLister
+	logger       *zap.SugaredLogger
+	eventEmitter *events.EventEmitter
+	states       map[string]*repoState
+	// isLeader tells if this replica of the watcher polls the repository,
+	// only the leader of its bucket does.
+	isLeader func(ktypes.NamespacedName) bool
+	// trigger runs the PipelineRuns of the event, replaced by the tests.
+	trigger func(ctx context.Context, repo *v1alpha1.Repository, event *info.Event) error
+}
+
+// repoState is when a polled repository has been polled last by this replica,
+// the references are recorded in the Repository status.
+type repoState struct {
+	url      string
+	lastPoll time.Time
+}
+
+func New(run *params.Run, kint kubeinteraction.Interface, repoLister listers.RepositoryLister, isLeader func(ktypes.NamespacedName) bool, logger *zap.SugaredLogger) *Poller {
+	p := &Poller{
+		run:          run,
+		kint:         kint,
+		repoLister:   repoLister,
+		logger:       logger,
+		eventEmitter: events.NewEventEmitter(run.Clients.Kube, logger),
+		states:       map[string]*repoState{},
+		isLeader:     isLeader,
+	}
+	p.trigger = p.runPac
+	return p
+}
+
+// Start polls the repositories until the context is done, it needs the git
+// binary.
+func (p *Poller) Start(ctx context.Context) {
+	if _, err := exec.LookPath("git"); err != nil {
+		p.logger.Warnf("git binary not found, polling of plain git repositories is disabled: %v", err)
+		return
+	}
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	for {
+		p.poll(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll polls the repositories which are due.
+func (p *Poller) poll(ctx context.Context, now time.Time) {
+	repos, err := p.repoLister.List(labels.Everything())
+	if err != nil {
+		p.logger.Errorf("cannot list repositories to poll: %v", err)
+		return
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].GetNamespace()+"/"+repos[i].GetName() < repos[j].GetNamespace()+"/"+repos[j].GetName()
+	})
+
+	seen := map[string]bool{}
+	for _, repo := range repos {
+		if repo.Spec.Poll == nil || !p.isLeader(ktypes.NamespacedName{Namespace: repo.GetNamespace(), Name: repo.GetName()}) {
+			continue
+		}
+		key := repo.GetNamespace() + "/" + repo.GetName()
+		seen[key] = true
+		repoURL := strings.TrimSuffix(repo.Spec.URL, "/")
+		state, ok := p.states[key]
+		if !ok || state.url != repoURL {
+			state = &repoState{url: repoURL}
+			p.states[key] = state
+		}
+		if !state.lastPoll.IsZero() && now.Sub(state.lastPoll) < pollInterval(repo.Spec.Poll) {
+			continue
+		}
+		state.lastPoll = now
+		if err := p.pollRepository(ctx, repo, state); err != nil {
+			msg := fmt.Sprintf("cannot poll repository %s: %v", repoURL, err)
+			p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPollFailed", msg)
+		}
+	}
+	// forget the repositories which have been deleted or are not polled anymore
+	for key := range p.states {
+		if !seen[key] {
+			delete(p.states, key)
+		}
+	}
+}
+
+// pollInterval returns the interval between two polls of a repository.
+func pollInterval(poll *v1alpha1.Poll) time.Duration {
+	if poll.Interval == "" {
+		return DefaultInterval
+	}
+	interval, err := time.ParseDuration(poll.Interval)
+	if err != nil {
+		return DefaultInterval
+	}
+	if interval < MinInterval {
+		return MinInterval
+	}
+	return interval
+}
+
+// pollRepository lists the references of the repository and triggers the
+// push PipelineRuns of the references which have changed since the last poll.
+// The first poll only records the references. The references are recorded in
+// the Repository status before the PipelineRuns are run, a restart of the
+// watcher or a new leader will not run them again.
+func (p *Poller) pollRepository(ctx context.Context, repo *v1alpha1.Repository, state *repoState) error {
+	logger := p.logger.With("namespace", repo.GetNamespace(), "repository", repo.GetName())
+
+	credentials := info.NewEvent()
+	if repo.Spec.GitProvider != nil && repo.Spec.GitProvider.Secret != nil {
+		scm := pipelineascode.SecretFromRepository{
+			K8int:     p.kint,
+			Config:    (&plaingit.Provider{}).GetConfig(),
+			Event:     credentials,
+			Repo:      repo.DeepCopy(),
+			Logger:    logger,
+			Namespace: repo.GetNamespace(),
+		}
+		if err := scm.Get(ctx); err != nil {
+			return fmt.Errorf("cannot get secret from repository: %w", err)
+		}
+	}
+
+	refs, defaultBranch, err := plaingit.LsRemote(ctx, state.url, credentials.Provider.User, credentials.Provider.Token)
+	if err != nil {
+		return err
+	}
+	refs, err = filterRefs(refs, repo.Spec.Poll.Refs)
+	if err != nil {
+		return err
+	}
+
+	previous, err := p.recordRefs(ctx, repo, state.url, refs)
+	if err != nil {
+		return fmt.Errorf("cannot record the references in the repository status: %w", err)
+	}
+	if previous == nil {
+		logger.Infof("polling %d references of %s every %s", len(refs), state.url, pollInterval(repo.Spec.Poll))
+		return nil
+	}
+
+	changed := []string{}
+	for ref, sha := range refs {
+		if previous[ref] != sha {
+			changed = append(changed, ref)
+		}
+	}
+	sort.Strings(changed)
+	for _, ref := range changed {
+		event := p.makeEvent(state.url, ref, previous[ref], refs[ref], defaultBranch)
+		logger.Infof("reference %s of %s has changed from %q to %s", ref, state.url, previous[ref], refs[ref])
+		if err := p.trigger(ctx, repo, event); err != nil {
+			logger.Errorf("cannot run the pipelineruns of %s on %s: %v", ref, state.url, err)
+		}
+	}
+	return nil
+}
+
+// recordRefs sets the references of the repository in its status and returns
+// the ones recorded on the previous poll, nil if the repository has never been
+// polled from this URL.
+func (p *Poller) recordRefs(ctx context.Context, repo *v1alpha1.Repository, repoURL string, refs map[string]string) (map[string]string, error) {
+	var previous map[string]string
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lastrepo, err := p.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Get(ctx, repo.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		previous = nil
+		if lastrepo.PollStatus != nil && lastrepo.PollStatus.URL == repoURL {
+			previous = lastrepo.PollStatus.Refs
+			if previous == nil {
+				previous = map[string]string{}
+			}
+		}
+		if previous != nil && maps.Equal(previous, refs) {
+			return nil
+		}
+		lastrepo.PollStatus = &v1alpha1.PollStatus{URL: repoURL, Refs: refs}
+		_, err = p.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(lastrepo.GetNamespace()).Update(ctx, lastrepo, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// filterRefs keeps the references matching one of the glob patterns.
+func filterRefs(refs map[string]string, patterns []string) (map[string]string, error) {
+	if len(patterns) == 0 {
+		patterns = defaultRefs
+	}
+	globs := make([]glob.Glob, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid poll ref pattern %s: %w", pattern, err)
+		}
+		globs = append(globs, g)
+	}
+	filtered := map[string]string{}
+	for ref, sha := range refs {
+		for _, g := range globs {
+			if g.Match(ref) {
+				filtered[ref] = sha
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
+
+// makeEvent synthesizes the push event of a reference update.
+func (p *Poller) makeEvent(repoURL, ref, before, after, defaultBranch string) *info.Event {
+	pushEvent := &types.PushEvent{Ref: ref, Before: before, After: after}
+	payload, _ := json.Marshal(pushEvent)
+
+	event := info.NewEvent()
+	event.Event = pushEvent
+	event.Request = &info.Request{Header: http.Header{}, Payload: payload}
+	event.URL = repoURL
+	event.BaseURL = repoURL
+	event.HeadURL = repoURL
+	event.Organization, event.Repository = splitURL(repoURL)
+	event.SHA = after
+	event.HeadBranch = ref
+	event.BaseBranch = ref
+	event.DefaultBranch = defaultBranch
+	event.TriggerTarget = triggertype.Push
+	event.EventType = triggertype.Push.String()
+	return event
+}
+
+// splitURL returns the organization and the repository of a git URL, ie:
+// https://git.example.com/org/repo.git is the repo repository of the org
+// organization.
+func splitURL(repoURL string) (string, string) {
+	repoPath := repoURL
+	if u, err := url.Parse(repoURL); err == nil && u.Path != "" {
+		repoPath = u.Path
+	}
+	repoPath = strings.TrimSuffix(strings.TrimSuffix(repoPath, "/"), ".git")
+	organization, repository := path.Split(repoPath)
+	organization = path.Base(strings.TrimSuffix(organization, "/"))
+	if organization == "." || organization == "/" {
+		organization = repository
+	}
+	return organization, repository
+}
+
+// runPac runs the PipelineRuns of the event like the controller does for the
+// webhook events.
+func (p *Poller) runPac(ctx context.Context, repo *v1alpha1.Repository, event *info.Event) error {
+	logger := p.logger.With("namespace", repo.GetNamespace(), "event-sha", event.SHA, "event-type", event.EventType,
+		"source-repo-url", event.URL, "target-branch", event.BaseBranch)
+	pacInfo := p.run.Info.GetPacOpts()
+
+	globalRepo, err := p.repoLister.Repositories(p.run.Info.Kube.Namespace).Get(p.run.Info.Controller.GlobalRepository)
+	if err != nil || globalRepo == nil {
+		globalRepo = &v1alpha1.Repository{}
+	}
+
+	vcx := &plaingit.Provider{}
+	vcx.SetLogger(logger)
+	vcx.SetPacInfo(&pacInfo)
+	defer vcx.Cleanup()
+
+	pac := pipelineascode.NewPacs(event, vcx, p.run, &pacInfo, p.kint, logger, globalRepo.DeepCopy())
+	return pac.Run(ctx)
+}
@@ -0,0 +1,300 @@
+package poller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	fakepacclientset "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned/fake"
+	listers "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	gittest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/plaingit/test"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/plaingit/types"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+type triggered struct {
+	repo  string
+	event *info.Event
+}
+
+func newPoller(t *testing.T, ctx context.Context, repos ...*v1alpha1.Repository) (*Poller, *[]triggered, *zapobserver.ObservedLogs) {
+	t.Helper()
+	observer, logs := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Repositories: repos})
+	triggeredEvents := []triggered{}
+	p := &Poller{
+		run:          &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Log: logger}},
+		repoLister:   stdata.RepositoryLister,
+		logger:       logger,
+		eventEmitter: events.NewEventEmitter(nil, logger),
+		states:       map[string]*repoState{},
+		isLeader:     func(ktypes.NamespacedName) bool { return true },
+	}
+	p.trigger = func(_ context.Context, repo *v1alpha1.Repository, event *info.Event) error {
+		triggeredEvents = append(triggeredEvents, triggered{repo: repo.GetName(), event: event})
+		return nil
+	}
+	return p, &triggeredEvents, logs
+}
+
+func makeRepository(name, url string, poll *v1alpha1.Poll) *v1alpha1.Repository {
+	return &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{URL: url, Poll: poll},
+	}
+}
+
+func TestPoll(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	gitRepo := gittest.NewRepository(t)
+	first := gitRepo.Commit(t, "first", map[string]string{"README.md": "first"})
+
+	p, triggeredEvents, _ := newPoller(t, ctx,
+		makeRepository("polled", gitRepo.URL, &v1alpha1.Poll{Interval: "10m"}),
+		makeRepository("not-polled", gitRepo.URL, nil),
+	)
+	now := time.Now()
+
+	// the first poll only records the references
+	p.poll(ctx, now)
+	assert.Equal(t, len(*triggeredEvents), 0)
+
+	second := gitRepo.Commit(t, "second", map[string]string{"README.md": "second"})
+
+	// not polled again before the interval
+	p.poll(ctx, now.Add(5*time.Minute))
+	assert.Equal(t, len(*triggeredEvents), 0)
+
+	p.poll(ctx, now.Add(10*time.Minute))
+	assert.Equal(t, len(*triggeredEvents), 1)
+	got := (*triggeredEvents)[0]
+	assert.Equal(t, got.repo, "polled")
+	assert.Equal(t, got.event.TriggerTarget, triggertype.Push)
+	assert.Equal(t, got.event.EventType, triggertype.Push.String())
+	assert.Equal(t, got.event.SHA, second)
+	assert.Equal(t, got.event.HeadBranch, "refs/heads/main")
+	assert.Equal(t, got.event.BaseBranch, "refs/heads/main")
+	assert.Equal(t, got.event.DefaultBranch, "main")
+	assert.Equal(t, got.event.URL, gitRepo.URL)
+	assert.DeepEqual(t, got.event.Event, &types.PushEvent{Ref: "refs/heads/main", Before: first, After: second})
+	assert.Assert(t, got.event.Request.Header != nil)
+
+	// new branches and annotated tags are triggered too
+	gitRepo.Git(t, "branch", "feature", first)
+	gitRepo.Git(t, "tag", "-a", "-m", "release", "v1.0.0", second)
+	p.poll(ctx, now.Add(20*time.Minute))
+	assert.Equal(t, len(*triggeredEvents), 3)
+	assert.DeepEqual(t, (*triggeredEvents)[1].event.Event, &types.PushEvent{Ref: "refs/heads/feature", After: first})
+	assert.DeepEqual(t, (*triggeredEvents)[2].event.Event, &types.PushEvent{Ref: "refs/tags/v1.0.0", After: second})
+
+	// nothing changed
+	p.poll(ctx, now.Add(30*time.Minute))
+	assert.Equal(t, len(*triggeredEvents), 3)
+
+	repo, err := p.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "polled", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, repo.PollStatus, &v1alpha1.PollStatus{URL: gitRepo.URL, Refs: map[string]string{
+		"refs/heads/main":    second,
+		"refs/heads/feature": first,
+		"refs/tags/v1.0.0":   second,
+	}})
+}
+
+func TestPollRestart(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	gitRepo := gittest.NewRepository(t)
+	first := gitRepo.Commit(t, "first", map[string]string{"README.md": "first"})
+
+	repo := makeRepository("polled", gitRepo.URL, &v1alpha1.Poll{})
+	repo.PollStatus = &v1alpha1.PollStatus{URL: gitRepo.URL, Refs: map[string]string{"refs/heads/main": first}}
+	p, triggeredEvents, _ := newPoller(t, ctx, repo)
+
+	// the references recorded before the restart are compared on the first poll
+	second := gitRepo.Commit(t, "second", map[string]string{"README.md": "second"})
+	p.poll(ctx, time.Now())
+	assert.Equal(t, len(*triggeredEvents), 1)
+	assert.DeepEqual(t, (*triggeredEvents)[0].event.Event, &types.PushEvent{Ref: "refs/heads/main", Before: first, After: second})
+
+	// another replica taking over does not run them again
+	other := *p
+	other.states = map[string]*repoState{}
+	other.poll(ctx, time.Now())
+	assert.Equal(t, len(*triggeredEvents), 1)
+}
+
+func TestPollURLChanged(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	gitRepo := gittest.NewRepository(t)
+	gitRepo.Commit(t, "first", map[string]string{"README.md": "first"})
+
+	repo := makeRepository("polled", gitRepo.URL, &v1alpha1.Poll{})
+	repo.PollStatus = &v1alpha1.PollStatus{URL: "https://git.example.com/old.git", Refs: map[string]string{"refs/heads/main": "0000"}}
+	p, triggeredEvents, _ := newPoller(t, ctx, repo)
+	p.poll(ctx, time.Now())
+	assert.Equal(t, len(*triggeredEvents), 0)
+}
+
+func TestPollNotLeader(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	gitRepo := gittest.NewRepository(t)
+	gitRepo.Commit(t, "first", map[string]string{"README.md": "first"})
+
+	p, _, _ := newPoller(t, ctx, makeRepository("polled", gitRepo.URL, &v1alpha1.Poll{}))
+	p.isLeader = func(ktypes.NamespacedName) bool { return false }
+	p.poll(ctx, time.Now())
+	assert.Equal(t, len(p.states), 0)
+
+	repo, err := p.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "polled", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, repo.PollStatus == nil)
+}
+
+func TestPollRefs(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	gitRepo := gittest.NewRepository(t)
+	first := gitRepo.Commit(t, "first", map[string]string{"README.md": "first"})
+
+	p, triggeredEvents, _ := newPoller(t, ctx,
+		makeRepository("polled", gitRepo.URL, &v1alpha1.Poll{Refs: []string{"refs/heads/release-*"}}),
+	)
+	now := time.Now()
+	p.poll(ctx, now)
+
+	gitRepo.Commit(t, "second", map[string]string{"README.md": "second"})
+	gitRepo.Git(t, "branch", "release-1.0", first)
+	gitRepo.Git(t, "tag", "v1.0.0", first)
+	p.poll(ctx, now.Add(DefaultInterval))
+	assert.Equal(t, len(*triggeredEvents), 1)
+	assert.Equal(t, (*triggeredEvents)[0].event.HeadBranch, "refs/heads/release-1.0")
+}
+
+func TestPollForgetsRepositories(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	gitRepo := gittest.NewRepository(t)
+	gitRepo.Commit(t, "first", map[string]string{"README.md": "first"})
+
+	repo := makeRepository("polled", gitRepo.URL, &v1alpha1.Poll{})
+	p, _, _ := newPoller(t, ctx, repo)
+	p.poll(ctx, time.Now())
+	assert.Equal(t, len(p.states), 1)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	p.repoLister = listers.NewRepositoryLister(indexer)
+	p.poll(ctx, time.Now())
+	assert.Equal(t, len(p.states), 0)
+}
+
+func TestPollFailure(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	p, triggeredEvents, logs := newPoller(t, ctx,
+		makeRepository("polled", "file://"+t.TempDir()+"/notthere", &v1alpha1.Poll{}),
+	)
+	p.poll(ctx, time.Now())
+	assert.Equal(t, len(*triggeredEvents), 0)
+	assert.Equal(t, logs.FilterMessageSnippet("cannot poll repository").Len(), 1)
+}
+
+func TestRecordRefsRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		updateErr   error
+		wantUpdates int
+		wantErr     bool
+	}{
+		{
+			name:        "retry on conflict",
+			updateErr:   errors.NewConflict(v1alpha1.Resource("repositories"), "polled", fmt.Errorf("modified")),
+			wantUpdates: 2,
+		},
+		{
+			name:        "no retry on other errors",
+			updateErr:   errors.NewForbidden(v1alpha1.Resource("repositories"), "polled", fmt.Errorf("denied")),
+			wantUpdates: 1,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			repo := makeRepository("polled", "https://git.example.com/repo.git", &v1alpha1.Poll{})
+			p, _, _ := newPoller(t, ctx, repo)
+			updates := 0
+			fakeClient, ok := p.run.Clients.PipelineAsCode.(*fakepacclientset.Clientset)
+			assert.Assert(t, ok)
+			fakeClient.PrependReactor("update", "repositories", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+				updates++
+				if updates == 1 {
+					return true, nil, tt.updateErr
+				}
+				return false, nil, nil
+			})
+
+			refs := map[string]string{"refs/heads/main": "0000"}
+			_, err := p.recordRefs(ctx, repo, repo.Spec.URL, refs)
+			assert.Equal(t, updates, tt.wantUpdates)
+			if tt.wantErr {
+				assert.Assert(t, errors.IsForbidden(err))
+				return
+			}
+			assert.NilError(t, err)
+			got, err := p.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "polled", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got.PollStatus, &v1alpha1.PollStatus{URL: repo.Spec.URL, Refs: refs})
+		})
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	tests := []struct {
+		interval string
+		want     time.Duration
+	}{
+		{interval: "", want: DefaultInterval},
+		{interval: "1h", want: time.Hour},
+		{interval: "10s", want: MinInterval},
+		{interval: "invalid", want: DefaultInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.interval, func(t *testing.T) {
+			assert.Equal(t, pollInterval(&v1alpha1.Poll{Interval: tt.interval}), tt.want)
+		})
+	}
+}
+
+func TestSplitURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		wantOrg  string
+		wantRepo string
+	}{
+		{url: "https://git.example.com/org/repo.git", wantOrg: "org", wantRepo: "repo"},
+		{url: "https://git.example.com/cgit/org/repo", wantOrg: "org", wantRepo: "repo"},
+		{url: "https://git.example.com/repo.git", wantOrg: "repo", wantRepo: "repo"},
+		{url: "file:///srv/git/repo.git", wantOrg: "git", wantRepo: "repo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			org, repo := splitURL(tt.url)
+			assert.Equal(t, org, tt.wantOrg)
+			assert.Equal(t, repo, tt.wantRepo)
+		})
+	}
+}
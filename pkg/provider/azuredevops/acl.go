@@ -0,0 +1,209 @@
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops/types"
+)
+
+// CheckPolicyAllowing checks if the sender is a member of one of the allowed
+// teams of the project.
+func (v *Provider) CheckPolicyAllowing(ctx context.Context, event *info.Event, allowedTeams []string) (bool, string) {
+	for _, team := range allowedTeams {
+		members, err := v.teamMembers(ctx, team)
+		if err != nil {
+			v.Logger.Infof("error while getting members of team: %s, error: %s", team, err.Error())
+			continue
+		}
+		if isMember(members, event.Sender) {
+			return true, fmt.Sprintf("allowing user: %s as a member of the team: %s", event.Sender, team)
+		}
+	}
+	return false, fmt.Sprintf("user: %s is not a member of any of the allowed teams: %v", event.Sender, allowedTeams)
+}
+
+func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, error) {
+	if v.httpClient == nil {
+		return false, fmt.Errorf("%s", noClientErrStr)
+	}
+
+	aclPolicy := policy.Policy{
+		Repository:   v.repo,
+		EventEmitter: v.eventEmitter,
+		Event:        event,
+		VCX:          v,
+		Logger:       v.Logger,
+	}
+
+	// Try to detect a policy rule allowed it
+	policyAllowed, policyReason := aclPolicy.IsAllowed(ctx, detectTriggerType(event))
+	switch policyAllowed {
+	case policy.ResultAllowed:
+		return true, nil
+	case policy.ResultDisallowed:
+		return false, nil
+	case policy.ResultNotSet: // this is to make golangci-lint happy
+	}
+
+	allowed, err := v.aclCheckAll(ctx, event)
+	if err != nil {
+		return false, err
+	}
+	if allowed {
+		return true, nil
+	}
+
+	// Try to parse the comment from an owner who has issues a /ok-to-test
+	ownerAllowed, err := v.aclAllowedOkToTestFromAnOwner(ctx, event)
+	if err != nil {
+		return false, err
+	}
+	if ownerAllowed {
+		return true, nil
+	}
+
+	// error with the policy reason if it was set
+	if policyReason != "" {
+		return false, fmt.Errorf("%s", policyReason)
+	}
+	return false, nil
+}
+
+// detectTriggerType maps the event to the trigger type used by the policy
+// rules.
+func detectTriggerType(event *info.Event) triggertype.Trigger {
+	switch opscomments.EventType(event.EventType) {
+	case opscomments.OkToTestCommentEventType:
+		return triggertype.OkToTest
+	case opscomments.RetestAllCommentEventType, opscomments.RetestSingleCommentEventType,
+		opscomments.TestAllCommentEventType, opscomments.TestSingleCommentEventType:
+		return triggertype.Retest
+	default:
+	}
+	return event.TriggerTarget
+}
+
+// aclCheckAll checks if the sender is a member of the project default team
+// or is allowed in the OWNERS file.
+func (v *Provider) aclCheckAll(ctx context.Context, event *info.Event) (bool, error) {
+	if v.checkMembership(ctx, event.Sender) {
+		return true, nil
+	}
+	return v.IsAllowedOwnersFile(ctx, event)
+}
+
+// aclAllowedOkToTestFromAnOwner goes over the comments of the pull request
+// and checks if there is a /ok-to-test from an allowed user.
+func (v *Provider) aclAllowedOkToTestFromAnOwner(ctx context.Context, event *info.Event) (bool, error) {
+	if event.PullRequestNumber == 0 || v.pacInfo == nil || !v.pacInfo.RememberOKToTest {
+		return false, nil
+	}
+
+	threads, err := v.listThreads(ctx, event.PullRequestNumber)
+	if err != nil {
+		return false, err
+	}
+	for _, thread := range threads {
+		for _, comment := range thread.Comments {
+			if !acl.MatchRegexp(acl.OKToTestCommentRegexp, comment.Content) {
+				continue
+			}
+			commenterEvent := info.NewEvent()
+			commenterEvent.Event = event.Event
+			commenterEvent.Sender = comment.Author.UniqueName
+			commenterEvent.AccountID = comment.Author.ID
+			commenterEvent.BaseBranch = event.BaseBranch
+			commenterEvent.HeadBranch = event.HeadBranch
+			commenterEvent.DefaultBranch = event.DefaultBranch
+			allowed, err := v.aclCheckAll(ctx, commenterEvent)
+			if err != nil {
+				return false, err
+			}
+			if allowed {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// IsAllowedOwnersFile get the owner files (OWNERS, OWNERS_ALIASES) from main branch
+// and check if we have explicitly allowed the user in there.
+func (v *Provider) IsAllowedOwnersFile(ctx context.Context, event *info.Event) (bool, error) {
+	ownerContent, err := v.GetFileInsideRepo(ctx, event, "OWNERS", event.DefaultBranch)
+	if err != nil {
+		if strings.Contains(err.Error(), "cannot find") {
+			// no owner file, skipping
+			return false, nil
+		}
+		return false, err
+	}
+	// OWNERS_ALIASES file existence is not required, if we get "not found" continue
+	ownerAliasesContent, err := v.GetFileInsideRepo(ctx, event, "OWNERS_ALIASES", event.DefaultBranch)
+	if err != nil {
+		if !strings.Contains(err.Error(), "cannot find") {
+			return false, err
+		}
+	}
+	return acl.UserInOwnerFile(ownerContent, ownerAliasesContent, event.Sender)
+}
+
+// checkMembership checks if the user is a member of the default team of the
+// project, which by default are the contributors of the project.
+func (v *Provider) checkMembership(ctx context.Context, uniqueName string) bool {
+	// Initialize cache lazily
+	if v.memberCache == nil {
+		v.memberCache = map[string]bool{}
+	}
+	if allowed, ok := v.memberCache[uniqueName]; ok {
+		return allowed
+	}
+
+	project := &types.Project{}
+	if _, err := v.request(ctx, http.MethodGet, v.projectAPIURL(""), nil, nil, project); err != nil {
+		// If the API call fails, don't cache the result so a transient
+		// failure can be retried on the next invocation.
+		v.Logger.Debugf("cannot get project %s: %v", v.project, err)
+		return false
+	}
+	if project.DefaultTeam == nil {
+		v.memberCache[uniqueName] = false
+		return false
+	}
+
+	members, err := v.teamMembers(ctx, project.DefaultTeam.Name)
+	if err != nil {
+		v.Logger.Debugf("cannot get members of team %s: %v", project.DefaultTeam.Name, err)
+		return false
+	}
+	allowed := isMember(members, uniqueName)
+	v.memberCache[uniqueName] = allowed
+	return allowed
+}
+
+func (v *Provider) teamMembers(ctx context.Context, team string) ([]types.TeamMember, error) {
+	members := &types.TeamMemberList{}
+	membersURL := v.projectAPIURL(fmt.Sprintf("/teams/%s/members", url.PathEscape(team)))
+	if _, err := v.request(ctx, http.MethodGet, membersURL, nil, nil, members); err != nil {
+		return nil, err
+	}
+	return members.Value, nil
+}
+
+func isMember(members []types.TeamMember, uniqueName string) bool {
+	for _, member := range members {
+		if strings.EqualFold(member.Identity.UniqueName, uniqueName) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package azuredevops
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	azuretest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops/test"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops/types"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestIsAllowed(t *testing.T) {
+	tests := []struct {
+		name             string
+		event            *info.Event
+		members          []string
+		teams            map[string][]string
+		threads          []types.CommentThread
+		filescontents    map[string]string
+		policy           *v1alpha1.Policy
+		rememberOKToTest bool
+		isAllowed        bool
+		wantErrSubstr    string
+	}{
+		{
+			name:      "allowed/user is a member of the project",
+			event:     azuretest.MakeEvent(&info.Event{Sender: "member@example.com"}),
+			members:   []string{"Member@example.com"},
+			isAllowed: true,
+		},
+		{
+			name:    "allowed/user is in the owners file",
+			event:   azuretest.MakeEvent(&info.Event{Sender: "owner@example.com"}),
+			members: []string{"member@example.com"},
+			filescontents: map[string]string{
+				"OWNERS": "---\n approvers:\n  - owner@example.com\n",
+			},
+			isAllowed: true,
+		},
+		{
+			name: "allowed/from an ok-to-test comment of a member",
+			event: azuretest.MakeEvent(&info.Event{
+				Sender:            "external@example.com",
+				PullRequestNumber: 1,
+				TriggerTarget:     triggertype.PullRequest,
+			}),
+			members: []string{"member@example.com"},
+			threads: []types.CommentThread{
+				{ID: 1, Comments: []types.Comment{{Content: "/ok-to-test", Author: types.Identity{UniqueName: "member@example.com"}}}},
+			},
+			rememberOKToTest: true,
+			isAllowed:        true,
+		},
+		{
+			name: "disallowed/ok-to-test comments are not remembered",
+			event: azuretest.MakeEvent(&info.Event{
+				Sender:            "external@example.com",
+				PullRequestNumber: 1,
+				TriggerTarget:     triggertype.PullRequest,
+			}),
+			members: []string{"member@example.com"},
+			threads: []types.CommentThread{
+				{ID: 1, Comments: []types.Comment{{Content: "/ok-to-test", Author: types.Identity{UniqueName: "member@example.com"}}}},
+			},
+			isAllowed: false,
+		},
+		{
+			name: "disallowed/ok-to-test comment from a non member",
+			event: azuretest.MakeEvent(&info.Event{
+				Sender:            "external@example.com",
+				PullRequestNumber: 1,
+				TriggerTarget:     triggertype.PullRequest,
+			}),
+			members: []string{"member@example.com"},
+			threads: []types.CommentThread{
+				{ID: 1, Comments: []types.Comment{{Content: "/ok-to-test", Author: types.Identity{UniqueName: "other@example.com"}}}},
+			},
+			rememberOKToTest: true,
+			isAllowed:        false,
+		},
+		{
+			name:      "disallowed/user is not a member",
+			event:     azuretest.MakeEvent(&info.Event{Sender: "external@example.com"}),
+			members:   []string{"member@example.com"},
+			isAllowed: false,
+		},
+		{
+			name: "allowed/policy allows the team of the user",
+			event: azuretest.MakeEvent(&info.Event{
+				Sender:        "release@example.com",
+				TriggerTarget: triggertype.PullRequest,
+			}),
+			members: []string{"member@example.com"},
+			teams:   map[string][]string{"releasers": {"release@example.com"}},
+			policy: &v1alpha1.Policy{
+				PullRequest: []string{"releasers"},
+			},
+			isAllowed: true,
+		},
+		{
+			name: "disallowed/member not in the policy team",
+			event: azuretest.MakeEvent(&info.Event{
+				Sender:        "member@example.com",
+				TriggerTarget: triggertype.PullRequest,
+			}),
+			members: []string{"member@example.com"},
+			teams:   map[string][]string{"releasers": {"release@example.com"}},
+			policy: &v1alpha1.Policy{
+				PullRequest: []string{"releasers"},
+			},
+			isAllowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			client, mux, apiURL, tearDown := azuretest.Setup(t)
+			defer tearDown()
+
+			azuretest.MuxProjectTeam(t, mux, tt.event.Organization, "projectTeam", tt.members...)
+			for team, members := range tt.teams {
+				azuretest.MuxTeamMembers(t, mux, tt.event.Organization, team, members...)
+			}
+			azuretest.MuxThreads(t, mux, tt.event, tt.threads, "", nil)
+			azuretest.MuxItems(t, mux, tt.event, "", tt.filescontents, false, false)
+
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec:       v1alpha1.RepositorySpec{Settings: &v1alpha1.Settings{Policy: tt.policy}},
+			}
+			v := &Provider{
+				Logger:       logger,
+				apiURL:       apiURL,
+				httpClient:   client,
+				project:      tt.event.Organization,
+				repoName:     tt.event.Repository,
+				repo:         repo,
+				eventEmitter: events.NewEventEmitter(nil, logger),
+				pacInfo:      &info.PacOpts{Settings: settings.Settings{RememberOKToTest: tt.rememberOKToTest}},
+			}
+
+			got, err := v.IsAllowed(ctx, tt.event)
+			if tt.wantErrSubstr != "" {
+				assert.ErrorContains(t, err, tt.wantErrSubstr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.isAllowed)
+		})
+	}
+}
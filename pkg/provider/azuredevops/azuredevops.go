@@ -0,0 +1,560 @@
+package azuredevops
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/changedfiles"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops/types"
+	"go.uber.org/zap"
+)
+
+const (
+	taskStatusTemplate = `{{range $taskrun := .TaskRunList }}| **{{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }}** | {{ $taskrun.ConsoleLogURL }} | *{{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.PipelineRunTaskRunStatus.Status.CompletionTime }}* |
+{{ end }}`
+	noClientErrStr   = `no azure devops client has been initialized, exiting... (hint: did you forget setting a secret on your repo?)`
+	statusGenre      = "pipelines-as-code"
+	apiResponseLimit = 100
+)
+
+var _ provider.Interface = (*Provider)(nil)
+
+type Provider struct {
+	httpClient        *http.Client
+	Logger            *zap.SugaredLogger
+	run               *params.Run
+	pacInfo           *info.PacOpts
+	token             string
+	apiURL            string
+	project           string
+	repoName          string
+	pullRequestNumber int
+	eventEmitter      *events.EventEmitter
+	repo              *v1alpha1.Repository
+	triggerEvent      string
+	// memberCache caches team membership checks by unique name within the
+	// current provider instance lifecycle to avoid repeated API calls.
+	memberCache        map[string]bool
+	cachedChangedFiles *changedfiles.ChangedFiles
+}
+
+func (v *Provider) SetLogger(logger *zap.SugaredLogger) {
+	v.Logger = logger
+}
+
+func (v *Provider) SetPacInfo(pacInfo *info.PacOpts) {
+	v.pacInfo = pacInfo
+}
+
+// GetTaskURI TODO: Implement ME.
+func (v *Provider) GetTaskURI(_ context.Context, _ *info.Event, _ string) (bool, string, error) {
+	return false, "", nil
+}
+
+func (v *Provider) CreateToken(_ context.Context, _ []string, _ *info.Event) (string, error) {
+	return "", nil
+}
+
+func (v *Provider) GetTemplate(commentType provider.CommentType) string {
+	return provider.GetMarkdownTemplate(commentType)
+}
+
+func (v *Provider) GetConfig() *info.ProviderConfig {
+	return &info.ProviderConfig{
+		TaskStatusTMPL: taskStatusTemplate,
+		APIURL:         v.apiURL,
+		Name:           "azure-devops",
+	}
+}
+
+// Validate checks the service hook credentials, Azure DevOps service hooks
+// do not sign their payloads but let you configure a basic authentication
+// which password is compared to the webhook secret.
+func (v *Provider) Validate(_ context.Context, _ *params.Run, event *info.Event) error {
+	if event.Provider.WebhookSecret == "" {
+		return fmt.Errorf("no webhook secret configured: set webhook secret in repository CR or secret")
+	}
+
+	req := &http.Request{Header: event.Request.Header}
+	_, password, ok := req.BasicAuth()
+	if !ok {
+		return fmt.Errorf("no basic authentication detected in the azure devops service hook request: webhook validation requires a secret")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(event.Provider.WebhookSecret), []byte(password)) == 0 {
+		return fmt.Errorf("azure devops webhook validation failed: password does not match configured secret")
+	}
+	return nil
+}
+
+func (v *Provider) SetClient(_ context.Context, run *params.Run, runevent *info.Event, repo *v1alpha1.Repository, eventsEmitter *events.EventEmitter) error {
+	if runevent.Provider.Token == "" {
+		return fmt.Errorf("no git_provider.secret has been set in the repo crd")
+	}
+
+	collectionURL, project, repoName, err := parseRepoURL(runevent.URL)
+	if err != nil {
+		return err
+	}
+	// the project and repository names from the payload are more accurate
+	// than the one we guess from the URL.
+	if v.project == "" {
+		v.project = project
+	}
+	if v.repoName == "" {
+		v.repoName = repoName
+	}
+	runevent.Organization = v.project
+	runevent.Repository = v.repoName
+
+	// Let the user override the collection URL for Azure DevOps Server
+	// installations where we cannot guess it from the repository URL.
+	switch {
+	case runevent.Provider.URL != "":
+		v.apiURL = strings.TrimSuffix(runevent.Provider.URL, "/")
+	case v.apiURL == "":
+		v.apiURL = collectionURL
+	}
+	if _, err := url.Parse(v.apiURL); err != nil {
+		return fmt.Errorf("failed to parse api url %s: %w", v.apiURL, err)
+	}
+
+	if v.httpClient == nil {
+		v.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	v.token = runevent.Provider.Token
+	v.run = run
+	v.eventEmitter = eventsEmitter
+	v.repo = repo
+	v.triggerEvent = runevent.EventType
+	if v.pullRequestNumber == 0 {
+		v.pullRequestNumber = runevent.PullRequestNumber
+	}
+
+	// Added for security audit purposes to log client access when a token is used
+	run.Clients.Log.Infof("azure-devops: initialized client with provided token for apiURL=%s, project=%s, repo=%s", v.apiURL, v.project, v.repoName)
+	return nil
+}
+
+// commitStatusState returns the state of the commit status of statusOpts and
+// sets the title matching the state.
+func commitStatusState(statusOpts *provider.StatusOpts) string {
+	var state string
+	switch statusOpts.Conclusion {
+	case "skipped":
+		state = "notApplicable"
+		statusOpts.Title = "Skipped validating this commit"
+	case "neutral":
+		state = "notApplicable"
+		statusOpts.Title = "Stopped"
+	case "cancelled":
+		state = "failed"
+		statusOpts.Title = "Cancelled validating this commit"
+	case "failure":
+		state = "failed"
+		statusOpts.Title = "Failed"
+	case "success":
+		state = "succeeded"
+		statusOpts.Title = "Successfully validated your commit"
+	case "completed":
+		state = "succeeded"
+		statusOpts.Title = "Completed"
+	case "pending":
+		state = "pending"
+		if statusOpts.Title == "" {
+			statusOpts.Title = "Pending"
+		}
+	default:
+		state = "notSet"
+	}
+	if statusOpts.Status == "in_progress" {
+		state = "pending"
+		statusOpts.Title = "CI has started"
+	}
+	return state
+}
+
+// gitStatus returns the commit status of the PipelineRun with state and
+// description.
+func (v *Provider) gitStatus(statusOpts provider.StatusOpts, state, description string) types.GitStatus {
+	contextName := provider.GetCheckName(statusOpts, v.pacInfo)
+	if contextName == "" {
+		contextName = statusGenre
+	}
+	return types.GitStatus{
+		State:       state,
+		Description: description,
+		TargetURL:   statusOpts.DetailsURL,
+		Context: types.GitStatusContext{
+			Name:  contextName,
+			Genre: statusGenre,
+		},
+	}
+}
+
+// postStatus sets gitStatus on the commit of the event, and on its pull
+// request when the event is on one. It returns whether it was set on a pull
+// request.
+func (v *Provider) postStatus(ctx context.Context, event *info.Event, gitStatus types.GitStatus) (bool, error) {
+	if _, err := v.request(ctx, http.MethodPost, v.repoAPIURL(fmt.Sprintf("/commits/%s/statuses", event.SHA)), nil, gitStatus, nil); err != nil {
+		return false, fmt.Errorf("cannot create commit status on %s: %w", event.SHA, err)
+	}
+
+	eventType := triggertype.IsPullRequestType(event.EventType)
+	if opscomments.IsAnyOpsEventType(event.EventType) {
+		eventType = triggertype.PullRequest
+	}
+	if event.PullRequestNumber == 0 || (eventType != triggertype.PullRequest && event.TriggerTarget != triggertype.PullRequest) {
+		return false, nil
+	}
+
+	// The pull request status is what shows up in the status section of the
+	// pull request and what the branch policies can require.
+	prStatusURL := v.repoAPIURL(fmt.Sprintf("/pullRequests/%d/statuses", event.PullRequestNumber))
+	if _, err := v.request(ctx, http.MethodPost, prStatusURL, nil, gitStatus, nil); err != nil {
+		return false, fmt.Errorf("cannot create pull request status on #%d: %w", event.PullRequestNumber, err)
+	}
+
+	return true, nil
+}
+
+// UpdateStatusDetails replaces the description of the commit status of the
+// PipelineRun, and of its pull request status, Azure DevOps has no check runs
+// to add text to.
+func (v *Provider) UpdateStatusDetails(ctx context.Context, event *info.Event, statusOpts provider.StatusOpts, _, description string) error {
+	if v.httpClient == nil {
+		return fmt.Errorf("%s", noClientErrStr)
+	}
+	state := commitStatusState(&statusOpts)
+	_, err := v.postStatus(ctx, event, v.gitStatus(statusOpts, state, description))
+	return err
+}
+
+func (v *Provider) CreateStatus(ctx context.Context, event *info.Event, statusOpts provider.StatusOpts) error {
+	if v.httpClient == nil {
+		return fmt.Errorf("%s", noClientErrStr)
+	}
+
+	state := commitStatusState(&statusOpts)
+
+	onPullRequest, err := v.postStatus(ctx, event, v.gitStatus(statusOpts, state, statusOpts.Title))
+	if err != nil || !onPullRequest {
+		return err
+	}
+
+	if statusOpts.Text == "" || (statusOpts.Status != "completed" && !statusOpts.AccessDenied) {
+		return nil
+	}
+
+	onPr := ""
+	if statusOpts.OriginalPipelineRunName != "" {
+		onPr = "/" + statusOpts.OriginalPipelineRunName
+	}
+	body := fmt.Sprintf("**%s%s** - %s\n\n%s", v.pacInfo.ApplicationName, onPr, statusOpts.Title, statusOpts.Text)
+	if statusOpts.DetailsURL != "" {
+		body += fmt.Sprintf("\n\nFull log available [here](%s)", statusOpts.DetailsURL)
+	}
+	return v.createThread(ctx, event.PullRequestNumber, body)
+}
+
+// createThread creates a new comment thread on a pull request. Threads are
+// created as closed so they don't block the "check for comment resolution"
+// branch policy.
+func (v *Provider) createThread(ctx context.Context, prNumber int, body string) error {
+	thread := types.CommentThread{
+		Comments: []types.Comment{{Content: body, CommentType: "text"}},
+		Status:   "closed",
+	}
+	if _, err := v.request(ctx, http.MethodPost, v.repoAPIURL(fmt.Sprintf("/pullRequests/%d/threads", prNumber)), nil, thread, nil); err != nil {
+		return fmt.Errorf("cannot create comment on pull request #%d: %w", prNumber, err)
+	}
+	return nil
+}
+
+func (v *Provider) CreateCommitComment(_ context.Context, _ *info.Event, _ string) error {
+	return fmt.Errorf("commit comments are not supported on azure devops")
+}
+
+func (v *Provider) CreateComment(ctx context.Context, event *info.Event, comment, updateMarker string) error {
+	if v.httpClient == nil {
+		return fmt.Errorf("%s", noClientErrStr)
+	}
+
+	if event.PullRequestNumber == 0 {
+		return fmt.Errorf("create comment only works on pull requests")
+	}
+
+	if updateMarker != "" {
+		threads, err := v.listThreads(ctx, event.PullRequestNumber)
+		if err != nil {
+			return err
+		}
+		re := regexp.MustCompile(updateMarker)
+		for _, thread := range threads {
+			for _, c := range thread.Comments {
+				if !re.MatchString(c.Content) {
+					continue
+				}
+				commentURL := v.repoAPIURL(fmt.Sprintf("/pullRequests/%d/threads/%d/comments/%d", event.PullRequestNumber, thread.ID, c.ID))
+				if _, err := v.request(ctx, http.MethodPatch, commentURL, nil, types.Comment{Content: comment}, nil); err != nil {
+					return fmt.Errorf("unable to update pull request comment: %w", err)
+				}
+				return nil
+			}
+		}
+	}
+
+	return v.createThread(ctx, event.PullRequestNumber, comment)
+}
+
+func (v *Provider) listThreads(ctx context.Context, prNumber int) ([]types.CommentThread, error) {
+	threads := &types.CommentThreadList{}
+	if _, err := v.request(ctx, http.MethodGet, v.repoAPIURL(fmt.Sprintf("/pullRequests/%d/threads", prNumber)), nil, nil, threads); err != nil {
+		return nil, fmt.Errorf("cannot list comments of pull request #%d: %w", prNumber, err)
+	}
+	return threads.Value, nil
+}
+
+// versionQuery returns the versionDescriptor query parameters for a commit
+// SHA or a branch.
+func versionQuery(version, versionType string) url.Values {
+	query := url.Values{}
+	query.Set("versionDescriptor.version", strings.TrimPrefix(version, "refs/heads/"))
+	query.Set("versionDescriptor.versionType", versionType)
+	return query
+}
+
+func (v *Provider) GetTektonDir(ctx context.Context, event *info.Event, path, provenance string) (string, error) {
+	if v.httpClient == nil {
+		return "", fmt.Errorf("%s", noClientErrStr)
+	}
+
+	// default set provenance from the SHA
+	version, versionType := event.SHA, "commit"
+	if provenance == "default_branch" {
+		version, versionType = event.DefaultBranch, "branch"
+		v.Logger.Infof("Using PipelineRun definition from default_branch: %s", event.DefaultBranch)
+	} else {
+		v.Logger.Infof("Using PipelineRun definition from source %s commit SHA: %s", event.TriggerTarget.String(), event.SHA)
+	}
+
+	query := versionQuery(version, versionType)
+	query.Set("scopePath", "/"+strings.TrimPrefix(path, "/"))
+	query.Set("recursionLevel", "Full")
+	items := &types.ItemList{}
+	resp, err := v.request(ctx, http.MethodGet, v.repoAPIURL("/items"), query, nil, items)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to list %s dir: %w", path, err)
+	}
+
+	var allTemplates string
+	for _, item := range items.Value {
+		if item.IsFolder || (!strings.HasSuffix(item.Path, ".yaml") && !strings.HasSuffix(item.Path, ".yml")) {
+			continue
+		}
+		fpath := strings.TrimPrefix(item.Path, "/")
+		data, err := v.getObject(ctx, fpath, version, versionType)
+		if err != nil {
+			return "", err
+		}
+		if err := provider.ValidateYaml([]byte(data), fpath); err != nil {
+			return "", err
+		}
+		if allTemplates != "" && !strings.HasPrefix(data, "---") {
+			allTemplates += "---"
+		}
+		allTemplates += "\n" + data + "\n"
+	}
+	return allTemplates, nil
+}
+
+func (v *Provider) getObject(ctx context.Context, path, version, versionType string) (string, error) {
+	query := versionQuery(version, versionType)
+	query.Set("path", "/"+strings.TrimPrefix(path, "/"))
+	query.Set("includeContent", "true")
+	item := &types.Item{}
+	if _, err := v.request(ctx, http.MethodGet, v.repoAPIURL("/items"), query, nil, item); err != nil {
+		return "", fmt.Errorf("cannot find %s inside the %s repository: %w", path, v.repoName, err)
+	}
+	return item.Content, nil
+}
+
+func (v *Provider) GetFileInsideRepo(ctx context.Context, runevent *info.Event, path, target string) (string, error) {
+	if v.httpClient == nil {
+		return "", fmt.Errorf("%s", noClientErrStr)
+	}
+	if target != "" {
+		return v.getObject(ctx, path, target, "branch")
+	}
+	return v.getObject(ctx, path, runevent.SHA, "commit")
+}
+
+func (v *Provider) GetCommitInfo(ctx context.Context, runevent *info.Event) error {
+	if v.httpClient == nil {
+		return fmt.Errorf("%s", noClientErrStr)
+	}
+
+	commit := &types.Commit{}
+	// if we don't have a SHA (ie: incoming-webhook) then get it from the branch
+	// and populate in the runevent.
+	if runevent.SHA == "" && runevent.HeadBranch != "" {
+		query := url.Values{}
+		query.Set("searchCriteria.itemVersion.version", strings.TrimPrefix(runevent.HeadBranch, "refs/heads/"))
+		query.Set("searchCriteria.itemVersion.versionType", "branch")
+		query.Set("searchCriteria.$top", "1")
+		commits := &types.CommitList{}
+		if _, err := v.request(ctx, http.MethodGet, v.repoAPIURL("/commits"), query, nil, commits); err != nil {
+			return err
+		}
+		if len(commits.Value) == 0 {
+			return fmt.Errorf("cannot find any commit on branch %s", runevent.HeadBranch)
+		}
+		runevent.SHA = commits.Value[0].CommitID
+	}
+
+	if _, err := v.request(ctx, http.MethodGet, v.repoAPIURL("/commits/"+runevent.SHA), nil, nil, commit); err != nil {
+		return err
+	}
+
+	runevent.SHATitle = strings.Split(commit.Comment, "\n")[0]
+	if commit.RemoteURL != "" {
+		runevent.SHAURL = commit.RemoteURL
+	} else if runevent.URL != "" {
+		runevent.SHAURL = fmt.Sprintf("%s/commit/%s", runevent.URL, runevent.SHA)
+	}
+
+	// Populate full commit information for LLM context
+	runevent.SHAMessage = commit.Comment
+	if commit.Author != nil {
+		runevent.SHAAuthorName = commit.Author.Name
+		runevent.SHAAuthorEmail = commit.Author.Email
+		if authorDate, err := time.Parse(time.RFC3339, commit.Author.Date); err == nil {
+			runevent.SHAAuthorDate = authorDate
+		}
+	}
+	if commit.Committer != nil {
+		runevent.SHACommitterName = commit.Committer.Name
+		runevent.SHACommitterEmail = commit.Committer.Email
+		if committerDate, err := time.Parse(time.RFC3339, commit.Committer.Date); err == nil {
+			runevent.SHACommitterDate = committerDate
+		}
+	}
+	runevent.HasSkipCommand = provider.SkipCI(commit.Comment)
+
+	if runevent.DefaultBranch == "" {
+		repository := &types.Repository{}
+		if _, err := v.request(ctx, http.MethodGet, v.repoAPIURL(""), nil, nil, repository); err != nil {
+			return err
+		}
+		runevent.DefaultBranch = strings.TrimPrefix(repository.DefaultBranch, "refs/heads/")
+	}
+	return nil
+}
+
+// GetFiles gets and caches the list of files changed by a given event.
+func (v *Provider) GetFiles(ctx context.Context, runevent *info.Event) (changedfiles.ChangedFiles, error) {
+	if v.cachedChangedFiles == nil {
+		changes, err := v.fetchChangedFiles(ctx, runevent)
+		if err != nil {
+			return changedfiles.ChangedFiles{}, err
+		}
+		v.cachedChangedFiles = &changes
+	}
+	return *v.cachedChangedFiles, nil
+}
+
+func (v *Provider) fetchChangedFiles(ctx context.Context, runevent *info.Event) (changedfiles.ChangedFiles, error) {
+	if v.httpClient == nil {
+		return changedfiles.ChangedFiles{}, fmt.Errorf("%s", noClientErrStr)
+	}
+
+	changedFiles := changedfiles.ChangedFiles{}
+	//nolint:exhaustive // we don't need to handle all cases
+	switch runevent.TriggerTarget {
+	case triggertype.PullRequest, triggertype.PullRequestClosed:
+		iterations := &types.IterationList{}
+		iterationsURL := v.repoAPIURL(fmt.Sprintf("/pullRequests/%d/iterations", runevent.PullRequestNumber))
+		if _, err := v.request(ctx, http.MethodGet, iterationsURL, nil, nil, iterations); err != nil {
+			return changedfiles.ChangedFiles{}, err
+		}
+		if len(iterations.Value) == 0 {
+			return changedFiles, nil
+		}
+		// the changes of the last iteration are compared to the target branch.
+		lastIteration := iterations.Value[len(iterations.Value)-1].ID
+		changesURL := fmt.Sprintf("%s/%d/changes", iterationsURL, lastIteration)
+		skip := 0
+		for {
+			query := url.Values{}
+			query.Set("$top", strconv.Itoa(apiResponseLimit))
+			query.Set("$skip", strconv.Itoa(skip))
+			changes := &types.IterationChanges{}
+			if _, err := v.request(ctx, http.MethodGet, changesURL, query, nil, changes); err != nil {
+				return changedfiles.ChangedFiles{}, err
+			}
+			addChanges(&changedFiles, changes.ChangeEntries)
+			if changes.NextSkip == 0 {
+				break
+			}
+			skip = changes.NextSkip
+		}
+	case triggertype.Push:
+		skip := 0
+		for {
+			query := url.Values{}
+			query.Set("top", strconv.Itoa(apiResponseLimit))
+			query.Set("skip", strconv.Itoa(skip))
+			changes := &types.CommitChanges{}
+			if _, err := v.request(ctx, http.MethodGet, v.repoAPIURL(fmt.Sprintf("/commits/%s/changes", runevent.SHA)), query, nil, changes); err != nil {
+				return changedfiles.ChangedFiles{}, err
+			}
+			addChanges(&changedFiles, changes.Changes)
+			if len(changes.Changes) < apiResponseLimit {
+				break
+			}
+			skip += apiResponseLimit
+		}
+	default:
+		// No action necessary
+	}
+	return changedFiles, nil
+}
+
+// addChanges sorts the Azure DevOps changes, the changeType is a comma
+// separated list of flags (ie: "edit, rename").
+func addChanges(changedFiles *changedfiles.ChangedFiles, changes []types.Change) {
+	for _, change := range changes {
+		if change.Item.IsFolder || change.Item.GitObjectType == "tree" {
+			continue
+		}
+		path := strings.TrimPrefix(change.Item.Path, "/")
+		changedFiles.All = append(changedFiles.All, path)
+		switch {
+		case strings.Contains(change.ChangeType, "add"):
+			changedFiles.Added = append(changedFiles.Added, path)
+		case strings.Contains(change.ChangeType, "delete"):
+			changedFiles.Deleted = append(changedFiles.Deleted, path)
+		case strings.Contains(change.ChangeType, "rename"):
+			changedFiles.Renamed = append(changedFiles.Renamed, path)
+		case strings.Contains(change.ChangeType, "edit"):
+			changedFiles.Modified = append(changedFiles.Modified, path)
+		}
+	}
+}
@@ -0,0 +1,512 @@
+package azuredevops
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/changedfiles"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	azuretest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops/test"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops/types"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGetTektonDir(t *testing.T) {
+	tests := []struct {
+		name            string
+		event           *info.Event
+		path            string
+		testDirPath     string
+		contentContains string
+		wantDirAPIErr   bool
+		wantFilesAPIErr bool
+		wantErr         string
+	}{
+		{
+			name:            "good/get tekton directory",
+			event:           azuretest.MakeEvent(nil),
+			path:            ".tekton",
+			testDirPath:     "../../pipelineascode/testdata/pull_request/.tekton",
+			contentContains: "kind: PipelineRun",
+		},
+		{
+			name:            "good/no yaml files in there",
+			event:           azuretest.MakeEvent(nil),
+			path:            ".tekton",
+			testDirPath:     "./",
+			contentContains: "",
+		},
+		{
+			name:            "good/no tekton directory",
+			event:           azuretest.MakeEvent(nil),
+			path:            ".notthere",
+			testDirPath:     "../../pipelineascode/testdata/pull_request/.tekton",
+			contentContains: "",
+		},
+		{
+			name:        "bad/badly formatted yaml",
+			event:       azuretest.MakeEvent(nil),
+			path:        ".tekton",
+			testDirPath: "../../pipelineascode/testdata/bad_yaml/.tekton",
+			wantErr:     "error unmarshalling yaml file .tekton/badyaml.yaml: yaml: line 2: did not find expected key",
+		},
+		{
+			name:          "bad/get dir api error",
+			event:         azuretest.MakeEvent(nil),
+			path:          ".tekton",
+			testDirPath:   "../../pipelineascode/testdata/pull_request/.tekton",
+			wantDirAPIErr: true,
+			wantErr:       "failed to list .tekton dir",
+		},
+		{
+			name:            "bad/get files api error",
+			event:           azuretest.MakeEvent(nil),
+			path:            ".tekton",
+			testDirPath:     "../../pipelineascode/testdata/pull_request/.tekton",
+			wantFilesAPIErr: true,
+			wantErr:         "cannot find .tekton/pipeline.yaml inside the repo repository",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, apiURL, tearDown := azuretest.Setup(t)
+			defer tearDown()
+			v := &Provider{Logger: logger, apiURL: apiURL, httpClient: client, project: tt.event.Organization, repoName: tt.event.Repository}
+			azuretest.MuxDirContent(t, mux, tt.event, tt.testDirPath, ".tekton", tt.wantDirAPIErr, tt.wantFilesAPIErr)
+			content, err := v.GetTektonDir(ctx, tt.event, tt.path, "")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(content, tt.contentContains), "content %s doesn't have %s", content, tt.contentContains)
+		})
+	}
+}
+
+func TestCreateStatus(t *testing.T) {
+	pacopts := &info.PacOpts{
+		Settings: settings.Settings{
+			ApplicationName: "HELLO APP",
+		},
+	}
+
+	tests := []struct {
+		name                  string
+		status                provider.StatusOpts
+		pullRequestNumber     int
+		expectedState         string
+		expectedDescription   string
+		expectedCommentSubstr string
+		wantPRStatus          bool
+		wantComment           bool
+		nilClient             bool
+		wantErr               string
+	}{
+		{
+			name:      "bad/no client",
+			nilClient: true,
+			wantErr:   noClientErrStr,
+		},
+		{
+			name: "good/push success",
+			status: provider.StatusOpts{
+				Conclusion: "success",
+			},
+			expectedState:       "succeeded",
+			expectedDescription: "Successfully validated your commit",
+		},
+		{
+			name: "good/in progress",
+			status: provider.StatusOpts{
+				Status:     "in_progress",
+				Conclusion: "pending",
+			},
+			pullRequestNumber:   10,
+			expectedState:       "pending",
+			expectedDescription: "CI has started",
+			wantPRStatus:        true,
+		},
+		{
+			name: "good/pull request failure with comment",
+			status: provider.StatusOpts{
+				Status:     "completed",
+				Conclusion: "failure",
+				Text:       "Oh no it failed",
+				DetailsURL: "https://console/pr",
+			},
+			pullRequestNumber:     10,
+			expectedState:         "failed",
+			expectedDescription:   "Failed",
+			expectedCommentSubstr: "Oh no it failed",
+			wantPRStatus:          true,
+			wantComment:           true,
+		},
+		{
+			name: "good/skipped",
+			status: provider.StatusOpts{
+				Conclusion: "skipped",
+			},
+			expectedState:       "notApplicable",
+			expectedDescription: "Skipped validating this commit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, apiURL, tearDown := azuretest.Setup(t)
+			defer tearDown()
+			event := azuretest.MakeEvent(nil)
+			event.PullRequestNumber = tt.pullRequestNumber
+			if tt.pullRequestNumber != 0 {
+				event.EventType = triggertype.PullRequest.String()
+				event.TriggerTarget = triggertype.PullRequest
+			}
+			v := &Provider{apiURL: apiURL, httpClient: client, project: event.Organization, repoName: event.Repository, pacInfo: pacopts}
+			if tt.nilClient {
+				v.httpClient = nil
+			}
+			prStatusCalled, commentCreated := false, false
+			azuretest.MuxCreateCommitStatus(t, mux, event, tt.expectedState, tt.expectedDescription)
+			azuretest.MuxCreatePullRequestStatus(t, mux, event, tt.expectedState, &prStatusCalled)
+			azuretest.MuxThreads(t, mux, event, nil, tt.expectedCommentSubstr, &commentCreated)
+
+			err := v.CreateStatus(ctx, event, tt.status)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, prStatusCalled, tt.wantPRStatus)
+			assert.Equal(t, commentCreated, tt.wantComment)
+		})
+	}
+}
+
+func TestCreateComment(t *testing.T) {
+	tests := []struct {
+		name         string
+		event        *info.Event
+		updateMarker string
+		threads      []types.CommentThread
+		wantCreated  bool
+		wantUpdated  bool
+		wantErr      string
+	}{
+		{
+			name:    "bad/no pull request",
+			event:   azuretest.MakeEvent(nil),
+			wantErr: "create comment only works on pull requests",
+		},
+		{
+			name:        "good/create new comment",
+			event:       azuretest.MakeEvent(&info.Event{PullRequestNumber: 1}),
+			wantCreated: true,
+		},
+		{
+			name:         "good/update existing comment",
+			event:        azuretest.MakeEvent(&info.Event{PullRequestNumber: 1}),
+			updateMarker: "MARKER",
+			threads: []types.CommentThread{
+				{ID: 1, Comments: []types.Comment{{ID: 1, Content: "not this one"}}},
+				{ID: 2, Comments: []types.Comment{{ID: 3, Content: "<!-- MARKER -->"}}},
+			},
+			wantUpdated: true,
+		},
+		{
+			name:         "good/marker not found create new comment",
+			event:        azuretest.MakeEvent(&info.Event{PullRequestNumber: 1}),
+			updateMarker: "MARKER",
+			threads: []types.CommentThread{
+				{ID: 1, Comments: []types.Comment{{ID: 1, Content: "not this one"}}},
+			},
+			wantCreated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, apiURL, tearDown := azuretest.Setup(t)
+			defer tearDown()
+			v := &Provider{apiURL: apiURL, httpClient: client, project: tt.event.Organization, repoName: tt.event.Repository}
+			created, updated := false, false
+			azuretest.MuxThreads(t, mux, tt.event, tt.threads, "New Comment", &created)
+			azuretest.MuxUpdateComment(t, mux, tt.event, 2, 3, "New Comment", &updated)
+
+			err := v.CreateComment(ctx, tt.event, "New Comment", tt.updateMarker)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, created, tt.wantCreated)
+			assert.Equal(t, updated, tt.wantUpdated)
+		})
+	}
+}
+
+func TestGetCommitInfo(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client, mux, apiURL, tearDown := azuretest.Setup(t)
+	defer tearDown()
+	event := azuretest.MakeEvent(nil)
+	event.DefaultBranch = ""
+	azuretest.MuxCommit(t, mux, event, types.Commit{
+		CommitID: event.SHA,
+		Comment:  "Hello World\n\nThis is the body [skip ci]",
+		Author: &types.GitUserDate{
+			Name:  "Author",
+			Email: "author@example.com",
+			Date:  "2024-01-02T03:04:05Z",
+		},
+		Committer: &types.GitUserDate{
+			Name:  "Committer",
+			Email: "committer@example.com",
+			Date:  "2024-01-02T03:04:06Z",
+		},
+	})
+	azuretest.MuxRepository(t, mux, event, "refs/heads/main")
+
+	v := &Provider{apiURL: apiURL, httpClient: client, project: event.Organization, repoName: event.Repository}
+	assert.NilError(t, v.GetCommitInfo(ctx, event))
+	assert.Equal(t, event.SHATitle, "Hello World")
+	assert.Equal(t, event.SHAURL, event.URL+"/commit/"+event.SHA)
+	assert.Equal(t, event.SHAAuthorName, "Author")
+	assert.Equal(t, event.SHACommitterEmail, "committer@example.com")
+	assert.Equal(t, event.SHAAuthorDate.Year(), 2024)
+	assert.Equal(t, event.DefaultBranch, "main")
+	assert.Assert(t, event.HasSkipCommand)
+}
+
+func TestGetFiles(t *testing.T) {
+	changes := []types.Change{
+		{ChangeType: "add", Item: types.Item{Path: "/added.go"}},
+		{ChangeType: "edit", Item: types.Item{Path: "/modified.go"}},
+		{ChangeType: "delete", Item: types.Item{Path: "/deleted.go"}},
+		{ChangeType: "edit, rename", Item: types.Item{Path: "/renamed.go"}},
+		{ChangeType: "add", Item: types.Item{Path: "/dir", IsFolder: true}},
+	}
+	want := changedfiles.ChangedFiles{
+		All:      []string{"added.go", "modified.go", "deleted.go", "renamed.go"},
+		Added:    []string{"added.go"},
+		Modified: []string{"modified.go"},
+		Deleted:  []string{"deleted.go"},
+		Renamed:  []string{"renamed.go"},
+	}
+	tests := []struct {
+		name          string
+		triggerTarget triggertype.Trigger
+	}{
+		{
+			name:          "pull request",
+			triggerTarget: triggertype.PullRequest,
+		},
+		{
+			name:          "push",
+			triggerTarget: triggertype.Push,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, apiURL, tearDown := azuretest.Setup(t)
+			defer tearDown()
+			event := azuretest.MakeEvent(&info.Event{PullRequestNumber: 5, TriggerTarget: tt.triggerTarget})
+			azuretest.MuxPullRequestChanges(t, mux, event, changes)
+			azuretest.MuxCommitChanges(t, mux, event, changes)
+
+			v := &Provider{apiURL: apiURL, httpClient: client, project: event.Organization, repoName: event.Repository}
+			got, err := v.GetFiles(ctx, event)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, want)
+		})
+	}
+}
+
+func TestParseRepoURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		repoURL        string
+		wantCollection string
+		wantProject    string
+		wantRepo       string
+		wantErr        string
+	}{
+		{
+			name:           "azure devops services",
+			repoURL:        "https://dev.azure.com/org/project/_git/repo",
+			wantCollection: "https://dev.azure.com/org",
+			wantProject:    "project",
+			wantRepo:       "repo",
+		},
+		{
+			name:           "azure devops services repository named like the project",
+			repoURL:        "https://dev.azure.com/org/_git/project",
+			wantCollection: "https://dev.azure.com/org",
+			wantProject:    "project",
+			wantRepo:       "project",
+		},
+		{
+			name:           "azure devops server",
+			repoURL:        "https://tfs.example.com/tfs/DefaultCollection/project/_git/repo",
+			wantCollection: "https://tfs.example.com/tfs/DefaultCollection",
+			wantProject:    "project",
+			wantRepo:       "repo",
+		},
+		{
+			name:    "not an azure devops repository url",
+			repoURL: "https://github.com/owner/repo",
+			wantErr: "invalid azure devops repository url",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collection, project, repo, err := parseRepoURL(tt.repoURL)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, collection, tt.wantCollection)
+			assert.Equal(t, project, tt.wantProject)
+			assert.Equal(t, repo, tt.wantRepo)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		webhookSecret string
+		user          string
+		password      string
+		noAuth        bool
+		wantErr       string
+	}{
+		{
+			name:          "good/password match",
+			webhookSecret: "secret",
+			password:      "secret",
+		},
+		{
+			name:          "good/user is ignored",
+			webhookSecret: "secret",
+			user:          "pac",
+			password:      "secret",
+		},
+		{
+			name:          "bad/password mismatch",
+			webhookSecret: "secret",
+			password:      "notsecret",
+			wantErr:       "password does not match configured secret",
+		},
+		{
+			name:          "bad/no basic auth",
+			webhookSecret: "secret",
+			noAuth:        true,
+			wantErr:       "no basic authentication detected",
+		},
+		{
+			name:    "bad/no webhook secret",
+			wantErr: "no webhook secret configured",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			req, err := http.NewRequest(http.MethodPost, "/", nil)
+			assert.NilError(t, err)
+			if !tt.noAuth {
+				req.SetBasicAuth(tt.user, tt.password)
+			}
+			event := info.NewEvent()
+			event.Provider = &info.Provider{WebhookSecret: tt.webhookSecret}
+			event.Request = &info.Request{Header: req.Header}
+
+			v := &Provider{}
+			err = v.Validate(ctx, nil, event)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestSetClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       *info.Event
+		apiURL      string
+		wantAPIURL  string
+		wantProject string
+		wantErr     string
+	}{
+		{
+			name:    "bad/no token",
+			event:   azuretest.MakeEvent(nil),
+			wantErr: "no git_provider.secret has been set in the repo crd",
+		},
+		{
+			name: "good/guess collection url",
+			event: azuretest.MakeEvent(&info.Event{
+				Provider: &info.Provider{Token: "token"},
+				URL:      "https://dev.azure.com/org/myproject/_git/myrepo",
+			}),
+			wantAPIURL:  "https://dev.azure.com/org",
+			wantProject: "myproject",
+		},
+		{
+			name: "good/collection url from payload",
+			event: azuretest.MakeEvent(&info.Event{
+				Provider: &info.Provider{Token: "token"},
+				URL:      "https://tfs.example.com/tfs/DefaultCollection/myproject/_git/myrepo",
+			}),
+			apiURL:      "https://tfs.example.com/tfs/Collection",
+			wantAPIURL:  "https://tfs.example.com/tfs/Collection",
+			wantProject: "myproject",
+		},
+		{
+			name: "good/collection url from repository",
+			event: azuretest.MakeEvent(&info.Event{
+				Provider: &info.Provider{Token: "token", URL: "https://tfs.example.com/override/"},
+				URL:      "https://tfs.example.com/tfs/DefaultCollection/myproject/_git/myrepo",
+			}),
+			apiURL:      "https://tfs.example.com/tfs/Collection",
+			wantAPIURL:  "https://tfs.example.com/override",
+			wantProject: "myproject",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			run := &params.Run{Clients: clients.Clients{Log: logger}}
+			v := &Provider{apiURL: tt.apiURL}
+			err := v.SetClient(ctx, run, tt.event, nil, nil)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, v.apiURL, tt.wantAPIURL)
+			assert.Equal(t, v.project, tt.wantProject)
+			assert.Equal(t, tt.event.Organization, tt.wantProject)
+			assert.Assert(t, v.httpClient != nil)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package azuredevops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	providerMetrics "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/providermetrics"
+)
+
+const (
+	apiVersion  = "7.1"
+	publicHost  = "dev.azure.com"
+	gitPathPart = "/_git/"
+)
+
+// apiError is the error payload returned by the Azure DevOps REST API.
+type apiError struct {
+	Message string `json:"message"`
+	TypeKey string `json:"typeKey"`
+}
+
+// parseRepoURL split an Azure DevOps repository URL in its collection URL,
+// project and repository name. The repository URL looks like this:
+//
+//	https://dev.azure.com/{organization}/{project}/_git/{repository}
+//	https://{server}/{collection}/{project}/_git/{repository}
+//
+// when the project and the repository share the same name, Azure DevOps
+// drops the project segment from the URL.
+func parseRepoURL(repoURL string) (string, string, string, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", "", "", err
+	}
+	idx := strings.Index(u.Path, gitPathPart)
+	if idx == -1 {
+		return "", "", "", fmt.Errorf("invalid azure devops repository url %s: no %s segment", repoURL, gitPathPart)
+	}
+	repo := strings.Split(u.Path[idx+len(gitPathPart):], "/")[0]
+	if repo == "" {
+		return "", "", "", fmt.Errorf("invalid azure devops repository url %s: no repository name", repoURL)
+	}
+	prefix := strings.Split(strings.Trim(u.Path[:idx], "/"), "/")
+
+	var collection []string
+	project := repo
+	switch {
+	case u.Host == publicHost && len(prefix) == 1:
+		collection = prefix
+	case u.Host == publicHost && len(prefix) >= 2:
+		collection, project = prefix[:1], prefix[1]
+	case len(prefix) >= 2:
+		collection, project = prefix[:len(prefix)-1], prefix[len(prefix)-1]
+	default:
+		collection = prefix
+	}
+	collectionURL := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+	if p := strings.Join(collection, "/"); p != "" {
+		collectionURL += "/" + p
+	}
+	return collectionURL, project, repo, nil
+}
+
+// repoAPIURL returns the URL of the git repository REST endpoint for path.
+func (v *Provider) repoAPIURL(path string) string {
+	return fmt.Sprintf("%s/%s/_apis/git/repositories/%s%s", v.apiURL,
+		url.PathEscape(v.project), url.PathEscape(v.repoName), path)
+}
+
+// projectAPIURL returns the URL of the project scoped REST endpoint for path.
+func (v *Provider) projectAPIURL(path string) string {
+	return fmt.Sprintf("%s/_apis/projects/%s%s", v.apiURL, url.PathEscape(v.project), path)
+}
+
+// request sends a request to the Azure DevOps REST API, body is JSON encoded
+// when not nil and the response is JSON decoded into out when not nil.
+func (v *Provider) request(ctx context.Context, method, apiURL string, query url.Values, body, out any) (*http.Response, error) {
+	if v.httpClient == nil {
+		return nil, fmt.Errorf("%s", noClientErrStr)
+	}
+	providerMetrics.RecordAPIUsage(
+		v.Logger,
+		// URL used instead of "azure-devops" to differentiate in the case of a CI cluster which
+		// serves multiple Azure DevOps Server instances
+		v.apiURL,
+		v.triggerEvent,
+		v.repo,
+	)
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", apiVersion)
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiURL+"?"+query.Encode(), reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// Personal access tokens are sent as the password of a basic auth with an empty user.
+	req.SetBasicAuth("", v.token)
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		aerr := &apiError{}
+		if jerr := json.Unmarshal(data, aerr); jerr != nil || aerr.Message == "" {
+			aerr.Message = strings.TrimSpace(string(data))
+		}
+		return resp, fmt.Errorf("azure devops api %s %s returned %d: %s", method, req.URL.Path, resp.StatusCode, aerr.Message)
+	}
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp, fmt.Errorf("cannot decode azure devops api response from %s: %w", req.URL.Path, err)
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,116 @@
+package azuredevops
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops/types"
+	"go.uber.org/zap"
+)
+
+const (
+	publisherID = "tfs"
+
+	eventTypePush               = "git.push"
+	eventTypePullRequestCreated = "git.pullrequest.created"
+	eventTypePullRequestUpdated = "git.pullrequest.updated"
+	eventTypePullRequestComment = "ms.vss-code.git-pullrequest-comment-event"
+
+	prStatusActive    = "active"
+	prStatusAbandoned = "abandoned"
+	prStatusCompleted = "completed"
+)
+
+// Detect processes event and detect if it is an Azure DevOps service hook
+// event, whether to process or reject it.
+// returns (if is an azure devops event, whether to process or reject, logger, reason, error if any occurred).
+func (v *Provider) Detect(req *http.Request, payload string, logger *zap.SugaredLogger) (bool, bool, *zap.SugaredLogger, string, error) {
+	isAzure := false
+	// Azure DevOps doesn't send any header identifying the service hook, we
+	// rely on the publisherId of the payload instead.
+	envelope := &types.Event{}
+	if err := json.Unmarshal([]byte(payload), envelope); err != nil || envelope.PublisherID != publisherID || envelope.EventType == "" {
+		return false, false, logger, "", nil
+	}
+
+	// it is an Azure DevOps event
+	isAzure = true
+
+	setLoggerAndProceed := func(processEvent bool, reason string, err error) (bool, bool, *zap.SugaredLogger, string, error) {
+		logger = logger.With("provider", "azure-devops", "event-id", envelope.ID)
+		return isAzure, processEvent, logger, reason, err
+	}
+
+	eventInt, err := parsePayloadType(envelope.EventType, payload)
+	if err != nil {
+		return setLoggerAndProceed(false, "", err)
+	}
+
+	switch gitEvent := eventInt.(type) {
+	case *types.PushEvent:
+		if len(gitEvent.Resource.RefUpdates) == 0 {
+			return setLoggerAndProceed(false, "push event contains no ref updates", nil)
+		}
+		if provider.IsZeroSHA(gitEvent.Resource.RefUpdates[0].NewObjectID) {
+			return setLoggerAndProceed(false, "branch or tag deletion is not supported", nil)
+		}
+		return setLoggerAndProceed(true, "", nil)
+	case *types.PullRequestEvent:
+		switch {
+		case envelope.EventType == eventTypePullRequestCreated:
+			return setLoggerAndProceed(true, "", nil)
+		case provider.Valid(gitEvent.Resource.Status, []string{prStatusAbandoned, prStatusCompleted}):
+			return setLoggerAndProceed(true, "", nil)
+		case gitEvent.Resource.Status == prStatusActive && isSourceBranchUpdate(gitEvent):
+			return setLoggerAndProceed(true, "", nil)
+		}
+		return setLoggerAndProceed(false, fmt.Sprintf("not a pull request update we care about: status \"%s\"", gitEvent.Resource.Status), nil)
+	case *types.PullRequestCommentEvent:
+		if gitEvent.Resource.PullRequest.Status != prStatusActive {
+			return setLoggerAndProceed(false, "comments on closed pull requests is not supported", nil)
+		}
+		if gitEvent.Resource.Comment.CommentType != "" && gitEvent.Resource.Comment.CommentType != "text" {
+			return setLoggerAndProceed(false, fmt.Sprintf("comment of type \"%s\" is not supported", gitEvent.Resource.Comment.CommentType), nil)
+		}
+		if opscomments.CommentEventType(gitEvent.Resource.Comment.Content) == opscomments.NoOpsCommentEventType {
+			return setLoggerAndProceed(false, "comment is not a GitOps command", nil)
+		}
+		return setLoggerAndProceed(true, "", nil)
+	default:
+		return setLoggerAndProceed(false, "", fmt.Errorf("azure-devops: event \"%s\" is not supported", envelope.EventType))
+	}
+}
+
+// isSourceBranchUpdate checks that the git.pullrequest.updated event has been
+// generated by a push to the source branch and not by a reviewer, title or
+// vote change. Azure DevOps only tells us this through the message.
+func isSourceBranchUpdate(event *types.PullRequestEvent) bool {
+	if event.Message == nil || event.Message.Text == "" {
+		return true
+	}
+	return strings.Contains(event.Message.Text, "updated the source branch")
+}
+
+// parsePayloadType decodes the payload into the struct matching the service
+// hook event type.
+func parsePayloadType(eventType, payload string) (any, error) {
+	var eventInt any
+	switch eventType {
+	case eventTypePush:
+		eventInt = &types.PushEvent{}
+	case eventTypePullRequestCreated, eventTypePullRequestUpdated:
+		eventInt = &types.PullRequestEvent{}
+	case eventTypePullRequestComment:
+		eventInt = &types.PullRequestCommentEvent{}
+	default:
+		return nil, fmt.Errorf("azure-devops: event \"%s\" is not supported", eventType)
+	}
+	if err := json.Unmarshal([]byte(payload), eventInt); err != nil {
+		return nil, err
+	}
+	return eventInt, nil
+}
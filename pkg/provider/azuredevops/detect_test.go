@@ -0,0 +1,195 @@
+package azuredevops
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops/types"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+)
+
+func TestProvider_Detect(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantErrString string
+		isAzure       bool
+		processReq    bool
+		event         any
+		wantReason    string
+	}{
+		{
+			name:       "not an azure devops event",
+			event:      map[string]string{"hello": "moto"},
+			isAzure:    false,
+			processReq: false,
+		},
+		{
+			name:       "not from the tfs publisher",
+			event:      types.Event{PublisherID: "rm", EventType: "ms.vss-release.deployment-completed-event"},
+			isAzure:    false,
+			processReq: false,
+		},
+		{
+			name:          "unsupported event",
+			event:         types.Event{PublisherID: publisherID, EventType: "workitem.created"},
+			isAzure:       true,
+			wantErrString: "event \"workitem.created\" is not supported",
+		},
+		{
+			name: "push event",
+			event: types.PushEvent{
+				Event: types.Event{PublisherID: publisherID, EventType: eventTypePush},
+				Resource: types.Push{
+					RefUpdates: []types.RefUpdate{{Name: "refs/heads/main", NewObjectID: "1234"}},
+				},
+			},
+			isAzure:    true,
+			processReq: true,
+		},
+		{
+			name: "push event branch deletion",
+			event: types.PushEvent{
+				Event: types.Event{PublisherID: publisherID, EventType: eventTypePush},
+				Resource: types.Push{
+					RefUpdates: []types.RefUpdate{{Name: "refs/heads/main", NewObjectID: "0000000000000000000000000000000000000000"}},
+				},
+			},
+			isAzure:    true,
+			processReq: false,
+			wantReason: "branch or tag deletion is not supported",
+		},
+		{
+			name: "pull request created",
+			event: types.PullRequestEvent{
+				Event:    types.Event{PublisherID: publisherID, EventType: eventTypePullRequestCreated},
+				Resource: types.PullRequest{Status: prStatusActive},
+			},
+			isAzure:    true,
+			processReq: true,
+		},
+		{
+			name: "pull request source branch updated",
+			event: types.PullRequestEvent{
+				Event: types.Event{
+					PublisherID: publisherID, EventType: eventTypePullRequestUpdated,
+					Message: &types.Message{Text: "Jamal Hartnett updated the source branch of pull request 1"},
+				},
+				Resource: types.PullRequest{Status: prStatusActive},
+			},
+			isAzure:    true,
+			processReq: true,
+		},
+		{
+			name: "pull request vote updated",
+			event: types.PullRequestEvent{
+				Event: types.Event{
+					PublisherID: publisherID, EventType: eventTypePullRequestUpdated,
+					Message: &types.Message{Text: "Jamal Hartnett approved pull request 1"},
+				},
+				Resource: types.PullRequest{Status: prStatusActive},
+			},
+			isAzure:    true,
+			processReq: false,
+			wantReason: "not a pull request update we care about",
+		},
+		{
+			name: "pull request completed",
+			event: types.PullRequestEvent{
+				Event:    types.Event{PublisherID: publisherID, EventType: eventTypePullRequestUpdated},
+				Resource: types.PullRequest{Status: prStatusCompleted},
+			},
+			isAzure:    true,
+			processReq: true,
+		},
+		{
+			name: "retest comment",
+			event: types.PullRequestCommentEvent{
+				Event: types.Event{PublisherID: publisherID, EventType: eventTypePullRequestComment},
+				Resource: types.PullRequestComment{
+					Comment:     types.Comment{Content: "/retest", CommentType: "text"},
+					PullRequest: types.PullRequest{Status: prStatusActive},
+				},
+			},
+			isAzure:    true,
+			processReq: true,
+		},
+		{
+			name: "ok-to-test comment",
+			event: types.PullRequestCommentEvent{
+				Event: types.Event{PublisherID: publisherID, EventType: eventTypePullRequestComment},
+				Resource: types.PullRequestComment{
+					Comment:     types.Comment{Content: "/ok-to-test"},
+					PullRequest: types.PullRequest{Status: prStatusActive},
+				},
+			},
+			isAzure:    true,
+			processReq: true,
+		},
+		{
+			name: "random comment",
+			event: types.PullRequestCommentEvent{
+				Event: types.Event{PublisherID: publisherID, EventType: eventTypePullRequestComment},
+				Resource: types.PullRequestComment{
+					Comment:     types.Comment{Content: "random string, ignore me :)", CommentType: "text"},
+					PullRequest: types.PullRequest{Status: prStatusActive},
+				},
+			},
+			isAzure:    true,
+			processReq: false,
+			wantReason: "comment is not a GitOps command",
+		},
+		{
+			name: "system comment",
+			event: types.PullRequestCommentEvent{
+				Event: types.Event{PublisherID: publisherID, EventType: eventTypePullRequestComment},
+				Resource: types.PullRequestComment{
+					Comment:     types.Comment{Content: "/retest", CommentType: "system"},
+					PullRequest: types.PullRequest{Status: prStatusActive},
+				},
+			},
+			isAzure:    true,
+			processReq: false,
+			wantReason: "comment of type \"system\" is not supported",
+		},
+		{
+			name: "comment on a completed pull request",
+			event: types.PullRequestCommentEvent{
+				Event: types.Event{PublisherID: publisherID, EventType: eventTypePullRequestComment},
+				Resource: types.PullRequestComment{
+					Comment:     types.Comment{Content: "/retest"},
+					PullRequest: types.PullRequest{Status: prStatusCompleted},
+				},
+			},
+			isAzure:    true,
+			processReq: false,
+			wantReason: "comments on closed pull requests is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := Provider{}
+			logger, _ := logger.GetLogger()
+
+			jeez, err := json.Marshal(tt.event)
+			assert.NilError(t, err)
+
+			req := &http.Request{Header: http.Header{}}
+			isAzure, processReq, _, reason, err := v.Detect(req, string(jeez), logger)
+			if tt.wantErrString != "" {
+				assert.ErrorContains(t, err, tt.wantErrString)
+				assert.Equal(t, tt.isAzure, isAzure)
+				return
+			}
+			assert.NilError(t, err)
+			if tt.wantReason != "" {
+				assert.Assert(t, strings.Contains(reason, tt.wantReason), "reason %s doesn't have %s", reason, tt.wantReason)
+			}
+			assert.Equal(t, tt.isAzure, isAzure)
+			assert.Equal(t, tt.processReq, processReq)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops/types"
+)
+
+// ParsePayload parses the service hook payload into an event.
+func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, _ *http.Request, payload string) (*info.Event, error) {
+	envelope := &types.Event{}
+	if err := json.Unmarshal([]byte(payload), envelope); err != nil {
+		return nil, err
+	}
+
+	eventInt, err := parsePayloadType(envelope.EventType, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	processedEvent := info.NewEvent()
+	processedEvent.Event = eventInt
+
+	var repository types.Repository
+	switch gitEvent := eventInt.(type) {
+	case *types.PushEvent:
+		if len(gitEvent.Resource.RefUpdates) == 0 {
+			return nil, fmt.Errorf("push event contains no ref updates; cannot proceed")
+		}
+		refUpdate := gitEvent.Resource.RefUpdates[0]
+		repository = gitEvent.Resource.Repository
+		processedEvent.TriggerTarget = triggertype.Push
+		processedEvent.EventType = triggertype.Push.String()
+		processedEvent.SHA = refUpdate.NewObjectID
+		processedEvent.HeadBranch = refUpdate.Name
+		processedEvent.BaseBranch = refUpdate.Name
+		processedEvent.Sender = gitEvent.Resource.PushedBy.UniqueName
+		processedEvent.AccountID = gitEvent.Resource.PushedBy.ID
+		for _, commit := range gitEvent.Resource.Commits {
+			if commit.CommitID == processedEvent.SHA {
+				processedEvent.SHATitle = strings.Split(commit.Comment, "\n")[0]
+				processedEvent.SHAMessage = commit.Comment
+			}
+		}
+	case *types.PullRequestEvent:
+		repository = gitEvent.Resource.Repository
+		v.fillPullRequest(processedEvent, &gitEvent.Resource)
+		processedEvent.Sender = gitEvent.Resource.CreatedBy.UniqueName
+		processedEvent.AccountID = gitEvent.Resource.CreatedBy.ID
+		processedEvent.TriggerTarget = triggertype.PullRequest
+		processedEvent.EventType = triggertype.PullRequest.String()
+		if gitEvent.Resource.Status == prStatusCompleted || gitEvent.Resource.Status == prStatusAbandoned {
+			processedEvent.TriggerTarget = triggertype.PullRequestClosed
+			processedEvent.PullRequestMerged = gitEvent.Resource.Status == prStatusCompleted
+		}
+	case *types.PullRequestCommentEvent:
+		repository = gitEvent.Resource.PullRequest.Repository
+		v.fillPullRequest(processedEvent, &gitEvent.Resource.PullRequest)
+		processedEvent.Sender = gitEvent.Resource.Comment.Author.UniqueName
+		processedEvent.AccountID = gitEvent.Resource.Comment.Author.ID
+		processedEvent.TriggerTarget = triggertype.PullRequest
+		opscomments.SetEventTypeAndTargetPR(processedEvent, gitEvent.Resource.Comment.Content)
+	default:
+		return nil, fmt.Errorf("event %s is not supported", envelope.EventType)
+	}
+
+	repoURL, err := repositoryWebURL(repository)
+	if err != nil {
+		return nil, err
+	}
+	processedEvent.URL = repoURL
+	processedEvent.BaseURL = repoURL
+	if processedEvent.HeadURL == "" {
+		processedEvent.HeadURL = repoURL
+	}
+	if processedEvent.SHAURL == "" && processedEvent.SHA != "" {
+		processedEvent.SHAURL = fmt.Sprintf("%s/commit/%s", repoURL, processedEvent.SHA)
+	}
+	processedEvent.Organization = repository.Project.Name
+	processedEvent.Repository = repository.Name
+	processedEvent.DefaultBranch = strings.TrimPrefix(repository.DefaultBranch, "refs/heads/")
+
+	v.project = repository.Project.Name
+	v.repoName = repository.Name
+	if envelope.ResourceContainers.Collection != nil && envelope.ResourceContainers.Collection.BaseURL != "" {
+		v.apiURL = strings.TrimSuffix(envelope.ResourceContainers.Collection.BaseURL, "/")
+	}
+	return processedEvent, nil
+}
+
+func (v *Provider) fillPullRequest(processedEvent *info.Event, pr *types.PullRequest) {
+	if pr.LastMergeSourceCommit != nil {
+		processedEvent.SHA = pr.LastMergeSourceCommit.CommitID
+	}
+	processedEvent.HeadBranch = strings.TrimPrefix(pr.SourceRefName, "refs/heads/")
+	processedEvent.BaseBranch = strings.TrimPrefix(pr.TargetRefName, "refs/heads/")
+	processedEvent.PullRequestNumber = pr.PullRequestID
+	processedEvent.PullRequestTitle = pr.Title
+	for _, label := range pr.Labels {
+		processedEvent.PullRequestLabel = append(processedEvent.PullRequestLabel, label.Name)
+	}
+	if pr.ForkSource != nil && pr.ForkSource.Repository != nil {
+		if forkURL, err := repositoryWebURL(*pr.ForkSource.Repository); err == nil {
+			processedEvent.HeadURL = forkURL
+		}
+	}
+	v.pullRequestNumber = pr.PullRequestID
+}
+
+// repositoryWebURL returns the browsable URL of the repository, the
+// remoteUrl may have the user name in it (ie: https://org@dev.azure.com/...)
+// which would not match the Repository CR URL.
+func repositoryWebURL(repository types.Repository) (string, error) {
+	repoURL := repository.WebURL
+	if repoURL == "" {
+		repoURL = repository.RemoteURL
+	}
+	if repoURL == "" {
+		return "", fmt.Errorf("azure devops repository %s has no url in payload", repository.Name)
+	}
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", err
+	}
+	u.User = nil
+	return u.String(), nil
+}
@@ -0,0 +1,236 @@
+package azuredevops
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops/types"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestParsePayload(t *testing.T) {
+	envelope := func(eventType string) types.Event {
+		return types.Event{
+			PublisherID: publisherID,
+			EventType:   eventType,
+			ResourceContainers: types.ResourceContainers{
+				Collection: &types.ResourceContainer{BaseURL: "https://dev.azure.com/org/"},
+			},
+		}
+	}
+	repository := types.Repository{
+		Name:          "repo",
+		Project:       types.Project{Name: "project"},
+		DefaultBranch: "refs/heads/main",
+		RemoteURL:     "https://org@dev.azure.com/org/project/_git/repo",
+	}
+	pullRequest := types.PullRequest{
+		Repository:            repository,
+		PullRequestID:         42,
+		Status:                prStatusActive,
+		CreatedBy:             types.Identity{ID: "abcd", UniqueName: "author@example.com"},
+		Title:                 "My pull request",
+		SourceRefName:         "refs/heads/feature",
+		TargetRefName:         "refs/heads/main",
+		LastMergeSourceCommit: &types.CommitRef{CommitID: "sha"},
+		Labels:                []types.WebAPITagName{{Name: "bug"}},
+	}
+	completedPullRequest := pullRequest
+	completedPullRequest.Status = prStatusCompleted
+
+	tests := []struct {
+		name            string
+		payload         any
+		rawStr          string
+		wantErrSubstr   string
+		wantTrigger     triggertype.Trigger
+		wantEventType   string
+		wantSHA         string
+		wantHeadBranch  string
+		wantBaseBranch  string
+		wantSender      string
+		wantPRNumber    int
+		wantLabels      []string
+		wantTargetPRRun string
+		wantSHAMessage  string
+		wantHeadURL     string
+		wantMerged      bool
+	}{
+		{
+			name:          "bad/bad json",
+			rawStr:        "rageAgainst",
+			wantErrSubstr: "invalid character",
+		},
+		{
+			name:          "bad/unsupported event",
+			payload:       envelope("workitem.created"),
+			wantErrSubstr: "event \"workitem.created\" is not supported",
+		},
+		{
+			name: "bad/push without ref updates",
+			payload: types.PushEvent{
+				Event:    envelope(eventTypePush),
+				Resource: types.Push{Repository: repository},
+			},
+			wantErrSubstr: "push event contains no ref updates",
+		},
+		{
+			name: "good/push",
+			payload: types.PushEvent{
+				Event: envelope(eventTypePush),
+				Resource: types.Push{
+					Repository: repository,
+					RefUpdates: []types.RefUpdate{{Name: "refs/heads/main", NewObjectID: "sha"}},
+					Commits:    []types.Commit{{CommitID: "sha", Comment: "title\n\nbody"}},
+					PushedBy:   types.Identity{ID: "abcd", UniqueName: "pusher@example.com"},
+				},
+			},
+			wantTrigger:    triggertype.Push,
+			wantEventType:  triggertype.Push.String(),
+			wantSHA:        "sha",
+			wantHeadBranch: "refs/heads/main",
+			wantBaseBranch: "refs/heads/main",
+			wantSender:     "pusher@example.com",
+			wantSHAMessage: "title\n\nbody",
+		},
+		{
+			name: "good/pull request",
+			payload: types.PullRequestEvent{
+				Event:    envelope(eventTypePullRequestCreated),
+				Resource: pullRequest,
+			},
+			wantTrigger:    triggertype.PullRequest,
+			wantEventType:  triggertype.PullRequest.String(),
+			wantSHA:        "sha",
+			wantHeadBranch: "feature",
+			wantBaseBranch: "main",
+			wantSender:     "author@example.com",
+			wantPRNumber:   42,
+			wantLabels:     []string{"bug"},
+		},
+		{
+			name: "good/pull request completed",
+			payload: types.PullRequestEvent{
+				Event:    envelope(eventTypePullRequestUpdated),
+				Resource: completedPullRequest,
+			},
+			wantMerged:     true,
+			wantTrigger:    triggertype.PullRequestClosed,
+			wantEventType:  triggertype.PullRequest.String(),
+			wantSHA:        "sha",
+			wantHeadBranch: "feature",
+			wantBaseBranch: "main",
+			wantSender:     "author@example.com",
+			wantPRNumber:   42,
+			wantLabels:     []string{"bug"},
+		},
+		{
+			name: "good/pull request from a fork",
+			payload: types.PullRequestEvent{
+				Event: envelope(eventTypePullRequestCreated),
+				Resource: func() types.PullRequest {
+					pr := pullRequest
+					pr.ForkSource = &types.ForkSource{
+						Repository: &types.Repository{Name: "fork", WebURL: "https://dev.azure.com/org/project/_git/fork"},
+					}
+					return pr
+				}(),
+			},
+			wantTrigger:    triggertype.PullRequest,
+			wantEventType:  triggertype.PullRequest.String(),
+			wantSHA:        "sha",
+			wantHeadBranch: "feature",
+			wantBaseBranch: "main",
+			wantSender:     "author@example.com",
+			wantPRNumber:   42,
+			wantLabels:     []string{"bug"},
+			wantHeadURL:    "https://dev.azure.com/org/project/_git/fork",
+		},
+		{
+			name: "good/comment retest",
+			payload: types.PullRequestCommentEvent{
+				Event: envelope(eventTypePullRequestComment),
+				Resource: types.PullRequestComment{
+					Comment:     types.Comment{Content: "/retest", Author: types.Identity{UniqueName: "commenter@example.com"}},
+					PullRequest: pullRequest,
+				},
+			},
+			wantTrigger:    triggertype.PullRequest,
+			wantEventType:  opscomments.RetestAllCommentEventType.String(),
+			wantSHA:        "sha",
+			wantHeadBranch: "feature",
+			wantBaseBranch: "main",
+			wantSender:     "commenter@example.com",
+			wantPRNumber:   42,
+			wantLabels:     []string{"bug"},
+		},
+		{
+			name: "good/comment test a pipelinerun",
+			payload: types.PullRequestCommentEvent{
+				Event: envelope(eventTypePullRequestComment),
+				Resource: types.PullRequestComment{
+					Comment:     types.Comment{Content: "/test dummy", Author: types.Identity{UniqueName: "commenter@example.com"}},
+					PullRequest: pullRequest,
+				},
+			},
+			wantTrigger:     triggertype.PullRequest,
+			wantEventType:   opscomments.TestSingleCommentEventType.String(),
+			wantSHA:         "sha",
+			wantHeadBranch:  "feature",
+			wantBaseBranch:  "main",
+			wantSender:      "commenter@example.com",
+			wantPRNumber:    42,
+			wantLabels:      []string{"bug"},
+			wantTargetPRRun: "dummy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			payload := tt.rawStr
+			if payload == "" {
+				b, err := json.Marshal(tt.payload)
+				assert.NilError(t, err)
+				payload = string(b)
+			}
+
+			v := &Provider{}
+			got, err := v.ParsePayload(ctx, &params.Run{}, nil, payload)
+			if tt.wantErrSubstr != "" {
+				assert.ErrorContains(t, err, tt.wantErrSubstr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got.TriggerTarget, tt.wantTrigger)
+			assert.Equal(t, got.PullRequestMerged, tt.wantMerged)
+			assert.Equal(t, got.EventType, tt.wantEventType)
+			assert.Equal(t, got.SHA, tt.wantSHA)
+			assert.Equal(t, got.HeadBranch, tt.wantHeadBranch)
+			assert.Equal(t, got.BaseBranch, tt.wantBaseBranch)
+			assert.Equal(t, got.Sender, tt.wantSender)
+			assert.Equal(t, got.PullRequestNumber, tt.wantPRNumber)
+			assert.DeepEqual(t, got.PullRequestLabel, tt.wantLabels)
+			assert.Equal(t, got.TargetTestPipelineRun, tt.wantTargetPRRun)
+			assert.Equal(t, got.SHAMessage, tt.wantSHAMessage)
+			assert.Equal(t, got.URL, "https://dev.azure.com/org/project/_git/repo")
+			assert.Equal(t, got.SHAURL, "https://dev.azure.com/org/project/_git/repo/commit/"+tt.wantSHA)
+			assert.Equal(t, got.Organization, "project")
+			assert.Equal(t, got.Repository, "repo")
+			assert.Equal(t, got.DefaultBranch, "main")
+			if tt.wantHeadURL != "" {
+				assert.Equal(t, got.HeadURL, tt.wantHeadURL)
+			} else {
+				assert.Equal(t, got.HeadURL, got.URL)
+			}
+
+			assert.Equal(t, v.apiURL, "https://dev.azure.com/org")
+			assert.Equal(t, v.project, "project")
+			assert.Equal(t, v.repoName, "repo")
+			assert.Equal(t, v.pullRequestNumber, tt.wantPRNumber)
+		})
+	}
+}
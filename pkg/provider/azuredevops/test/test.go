@@ -0,0 +1,288 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops/types"
+	"gotest.tools/v3/assert"
+)
+
+var defaultCollection = "/organization"
+
+// Setup starts a http server standing in for Azure DevOps, it returns the
+// client, the mux to register the API handlers on, the collection URL and a
+// function to tear it down.
+func Setup(t *testing.T) (*http.Client, *http.ServeMux, string, func()) {
+	t.Helper()
+	mux := http.NewServeMux()
+	apiHandler := http.NewServeMux()
+	apiHandler.Handle(defaultCollection+"/", http.StripPrefix(defaultCollection, mux))
+	apiHandler.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintln(os.Stderr, "FAIL: Client.BaseURL path prefix is not preserved in the request URL:")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "\t"+req.URL.String())
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "\tDid you accidentally use an absolute endpoint URL rather than relative?")
+		http.Error(w, "Client.BaseURL path prefix is not preserved in the request URL.", http.StatusInternalServerError)
+	})
+
+	// server is a test HTTP server used to provide mock API responses.
+	server := httptest.NewServer(apiHandler)
+	tearDown := func() {
+		server.Close()
+	}
+	return server.Client(), mux, server.URL + defaultCollection, tearDown
+}
+
+func repoPath(event *info.Event, path string) string {
+	return fmt.Sprintf("/%s/_apis/git/repositories/%s%s", event.Organization, event.Repository, path)
+}
+
+// MuxCreateCommitStatus checks the commit status we are setting has the expected state.
+func MuxCreateCommitStatus(t *testing.T, mux *http.ServeMux, event *info.Event, expectedState, expectedDescription string) {
+	t.Helper()
+	mux.HandleFunc(repoPath(event, fmt.Sprintf("/commits/%s/statuses", event.SHA)), func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		status := &types.GitStatus{}
+		bit, _ := io.ReadAll(r.Body)
+		assert.NilError(t, json.Unmarshal(bit, status))
+		assert.Equal(t, status.State, expectedState)
+		if expectedDescription != "" {
+			assert.Equal(t, status.Description, expectedDescription)
+		}
+		fmt.Fprint(rw, "{}")
+	})
+}
+
+// MuxCreatePullRequestStatus checks the pull request status we are setting has the expected state.
+func MuxCreatePullRequestStatus(t *testing.T, mux *http.ServeMux, event *info.Event, expectedState string, called *bool) {
+	t.Helper()
+	mux.HandleFunc(repoPath(event, fmt.Sprintf("/pullRequests/%d/statuses", event.PullRequestNumber)), func(rw http.ResponseWriter, r *http.Request) {
+		status := &types.GitStatus{}
+		bit, _ := io.ReadAll(r.Body)
+		assert.NilError(t, json.Unmarshal(bit, status))
+		assert.Equal(t, status.State, expectedState)
+		if called != nil {
+			*called = true
+		}
+		fmt.Fprint(rw, "{}")
+	})
+}
+
+// MuxThreads serves the existing comment threads of a pull request and checks
+// that the new comment contains expectedCommentSubstr.
+func MuxThreads(t *testing.T, mux *http.ServeMux, event *info.Event, threads []types.CommentThread, expectedCommentSubstr string, created *bool) {
+	t.Helper()
+	mux.HandleFunc(repoPath(event, fmt.Sprintf("/pullRequests/%d/threads", event.PullRequestNumber)), func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			b, err := json.Marshal(types.CommentThreadList{Count: len(threads), Value: threads})
+			assert.NilError(t, err)
+			fmt.Fprint(rw, string(b))
+			return
+		}
+		thread := &types.CommentThread{}
+		bit, _ := io.ReadAll(r.Body)
+		assert.NilError(t, json.Unmarshal(bit, thread))
+		assert.Assert(t, len(thread.Comments) == 1)
+		if expectedCommentSubstr != "" {
+			assert.Assert(t, strings.Contains(thread.Comments[0].Content, expectedCommentSubstr), "comment: %s doesn't have: %s",
+				thread.Comments[0].Content, expectedCommentSubstr)
+		}
+		if created != nil {
+			*created = true
+		}
+		fmt.Fprint(rw, "{}")
+	})
+}
+
+// MuxUpdateComment checks the updated comment contains expectedCommentSubstr.
+func MuxUpdateComment(t *testing.T, mux *http.ServeMux, event *info.Event, threadID, commentID int, expectedCommentSubstr string, updated *bool) {
+	t.Helper()
+	path := repoPath(event, fmt.Sprintf("/pullRequests/%d/threads/%d/comments/%d", event.PullRequestNumber, threadID, commentID))
+	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPatch)
+		comment := &types.Comment{}
+		bit, _ := io.ReadAll(r.Body)
+		assert.NilError(t, json.Unmarshal(bit, comment))
+		assert.Assert(t, strings.Contains(comment.Content, expectedCommentSubstr))
+		if updated != nil {
+			*updated = true
+		}
+		fmt.Fprint(rw, "{}")
+	})
+}
+
+// MuxItems serves the items API, a request with a scopePath lists the
+// directory and a request with a path returns the file content from files.
+func MuxItems(t *testing.T, mux *http.ServeMux, event *info.Event, dirName string, files map[string]string, wantDirErr, wantFilesErr bool) {
+	t.Helper()
+	mux.HandleFunc(repoPath(event, "/items"), func(rw http.ResponseWriter, r *http.Request) {
+		if scopePath := r.URL.Query().Get("scopePath"); scopePath != "" {
+			if wantDirErr {
+				rw.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(rw, `{"message": "access denied"}`)
+				return
+			}
+			if strings.TrimPrefix(scopePath, "/") != dirName {
+				rw.WriteHeader(http.StatusNotFound)
+				fmt.Fprintf(rw, `{"message": "TF401174: The item '%s' could not be found"}`, scopePath)
+				return
+			}
+			items := []types.Item{{Path: "/" + dirName, IsFolder: true}}
+			for _, name := range slices.Sorted(maps.Keys(files)) {
+				items = append(items, types.Item{Path: "/" + name, GitObjectType: "blob"})
+			}
+			b, err := json.Marshal(types.ItemList{Count: len(items), Value: items})
+			assert.NilError(t, err)
+			fmt.Fprint(rw, string(b))
+			return
+		}
+
+		path := strings.TrimPrefix(r.URL.Query().Get("path"), "/")
+		content, ok := files[path]
+		if wantFilesErr || !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(rw, `{"message": "TF401174: The item '%s' could not be found"}`, path)
+			return
+		}
+		b, err := json.Marshal(types.Item{Path: "/" + path, Content: content})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
+
+// MuxDirContent serves the yaml files of a local directory as the content of dirName.
+func MuxDirContent(t *testing.T, mux *http.ServeMux, event *info.Event, testDir, dirName string, wantDirErr, wantFilesErr bool) {
+	t.Helper()
+	files, err := os.ReadDir(testDir)
+	if err != nil {
+		// no error just disappointed
+		return
+	}
+	filecontents := map[string]string{}
+	for _, value := range files {
+		if value.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(testDir, value.Name()))
+		assert.NilError(t, err)
+		filecontents[filepath.Join(dirName, value.Name())] = string(content)
+	}
+	MuxItems(t, mux, event, dirName, filecontents, wantDirErr, wantFilesErr)
+}
+
+// MuxCommit serves the commit information.
+func MuxCommit(t *testing.T, mux *http.ServeMux, event *info.Event, commit types.Commit) {
+	t.Helper()
+	mux.HandleFunc(repoPath(event, "/commits/"+commit.CommitID), func(rw http.ResponseWriter, _ *http.Request) {
+		b, err := json.Marshal(commit)
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
+
+// MuxRepository serves the repository information.
+func MuxRepository(t *testing.T, mux *http.ServeMux, event *info.Event, defaultBranch string) {
+	t.Helper()
+	mux.HandleFunc(repoPath(event, ""), func(rw http.ResponseWriter, _ *http.Request) {
+		b, err := json.Marshal(types.Repository{Name: event.Repository, DefaultBranch: defaultBranch})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
+
+// MuxPullRequestChanges serves the changes of the last iteration of a pull request.
+func MuxPullRequestChanges(t *testing.T, mux *http.ServeMux, event *info.Event, changes []types.Change) {
+	t.Helper()
+	iterationsPath := repoPath(event, fmt.Sprintf("/pullRequests/%d/iterations", event.PullRequestNumber))
+	mux.HandleFunc(iterationsPath, func(rw http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(rw, `{"count": 2, "value": [{"id": 1}, {"id": 2}]}`)
+	})
+	mux.HandleFunc(iterationsPath+"/2/changes", func(rw http.ResponseWriter, _ *http.Request) {
+		b, err := json.Marshal(types.IterationChanges{ChangeEntries: changes})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
+
+// MuxCommitChanges serves the changes of a commit.
+func MuxCommitChanges(t *testing.T, mux *http.ServeMux, event *info.Event, changes []types.Change) {
+	t.Helper()
+	mux.HandleFunc(repoPath(event, fmt.Sprintf("/commits/%s/changes", event.SHA)), func(rw http.ResponseWriter, _ *http.Request) {
+		b, err := json.Marshal(types.CommitChanges{Changes: changes})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
+
+// MuxProjectTeam serves the project with its default team and the members of that team.
+func MuxProjectTeam(t *testing.T, mux *http.ServeMux, project, team string, members ...string) {
+	t.Helper()
+	mux.HandleFunc(fmt.Sprintf("/_apis/projects/%s", project), func(rw http.ResponseWriter, _ *http.Request) {
+		b, err := json.Marshal(types.Project{Name: project, DefaultTeam: &types.TeamRef{Name: team}})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+	MuxTeamMembers(t, mux, project, team, members...)
+}
+
+// MuxTeamMembers serves the members of a team.
+func MuxTeamMembers(t *testing.T, mux *http.ServeMux, project, team string, members ...string) {
+	t.Helper()
+	mux.HandleFunc(fmt.Sprintf("/_apis/projects/%s/teams/%s/members", project, team), func(rw http.ResponseWriter, _ *http.Request) {
+		list := types.TeamMemberList{Count: len(members)}
+		for _, member := range members {
+			list.Value = append(list.Value, types.TeamMember{Identity: types.Identity{UniqueName: member}})
+		}
+		b, err := json.Marshal(list)
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
+
+// MakeEvent fills the event with some default values.
+func MakeEvent(event *info.Event) *info.Event {
+	if event == nil {
+		event = info.NewEvent()
+	}
+	rev := event
+	if event.Provider == nil {
+		rev.Provider = &info.Provider{}
+	}
+	if rev.HeadBranch == "" {
+		rev.HeadBranch = "pr"
+	}
+	if rev.BaseBranch == "" {
+		rev.BaseBranch = "main"
+	}
+	if rev.SHA == "" {
+		rev.SHA = "1234"
+	}
+	if rev.Organization == "" {
+		rev.Organization = "project"
+	}
+	if rev.Repository == "" {
+		rev.Repository = "repo"
+	}
+	if rev.DefaultBranch == "" {
+		rev.DefaultBranch = "main"
+	}
+	if rev.Sender == "" {
+		rev.Sender = "sender@example.com"
+	}
+	if rev.URL == "" {
+		rev.URL = fmt.Sprintf("https://dev.azure.com/organization/%s/_git/%s", rev.Organization, rev.Repository)
+	}
+	return rev
+}
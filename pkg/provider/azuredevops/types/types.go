@@ -0,0 +1,244 @@
+//revive:disable-next-line:var-naming
+package types
+
+// Event is the envelope of an Azure DevOps service hook notification, the
+// Resource is decoded depending of the EventType.
+type Event struct {
+	SubscriptionID     string             `json:"subscriptionId"`
+	NotificationID     int                `json:"notificationId"`
+	ID                 string             `json:"id"`
+	EventType          string             `json:"eventType"`
+	PublisherID        string             `json:"publisherId"`
+	Message            *Message           `json:"message,omitempty"`
+	DetailedMessage    *Message           `json:"detailedMessage,omitempty"`
+	ResourceVersion    string             `json:"resourceVersion,omitempty"`
+	ResourceContainers ResourceContainers `json:"resourceContainers"`
+	CreatedDate        string             `json:"createdDate,omitempty"`
+}
+
+type Message struct {
+	Text     string `json:"text"`
+	HTML     string `json:"html,omitempty"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+type ResourceContainers struct {
+	Collection *ResourceContainer `json:"collection,omitempty"`
+	Account    *ResourceContainer `json:"account,omitempty"`
+	Project    *ResourceContainer `json:"project,omitempty"`
+}
+
+type ResourceContainer struct {
+	ID      string `json:"id"`
+	BaseURL string `json:"baseUrl,omitempty"`
+}
+
+// PushEvent is the git.push service hook event.
+type PushEvent struct {
+	Event
+	Resource Push `json:"resource"`
+}
+
+// PullRequestEvent is the git.pullrequest.created and git.pullrequest.updated
+// service hook events.
+type PullRequestEvent struct {
+	Event
+	Resource PullRequest `json:"resource"`
+}
+
+// PullRequestCommentEvent is the ms.vss-code.git-pullrequest-comment-event
+// service hook event.
+type PullRequestCommentEvent struct {
+	Event
+	Resource PullRequestComment `json:"resource"`
+}
+
+type Push struct {
+	Commits    []Commit    `json:"commits"`
+	RefUpdates []RefUpdate `json:"refUpdates"`
+	Repository Repository  `json:"repository"`
+	PushedBy   Identity    `json:"pushedBy"`
+	PushID     int         `json:"pushId"`
+	Date       string      `json:"date,omitempty"`
+	URL        string      `json:"url,omitempty"`
+}
+
+type RefUpdate struct {
+	Name        string `json:"name"`
+	OldObjectID string `json:"oldObjectId"`
+	NewObjectID string `json:"newObjectId"`
+}
+
+type PullRequest struct {
+	Repository            Repository      `json:"repository"`
+	PullRequestID         int             `json:"pullRequestId"`
+	Status                string          `json:"status"`
+	CreatedBy             Identity        `json:"createdBy"`
+	CreationDate          string          `json:"creationDate,omitempty"`
+	Title                 string          `json:"title"`
+	Description           string          `json:"description,omitempty"`
+	SourceRefName         string          `json:"sourceRefName"`
+	TargetRefName         string          `json:"targetRefName"`
+	MergeStatus           string          `json:"mergeStatus,omitempty"`
+	IsDraft               bool            `json:"isDraft,omitempty"`
+	LastMergeSourceCommit *CommitRef      `json:"lastMergeSourceCommit,omitempty"`
+	LastMergeTargetCommit *CommitRef      `json:"lastMergeTargetCommit,omitempty"`
+	LastMergeCommit       *CommitRef      `json:"lastMergeCommit,omitempty"`
+	Labels                []WebAPITagName `json:"labels,omitempty"`
+	ForkSource            *ForkSource     `json:"forkSource,omitempty"`
+	URL                   string          `json:"url,omitempty"`
+}
+
+type PullRequestComment struct {
+	Comment     Comment     `json:"comment"`
+	PullRequest PullRequest `json:"pullRequest"`
+}
+
+type ForkSource struct {
+	Name       string      `json:"name"`
+	Repository *Repository `json:"repository,omitempty"`
+}
+
+type WebAPITagName struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name"`
+	Active bool   `json:"active,omitempty"`
+}
+
+type Repository struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	URL           string  `json:"url,omitempty"`
+	Project       Project `json:"project"`
+	DefaultBranch string  `json:"defaultBranch,omitempty"`
+	RemoteURL     string  `json:"remoteUrl,omitempty"`
+	WebURL        string  `json:"webUrl,omitempty"`
+}
+
+type Project struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	URL         string   `json:"url,omitempty"`
+	State       string   `json:"state,omitempty"`
+	DefaultTeam *TeamRef `json:"defaultTeam,omitempty"`
+}
+
+type TeamRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type Identity struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+	URL         string `json:"url,omitempty"`
+	ImageURL    string `json:"imageUrl,omitempty"`
+}
+
+type CommitRef struct {
+	CommitID string `json:"commitId"`
+	URL      string `json:"url,omitempty"`
+}
+
+type GitUserDate struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Date  string `json:"date,omitempty"`
+}
+
+type Commit struct {
+	CommitID  string       `json:"commitId"`
+	Author    *GitUserDate `json:"author,omitempty"`
+	Committer *GitUserDate `json:"committer,omitempty"`
+	Comment   string       `json:"comment"`
+	URL       string       `json:"url,omitempty"`
+	RemoteURL string       `json:"remoteUrl,omitempty"`
+}
+
+type CommitList struct {
+	Count int      `json:"count"`
+	Value []Commit `json:"value"`
+}
+
+type Comment struct {
+	ID              int      `json:"id,omitempty"`
+	ParentCommentID int      `json:"parentCommentId,omitempty"`
+	Author          Identity `json:"author"`
+	Content         string   `json:"content"`
+	CommentType     string   `json:"commentType,omitempty"`
+}
+
+type CommentThread struct {
+	ID       int       `json:"id,omitempty"`
+	Comments []Comment `json:"comments"`
+	Status   string    `json:"status,omitempty"`
+}
+
+type CommentThreadList struct {
+	Count int             `json:"count"`
+	Value []CommentThread `json:"value"`
+}
+
+// GitStatus is used for both the commit status and the pull request status API.
+type GitStatus struct {
+	State       string           `json:"state"`
+	Description string           `json:"description,omitempty"`
+	TargetURL   string           `json:"targetUrl,omitempty"`
+	Context     GitStatusContext `json:"context"`
+}
+
+type GitStatusContext struct {
+	Name  string `json:"name"`
+	Genre string `json:"genre,omitempty"`
+}
+
+type Item struct {
+	ObjectID      string `json:"objectId,omitempty"`
+	GitObjectType string `json:"gitObjectType,omitempty"`
+	CommitID      string `json:"commitId,omitempty"`
+	Path          string `json:"path"`
+	IsFolder      bool   `json:"isFolder,omitempty"`
+	Content       string `json:"content,omitempty"`
+	URL           string `json:"url,omitempty"`
+}
+
+type ItemList struct {
+	Count int    `json:"count"`
+	Value []Item `json:"value"`
+}
+
+type Change struct {
+	ChangeType string `json:"changeType"`
+	Item       Item   `json:"item"`
+}
+
+type CommitChanges struct {
+	Changes []Change `json:"changes"`
+}
+
+type Iteration struct {
+	ID int `json:"id"`
+}
+
+type IterationList struct {
+	Count int         `json:"count"`
+	Value []Iteration `json:"value"`
+}
+
+type IterationChanges struct {
+	ChangeEntries []Change `json:"changeEntries"`
+	NextSkip      int      `json:"nextSkip,omitempty"`
+	NextTop       int      `json:"nextTop,omitempty"`
+}
+
+type TeamMember struct {
+	Identity    Identity `json:"identity"`
+	IsTeamAdmin bool     `json:"isTeamAdmin,omitempty"`
+}
+
+type TeamMemberList struct {
+	Count int          `json:"count"`
+	Value []TeamMember `json:"value"`
+}
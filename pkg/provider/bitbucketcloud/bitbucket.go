@@ -1,8 +1,13 @@
 package bitbucketcloud
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -49,6 +54,38 @@ func (v *Provider) CreateComment(_ context.Context, _ *info.Event, _, _ string)
 	return nil
 }
 
+// CreateCommitComment posts a comment on the commit of the event, the
+// bitbucket client doesn't expose the commit comments API.
+func (v *Provider) CreateCommitComment(ctx context.Context, event *info.Event, comment string) error {
+	if v.bbClient == nil {
+		return fmt.Errorf("no token has been set, cannot create commit comment")
+	}
+	body, err := json.Marshal(map[string]any{"content": types.Content{Raw: comment}})
+	if err != nil {
+		return err
+	}
+	commentURL := fmt.Sprintf("%s/repositories/%s/%s/commit/%s/comments", v.Client().GetApiBaseURL(),
+		url.PathEscape(event.Organization), url.PathEscape(event.Repository), url.PathEscape(event.SHA))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, commentURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if v.Username != nil && v.Token != nil {
+		req.SetBasicAuth(*v.Username, *v.Token)
+	}
+	resp, err := v.bbClient.HttpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot create commit comment on %s: %w", event.SHA, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("cannot create commit comment on %s: %s: %s", event.SHA, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
 // CheckPolicyAllowing TODO: Implement ME.
 func (v *Provider) CheckPolicyAllowing(_ context.Context, _ *info.Event, _ []string) (bool, string) {
 	return false, ""
@@ -82,7 +119,9 @@ func (v *Provider) GetConfig() *info.ProviderConfig {
 	}
 }
 
-func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusopts provider.StatusOpts) error {
+// setCommitStatusState sets the state of the commit status of statusopts in
+// its conclusion, and the title matching the state.
+func setCommitStatusState(statusopts *provider.StatusOpts) {
 	switch statusopts.Conclusion {
 	case "skipped":
 		statusopts.Conclusion = "STOPPED"
@@ -103,6 +142,36 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusopts
 		statusopts.Conclusion = "SUCCESSFUL"
 		statusopts.Title = "✅ Completed"
 	}
+}
+
+// UpdateStatusDetails replaces the description of the commit status of the
+// PipelineRun, Bitbucket Cloud has no check runs to add text to.
+func (v *Provider) UpdateStatusDetails(_ context.Context, event *info.Event, statusopts provider.StatusOpts, _, description string) error {
+	if v.bbClient == nil {
+		return fmt.Errorf("no token has been set, cannot set status")
+	}
+	setCommitStatusState(&statusopts)
+	detailsURL := event.Provider.URL
+	if statusopts.DetailsURL != "" {
+		detailsURL = statusopts.DetailsURL
+	}
+	cso := &bitbucket.CommitStatusOptions{
+		Key:         provider.GetCheckName(statusopts, v.pacInfo),
+		Url:         detailsURL,
+		State:       statusopts.Conclusion,
+		Description: description,
+	}
+	cmo := &bitbucket.CommitsOptions{
+		Owner:    event.Organization,
+		RepoSlug: event.Repository,
+		Revision: event.SHA,
+	}
+	_, err := v.Client().Repositories.Commits.CreateCommitStatus(cmo, cso)
+	return err
+}
+
+func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusopts provider.StatusOpts) error {
+	setCommitStatusState(&statusopts)
 	detailsURL := event.Provider.URL
 	if statusopts.DetailsURL != "" {
 		detailsURL = statusopts.DetailsURL
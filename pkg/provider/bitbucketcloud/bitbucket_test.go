@@ -2,6 +2,7 @@ package bitbucketcloud
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
@@ -393,3 +394,49 @@ func TestCreateStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateCommitComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		noToken bool
+		wantErr string
+	}{
+		{
+			name:   "comment created",
+			status: http.StatusCreated,
+		},
+		{
+			name:    "comment refused",
+			status:  http.StatusForbidden,
+			wantErr: "cannot create commit comment on sha: 403 Forbidden: {}",
+		},
+		{
+			name:    "no token",
+			noToken: true,
+			wantErr: "no token has been set, cannot create commit comment",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			bbclient, mux, tearDown := bbcloudtest.SetupBBCloudClient(t)
+			defer tearDown()
+
+			v := &Provider{bbClient: bbclient, run: params.New()}
+			if tt.noToken {
+				v.bbClient = nil
+			}
+			event := bbcloudtest.MakeEvent(nil)
+			event.SHA = "sha"
+			bbcloudtest.MuxCreateCommitComment(t, mux, event, "analysis", tt.status)
+
+			err := v.CreateCommitComment(ctx, event, "analysis")
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
@@ -10,8 +10,11 @@ import (
 	"go.uber.org/zap"
 )
 
+// pullRequestFulfilled is the event sent when a pull request is merged.
+const pullRequestFulfilled = "pullrequest:fulfilled"
+
 var (
-	pullRequestsClosed         = []string{"pullrequest:closed", "pullrequest:fulfilled", "pullrequest:rejected"}
+	pullRequestsClosed         = []string{"pullrequest:closed", pullRequestFulfilled, "pullrequest:rejected"}
 	pullRequestsCreated        = []string{"pullrequest:created", "pullrequest:updated"}
 	pullRequestsCommentCreated = []string{"pullrequest:comment_created"}
 	pushRepo                   = []string{"repo:push"}
@@ -134,6 +134,7 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 		case provider.Valid(event, pullRequestsClosed):
 			processedEvent.EventType = string(triggertype.PullRequestClosed)
 			processedEvent.TriggerTarget = triggertype.PullRequestClosed
+			processedEvent.PullRequestMerged = event == pullRequestFulfilled
 		}
 		processedEvent.Organization = e.Repository.Workspace.Slug
 		processedEvent.Repository = strings.Split(e.Repository.FullName, "/")[1]
@@ -147,6 +148,7 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 		processedEvent.Sender = e.PullRequest.Author.Nickname
 		processedEvent.PullRequestNumber = e.PullRequest.ID
 		processedEvent.PullRequestTitle = e.PullRequest.Title
+		processedEvent.IsDraft = e.PullRequest.Draft
 	case *types.PushRequestEvent:
 		processedEvent.Event = "push"
 		processedEvent.TriggerTarget = "push"
@@ -225,6 +225,21 @@ func MuxCreateComment(t *testing.T, mux *http.ServeMux, event *info.Event, expec
 	})
 }
 
+func MuxCreateCommitComment(t *testing.T, mux *http.ServeMux, event *info.Event, expectedComment string, status int) {
+	t.Helper()
+
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/comments", event.Organization, event.Repository, event.SHA)
+	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		comment := &types.Comment{}
+		bit, _ := io.ReadAll(r.Body)
+		assert.NilError(t, json.Unmarshal(bit, comment))
+		assert.Equal(t, comment.Content.Raw, expectedComment)
+		rw.WriteHeader(status)
+		fmt.Fprintf(rw, "{}")
+	})
+}
+
 func MuxDirContent(t *testing.T, mux *http.ServeMux, event *info.Event, testdir, provenance string) {
 	t.Helper()
 	files, err := os.ReadDir(testdir)
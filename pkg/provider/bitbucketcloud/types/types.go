@@ -57,6 +57,7 @@ type PullRequest struct {
 	Links       Links
 	Title       string `json:"title"`
 	State       string `json:"state"`
+	Draft       bool   `json:"draft"`
 }
 
 type PullRequestEvent struct {
@@ -1,8 +1,11 @@
 package bitbucketdatacenter
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path/filepath"
@@ -59,6 +62,38 @@ func (v *Provider) CreateComment(_ context.Context, _ *info.Event, _, _ string)
 	return nil
 }
 
+// CreateCommitComment posts a comment on the commit of the event, go-scm
+// doesn't expose the commit comments API.
+func (v *Provider) CreateCommitComment(ctx context.Context, event *info.Event, comment string) error {
+	if v.client == nil {
+		return fmt.Errorf("no token has been set, cannot create commit comment")
+	}
+	body, err := json.Marshal(map[string]string{"text": comment})
+	if err != nil {
+		return err
+	}
+	req := &scm.Request{
+		Method: http.MethodPost,
+		Path: fmt.Sprintf("rest/api/1.0/projects/%s/repos/%s/commits/%s/comments",
+			url.PathEscape(event.Organization), url.PathEscape(event.Repository), url.PathEscape(event.SHA)),
+		Header: http.Header{
+			"Content-Type":      {"application/json"},
+			"X-Atlassian-Token": {"no-check"},
+		},
+		Body: bytes.NewReader(body),
+	}
+	resp, err := v.Client().Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("cannot create commit comment on %s: %w", event.SHA, err)
+	}
+	defer resp.Body.Close()
+	if resp.Status >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("cannot create commit comment on %s: %d: %s", event.SHA, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
 func (v *Provider) SetPacInfo(pacInfo *info.PacOpts) {
 	v.pacInfo = pacInfo
 }
@@ -89,8 +124,9 @@ func sanitizeTitle(s string) string {
 	return strings.Split(s, "\n")[0]
 }
 
-func (v *Provider) CreateStatus(ctx context.Context, event *info.Event, statusOpts provider.StatusOpts) error {
-	detailsURL := event.Provider.URL
+// setCommitStatusState sets the state of the build status of statusOpts in
+// its conclusion, and the title matching the state.
+func setCommitStatusState(statusOpts *provider.StatusOpts) {
 	switch statusOpts.Conclusion {
 	case "skipped":
 		statusOpts.Conclusion = "FAILED"
@@ -115,13 +151,10 @@ func (v *Provider) CreateStatus(ctx context.Context, event *info.Event, statusOp
 		statusOpts.Conclusion = "SUCCESSFUL"
 		statusOpts.Title = "Completed"
 	}
-	if statusOpts.DetailsURL != "" {
-		detailsURL = statusOpts.DetailsURL
-	}
-	if v.client == nil {
-		return fmt.Errorf("no token has been set, cannot set status")
-	}
+}
 
+// statusKey returns the key of the build status of the PipelineRun.
+func (v *Provider) statusKey(statusOpts provider.StatusOpts) string {
 	key := statusOpts.PipelineRunName
 	if key == "" {
 		key = statusOpts.Title
@@ -130,11 +163,44 @@ func (v *Provider) CreateStatus(ctx context.Context, event *info.Event, statusOp
 	if v.pacInfo.ApplicationName != "" {
 		key = fmt.Sprintf("%s / %s", v.pacInfo.ApplicationName, key)
 	}
+	return key
+}
+
+// UpdateStatusDetails replaces the description of the build status of the
+// PipelineRun, Bitbucket Data Center has no check runs to add text to.
+func (v *Provider) UpdateStatusDetails(ctx context.Context, event *info.Event, statusOpts provider.StatusOpts, _, description string) error {
+	if v.client == nil {
+		return fmt.Errorf("no token has been set, cannot set status")
+	}
+	setCommitStatusState(&statusOpts)
+	detailsURL := event.Provider.URL
+	if statusOpts.DetailsURL != "" {
+		detailsURL = statusOpts.DetailsURL
+	}
+	opts := &scm.StatusInput{
+		State: convertState(statusOpts.Conclusion),
+		Label: v.statusKey(statusOpts),
+		Desc:  description,
+		Link:  detailsURL,
+	}
+	_, _, err := v.Client().Repositories.CreateStatus(ctx, fmt.Sprintf("%s/%s", event.Organization, event.Repository), event.SHA, opts)
+	return err
+}
+
+func (v *Provider) CreateStatus(ctx context.Context, event *info.Event, statusOpts provider.StatusOpts) error {
+	detailsURL := event.Provider.URL
+	setCommitStatusState(&statusOpts)
+	if statusOpts.DetailsURL != "" {
+		detailsURL = statusOpts.DetailsURL
+	}
+	if v.client == nil {
+		return fmt.Errorf("no token has been set, cannot set status")
+	}
 
 	OrgAndRepo := fmt.Sprintf("%s/%s", event.Organization, event.Repository)
 	opts := &scm.StatusInput{
 		State: convertState(statusOpts.Conclusion),
-		Label: key,
+		Label: v.statusKey(statusOpts),
 		Desc:  statusOpts.Text,
 		Link:  detailsURL,
 	}
@@ -390,7 +456,7 @@ func (v *Provider) fetchChangedFiles(ctx context.Context, runevent *info.Event)
 	orgAndRepo := fmt.Sprintf("%s/%s", runevent.Organization, runevent.Repository)
 
 	switch runevent.TriggerTarget {
-	case triggertype.PullRequest:
+	case triggertype.PullRequest, triggertype.PullRequestClosed:
 		opts := &scm.ListOptions{Page: 1, Size: apiResponseLimit}
 		for {
 			changes, _, err := v.Client().PullRequests.ListChanges(ctx, orgAndRepo, runevent.PullRequestNumber, opts)
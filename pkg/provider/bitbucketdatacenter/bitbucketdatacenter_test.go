@@ -242,6 +242,51 @@ func TestCreateStatus(t *testing.T) {
 	}
 }
 
+func TestCreateCommitComment(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		nilClient bool
+		wantErr   string
+	}{
+		{
+			name:   "comment created",
+			status: http.StatusCreated,
+		},
+		{
+			name:    "comment refused",
+			status:  http.StatusForbidden,
+			wantErr: "cannot create commit comment on sha: 403: {}",
+		},
+		{
+			name:      "no token",
+			nilClient: true,
+			wantErr:   "no token has been set, cannot create commit comment",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, tearDown, tURL := bbtest.SetupBBDataCenterClient()
+			defer tearDown()
+			if tt.nilClient {
+				client = nil
+			}
+			event := bbtest.MakeEvent(nil)
+			event.SHA = "sha"
+			v := &Provider{baseURL: tURL, client: client, run: &params.Run{}}
+			bbtest.MuxCreateCommitComment(t, mux, event, "analysis", tt.status)
+
+			err := v.CreateCommitComment(ctx, event, "analysis")
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
 func TestGetFileInsideRepo(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -10,6 +10,13 @@ import (
 	"go.uber.org/zap"
 )
 
+var (
+	// pullRequestMerged is the event sent when a pull request is merged.
+	pullRequestMerged   = "pr:merged"
+	pullRequestsClosed  = []string{pullRequestMerged, "pr:declined", "pr:deleted"}
+	pullRequestsUpdated = []string{"pr:from_ref_updated", "pr:opened"}
+)
+
 // Detect processes event and detect if it is a bitbucket data center event, whether to process or reject it
 // returns (if is a bitbucket data center event, whether to process or reject, error if any occurred).
 func (v *Provider) Detect(req *http.Request, payload string, logger *zap.SugaredLogger) (bool, bool, *zap.SugaredLogger, string, error) {
@@ -38,7 +45,7 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 
 	switch e := eventPayload.(type) {
 	case *types.PullRequestEvent:
-		if provider.Valid(event, []string{"pr:from_ref_updated", "pr:opened"}) {
+		if provider.Valid(event, pullRequestsUpdated) || provider.Valid(event, pullRequestsClosed) {
 			return setLoggerAndProceed(true, "", nil)
 		}
 		if provider.Valid(event, []string{"pr:comment:added"}) {
@@ -107,9 +107,13 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 
 	switch e := eventPayload.(type) {
 	case *types.PullRequestEvent:
-		if provider.Valid(eventType, []string{"pr:from_ref_updated", "pr:opened"}) {
+		if provider.Valid(eventType, pullRequestsUpdated) {
 			processedEvent.TriggerTarget = triggertype.PullRequest
 			processedEvent.EventType = triggertype.PullRequest.String()
+		} else if provider.Valid(eventType, pullRequestsClosed) {
+			processedEvent.TriggerTarget = triggertype.PullRequestClosed
+			processedEvent.EventType = triggertype.PullRequest.String()
+			processedEvent.PullRequestMerged = eventType == pullRequestMerged
 		} else if provider.Valid(eventType, []string{"pr:comment:added", "pr:comment:edited"}) {
 			switch {
 			case provider.IsTestRetestComment(e.Comment.Text):
@@ -141,6 +145,7 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.Repository = e.PullRequest.ToRef.Repository.Name
 		processedEvent.SHA = e.PullRequest.FromRef.LatestCommit
 		processedEvent.PullRequestNumber = e.PullRequest.ID
+		processedEvent.IsDraft = e.PullRequest.Draft
 		processedEvent.URL = e.PullRequest.ToRef.Repository.Links.Self[0].Href
 		processedEvent.BaseBranch = e.PullRequest.ToRef.DisplayID
 		processedEvent.HeadBranch = e.PullRequest.FromRef.DisplayID
@@ -215,9 +220,9 @@ func parsePayloadType(event string) (any, error) {
 	// and cloud, so we check the event name directly
 	var localEvent string
 	if strings.HasPrefix(event, "pr:") {
-		if !provider.Valid(event, []string{
-			"pr:from_ref_updated", "pr:opened", "pr:comment:added", "pr:comment:edited",
-		}) {
+		if !provider.Valid(event, append(append([]string{
+			"pr:comment:added", "pr:comment:edited",
+		}, pullRequestsUpdated...), pullRequestsClosed...)) {
 			return nil, fmt.Errorf("event \"%s\" is not supported", event)
 		}
 		localEvent = triggertype.PullRequest.String()
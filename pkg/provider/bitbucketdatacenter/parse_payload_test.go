@@ -7,6 +7,7 @@ import (
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	bbv1test "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter/test"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter/types"
 	"gotest.tools/v3/assert"
@@ -574,6 +575,8 @@ func TestParsePayload(t *testing.T) {
 		rawStr                  string
 		targetPipelinerun       string
 		canceltargetPipelinerun string
+		wantTrigger             triggertype.Trigger
+		wantMerged              bool
 	}{
 		{
 			name:          "bad/invalid event type",
@@ -608,6 +611,21 @@ func TestParsePayload(t *testing.T) {
 			payloadEvent: bbv1test.MakePREvent(ev1, ""),
 			expEvent:     ev1,
 		},
+		{
+			name:         "good/pull_request merged",
+			eventType:    "pr:merged",
+			payloadEvent: bbv1test.MakePREvent(ev1, ""),
+			expEvent:     ev1,
+			wantTrigger:  triggertype.PullRequestClosed,
+			wantMerged:   true,
+		},
+		{
+			name:         "good/pull_request declined",
+			eventType:    "pr:declined",
+			payloadEvent: bbv1test.MakePREvent(ev1, ""),
+			expEvent:     ev1,
+			wantTrigger:  triggertype.PullRequestClosed,
+		},
 		{
 			name:         "good/push",
 			eventType:    "repo:refs_changed",
@@ -720,6 +738,10 @@ func TestParsePayload(t *testing.T) {
 			if tt.canceltargetPipelinerun != "" {
 				assert.Equal(t, got.TargetCancelPipelineRun, tt.canceltargetPipelinerun)
 			}
+			if tt.wantTrigger != "" {
+				assert.Equal(t, got.TriggerTarget, tt.wantTrigger)
+			}
+			assert.Equal(t, got.PullRequestMerged, tt.wantMerged)
 		})
 	}
 }
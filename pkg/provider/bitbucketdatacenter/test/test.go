@@ -69,6 +69,19 @@ func MuxCreateComment(t *testing.T, mux *http.ServeMux, event *info.Event, expec
 	})
 }
 
+func MuxCreateCommitComment(t *testing.T, mux *http.ServeMux, event *info.Event, expectedComment string, status int) {
+	path := fmt.Sprintf("/projects/%s/repos/%s/commits/%s/comments", event.Organization, event.Repository, event.SHA)
+	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		comment := &Comment{}
+		bit, _ := io.ReadAll(r.Body)
+		assert.NilError(t, json.Unmarshal(bit, comment))
+		assert.Equal(t, comment.Text, expectedComment)
+		rw.WriteHeader(status)
+		fmt.Fprintf(rw, "{}")
+	})
+}
+
 // MakeEvent should we try to reflect? or json.Marshall? may be better ways, right?
 func MakeEvent(event *info.Event) *info.Event {
 	if event == nil {
@@ -28,6 +28,7 @@ type PullRequest struct {
 	State        string             `json:"state"`
 	Open         bool               `json:"open"`
 	Closed       bool               `json:"closed"`
+	Draft        bool               `json:"draft"`
 	CreatedDate  int64              `json:"createdDate"`
 	UpdatedDate  int64              `json:"updatedDate"`
 	FromRef      PullRequestRef     `json:"fromRef"`
@@ -0,0 +1,198 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit/types"
+)
+
+// submitPermission is the permission a user needs on the target branch to be
+// allowed to run the CI.
+const submitPermission = "submit"
+
+// CheckPolicyAllowing checks if the sender is a member of one of the allowed
+// Gerrit groups.
+func (v *Provider) CheckPolicyAllowing(ctx context.Context, event *info.Event, allowedGroups []string) (bool, string) {
+	for _, group := range allowedGroups {
+		members := []types.AccountInfo{}
+		membersPath := fmt.Sprintf("/groups/%s/members/", url.PathEscape(group))
+		if _, err := v.requestJSON(ctx, http.MethodGet, membersPath, nil, nil, &members); err != nil {
+			v.Logger.Infof("error while getting members of group: %s, error: %s", group, err.Error())
+			continue
+		}
+		for _, member := range members {
+			if strings.EqualFold(member.Username, event.Sender) || strings.EqualFold(member.Email, event.Sender) {
+				return true, fmt.Sprintf("allowing user: %s as a member of the group: %s", event.Sender, group)
+			}
+		}
+	}
+	return false, fmt.Sprintf("user: %s is not a member of any of the allowed groups: %v", event.Sender, allowedGroups)
+}
+
+func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, error) {
+	if v.httpClient == nil {
+		return false, fmt.Errorf("%s", noClientErrStr)
+	}
+
+	aclPolicy := policy.Policy{
+		Repository:   v.repo,
+		EventEmitter: v.eventEmitter,
+		Event:        event,
+		VCX:          v,
+		Logger:       v.Logger,
+	}
+
+	// Try to detect a policy rule allowed it
+	policyAllowed, policyReason := aclPolicy.IsAllowed(ctx, detectTriggerType(event))
+	switch policyAllowed {
+	case policy.ResultAllowed:
+		return true, nil
+	case policy.ResultDisallowed:
+		return false, nil
+	case policy.ResultNotSet: // this is to make golangci-lint happy
+	}
+
+	allowed, err := v.aclCheckAll(ctx, event)
+	if err != nil {
+		return false, err
+	}
+	if allowed {
+		return true, nil
+	}
+
+	// Try to parse the comment from an owner who has issues a /ok-to-test
+	ownerAllowed, err := v.aclAllowedOkToTestFromAnOwner(ctx, event)
+	if err != nil {
+		return false, err
+	}
+	if ownerAllowed {
+		return true, nil
+	}
+
+	// error with the policy reason if it was set
+	if policyReason != "" {
+		return false, fmt.Errorf("%s", policyReason)
+	}
+	return false, nil
+}
+
+// detectTriggerType maps the event to the trigger type used by the policy
+// rules.
+func detectTriggerType(event *info.Event) triggertype.Trigger {
+	switch opscomments.EventType(event.EventType) {
+	case opscomments.OkToTestCommentEventType:
+		return triggertype.OkToTest
+	case opscomments.RetestAllCommentEventType, opscomments.RetestSingleCommentEventType,
+		opscomments.TestAllCommentEventType, opscomments.TestSingleCommentEventType:
+		return triggertype.Retest
+	default:
+	}
+	return event.TriggerTarget
+}
+
+// aclCheckAll checks if the sender is allowed to submit on the target branch
+// or is allowed in the OWNERS file.
+func (v *Provider) aclCheckAll(ctx context.Context, event *info.Event) (bool, error) {
+	if v.checkMembership(ctx, event) {
+		return true, nil
+	}
+	return v.IsAllowedOwnersFile(ctx, event)
+}
+
+// aclAllowedOkToTestFromAnOwner goes over the messages of the change and
+// checks if there is a /ok-to-test from an allowed user.
+func (v *Provider) aclAllowedOkToTestFromAnOwner(ctx context.Context, event *info.Event) (bool, error) {
+	if event.PullRequestNumber == 0 || v.pacInfo == nil || !v.pacInfo.RememberOKToTest {
+		return false, nil
+	}
+
+	messages := []types.ChangeMessageInfo{}
+	messagesPath := fmt.Sprintf("/changes/%s/messages", changeID(v.project, event.PullRequestNumber))
+	if _, err := v.requestJSON(ctx, http.MethodGet, messagesPath, nil, nil, &messages); err != nil {
+		return false, fmt.Errorf("cannot list messages of change %d: %w", event.PullRequestNumber, err)
+	}
+	for _, message := range messages {
+		if message.Author == nil || !acl.MatchRegexp(acl.OKToTestCommentRegexp, commentBody(message.Message)) {
+			continue
+		}
+		commenterEvent := info.NewEvent()
+		commenterEvent.Event = event.Event
+		commenterEvent.Sender = message.Author.Username
+		if commenterEvent.Sender == "" {
+			commenterEvent.Sender = message.Author.Email
+		}
+		commenterEvent.BaseBranch = event.BaseBranch
+		commenterEvent.HeadBranch = event.HeadBranch
+		commenterEvent.DefaultBranch = event.DefaultBranch
+		allowed, err := v.aclCheckAll(ctx, commenterEvent)
+		if err != nil {
+			return false, err
+		}
+		if allowed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsAllowedOwnersFile get the owner files (OWNERS, OWNERS_ALIASES) from main branch
+// and check if we have explicitly allowed the user in there.
+func (v *Provider) IsAllowedOwnersFile(ctx context.Context, event *info.Event) (bool, error) {
+	ownerContent, err := v.GetFileInsideRepo(ctx, event, "OWNERS", event.DefaultBranch)
+	if err != nil {
+		if strings.Contains(err.Error(), "cannot find") {
+			// no owner file, skipping
+			return false, nil
+		}
+		return false, err
+	}
+	// OWNERS_ALIASES file existence is not required, if we get "not found" continue
+	ownerAliasesContent, err := v.GetFileInsideRepo(ctx, event, "OWNERS_ALIASES", event.DefaultBranch)
+	if err != nil {
+		if !strings.Contains(err.Error(), "cannot find") {
+			return false, err
+		}
+	}
+	return acl.UserInOwnerFile(ownerContent, ownerAliasesContent, event.Sender)
+}
+
+// checkMembership checks if the user has the submit permission on the target
+// branch of the change, which is what makes someone a maintainer in Gerrit.
+func (v *Provider) checkMembership(ctx context.Context, event *info.Event) bool {
+	// Initialize cache lazily
+	if v.memberCache == nil {
+		v.memberCache = map[string]bool{}
+	}
+	if allowed, ok := v.memberCache[event.Sender]; ok {
+		return allowed
+	}
+
+	ref := event.BaseBranch
+	if !strings.HasPrefix(ref, "refs/") {
+		ref = "refs/heads/" + ref
+	}
+	query := url.Values{}
+	query.Set("account", event.Sender)
+	query.Set("ref", ref)
+	query.Set("perm", submitPermission)
+	access := &types.AccessCheckInfo{}
+	accessPath := fmt.Sprintf("/projects/%s/check.access", projectPath(v.project))
+	if _, err := v.requestJSON(ctx, http.MethodGet, accessPath, query, nil, access); err != nil {
+		// If the API call fails, don't cache the result so a transient
+		// failure can be retried on the next invocation.
+		v.Logger.Debugf("cannot check access of %s on %s: %v", event.Sender, v.project, err)
+		return false
+	}
+	allowed := access.Status == http.StatusOK
+	v.memberCache[event.Sender] = allowed
+	return allowed
+}
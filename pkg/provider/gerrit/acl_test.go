@@ -0,0 +1,160 @@
+package gerrit
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	gerrittest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit/test"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit/types"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestIsAllowed(t *testing.T) {
+	tests := []struct {
+		name             string
+		event            *info.Event
+		submitters       []string
+		groups           map[string][]string
+		messages         []types.ChangeMessageInfo
+		filescontents    map[string]string
+		policy           *v1alpha1.Policy
+		rememberOKToTest bool
+		isAllowed        bool
+		wantErrSubstr    string
+	}{
+		{
+			name:       "allowed/user can submit on the branch",
+			event:      gerrittest.MakeEvent(&info.Event{Sender: "maintainer"}),
+			submitters: []string{"maintainer"},
+			isAllowed:  true,
+		},
+		{
+			name:       "allowed/user is in the owners file",
+			event:      gerrittest.MakeEvent(&info.Event{Sender: "owner"}),
+			submitters: []string{"maintainer"},
+			filescontents: map[string]string{
+				"OWNERS": "---\n approvers:\n  - owner\n",
+			},
+			isAllowed: true,
+		},
+		{
+			name: "allowed/from an ok-to-test message of a maintainer",
+			event: gerrittest.MakeEvent(&info.Event{
+				Sender:            "external",
+				PullRequestNumber: 1,
+				TriggerTarget:     triggertype.PullRequest,
+			}),
+			submitters: []string{"maintainer"},
+			messages: []types.ChangeMessageInfo{
+				{ID: "1", Message: "Patch Set 1:\n\n/ok-to-test", Author: &types.AccountInfo{Username: "maintainer"}},
+			},
+			rememberOKToTest: true,
+			isAllowed:        true,
+		},
+		{
+			name: "disallowed/ok-to-test messages are not remembered",
+			event: gerrittest.MakeEvent(&info.Event{
+				Sender:            "external",
+				PullRequestNumber: 1,
+				TriggerTarget:     triggertype.PullRequest,
+			}),
+			submitters: []string{"maintainer"},
+			messages: []types.ChangeMessageInfo{
+				{ID: "1", Message: "Patch Set 1:\n\n/ok-to-test", Author: &types.AccountInfo{Username: "maintainer"}},
+			},
+			isAllowed: false,
+		},
+		{
+			name: "disallowed/ok-to-test message from a non maintainer",
+			event: gerrittest.MakeEvent(&info.Event{
+				Sender:            "external",
+				PullRequestNumber: 1,
+				TriggerTarget:     triggertype.PullRequest,
+			}),
+			submitters: []string{"maintainer"},
+			messages: []types.ChangeMessageInfo{
+				{ID: "1", Message: "Patch Set 1:\n\n/ok-to-test", Author: &types.AccountInfo{Username: "other"}},
+			},
+			rememberOKToTest: true,
+			isAllowed:        false,
+		},
+		{
+			name:       "disallowed/user cannot submit",
+			event:      gerrittest.MakeEvent(&info.Event{Sender: "external"}),
+			submitters: []string{"maintainer"},
+			isAllowed:  false,
+		},
+		{
+			name: "allowed/policy allows the group of the user",
+			event: gerrittest.MakeEvent(&info.Event{
+				Sender:        "release",
+				TriggerTarget: triggertype.PullRequest,
+			}),
+			submitters: []string{"maintainer"},
+			groups:     map[string][]string{"releasers": {"release"}},
+			policy: &v1alpha1.Policy{
+				PullRequest: []string{"releasers"},
+			},
+			isAllowed: true,
+		},
+		{
+			name: "disallowed/maintainer not in the policy group",
+			event: gerrittest.MakeEvent(&info.Event{
+				Sender:        "maintainer",
+				TriggerTarget: triggertype.PullRequest,
+			}),
+			submitters: []string{"maintainer"},
+			groups:     map[string][]string{"releasers": {"release"}},
+			policy: &v1alpha1.Policy{
+				PullRequest: []string{"releasers"},
+			},
+			isAllowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			client, mux, apiURL, tearDown := gerrittest.Setup(t)
+			defer tearDown()
+
+			gerrittest.MuxCheckAccess(t, mux, tt.event, tt.submitters...)
+			for group, members := range tt.groups {
+				gerrittest.MuxGroupMembers(t, mux, group, members...)
+			}
+			gerrittest.MuxMessages(t, mux, tt.event, tt.messages)
+			gerrittest.MuxFiles(t, mux, tt.event, tt.filescontents, false)
+
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec:       v1alpha1.RepositorySpec{Settings: &v1alpha1.Settings{Policy: tt.policy}},
+			}
+			v := &Provider{
+				Logger:       logger,
+				apiURL:       apiURL,
+				httpClient:   client,
+				project:      "project",
+				repo:         repo,
+				eventEmitter: events.NewEventEmitter(nil, logger),
+				pacInfo:      &info.PacOpts{Settings: settings.Settings{RememberOKToTest: tt.rememberOKToTest}},
+			}
+
+			got, err := v.IsAllowed(ctx, tt.event)
+			if tt.wantErrSubstr != "" {
+				assert.ErrorContains(t, err, tt.wantErrSubstr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.isAllowed)
+		})
+	}
+}
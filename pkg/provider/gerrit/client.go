@@ -0,0 +1,106 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	providerMetrics "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/providermetrics"
+)
+
+const (
+	// xssiPrefix is the magic prefix Gerrit adds to every JSON response to
+	// prevent against cross site script inclusion.
+	xssiPrefix = ")]}'"
+	// authPrefix is the path prefix of the authenticated REST endpoints.
+	authPrefix = "/a"
+)
+
+// projectPath returns the escaped project name as expected in the REST API
+// path, ie: a project named "org/repo" becomes "org%2Frepo".
+func projectPath(project string) string {
+	return url.PathEscape(project)
+}
+
+// changeID returns the change identifier used by the REST API, the
+// project~number form is unique across the whole Gerrit instance.
+func changeID(project string, number int) string {
+	return fmt.Sprintf("%s~%d", projectPath(project), number)
+}
+
+// request sends a request to the authenticated Gerrit REST API, path is
+// relative to the Gerrit URL. The response body is returned stripped of the
+// XSSI prefix.
+func (v *Provider) request(ctx context.Context, method, path string, query url.Values, body any) (*http.Response, []byte, error) {
+	if v.httpClient == nil {
+		return nil, nil, fmt.Errorf("%s", noClientErrStr)
+	}
+	providerMetrics.RecordAPIUsage(
+		v.Logger,
+		// URL used instead of "gerrit" to differentiate in the case of a CI cluster which
+		// serves multiple Gerrit instances
+		v.apiURL,
+		v.triggerEvent,
+		v.repo,
+	)
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	reqURL := v.apiURL + authPrefix + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.SetBasicAuth(v.user, v.token)
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte(xssiPrefix))
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, fmt.Errorf("gerrit api %s %s returned %d: %s", method, req.URL.EscapedPath(), resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return resp, data, nil
+}
+
+// requestJSON sends a request to the Gerrit REST API and decode the JSON
+// response into out when not nil.
+func (v *Provider) requestJSON(ctx context.Context, method, path string, query url.Values, body, out any) (*http.Response, error) {
+	resp, data, err := v.request(ctx, method, path, query, body)
+	if err != nil {
+		return resp, err
+	}
+	if out != nil && len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp, fmt.Errorf("cannot decode gerrit api response from %s: %w", path, err)
+		}
+	}
+	return resp, nil
+}
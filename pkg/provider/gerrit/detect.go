@@ -0,0 +1,120 @@
+package gerrit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit/types"
+	"go.uber.org/zap"
+)
+
+const (
+	eventTypePatchSetCreated = "patchset-created"
+	eventTypeCommentAdded    = "comment-added"
+	eventTypeChangeMerged    = "change-merged"
+	eventTypeChangeAbandoned = "change-abandoned"
+	eventTypeRefUpdated      = "ref-updated"
+
+	patchSetKindNoChange = "NO_CHANGE"
+)
+
+// patchSetHeaderRegexp matches the header Gerrit prepends to the review
+// messages, ie: "Patch Set 2: Code-Review+1".
+var patchSetHeaderRegexp = regexp.MustCompile(`^Patch Set \d+:[^\n]*\n*`)
+
+// Detect processes event and detect if it is a Gerrit stream event sent by the
+// webhooks plugin, whether to process or reject it.
+// returns (if is a gerrit event, whether to process or reject, logger, reason, error if any occurred).
+func (v *Provider) Detect(req *http.Request, payload string, logger *zap.SugaredLogger) (bool, bool, *zap.SugaredLogger, string, error) {
+	isGerrit := false
+	// The webhooks plugin doesn't send any header identifying Gerrit, every
+	// stream event has a type and a creation timestamp.
+	envelope := &types.Event{}
+	if err := json.Unmarshal([]byte(payload), envelope); err != nil || envelope.Type == "" || envelope.EventCreatedOn == 0 {
+		return false, false, logger, "", nil
+	}
+
+	// it is a Gerrit event
+	isGerrit = true
+
+	setLoggerAndProceed := func(processEvent bool, reason string, err error) (bool, bool, *zap.SugaredLogger, string, error) {
+		logger = logger.With("provider", "gerrit", "event-type", envelope.Type)
+		return isGerrit, processEvent, logger, reason, err
+	}
+
+	if !provider.Valid(envelope.Type, []string{
+		eventTypePatchSetCreated, eventTypeCommentAdded, eventTypeChangeMerged,
+		eventTypeChangeAbandoned, eventTypeRefUpdated,
+	}) {
+		return setLoggerAndProceed(false, fmt.Sprintf("event \"%s\" is not supported", envelope.Type), nil)
+	}
+
+	eventInt, err := parsePayloadType(envelope.Type, payload)
+	if err != nil {
+		return setLoggerAndProceed(false, "", err)
+	}
+
+	switch gitEvent := eventInt.(type) {
+	case *types.PatchSetCreatedEvent:
+		if gitEvent.PatchSet.Kind == patchSetKindNoChange {
+			return setLoggerAndProceed(false, "patchset has no change from the previous one", nil)
+		}
+		return setLoggerAndProceed(true, "", nil)
+	case *types.CommentAddedEvent:
+		if gitEvent.Change.Status != "" && gitEvent.Change.Status != changeStatusNew {
+			return setLoggerAndProceed(false, "comments on closed changes is not supported", nil)
+		}
+		if opscomments.CommentEventType(commentBody(gitEvent.Comment)) == opscomments.NoOpsCommentEventType {
+			return setLoggerAndProceed(false, "comment is not a GitOps command", nil)
+		}
+		return setLoggerAndProceed(true, "", nil)
+	case *types.ChangeMergedEvent, *types.ChangeAbandonedEvent:
+		return setLoggerAndProceed(true, "", nil)
+	case *types.RefUpdatedEvent:
+		refName := gitEvent.RefUpdate.RefName
+		if !strings.HasPrefix(refName, "refs/heads/") && !strings.HasPrefix(refName, "refs/tags/") {
+			return setLoggerAndProceed(false, fmt.Sprintf("ref \"%s\" is not a branch or a tag", refName), nil)
+		}
+		if provider.IsZeroSHA(gitEvent.RefUpdate.NewRev) {
+			return setLoggerAndProceed(false, "branch or tag deletion is not supported", nil)
+		}
+		return setLoggerAndProceed(true, "", nil)
+	default:
+		return setLoggerAndProceed(false, "", fmt.Errorf("gerrit: event \"%s\" is not supported", envelope.Type))
+	}
+}
+
+// commentBody strips the "Patch Set N:" header Gerrit adds to the review
+// messages so the GitOps commands are at the beginning of the comment.
+func commentBody(comment string) string {
+	return strings.TrimSpace(patchSetHeaderRegexp.ReplaceAllString(comment, ""))
+}
+
+// parsePayloadType decodes the payload into the struct matching the stream
+// event type.
+func parsePayloadType(eventType, payload string) (any, error) {
+	var eventInt any
+	switch eventType {
+	case eventTypePatchSetCreated:
+		eventInt = &types.PatchSetCreatedEvent{}
+	case eventTypeCommentAdded:
+		eventInt = &types.CommentAddedEvent{}
+	case eventTypeChangeMerged:
+		eventInt = &types.ChangeMergedEvent{}
+	case eventTypeChangeAbandoned:
+		eventInt = &types.ChangeAbandonedEvent{}
+	case eventTypeRefUpdated:
+		eventInt = &types.RefUpdatedEvent{}
+	default:
+		return nil, fmt.Errorf("gerrit: event \"%s\" is not supported", eventType)
+	}
+	if err := json.Unmarshal([]byte(payload), eventInt); err != nil {
+		return nil, err
+	}
+	return eventInt, nil
+}
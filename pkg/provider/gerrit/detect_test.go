@@ -0,0 +1,189 @@
+package gerrit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit/types"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+)
+
+func TestProvider_Detect(t *testing.T) {
+	envelope := func(eventType string) types.Event {
+		return types.Event{Type: eventType, EventCreatedOn: 1700000000}
+	}
+	tests := []struct {
+		name          string
+		wantErrString string
+		isGerrit      bool
+		processReq    bool
+		event         any
+		wantReason    string
+	}{
+		{
+			name:       "not a gerrit event",
+			event:      map[string]string{"hello": "moto"},
+			isGerrit:   false,
+			processReq: false,
+		},
+		{
+			name:       "no creation timestamp",
+			event:      types.Event{Type: eventTypePatchSetCreated},
+			isGerrit:   false,
+			processReq: false,
+		},
+		{
+			name:       "unsupported event",
+			event:      envelope("reviewer-added"),
+			isGerrit:   true,
+			processReq: false,
+			wantReason: "event \"reviewer-added\" is not supported",
+		},
+		{
+			name: "patchset created",
+			event: types.PatchSetCreatedEvent{
+				Event:    envelope(eventTypePatchSetCreated),
+				PatchSet: types.PatchSet{Kind: "REWORK"},
+			},
+			isGerrit:   true,
+			processReq: true,
+		},
+		{
+			name: "patchset without code change",
+			event: types.PatchSetCreatedEvent{
+				Event:    envelope(eventTypePatchSetCreated),
+				PatchSet: types.PatchSet{Kind: patchSetKindNoChange},
+			},
+			isGerrit:   true,
+			processReq: false,
+			wantReason: "patchset has no change from the previous one",
+		},
+		{
+			name: "retest comment",
+			event: types.CommentAddedEvent{
+				Event:   envelope(eventTypeCommentAdded),
+				Change:  types.Change{Status: changeStatusNew},
+				Comment: "Patch Set 2:\n\n/retest",
+			},
+			isGerrit:   true,
+			processReq: true,
+		},
+		{
+			name: "cancel comment with a vote",
+			event: types.CommentAddedEvent{
+				Event:   envelope(eventTypeCommentAdded),
+				Change:  types.Change{Status: changeStatusNew},
+				Comment: "Patch Set 1: Code-Review+1\n\n/cancel",
+			},
+			isGerrit:   true,
+			processReq: true,
+		},
+		{
+			name: "random comment",
+			event: types.CommentAddedEvent{
+				Event:   envelope(eventTypeCommentAdded),
+				Change:  types.Change{Status: changeStatusNew},
+				Comment: "Patch Set 1:\n\nrandom string, ignore me :)",
+			},
+			isGerrit:   true,
+			processReq: false,
+			wantReason: "comment is not a GitOps command",
+		},
+		{
+			name: "comment on a merged change",
+			event: types.CommentAddedEvent{
+				Event:   envelope(eventTypeCommentAdded),
+				Change:  types.Change{Status: "MERGED"},
+				Comment: "Patch Set 1:\n\n/retest",
+			},
+			isGerrit:   true,
+			processReq: false,
+			wantReason: "comments on closed changes is not supported",
+		},
+		{
+			name:       "change merged",
+			event:      types.ChangeMergedEvent{Event: envelope(eventTypeChangeMerged)},
+			isGerrit:   true,
+			processReq: true,
+		},
+		{
+			name:       "change abandoned",
+			event:      types.ChangeAbandonedEvent{Event: envelope(eventTypeChangeAbandoned)},
+			isGerrit:   true,
+			processReq: true,
+		},
+		{
+			name: "ref updated",
+			event: types.RefUpdatedEvent{
+				Event:     envelope(eventTypeRefUpdated),
+				RefUpdate: types.RefUpdate{RefName: "refs/heads/main", NewRev: "1234"},
+			},
+			isGerrit:   true,
+			processReq: true,
+		},
+		{
+			name: "ref updated on a change ref",
+			event: types.RefUpdatedEvent{
+				Event:     envelope(eventTypeRefUpdated),
+				RefUpdate: types.RefUpdate{RefName: "refs/changes/01/1/1", NewRev: "1234"},
+			},
+			isGerrit:   true,
+			processReq: false,
+			wantReason: "ref \"refs/changes/01/1/1\" is not a branch or a tag",
+		},
+		{
+			name: "branch deletion",
+			event: types.RefUpdatedEvent{
+				Event:     envelope(eventTypeRefUpdated),
+				RefUpdate: types.RefUpdate{RefName: "refs/heads/main", NewRev: "0000000000000000000000000000000000000000"},
+			},
+			isGerrit:   true,
+			processReq: false,
+			wantReason: "branch or tag deletion is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := Provider{}
+			logger, _ := logger.GetLogger()
+
+			jeez, err := json.Marshal(tt.event)
+			assert.NilError(t, err)
+
+			req := &http.Request{Header: http.Header{}}
+			isGerrit, processReq, _, reason, err := v.Detect(req, string(jeez), logger)
+			if tt.wantErrString != "" {
+				assert.ErrorContains(t, err, tt.wantErrString)
+				assert.Equal(t, tt.isGerrit, isGerrit)
+				return
+			}
+			assert.NilError(t, err)
+			if tt.wantReason != "" {
+				assert.Assert(t, strings.Contains(reason, tt.wantReason), "reason %s doesn't have %s", reason, tt.wantReason)
+			}
+			assert.Equal(t, tt.isGerrit, isGerrit)
+			assert.Equal(t, tt.processReq, processReq)
+		})
+	}
+}
+
+func TestCommentBody(t *testing.T) {
+	tests := []struct {
+		comment string
+		want    string
+	}{
+		{comment: "Patch Set 2:\n\n/retest", want: "/retest"},
+		{comment: "Patch Set 12: Verified+1 Code-Review+2\n\n/test pr-name", want: "/test pr-name"},
+		{comment: "/ok-to-test", want: "/ok-to-test"},
+		{comment: "Patch Set 1:", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, commentBody(tt.comment), tt.want)
+		})
+	}
+}
@@ -0,0 +1,466 @@
+package gerrit
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/changedfiles"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit/types"
+	"go.uber.org/zap"
+)
+
+const (
+	taskStatusTemplate = `{{range $taskrun := .TaskRunList }}* {{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }} {{ $taskrun.ConsoleLogURL }} ({{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.PipelineRunTaskRunStatus.Status.CompletionTime }})
+{{ end }}`
+	noClientErrStr = `no gerrit client has been initialized, exiting... (hint: did you forget setting a secret on your repo?)`
+
+	// verifiedLabel is the label we vote on when a PipelineRun has finished.
+	verifiedLabel = "Verified"
+	// reviewTag marks our review messages as automated, Gerrit hides them
+	// when only showing the human comments.
+	reviewTag = "autogenerated:pipelines-as-code"
+	// gerritDateLayout is the layout of the timestamps returned by the REST API.
+	gerritDateLayout = "2006-01-02 15:04:05.000000000"
+
+	changeStatusNew = "NEW"
+)
+
+var _ provider.Interface = (*Provider)(nil)
+
+type Provider struct {
+	httpClient   *http.Client
+	Logger       *zap.SugaredLogger
+	run          *params.Run
+	pacInfo      *info.PacOpts
+	user         string
+	token        string
+	apiURL       string
+	project      string
+	changeNumber int
+	eventEmitter *events.EventEmitter
+	repo         *v1alpha1.Repository
+	triggerEvent string
+	// memberCache caches access checks by user name within the current
+	// provider instance lifecycle to avoid repeated API calls.
+	memberCache        map[string]bool
+	cachedChangedFiles *changedfiles.ChangedFiles
+}
+
+func (v *Provider) SetLogger(logger *zap.SugaredLogger) {
+	v.Logger = logger
+}
+
+func (v *Provider) SetPacInfo(pacInfo *info.PacOpts) {
+	v.pacInfo = pacInfo
+}
+
+// GetTaskURI TODO: Implement ME.
+func (v *Provider) GetTaskURI(_ context.Context, _ *info.Event, _ string) (bool, string, error) {
+	return false, "", nil
+}
+
+func (v *Provider) CreateToken(_ context.Context, _ []string, _ *info.Event) (string, error) {
+	return "", nil
+}
+
+func (v *Provider) GetTemplate(commentType provider.CommentType) string {
+	return provider.GetMarkdownTemplate(commentType)
+}
+
+func (v *Provider) GetConfig() *info.ProviderConfig {
+	return &info.ProviderConfig{
+		TaskStatusTMPL: taskStatusTemplate,
+		APIURL:         v.apiURL,
+		Name:           "gerrit",
+	}
+}
+
+// Validate checks the webhook credentials, the Gerrit webhooks plugin does not
+// sign its payloads, the webhook secret is expected as the password of the
+// basic authentication set in the webhook URL.
+func (v *Provider) Validate(_ context.Context, _ *params.Run, event *info.Event) error {
+	if event.Provider.WebhookSecret == "" {
+		return fmt.Errorf("no webhook secret configured: set webhook secret in repository CR or secret")
+	}
+
+	req := &http.Request{Header: event.Request.Header}
+	_, password, ok := req.BasicAuth()
+	if !ok {
+		return fmt.Errorf("no basic authentication detected in the gerrit webhook request: webhook validation requires a secret")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(event.Provider.WebhookSecret), []byte(password)) == 0 {
+		return fmt.Errorf("gerrit webhook validation failed: password does not match configured secret")
+	}
+	return nil
+}
+
+func (v *Provider) SetClient(_ context.Context, run *params.Run, runevent *info.Event, repo *v1alpha1.Repository, eventsEmitter *events.EventEmitter) error {
+	if runevent.Provider.Token == "" {
+		return fmt.Errorf("no git_provider.secret has been set in the repo crd")
+	}
+	if runevent.Provider.User == "" {
+		return fmt.Errorf("no git_provider.user has been set in the repo crd")
+	}
+
+	repoURL, err := url.Parse(runevent.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse repository url %s: %w", runevent.URL, err)
+	}
+	// Let the user override the Gerrit URL when it is served under a path
+	// and we cannot guess it from the repository URL.
+	switch {
+	case runevent.Provider.URL != "":
+		v.apiURL = strings.TrimSuffix(runevent.Provider.URL, "/")
+	case v.apiURL == "":
+		v.apiURL = fmt.Sprintf("%s://%s", repoURL.Scheme, repoURL.Host)
+	}
+	if _, err := url.Parse(v.apiURL); err != nil {
+		return fmt.Errorf("failed to parse api url %s: %w", v.apiURL, err)
+	}
+	if v.project == "" {
+		if strings.HasPrefix(runevent.URL, v.apiURL+"/") {
+			v.project = strings.TrimPrefix(runevent.URL, v.apiURL+"/")
+		} else {
+			v.project = strings.Trim(repoURL.Path, "/")
+		}
+	}
+	runevent.Organization, runevent.Repository = splitProject(v.project)
+
+	if v.httpClient == nil {
+		v.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	v.user = runevent.Provider.User
+	v.token = runevent.Provider.Token
+	v.run = run
+	v.eventEmitter = eventsEmitter
+	v.repo = repo
+	v.triggerEvent = runevent.EventType
+	if v.changeNumber == 0 {
+		v.changeNumber = runevent.PullRequestNumber
+	}
+
+	// Added for security audit purposes to log client access when a token is used
+	run.Clients.Log.Infof("gerrit: initialized client with provided token for user=%s, apiURL=%s, project=%s", v.user, v.apiURL, v.project)
+	return nil
+}
+
+// CreateStatus reports the status of the PipelineRun as a review message on
+// the patchset, and as a vote on the Verified label once it has finished.
+// Gerrit has no status for commits outside of a change, push events are not
+// reported.
+func (v *Provider) CreateStatus(ctx context.Context, event *info.Event, statusOpts provider.StatusOpts) error {
+	if v.httpClient == nil {
+		return fmt.Errorf("%s", noClientErrStr)
+	}
+
+	if event.PullRequestNumber == 0 {
+		v.Logger.Debugf("not reporting status %s on %s, gerrit only reports status on changes", statusOpts.Conclusion, event.SHA)
+		return nil
+	}
+
+	var vote *int
+	voteFor := func(value int) *int { return &value }
+	switch statusOpts.Conclusion {
+	case "skipped":
+		statusOpts.Title = "Skipped validating this commit"
+		vote = voteFor(0)
+	case "neutral":
+		statusOpts.Title = "Stopped"
+		vote = voteFor(0)
+	case "cancelled":
+		statusOpts.Title = "Cancelled validating this commit"
+		vote = voteFor(0)
+	case "failure":
+		statusOpts.Title = "Failed"
+		vote = voteFor(-1)
+	case "success":
+		statusOpts.Title = "Successfully validated your commit"
+		vote = voteFor(1)
+	case "completed":
+		statusOpts.Title = "Completed"
+		vote = voteFor(1)
+	case "pending":
+		if statusOpts.Title == "" {
+			statusOpts.Title = "Pending"
+		}
+	}
+	if statusOpts.Status == "in_progress" {
+		statusOpts.Title = "CI has started"
+		// reset a previous vote on a /retest of the same patchset
+		vote = voteFor(0)
+	}
+
+	review := types.ReviewInput{Tag: reviewTag}
+	if vote != nil {
+		review.Labels = map[string]int{verifiedLabel: *vote}
+	}
+
+	// only chat about what's happening when there is something to say, or
+	// when we are starting the CI.
+	if statusOpts.Text != "" || statusOpts.Status == "in_progress" {
+		onPr := ""
+		if statusOpts.OriginalPipelineRunName != "" {
+			onPr = "/" + statusOpts.OriginalPipelineRunName
+		}
+		review.Message = fmt.Sprintf("%s%s: %s", v.pacInfo.ApplicationName, onPr, statusOpts.Title)
+		if statusOpts.Text != "" {
+			review.Message += "\n\n" + statusOpts.Text
+		}
+		if statusOpts.DetailsURL != "" {
+			review.Message += fmt.Sprintf("\n\nFull log available here: %s", statusOpts.DetailsURL)
+		}
+	}
+
+	if review.Message == "" && review.Labels == nil {
+		return nil
+	}
+	if statusOpts.Status == "in_progress" {
+		review.Notify = "OWNER"
+	}
+	return v.setReview(ctx, event, review)
+}
+
+func (v *Provider) setReview(ctx context.Context, event *info.Event, review types.ReviewInput) error {
+	reviewPath := fmt.Sprintf("/changes/%s/revisions/%s/review", changeID(v.project, event.PullRequestNumber), event.SHA)
+	if _, err := v.requestJSON(ctx, http.MethodPost, reviewPath, nil, review, nil); err != nil {
+		return fmt.Errorf("cannot set review on change %d: %w", event.PullRequestNumber, err)
+	}
+	return nil
+}
+
+// CreateCommitComment is not supported, the review messages are posted on
+// the changes and not on the commits.
+// UpdateStatusDetails does nothing, gerrit reports the status with a review
+// message and another one would be posted on the change.
+func (v *Provider) UpdateStatusDetails(_ context.Context, event *info.Event, _ provider.StatusOpts, _, _ string) error {
+	v.Logger.Debugf("not updating the status of %s, gerrit has no status to update without posting a review message", event.SHA)
+	return nil
+}
+
+func (v *Provider) CreateCommitComment(_ context.Context, _ *info.Event, _ string) error {
+	return fmt.Errorf("commit comments are not supported on gerrit")
+}
+
+// CreateComment posts a review message on the change, Gerrit doesn't allow
+// editing the review messages so the updateMarker is ignored and a new message
+// is always posted.
+func (v *Provider) CreateComment(ctx context.Context, event *info.Event, comment, _ string) error {
+	if v.httpClient == nil {
+		return fmt.Errorf("%s", noClientErrStr)
+	}
+
+	if event.PullRequestNumber == 0 {
+		return fmt.Errorf("create comment only works on changes")
+	}
+	return v.setReview(ctx, event, types.ReviewInput{Message: comment, Tag: reviewTag})
+}
+
+func (v *Provider) GetTektonDir(ctx context.Context, event *info.Event, path, provenance string) (string, error) {
+	if v.httpClient == nil {
+		return "", fmt.Errorf("%s", noClientErrStr)
+	}
+
+	// default set provenance from the SHA
+	revision := event.SHA
+	if provenance == "default_branch" {
+		revision = event.DefaultBranch
+		v.Logger.Infof("Using PipelineRun definition from default_branch: %s", event.DefaultBranch)
+	} else {
+		v.Logger.Infof("Using PipelineRun definition from source %s commit SHA: %s", event.TriggerTarget.String(), event.SHA)
+	}
+
+	// The REST API cannot list a directory, we rely on the gitiles plugin
+	// shipped with Gerrit for it.
+	dirPath := strings.Trim(path, "/")
+	query := url.Values{}
+	query.Set("format", "JSON")
+	query.Set("recursive", "1")
+	tree := &types.Tree{}
+	resp, err := v.requestJSON(ctx, http.MethodGet, fmt.Sprintf("/plugins/gitiles/%s/+/%s/%s/", v.project, revision, dirPath), query, nil, tree)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to list %s dir: %w", path, err)
+	}
+
+	var allTemplates string
+	for _, entry := range tree.Entries {
+		if entry.Type != "blob" || (!strings.HasSuffix(entry.Name, ".yaml") && !strings.HasSuffix(entry.Name, ".yml")) {
+			continue
+		}
+		fpath := dirPath + "/" + entry.Name
+		data, err := v.getObject(ctx, fpath, revision, provenance == "default_branch")
+		if err != nil {
+			return "", err
+		}
+		if err := provider.ValidateYaml([]byte(data), fpath); err != nil {
+			return "", err
+		}
+		if allTemplates != "" && !strings.HasPrefix(data, "---") {
+			allTemplates += "---"
+		}
+		allTemplates += "\n" + data + "\n"
+	}
+	return allTemplates, nil
+}
+
+// getObject gets the content of a file at a commit, or at the head of a
+// branch when isBranch is set.
+func (v *Provider) getObject(ctx context.Context, fpath, revision string, isBranch bool) (string, error) {
+	kind := "commits"
+	if isBranch {
+		kind = "branches"
+	}
+	contentPath := fmt.Sprintf("/projects/%s/%s/%s/files/%s/content", projectPath(v.project), kind, url.PathEscape(revision), url.PathEscape(fpath))
+	_, data, err := v.request(ctx, http.MethodGet, contentPath, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("cannot find %s inside the %s repository: %w", fpath, v.project, err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return "", fmt.Errorf("cannot decode %s content: %w", fpath, err)
+	}
+	return string(decoded), nil
+}
+
+func (v *Provider) GetFileInsideRepo(ctx context.Context, runevent *info.Event, path, target string) (string, error) {
+	if v.httpClient == nil {
+		return "", fmt.Errorf("%s", noClientErrStr)
+	}
+	if target != "" {
+		return v.getObject(ctx, path, strings.TrimPrefix(target, "refs/heads/"), true)
+	}
+	return v.getObject(ctx, path, runevent.SHA, false)
+}
+
+func (v *Provider) GetCommitInfo(ctx context.Context, runevent *info.Event) error {
+	if v.httpClient == nil {
+		return fmt.Errorf("%s", noClientErrStr)
+	}
+
+	// if we don't have a SHA (ie: incoming-webhook) then get it from the branch
+	// and populate in the runevent.
+	if runevent.SHA == "" && runevent.HeadBranch != "" {
+		branch := &types.BranchInfo{}
+		branchPath := fmt.Sprintf("/projects/%s/branches/%s", projectPath(v.project), url.PathEscape(runevent.HeadBranch))
+		if _, err := v.requestJSON(ctx, http.MethodGet, branchPath, nil, nil, branch); err != nil {
+			return err
+		}
+		runevent.SHA = branch.Revision
+	}
+
+	commit := &types.CommitInfo{}
+	commitPath := fmt.Sprintf("/projects/%s/commits/%s", projectPath(v.project), runevent.SHA)
+	if _, err := v.requestJSON(ctx, http.MethodGet, commitPath, nil, nil, commit); err != nil {
+		return err
+	}
+
+	runevent.SHATitle = commit.Subject
+	if runevent.SHAURL == "" {
+		runevent.SHAURL = fmt.Sprintf("%s/plugins/gitiles/%s/+/%s", v.apiURL, v.project, runevent.SHA)
+	}
+
+	// Populate full commit information for LLM context
+	runevent.SHAMessage = commit.Message
+	runevent.SHAAuthorName = commit.Author.Name
+	runevent.SHAAuthorEmail = commit.Author.Email
+	if authorDate, err := time.Parse(gerritDateLayout, commit.Author.Date); err == nil {
+		runevent.SHAAuthorDate = authorDate
+	}
+	runevent.SHACommitterName = commit.Committer.Name
+	runevent.SHACommitterEmail = commit.Committer.Email
+	if committerDate, err := time.Parse(gerritDateLayout, commit.Committer.Date); err == nil {
+		runevent.SHACommitterDate = committerDate
+	}
+	runevent.HasSkipCommand = provider.SkipCI(commit.Message)
+
+	if runevent.DefaultBranch == "" {
+		_, data, err := v.request(ctx, http.MethodGet, fmt.Sprintf("/projects/%s/HEAD", projectPath(v.project)), nil, nil)
+		if err != nil {
+			return err
+		}
+		head := ""
+		if err := json.Unmarshal(data, &head); err != nil {
+			return fmt.Errorf("cannot decode the HEAD of project %s: %w", v.project, err)
+		}
+		runevent.DefaultBranch = strings.TrimPrefix(head, "refs/heads/")
+	}
+	return nil
+}
+
+// GetFiles gets and caches the list of files changed by a given event.
+func (v *Provider) GetFiles(ctx context.Context, runevent *info.Event) (changedfiles.ChangedFiles, error) {
+	if v.cachedChangedFiles == nil {
+		changes, err := v.fetchChangedFiles(ctx, runevent)
+		if err != nil {
+			return changedfiles.ChangedFiles{}, err
+		}
+		v.cachedChangedFiles = &changes
+	}
+	return *v.cachedChangedFiles, nil
+}
+
+func (v *Provider) fetchChangedFiles(ctx context.Context, runevent *info.Event) (changedfiles.ChangedFiles, error) {
+	if v.httpClient == nil {
+		return changedfiles.ChangedFiles{}, fmt.Errorf("%s", noClientErrStr)
+	}
+
+	var filesPath string
+	//nolint:exhaustive // we don't need to handle all cases
+	switch runevent.TriggerTarget {
+	case triggertype.PullRequest, triggertype.PullRequestClosed:
+		filesPath = fmt.Sprintf("/changes/%s/revisions/%s/files", changeID(v.project, runevent.PullRequestNumber), runevent.SHA)
+	case triggertype.Push:
+		filesPath = fmt.Sprintf("/projects/%s/commits/%s/files/", projectPath(v.project), runevent.SHA)
+	default:
+		return changedfiles.ChangedFiles{}, nil
+	}
+
+	files := map[string]types.FileInfo{}
+	if _, err := v.requestJSON(ctx, http.MethodGet, filesPath, nil, nil, &files); err != nil {
+		return changedfiles.ChangedFiles{}, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for fpath := range files {
+		// magic files Gerrit adds for the commit message and merge commits
+		if strings.HasPrefix(fpath, "/") {
+			continue
+		}
+		paths = append(paths, fpath)
+	}
+	sort.Strings(paths)
+
+	changedFiles := changedfiles.ChangedFiles{}
+	for _, fpath := range paths {
+		file := files[fpath]
+		changedFiles.All = append(changedFiles.All, fpath)
+		switch file.Status {
+		case "A", "C":
+			changedFiles.Added = append(changedFiles.Added, fpath)
+		case "D":
+			changedFiles.Deleted = append(changedFiles.Deleted, fpath)
+		case "R":
+			changedFiles.Renamed = append(changedFiles.Renamed, fpath)
+		default:
+			changedFiles.Modified = append(changedFiles.Modified, fpath)
+		}
+	}
+	return changedFiles, nil
+}
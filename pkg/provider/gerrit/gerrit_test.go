@@ -0,0 +1,445 @@
+package gerrit
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/changedfiles"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	gerrittest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit/test"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit/types"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGetTektonDir(t *testing.T) {
+	tests := []struct {
+		name            string
+		event           *info.Event
+		path            string
+		testDirPath     string
+		contentContains string
+		wantDirAPIErr   bool
+		wantFilesAPIErr bool
+		wantErr         string
+	}{
+		{
+			name:            "good/get tekton directory",
+			event:           gerrittest.MakeEvent(nil),
+			path:            ".tekton",
+			testDirPath:     "../../pipelineascode/testdata/pull_request/.tekton",
+			contentContains: "kind: PipelineRun",
+		},
+		{
+			name:            "good/no yaml files in there",
+			event:           gerrittest.MakeEvent(nil),
+			path:            ".tekton",
+			testDirPath:     "./",
+			contentContains: "",
+		},
+		{
+			name:            "good/no tekton directory",
+			event:           gerrittest.MakeEvent(nil),
+			path:            ".notthere",
+			testDirPath:     "../../pipelineascode/testdata/pull_request/.tekton",
+			contentContains: "",
+		},
+		{
+			name:        "bad/badly formatted yaml",
+			event:       gerrittest.MakeEvent(nil),
+			path:        ".tekton",
+			testDirPath: "../../pipelineascode/testdata/bad_yaml/.tekton",
+			wantErr:     "error unmarshalling yaml file .tekton/badyaml.yaml: yaml: line 2: did not find expected key",
+		},
+		{
+			name:          "bad/get dir api error",
+			event:         gerrittest.MakeEvent(nil),
+			path:          ".tekton",
+			testDirPath:   "../../pipelineascode/testdata/pull_request/.tekton",
+			wantDirAPIErr: true,
+			wantErr:       "failed to list .tekton dir",
+		},
+		{
+			name:            "bad/get files api error",
+			event:           gerrittest.MakeEvent(nil),
+			path:            ".tekton",
+			testDirPath:     "../../pipelineascode/testdata/pull_request/.tekton",
+			wantFilesAPIErr: true,
+			wantErr:         "cannot find .tekton/pipeline.yaml inside the project repository",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, apiURL, tearDown := gerrittest.Setup(t)
+			defer tearDown()
+			v := &Provider{Logger: logger, apiURL: apiURL, httpClient: client, project: "project"}
+			gerrittest.MuxDirContent(t, mux, tt.event, tt.testDirPath, ".tekton", tt.wantDirAPIErr, tt.wantFilesAPIErr)
+			content, err := v.GetTektonDir(ctx, tt.event, tt.path, "")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(content, tt.contentContains), "content %s doesn't have %s", content, tt.contentContains)
+		})
+	}
+}
+
+func TestCreateStatus(t *testing.T) {
+	pacopts := &info.PacOpts{
+		Settings: settings.Settings{
+			ApplicationName: "HELLO APP",
+		},
+	}
+	vote := func(value int) map[string]int { return map[string]int{verifiedLabel: value} }
+
+	tests := []struct {
+		name              string
+		status            provider.StatusOpts
+		pullRequestNumber int
+		expectedReview    *types.ReviewInput
+		wantReview        bool
+		nilClient         bool
+		wantErr           string
+	}{
+		{
+			name:              "bad/no client",
+			nilClient:         true,
+			pullRequestNumber: 1,
+			wantErr:           noClientErrStr,
+		},
+		{
+			name: "good/push are not reported",
+			status: provider.StatusOpts{
+				Conclusion: "success",
+			},
+			wantReview: false,
+		},
+		{
+			name: "good/in progress resets the vote",
+			status: provider.StatusOpts{
+				Status:                  "in_progress",
+				Conclusion:              "pending",
+				OriginalPipelineRunName: "pr",
+				DetailsURL:              "https://console/pr",
+			},
+			pullRequestNumber: 1,
+			expectedReview: &types.ReviewInput{
+				Labels:  vote(0),
+				Message: "HELLO APP/pr: CI has started\n\nFull log available here: https://console/pr",
+			},
+			wantReview: true,
+		},
+		{
+			name: "good/success vote",
+			status: provider.StatusOpts{
+				Status:     "completed",
+				Conclusion: "success",
+				Text:       "Everything is fine",
+			},
+			pullRequestNumber: 1,
+			expectedReview: &types.ReviewInput{
+				Labels:  vote(1),
+				Message: "HELLO APP: Successfully validated your commit\n\nEverything is fine",
+			},
+			wantReview: true,
+		},
+		{
+			name: "good/failure vote",
+			status: provider.StatusOpts{
+				Status:     "completed",
+				Conclusion: "failure",
+				Text:       "Oh no it failed",
+			},
+			pullRequestNumber: 1,
+			expectedReview: &types.ReviewInput{
+				Labels:  vote(-1),
+				Message: "Oh no it failed",
+			},
+			wantReview: true,
+		},
+		{
+			name: "good/pending without text is not reported",
+			status: provider.StatusOpts{
+				Conclusion: "pending",
+			},
+			pullRequestNumber: 1,
+			wantReview:        false,
+		},
+		{
+			name: "good/pending with text has no vote",
+			status: provider.StatusOpts{
+				Conclusion:   "pending",
+				AccessDenied: true,
+				Text:         "waiting for /ok-to-test",
+			},
+			pullRequestNumber: 1,
+			expectedReview: &types.ReviewInput{
+				Message: "waiting for /ok-to-test",
+			},
+			wantReview: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, apiURL, tearDown := gerrittest.Setup(t)
+			defer tearDown()
+			event := gerrittest.MakeEvent(&info.Event{PullRequestNumber: tt.pullRequestNumber})
+			v := &Provider{Logger: logger, apiURL: apiURL, httpClient: client, project: "project", pacInfo: pacopts}
+			if tt.nilClient {
+				v.httpClient = nil
+			}
+			called := false
+			gerrittest.MuxReview(t, mux, event, tt.expectedReview, &called)
+
+			err := v.CreateStatus(ctx, event, tt.status)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, called, tt.wantReview)
+		})
+	}
+}
+
+func TestCreateComment(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client, mux, apiURL, tearDown := gerrittest.Setup(t)
+	defer tearDown()
+
+	v := &Provider{apiURL: apiURL, httpClient: client, project: "project"}
+	err := v.CreateComment(ctx, gerrittest.MakeEvent(nil), "Hello", "")
+	assert.ErrorContains(t, err, "create comment only works on changes")
+
+	event := gerrittest.MakeEvent(&info.Event{PullRequestNumber: 1})
+	called := false
+	gerrittest.MuxReview(t, mux, event, &types.ReviewInput{Message: "New Comment"}, &called)
+	assert.NilError(t, v.CreateComment(ctx, event, "New Comment", "MARKER"))
+	assert.Assert(t, called)
+}
+
+func TestGetCommitInfo(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client, mux, apiURL, tearDown := gerrittest.Setup(t)
+	defer tearDown()
+	event := gerrittest.MakeEvent(nil)
+	event.DefaultBranch = ""
+	gerrittest.MuxCommit(t, mux, event, types.CommitInfo{
+		Commit:  event.SHA,
+		Subject: "Hello World",
+		Message: "Hello World\n\nThis is the body [skip ci]\n\nChange-Id: I1234\n",
+		Author: types.GitPersonInfo{
+			Name:  "Author",
+			Email: "author@example.com",
+			Date:  "2024-01-02 03:04:05.000000000",
+		},
+		Committer: types.GitPersonInfo{
+			Name:  "Committer",
+			Email: "committer@example.com",
+			Date:  "2024-01-02 03:04:06.000000000",
+		},
+	})
+	gerrittest.MuxHead(t, mux, event, "main")
+
+	v := &Provider{apiURL: apiURL, httpClient: client, project: "project"}
+	assert.NilError(t, v.GetCommitInfo(ctx, event))
+	assert.Equal(t, event.SHATitle, "Hello World")
+	assert.Equal(t, event.SHAURL, apiURL+"/plugins/gitiles/project/+/"+event.SHA)
+	assert.Equal(t, event.SHAAuthorName, "Author")
+	assert.Equal(t, event.SHACommitterEmail, "committer@example.com")
+	assert.Equal(t, event.SHAAuthorDate.Year(), 2024)
+	assert.Equal(t, event.DefaultBranch, "main")
+	assert.Assert(t, event.HasSkipCommand)
+}
+
+func TestGetFiles(t *testing.T) {
+	files := map[string]types.FileInfo{
+		"/COMMIT_MSG":  {Status: "A"},
+		"added.go":     {Status: "A"},
+		"modified.go":  {},
+		"deleted.go":   {Status: "D"},
+		"renamed.go":   {Status: "R", OldPath: "old.go"},
+		"rewritten.go": {Status: "W"},
+	}
+	want := changedfiles.ChangedFiles{
+		All:      []string{"added.go", "deleted.go", "modified.go", "renamed.go", "rewritten.go"},
+		Added:    []string{"added.go"},
+		Modified: []string{"modified.go", "rewritten.go"},
+		Deleted:  []string{"deleted.go"},
+		Renamed:  []string{"renamed.go"},
+	}
+	tests := []struct {
+		name          string
+		triggerTarget triggertype.Trigger
+	}{
+		{
+			name:          "change",
+			triggerTarget: triggertype.PullRequest,
+		},
+		{
+			name:          "push",
+			triggerTarget: triggertype.Push,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, apiURL, tearDown := gerrittest.Setup(t)
+			defer tearDown()
+			event := gerrittest.MakeEvent(&info.Event{PullRequestNumber: 5, TriggerTarget: tt.triggerTarget})
+			gerrittest.MuxChangeFiles(t, mux, event, files)
+			gerrittest.MuxCommitFiles(t, mux, event, files)
+
+			v := &Provider{apiURL: apiURL, httpClient: client, project: "project"}
+			got, err := v.GetFiles(ctx, event)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, want)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		webhookSecret string
+		password      string
+		noAuth        bool
+		wantErr       string
+	}{
+		{
+			name:          "good/password match",
+			webhookSecret: "secret",
+			password:      "secret",
+		},
+		{
+			name:          "bad/password mismatch",
+			webhookSecret: "secret",
+			password:      "notsecret",
+			wantErr:       "password does not match configured secret",
+		},
+		{
+			name:          "bad/no basic auth",
+			webhookSecret: "secret",
+			noAuth:        true,
+			wantErr:       "no basic authentication detected",
+		},
+		{
+			name:    "bad/no webhook secret",
+			wantErr: "no webhook secret configured",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			req, err := http.NewRequest(http.MethodPost, "/", nil)
+			assert.NilError(t, err)
+			if !tt.noAuth {
+				req.SetBasicAuth("gerrit", tt.password)
+			}
+			event := info.NewEvent()
+			event.Provider = &info.Provider{WebhookSecret: tt.webhookSecret}
+			event.Request = &info.Request{Header: req.Header}
+
+			v := &Provider{}
+			err = v.Validate(ctx, nil, event)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestSetClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       *info.Event
+		apiURL      string
+		wantAPIURL  string
+		wantProject string
+		wantOrg     string
+		wantRepo    string
+		wantErr     string
+	}{
+		{
+			name:    "bad/no token",
+			event:   gerrittest.MakeEvent(&info.Event{Provider: &info.Provider{User: "bot"}}),
+			wantErr: "no git_provider.secret has been set in the repo crd",
+		},
+		{
+			name:    "bad/no user",
+			event:   gerrittest.MakeEvent(&info.Event{Provider: &info.Provider{Token: "token"}}),
+			wantErr: "no git_provider.user has been set in the repo crd",
+		},
+		{
+			name: "good/guess gerrit url",
+			event: gerrittest.MakeEvent(&info.Event{
+				Provider: &info.Provider{User: "bot", Token: "token"},
+				URL:      "https://gerrit.example.com/org/repo",
+			}),
+			wantAPIURL:  "https://gerrit.example.com",
+			wantProject: "org/repo",
+			wantOrg:     "org",
+			wantRepo:    "repo",
+		},
+		{
+			name: "good/gerrit url from the repository",
+			event: gerrittest.MakeEvent(&info.Event{
+				Provider: &info.Provider{User: "bot", Token: "token", URL: "https://example.com/gerrit/"},
+				URL:      "https://example.com/gerrit/repo",
+			}),
+			wantAPIURL:  "https://example.com/gerrit",
+			wantProject: "repo",
+			wantOrg:     "repo",
+			wantRepo:    "repo",
+		},
+		{
+			name: "good/gerrit url from the payload",
+			event: gerrittest.MakeEvent(&info.Event{
+				Provider: &info.Provider{User: "bot", Token: "token"},
+				URL:      "https://example.com/gerrit/org/sub/repo",
+			}),
+			apiURL:      "https://example.com/gerrit",
+			wantAPIURL:  "https://example.com/gerrit",
+			wantProject: "org/sub/repo",
+			wantOrg:     "org/sub",
+			wantRepo:    "repo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			run := &params.Run{Clients: clients.Clients{Log: logger}}
+			v := &Provider{apiURL: tt.apiURL}
+			err := v.SetClient(ctx, run, tt.event, nil, nil)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, v.apiURL, tt.wantAPIURL)
+			assert.Equal(t, v.project, tt.wantProject)
+			assert.Equal(t, tt.event.Organization, tt.wantOrg)
+			assert.Equal(t, tt.event.Repository, tt.wantRepo)
+			assert.Assert(t, v.httpClient != nil)
+		})
+	}
+}
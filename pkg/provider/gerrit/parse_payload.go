@@ -0,0 +1,165 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit/types"
+)
+
+// gerritURLQueryParam is the query parameter of the webhook URL where the
+// user can set the Gerrit URL, the ref-updated events don't have any URL
+// we could get it from.
+const gerritURLQueryParam = "gerrit_url"
+
+// ParsePayload parses the stream event payload into an event.
+func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.Request, payload string) (*info.Event, error) {
+	envelope := &types.Event{}
+	if err := json.Unmarshal([]byte(payload), envelope); err != nil {
+		return nil, err
+	}
+
+	eventInt, err := parsePayloadType(envelope.Type, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	processedEvent := info.NewEvent()
+	processedEvent.Event = eventInt
+
+	var project, changeURL string
+	switch gitEvent := eventInt.(type) {
+	case *types.PatchSetCreatedEvent:
+		project, changeURL = gitEvent.Change.Project, gitEvent.Change.URL
+		v.fillChange(processedEvent, &gitEvent.Change, &gitEvent.PatchSet)
+		processedEvent.Sender = accountName(gitEvent.Uploader)
+		processedEvent.TriggerTarget = triggertype.PullRequest
+		processedEvent.EventType = triggertype.PullRequest.String()
+	case *types.CommentAddedEvent:
+		project, changeURL = gitEvent.Change.Project, gitEvent.Change.URL
+		v.fillChange(processedEvent, &gitEvent.Change, &gitEvent.PatchSet)
+		processedEvent.Sender = accountName(gitEvent.Author)
+		processedEvent.TriggerTarget = triggertype.PullRequest
+		opscomments.SetEventTypeAndTargetPR(processedEvent, commentBody(gitEvent.Comment))
+	case *types.ChangeMergedEvent:
+		project, changeURL = gitEvent.Change.Project, gitEvent.Change.URL
+		v.fillChange(processedEvent, &gitEvent.Change, &gitEvent.PatchSet)
+		processedEvent.Sender = accountName(gitEvent.Submitter)
+		processedEvent.TriggerTarget = triggertype.PullRequestClosed
+		processedEvent.PullRequestMerged = true
+		processedEvent.EventType = triggertype.PullRequest.String()
+	case *types.ChangeAbandonedEvent:
+		project, changeURL = gitEvent.Change.Project, gitEvent.Change.URL
+		v.fillChange(processedEvent, &gitEvent.Change, &gitEvent.PatchSet)
+		processedEvent.Sender = accountName(gitEvent.Abandoner)
+		processedEvent.TriggerTarget = triggertype.PullRequestClosed
+		processedEvent.EventType = triggertype.PullRequest.String()
+	case *types.RefUpdatedEvent:
+		project = gitEvent.RefUpdate.Project
+		processedEvent.Sender = accountName(gitEvent.Submitter)
+		processedEvent.SHA = gitEvent.RefUpdate.NewRev
+		processedEvent.HeadBranch = gitEvent.RefUpdate.RefName
+		processedEvent.BaseBranch = gitEvent.RefUpdate.RefName
+		processedEvent.TriggerTarget = triggertype.Push
+		processedEvent.EventType = triggertype.Push.String()
+	default:
+		return nil, fmt.Errorf("event %s is not supported", envelope.Type)
+	}
+
+	gerritURL, err := guessGerritURL(request, changeURL)
+	if err != nil {
+		return nil, err
+	}
+	if gerritURL == "" {
+		return nil, fmt.Errorf("cannot detect the gerrit url of the %s event, add the %s query parameter to the webhook url", envelope.Type, gerritURLQueryParam)
+	}
+
+	processedEvent.URL = fmt.Sprintf("%s/%s", gerritURL, project)
+	processedEvent.BaseURL = processedEvent.URL
+	processedEvent.HeadURL = processedEvent.URL
+	processedEvent.Organization, processedEvent.Repository = splitProject(project)
+	if processedEvent.SHAURL == "" {
+		processedEvent.SHAURL = fmt.Sprintf("%s/plugins/gitiles/%s/+/%s", gerritURL, project, processedEvent.SHA)
+	}
+
+	v.apiURL = gerritURL
+	v.project = project
+	return processedEvent, nil
+}
+
+func (v *Provider) fillChange(processedEvent *info.Event, change *types.Change, patchSet *types.PatchSet) {
+	processedEvent.SHA = patchSet.Revision
+	processedEvent.SHATitle = change.Subject
+	processedEvent.SHAMessage = change.CommitMessage
+	if change.URL != "" {
+		processedEvent.SHAURL = fmt.Sprintf("%s/%d", strings.TrimSuffix(change.URL, "/"), patchSet.Number)
+	}
+	// Gerrit doesn't have source branches, the patchset ref is what can be
+	// fetched to get the change.
+	processedEvent.HeadBranch = patchSet.Ref
+	processedEvent.BaseBranch = change.Branch
+	processedEvent.PullRequestNumber = change.Number
+	processedEvent.PullRequestTitle = change.Subject
+	processedEvent.PullRequestLabel = append(processedEvent.PullRequestLabel, change.Hashtags...)
+	v.changeNumber = change.Number
+}
+
+// guessGerritURL returns the URL of the Gerrit instance, from the query
+// parameter of the webhook URL or from the change URL, which looks like
+// https://gerrit.example.com/c/project/+/123.
+func guessGerritURL(request *http.Request, changeURL string) (string, error) {
+	if request != nil && request.URL != nil {
+		if gerritURL := request.URL.Query().Get(gerritURLQueryParam); gerritURL != "" {
+			if _, err := url.Parse(gerritURL); err != nil {
+				return "", err
+			}
+			return strings.TrimSuffix(gerritURL, "/"), nil
+		}
+	}
+	if changeURL == "" {
+		return "", nil
+	}
+	u, err := url.Parse(changeURL)
+	if err != nil {
+		return "", err
+	}
+	prefix := u.Path
+	if idx := strings.Index(u.Path, "/c/"); idx != -1 {
+		prefix = u.Path[:idx]
+	} else {
+		// old style change URL, ie: https://gerrit.example.com/123
+		prefix = path.Dir(strings.TrimSuffix(u.Path, "/"))
+	}
+	u.Path = strings.TrimSuffix(prefix, "/")
+	u.RawQuery, u.Fragment = "", ""
+	return u.String(), nil
+}
+
+// splitProject splits a Gerrit project name in a organization and a
+// repository, a project at the top level is its own organization.
+func splitProject(project string) (string, string) {
+	organization, repository := path.Split(project)
+	organization = strings.TrimSuffix(organization, "/")
+	if organization == "" {
+		organization = repository
+	}
+	return organization, repository
+}
+
+// accountName returns the username of the account, or the email when the
+// account has no username.
+func accountName(account types.Account) string {
+	if account.Username != "" {
+		return account.Username
+	}
+	return account.Email
+}
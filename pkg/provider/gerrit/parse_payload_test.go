@@ -0,0 +1,330 @@
+package gerrit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit/types"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestParsePayload(t *testing.T) {
+	envelope := func(eventType string) types.Event {
+		return types.Event{Type: eventType, EventCreatedOn: 1700000000}
+	}
+	change := types.Change{
+		Project:       "org/repo",
+		Branch:        "main",
+		Number:        42,
+		Subject:       "My change",
+		CommitMessage: "My change\n\nChange-Id: I1234\n",
+		URL:           "https://gerrit.example.com/c/org/repo/+/42",
+		Status:        changeStatusNew,
+		Hashtags:      []string{"bug"},
+	}
+	patchSet := types.PatchSet{Number: 3, Revision: "sha", Ref: "refs/changes/42/42/3"}
+	changeSHAURL := "https://gerrit.example.com/c/org/repo/+/42/3"
+
+	tests := []struct {
+		name            string
+		payload         any
+		rawStr          string
+		gerritURL       string
+		wantErrSubstr   string
+		wantTrigger     triggertype.Trigger
+		wantEventType   string
+		wantSHA         string
+		wantSHAURL      string
+		wantHeadBranch  string
+		wantBaseBranch  string
+		wantSender      string
+		wantPRNumber    int
+		wantLabels      []string
+		wantTargetPRRun string
+		wantSHAMessage  string
+		wantMerged      bool
+	}{
+		{
+			name:          "bad/bad json",
+			rawStr:        "rageAgainst",
+			wantErrSubstr: "invalid character",
+		},
+		{
+			name:          "bad/unsupported event",
+			payload:       envelope("reviewer-added"),
+			wantErrSubstr: "event \"reviewer-added\" is not supported",
+		},
+		{
+			name: "bad/ref updated without a gerrit url",
+			payload: types.RefUpdatedEvent{
+				Event:     envelope(eventTypeRefUpdated),
+				RefUpdate: types.RefUpdate{Project: "org/repo", RefName: "refs/heads/main", NewRev: "sha"},
+			},
+			wantErrSubstr: "add the gerrit_url query parameter to the webhook url",
+		},
+		{
+			name: "good/ref updated",
+			payload: types.RefUpdatedEvent{
+				Event:     envelope(eventTypeRefUpdated),
+				Submitter: types.Account{Username: "pusher"},
+				RefUpdate: types.RefUpdate{Project: "org/repo", RefName: "refs/heads/main", NewRev: "sha"},
+			},
+			gerritURL:      "https://gerrit.example.com/",
+			wantTrigger:    triggertype.Push,
+			wantEventType:  triggertype.Push.String(),
+			wantSHA:        "sha",
+			wantSHAURL:     "https://gerrit.example.com/plugins/gitiles/org/repo/+/sha",
+			wantHeadBranch: "refs/heads/main",
+			wantBaseBranch: "refs/heads/main",
+			wantSender:     "pusher",
+		},
+		{
+			name: "good/patchset created",
+			payload: types.PatchSetCreatedEvent{
+				Event:    envelope(eventTypePatchSetCreated),
+				Change:   change,
+				PatchSet: patchSet,
+				Uploader: types.Account{Username: "uploader"},
+			},
+			wantTrigger:    triggertype.PullRequest,
+			wantEventType:  triggertype.PullRequest.String(),
+			wantSHA:        "sha",
+			wantSHAURL:     changeSHAURL,
+			wantHeadBranch: "refs/changes/42/42/3",
+			wantBaseBranch: "main",
+			wantSender:     "uploader",
+			wantPRNumber:   42,
+			wantLabels:     []string{"bug"},
+			wantSHAMessage: "My change\n\nChange-Id: I1234\n",
+		},
+		{
+			name: "good/patchset created by an account without username",
+			payload: types.PatchSetCreatedEvent{
+				Event:    envelope(eventTypePatchSetCreated),
+				Change:   change,
+				PatchSet: patchSet,
+				Uploader: types.Account{Email: "uploader@example.com"},
+			},
+			wantTrigger:    triggertype.PullRequest,
+			wantEventType:  triggertype.PullRequest.String(),
+			wantSHA:        "sha",
+			wantSHAURL:     changeSHAURL,
+			wantHeadBranch: "refs/changes/42/42/3",
+			wantBaseBranch: "main",
+			wantSender:     "uploader@example.com",
+			wantPRNumber:   42,
+			wantLabels:     []string{"bug"},
+			wantSHAMessage: "My change\n\nChange-Id: I1234\n",
+		},
+		{
+			name: "good/change merged",
+			payload: types.ChangeMergedEvent{
+				Event:     envelope(eventTypeChangeMerged),
+				Change:    change,
+				PatchSet:  patchSet,
+				Submitter: types.Account{Username: "submitter"},
+			},
+			wantMerged:     true,
+			wantTrigger:    triggertype.PullRequestClosed,
+			wantEventType:  triggertype.PullRequest.String(),
+			wantSHA:        "sha",
+			wantSHAURL:     changeSHAURL,
+			wantHeadBranch: "refs/changes/42/42/3",
+			wantBaseBranch: "main",
+			wantSender:     "submitter",
+			wantPRNumber:   42,
+			wantLabels:     []string{"bug"},
+			wantSHAMessage: "My change\n\nChange-Id: I1234\n",
+		},
+		{
+			name: "good/change abandoned",
+			payload: types.ChangeAbandonedEvent{
+				Event:     envelope(eventTypeChangeAbandoned),
+				Change:    change,
+				PatchSet:  patchSet,
+				Abandoner: types.Account{Username: "abandoner"},
+			},
+			wantTrigger:    triggertype.PullRequestClosed,
+			wantEventType:  triggertype.PullRequest.String(),
+			wantSHA:        "sha",
+			wantSHAURL:     changeSHAURL,
+			wantHeadBranch: "refs/changes/42/42/3",
+			wantBaseBranch: "main",
+			wantSender:     "abandoner",
+			wantPRNumber:   42,
+			wantLabels:     []string{"bug"},
+			wantSHAMessage: "My change\n\nChange-Id: I1234\n",
+		},
+		{
+			name: "good/comment retest",
+			payload: types.CommentAddedEvent{
+				Event:    envelope(eventTypeCommentAdded),
+				Change:   change,
+				PatchSet: patchSet,
+				Author:   types.Account{Username: "commenter"},
+				Comment:  "Patch Set 3:\n\n/retest",
+			},
+			wantTrigger:    triggertype.PullRequest,
+			wantEventType:  opscomments.RetestAllCommentEventType.String(),
+			wantSHA:        "sha",
+			wantSHAURL:     changeSHAURL,
+			wantHeadBranch: "refs/changes/42/42/3",
+			wantBaseBranch: "main",
+			wantSender:     "commenter",
+			wantPRNumber:   42,
+			wantLabels:     []string{"bug"},
+			wantSHAMessage: "My change\n\nChange-Id: I1234\n",
+		},
+		{
+			name: "good/comment test a pipelinerun",
+			payload: types.CommentAddedEvent{
+				Event:    envelope(eventTypeCommentAdded),
+				Change:   change,
+				PatchSet: patchSet,
+				Author:   types.Account{Username: "commenter"},
+				Comment:  "Patch Set 3: Code-Review+1\n\n/test dummy",
+			},
+			wantTrigger:     triggertype.PullRequest,
+			wantEventType:   opscomments.TestSingleCommentEventType.String(),
+			wantSHA:         "sha",
+			wantSHAURL:      changeSHAURL,
+			wantHeadBranch:  "refs/changes/42/42/3",
+			wantBaseBranch:  "main",
+			wantSender:      "commenter",
+			wantPRNumber:    42,
+			wantLabels:      []string{"bug"},
+			wantTargetPRRun: "dummy",
+			wantSHAMessage:  "My change\n\nChange-Id: I1234\n",
+		},
+		{
+			name: "good/comment cancel",
+			payload: types.CommentAddedEvent{
+				Event:    envelope(eventTypeCommentAdded),
+				Change:   change,
+				PatchSet: patchSet,
+				Author:   types.Account{Username: "commenter"},
+				Comment:  "Patch Set 3:\n\n/cancel",
+			},
+			wantTrigger:    triggertype.PullRequest,
+			wantEventType:  opscomments.CancelCommentAllEventType.String(),
+			wantSHA:        "sha",
+			wantSHAURL:     changeSHAURL,
+			wantHeadBranch: "refs/changes/42/42/3",
+			wantBaseBranch: "main",
+			wantSender:     "commenter",
+			wantPRNumber:   42,
+			wantLabels:     []string{"bug"},
+			wantSHAMessage: "My change\n\nChange-Id: I1234\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			payload := tt.rawStr
+			if payload == "" {
+				b, err := json.Marshal(tt.payload)
+				assert.NilError(t, err)
+				payload = string(b)
+			}
+			req := &http.Request{URL: &url.URL{Path: "/"}}
+			if tt.gerritURL != "" {
+				req.URL.RawQuery = url.Values{gerritURLQueryParam: []string{tt.gerritURL}}.Encode()
+			}
+
+			v := &Provider{}
+			got, err := v.ParsePayload(ctx, &params.Run{}, req, payload)
+			if tt.wantErrSubstr != "" {
+				assert.ErrorContains(t, err, tt.wantErrSubstr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got.TriggerTarget, tt.wantTrigger)
+			assert.Equal(t, got.PullRequestMerged, tt.wantMerged)
+			assert.Equal(t, got.EventType, tt.wantEventType)
+			assert.Equal(t, got.SHA, tt.wantSHA)
+			assert.Equal(t, got.SHAURL, tt.wantSHAURL)
+			assert.Equal(t, got.HeadBranch, tt.wantHeadBranch)
+			assert.Equal(t, got.BaseBranch, tt.wantBaseBranch)
+			assert.Equal(t, got.Sender, tt.wantSender)
+			assert.Equal(t, got.PullRequestNumber, tt.wantPRNumber)
+			assert.DeepEqual(t, got.PullRequestLabel, tt.wantLabels)
+			assert.Equal(t, got.TargetTestPipelineRun, tt.wantTargetPRRun)
+			assert.Equal(t, got.SHAMessage, tt.wantSHAMessage)
+			assert.Equal(t, got.URL, "https://gerrit.example.com/org/repo")
+			assert.Equal(t, got.HeadURL, got.URL)
+			assert.Equal(t, got.Organization, "org")
+			assert.Equal(t, got.Repository, "repo")
+
+			assert.Equal(t, v.apiURL, "https://gerrit.example.com")
+			assert.Equal(t, v.project, "org/repo")
+			assert.Equal(t, v.changeNumber, tt.wantPRNumber)
+		})
+	}
+}
+
+func TestGuessGerritURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		changeURL string
+		want      string
+	}{
+		{
+			name:  "from the query parameter",
+			query: "gerrit_url=https://example.com/gerrit/",
+			want:  "https://example.com/gerrit",
+		},
+		{
+			name:      "from the change url",
+			changeURL: "https://gerrit.example.com/c/org/repo/+/42",
+			want:      "https://gerrit.example.com",
+		},
+		{
+			name:      "from the change url with a path prefix",
+			changeURL: "https://example.com/gerrit/c/org/repo/+/42",
+			want:      "https://example.com/gerrit",
+		},
+		{
+			name:      "from an old style change url",
+			changeURL: "https://gerrit.example.com/42",
+			want:      "https://gerrit.example.com",
+		},
+		{
+			name: "nothing to guess from",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &http.Request{URL: &url.URL{Path: "/", RawQuery: tt.query}}
+			got, err := guessGerritURL(req, tt.changeURL)
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestSplitProject(t *testing.T) {
+	tests := []struct {
+		project  string
+		wantOrg  string
+		wantRepo string
+	}{
+		{project: "repo", wantOrg: "repo", wantRepo: "repo"},
+		{project: "org/repo", wantOrg: "org", wantRepo: "repo"},
+		{project: "org/sub/repo", wantOrg: "org/sub", wantRepo: "repo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.project, func(t *testing.T) {
+			org, repo := splitProject(tt.project)
+			assert.Equal(t, org, tt.wantOrg)
+			assert.Equal(t, repo, tt.wantRepo)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit/types"
+	"gotest.tools/v3/assert"
+)
+
+var defaultPrefix = "/gerrit"
+
+// Setup starts a http server standing in for Gerrit, it returns the client,
+// the mux to register the authenticated API handlers on, the Gerrit URL and a
+// function to tear it down.
+func Setup(t *testing.T) (*http.Client, *http.ServeMux, string, func()) {
+	t.Helper()
+	mux := http.NewServeMux()
+	apiHandler := http.NewServeMux()
+	apiHandler.Handle(defaultPrefix+"/", http.StripPrefix(defaultPrefix, mux))
+	apiHandler.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintln(os.Stderr, "FAIL: Client.BaseURL path prefix is not preserved in the request URL:")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "\t"+req.URL.String())
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "\tDid you accidentally use an absolute endpoint URL rather than relative?")
+		http.Error(w, "Client.BaseURL path prefix is not preserved in the request URL.", http.StatusInternalServerError)
+	})
+
+	// server is a test HTTP server used to provide mock API responses.
+	server := httptest.NewServer(apiHandler)
+	tearDown := func() {
+		server.Close()
+	}
+	return server.Client(), mux, server.URL + defaultPrefix, tearDown
+}
+
+// writeJSON writes the JSON response with the XSSI prefix Gerrit adds to
+// every response.
+func writeJSON(t *testing.T, rw http.ResponseWriter, v any) {
+	t.Helper()
+	b, err := json.Marshal(v)
+	assert.NilError(t, err)
+	fmt.Fprintf(rw, ")]}'\n%s", string(b))
+}
+
+func project(event *info.Event) string {
+	if event.Organization == event.Repository {
+		return event.Repository
+	}
+	return event.Organization + "/" + event.Repository
+}
+
+// MuxReview checks the review we are setting on the patchset.
+func MuxReview(t *testing.T, mux *http.ServeMux, event *info.Event, expectedReview *types.ReviewInput, called *bool) {
+	t.Helper()
+	path := fmt.Sprintf("/a/changes/%s~%d/revisions/%s/review", url.PathEscape(project(event)), event.PullRequestNumber, event.SHA)
+	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		review := &types.ReviewInput{}
+		bit, _ := io.ReadAll(r.Body)
+		assert.NilError(t, json.Unmarshal(bit, review))
+		if expectedReview != nil {
+			assert.DeepEqual(t, review.Labels, expectedReview.Labels)
+			assert.Assert(t, strings.Contains(review.Message, expectedReview.Message), "message: %s doesn't have: %s",
+				review.Message, expectedReview.Message)
+		}
+		if called != nil {
+			*called = true
+		}
+		writeJSON(t, rw, map[string]any{})
+	})
+}
+
+// MuxFiles serves the content of files at a commit or a branch, an unknown
+// file returns a 404.
+func MuxFiles(t *testing.T, mux *http.ServeMux, event *info.Event, files map[string]string, wantErr bool) {
+	t.Helper()
+	prefix := fmt.Sprintf("/a/projects/%s/", url.PathEscape(project(event)))
+	mux.HandleFunc(prefix, func(rw http.ResponseWriter, r *http.Request) {
+		// /commits/{sha}/files/{file}/content or /branches/{branch}/files/{file}/content
+		parts := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), prefix), "/")
+		if len(parts) != 5 || parts[2] != "files" || parts[4] != "content" {
+			http.NotFound(rw, r)
+			return
+		}
+		fpath, err := url.PathUnescape(parts[3])
+		assert.NilError(t, err)
+		content, ok := files[fpath]
+		if wantErr || !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(rw, "File not found: %s", fpath)
+			return
+		}
+		fmt.Fprint(rw, base64.StdEncoding.EncodeToString([]byte(content)))
+	})
+}
+
+// MuxDirContent serves the yaml files of a local directory as the content of
+// dirName through gitiles and the REST API.
+func MuxDirContent(t *testing.T, mux *http.ServeMux, event *info.Event, testDir, dirName string, wantDirErr, wantFilesErr bool) {
+	t.Helper()
+	files, err := os.ReadDir(testDir)
+	if err != nil {
+		// no error just disappointed
+		return
+	}
+	tree := types.Tree{}
+	filecontents := map[string]string{}
+	for _, value := range files {
+		if value.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(testDir, value.Name()))
+		assert.NilError(t, err)
+		filecontents[filepath.Join(dirName, value.Name())] = string(content)
+		tree.Entries = append(tree.Entries, types.TreeEntry{Type: "blob", Name: value.Name()})
+	}
+
+	mux.HandleFunc(fmt.Sprintf("/a/plugins/gitiles/%s/+/%s/%s/", project(event), event.SHA, dirName), func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Query().Get("format"), "JSON")
+		if wantDirErr {
+			rw.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(rw, "Unauthorized")
+			return
+		}
+		writeJSON(t, rw, tree)
+	})
+	MuxFiles(t, mux, event, filecontents, wantFilesErr)
+}
+
+// MuxCommit serves the commit information.
+func MuxCommit(t *testing.T, mux *http.ServeMux, event *info.Event, commit types.CommitInfo) {
+	t.Helper()
+	mux.HandleFunc(fmt.Sprintf("/a/projects/%s/commits/%s", url.PathEscape(project(event)), commit.Commit), func(rw http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, rw, commit)
+	})
+}
+
+// MuxHead serves the default branch of the project.
+func MuxHead(t *testing.T, mux *http.ServeMux, event *info.Event, defaultBranch string) {
+	t.Helper()
+	mux.HandleFunc(fmt.Sprintf("/a/projects/%s/HEAD", url.PathEscape(project(event))), func(rw http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, rw, "refs/heads/"+defaultBranch)
+	})
+}
+
+// MuxChangeFiles serves the files modified by the patchset.
+func MuxChangeFiles(t *testing.T, mux *http.ServeMux, event *info.Event, files map[string]types.FileInfo) {
+	t.Helper()
+	path := fmt.Sprintf("/a/changes/%s~%d/revisions/%s/files", url.PathEscape(project(event)), event.PullRequestNumber, event.SHA)
+	mux.HandleFunc(path, func(rw http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, rw, files)
+	})
+}
+
+// MuxCommitFiles serves the files modified by a commit.
+func MuxCommitFiles(t *testing.T, mux *http.ServeMux, event *info.Event, files map[string]types.FileInfo) {
+	t.Helper()
+	mux.HandleFunc(fmt.Sprintf("/a/projects/%s/commits/%s/files/", url.PathEscape(project(event)), event.SHA), func(rw http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, rw, files)
+	})
+}
+
+// MuxCheckAccess allows the submit permission to the given users.
+func MuxCheckAccess(t *testing.T, mux *http.ServeMux, event *info.Event, allowed ...string) {
+	t.Helper()
+	mux.HandleFunc(fmt.Sprintf("/a/projects/%s/check.access", url.PathEscape(project(event))), func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Query().Get("perm"), "submit")
+		assert.Assert(t, strings.HasPrefix(r.URL.Query().Get("ref"), "refs/heads/"))
+		account := r.URL.Query().Get("account")
+		for _, user := range allowed {
+			if user == account {
+				writeJSON(t, rw, types.AccessCheckInfo{Status: http.StatusOK})
+				return
+			}
+		}
+		writeJSON(t, rw, types.AccessCheckInfo{Status: http.StatusForbidden, Message: "user " + account + " cannot submit"})
+	})
+}
+
+// MuxGroupMembers serves the members of a group.
+func MuxGroupMembers(t *testing.T, mux *http.ServeMux, group string, members ...string) {
+	t.Helper()
+	mux.HandleFunc(fmt.Sprintf("/a/groups/%s/members/", group), func(rw http.ResponseWriter, _ *http.Request) {
+		accounts := []types.AccountInfo{}
+		for i, member := range members {
+			accounts = append(accounts, types.AccountInfo{AccountID: i + 1000, Username: member})
+		}
+		writeJSON(t, rw, accounts)
+	})
+}
+
+// MuxMessages serves the messages of a change.
+func MuxMessages(t *testing.T, mux *http.ServeMux, event *info.Event, messages []types.ChangeMessageInfo) {
+	t.Helper()
+	path := fmt.Sprintf("/a/changes/%s~%d/messages", url.PathEscape(project(event)), event.PullRequestNumber)
+	mux.HandleFunc(path, func(rw http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, rw, messages)
+	})
+}
+
+// MakeEvent fills the event with some default values.
+func MakeEvent(event *info.Event) *info.Event {
+	if event == nil {
+		event = info.NewEvent()
+	}
+	rev := event
+	if event.Provider == nil {
+		rev.Provider = &info.Provider{}
+	}
+	if rev.HeadBranch == "" {
+		rev.HeadBranch = "refs/changes/01/1/1"
+	}
+	if rev.BaseBranch == "" {
+		rev.BaseBranch = "main"
+	}
+	if rev.SHA == "" {
+		rev.SHA = "1234"
+	}
+	if rev.Organization == "" {
+		rev.Organization = "project"
+	}
+	if rev.Repository == "" {
+		rev.Repository = "project"
+	}
+	if rev.DefaultBranch == "" {
+		rev.DefaultBranch = "main"
+	}
+	if rev.Sender == "" {
+		rev.Sender = "sender"
+	}
+	if rev.URL == "" {
+		rev.URL = "https://gerrit.example.com/" + project(rev)
+	}
+	return rev
+}
@@ -0,0 +1,172 @@
+//revive:disable-next-line:var-naming
+package types
+
+// Event is the common part of the Gerrit stream events, as sent by the
+// webhooks plugin.
+type Event struct {
+	Type           string `json:"type"`
+	EventCreatedOn int64  `json:"eventCreatedOn"`
+}
+
+// PatchSetCreatedEvent is sent when a new change or a new patchset of a change
+// has been uploaded.
+type PatchSetCreatedEvent struct {
+	Event
+	Change   Change   `json:"change"`
+	PatchSet PatchSet `json:"patchSet"`
+	Uploader Account  `json:"uploader"`
+}
+
+// CommentAddedEvent is sent when a review message has been posted on a change.
+type CommentAddedEvent struct {
+	Event
+	Change    Change     `json:"change"`
+	PatchSet  PatchSet   `json:"patchSet"`
+	Author    Account    `json:"author"`
+	Approvals []Approval `json:"approvals,omitempty"`
+	Comment   string     `json:"comment"`
+}
+
+// ChangeMergedEvent is sent when a change has been submitted.
+type ChangeMergedEvent struct {
+	Event
+	Change    Change   `json:"change"`
+	PatchSet  PatchSet `json:"patchSet"`
+	Submitter Account  `json:"submitter"`
+	NewRev    string   `json:"newRev,omitempty"`
+}
+
+// ChangeAbandonedEvent is sent when a change has been abandoned.
+type ChangeAbandonedEvent struct {
+	Event
+	Change    Change   `json:"change"`
+	PatchSet  PatchSet `json:"patchSet"`
+	Abandoner Account  `json:"abandoner"`
+	Reason    string   `json:"reason,omitempty"`
+}
+
+// RefUpdatedEvent is sent when a reference has been updated, either by a
+// direct push or by submitting a change.
+type RefUpdatedEvent struct {
+	Event
+	Submitter Account   `json:"submitter"`
+	RefUpdate RefUpdate `json:"refUpdate"`
+}
+
+type Account struct {
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+type Change struct {
+	Project       string   `json:"project"`
+	Branch        string   `json:"branch"`
+	Topic         string   `json:"topic,omitempty"`
+	ID            string   `json:"id"`
+	Number        int      `json:"number"`
+	Subject       string   `json:"subject"`
+	Owner         Account  `json:"owner"`
+	URL           string   `json:"url"`
+	CommitMessage string   `json:"commitMessage,omitempty"`
+	Status        string   `json:"status,omitempty"`
+	WIP           bool     `json:"wip,omitempty"`
+	Private       bool     `json:"private,omitempty"`
+	Hashtags      []string `json:"hashtags,omitempty"`
+}
+
+type PatchSet struct {
+	Number    int      `json:"number"`
+	Revision  string   `json:"revision"`
+	Parents   []string `json:"parents,omitempty"`
+	Ref       string   `json:"ref"`
+	Uploader  Account  `json:"uploader"`
+	Author    Account  `json:"author"`
+	CreatedOn int64    `json:"createdOn,omitempty"`
+	Kind      string   `json:"kind,omitempty"`
+}
+
+type Approval struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Value       string `json:"value"`
+	OldValue    string `json:"oldValue,omitempty"`
+}
+
+type RefUpdate struct {
+	OldRev  string `json:"oldRev"`
+	NewRev  string `json:"newRev"`
+	RefName string `json:"refName"`
+	Project string `json:"project"`
+}
+
+// ReviewInput is the body of the set review REST API.
+type ReviewInput struct {
+	Message string         `json:"message,omitempty"`
+	Tag     string         `json:"tag,omitempty"`
+	Labels  map[string]int `json:"labels,omitempty"`
+	// Notify is who should be notified by email, NONE, OWNER,
+	// OWNER_REVIEWERS or ALL.
+	Notify string `json:"notify,omitempty"`
+}
+
+type GitPersonInfo struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Date  string `json:"date"`
+}
+
+type CommitInfo struct {
+	Commit    string        `json:"commit"`
+	Author    GitPersonInfo `json:"author"`
+	Committer GitPersonInfo `json:"committer"`
+	Subject   string        `json:"subject"`
+	Message   string        `json:"message"`
+}
+
+type BranchInfo struct {
+	Ref      string `json:"ref"`
+	Revision string `json:"revision"`
+}
+
+// FileInfo is the information of a file of a revision, a missing status
+// means the file has been modified.
+type FileInfo struct {
+	Status  string `json:"status,omitempty"`
+	OldPath string `json:"old_path,omitempty"`
+	Binary  bool   `json:"binary,omitempty"`
+}
+
+type AccountInfo struct {
+	AccountID int    `json:"_account_id"`
+	Name      string `json:"name,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Username  string `json:"username,omitempty"`
+}
+
+type ChangeMessageInfo struct {
+	ID      string       `json:"id"`
+	Author  *AccountInfo `json:"author,omitempty"`
+	Message string       `json:"message"`
+	Date    string       `json:"date,omitempty"`
+}
+
+// AccessCheckInfo is the result of the project check.access REST API.
+type AccessCheckInfo struct {
+	Status  int    `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// TreeEntry is an entry of a Gitiles tree listing.
+type TreeEntry struct {
+	Mode int    `json:"mode"`
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Tree is a Gitiles tree listing.
+type Tree struct {
+	ID      string      `json:"id"`
+	Entries []TreeEntry `json:"entries"`
+}
@@ -114,8 +114,9 @@ func (v *Provider) aclAllowedOkToTestFromAnOwner(ctx context.Context, event *inf
 		revent.URL = event.Issue.URL
 	case *forgejostructs.PullRequestPayload:
 		// if we don't need to check old comments, then on push event we don't need
-		// to check anything for the non-allowed user
-		if !v.pacInfo.RememberOKToTest {
+		// to check anything for the non-allowed user, a review is only
+		// allowed from its reviewer.
+		if !v.pacInfo.RememberOKToTest || event.Action == forgejostructs.HookIssueReviewed {
 			return false, nil
 		}
 		revent.URL = event.PullRequest.HTMLURL
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
@@ -15,6 +16,9 @@ var (
 	pullRequestOpenSyncEvent = []string{"opened", "synchronize", "synchronized", "reopened"}
 	pullRequestLabelUpdated  = "label_updated"
 	pullRequestLabelClosed   = "closed"
+	// draftTitlePrefixes are the default prefixes of the title of a draft
+	// pull request on Gitea and Forgejo.
+	draftTitlePrefixes = []string{"wip:", "[wip]"}
 )
 
 // Detect processes event and detect if it is a gitea event, whether to process or reject it
@@ -47,6 +51,38 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 	return setLoggerAndProceed(false, errReason, nil)
 }
 
+// reviewState returns the state of the review of a pull request review
+// event, as named on GitHub, or an empty string for a simple comment.
+func reviewState(event *forgejostructs.PullRequestPayload) string {
+	if event.Review == nil {
+		return ""
+	}
+	switch whEventType(event.Review.Type) {
+	case EventTypePullRequestReviewApproved:
+		return "approved"
+	case EventTypePullRequestReviewRejected:
+		return "changes_requested"
+	default:
+		return ""
+	}
+}
+
+// isMarkedAsReady returns true when the edition of a pull request is the
+// removal of the draft prefix from its title.
+func isMarkedAsReady(event *forgejostructs.PullRequestPayload) bool {
+	if event.Action != forgejostructs.HookIssueEdited || event.Changes == nil || event.Changes.Title == nil ||
+		event.PullRequest == nil || event.PullRequest.Draft {
+		return false
+	}
+	previousTitle := strings.ToLower(strings.TrimSpace(event.Changes.Title.From))
+	for _, prefix := range draftTitlePrefixes {
+		if strings.HasPrefix(previousTitle, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // detectTriggerTypeFromPayload will detect the event type from the payload,
 // filtering out the events that are not supported.
 func detectTriggerTypeFromPayload(ghEventType string, eventInt any) (triggertype.Trigger, string) {
@@ -57,9 +93,18 @@ func detectTriggerTypeFromPayload(ghEventType string, eventInt any) (triggertype
 		}
 		return "", "invalid payload: no pusher in event"
 	case *forgejostructs.PullRequestPayload:
+		if event.Action == forgejostructs.HookIssueReviewed {
+			if reviewState(event) != "" {
+				return triggertype.PullRequestReview, ""
+			}
+			return "", "pull_request_review: only approved and rejected reviews are supported"
+		}
 		if provider.Valid(string(event.Action), append(pullRequestOpenSyncEvent, pullRequestLabelUpdated, pullRequestLabelClosed)) {
 			return triggertype.PullRequest, ""
 		}
+		if isMarkedAsReady(event) {
+			return triggertype.PullRequest, ""
+		}
 		return "", fmt.Sprintf("pull_request: unsupported action \"%s\"", event.Action)
 	case *forgejostructs.IssueCommentPayload:
 		if event.Action == "created" &&
@@ -99,6 +99,71 @@ func TestProviderDetect(t *testing.T) {
 			isGitea:      true,
 			processEvent: true,
 		},
+		{
+			name: "good/pull request marked as ready",
+			args: args{
+				req: &http.Request{
+					Header: http.Header{
+						"X-Gitea-Event-Type": []string{"pull_request"},
+					},
+				},
+				payload: `{"action": "edited", "changes": {"title": {"from": "WIP: my change"}}, "pull_request": {"title": "my change", "draft": false}}`,
+			},
+			isGitea:      true,
+			processEvent: true,
+		},
+		{
+			name: "bad/pull request title edited",
+			args: args{
+				req: &http.Request{
+					Header: http.Header{
+						"X-Gitea-Event-Type": []string{"pull_request"},
+					},
+				},
+				payload: `{"action": "edited", "changes": {"title": {"from": "my chnage"}}, "pull_request": {"title": "my change", "draft": false}}`,
+			},
+			wantReason: "pull_request: unsupported action \"edited\"",
+			isGitea:    true,
+		},
+		{
+			name: "good/pull request review approved",
+			args: args{
+				req: &http.Request{
+					Header: http.Header{
+						"X-Gitea-Event-Type": []string{"pull_request_review_approved"},
+					},
+				},
+				payload: `{"action": "reviewed", "review": {"type": "pull_request_review_approved"}}`,
+			},
+			isGitea:      true,
+			processEvent: true,
+		},
+		{
+			name: "good/pull request review rejected",
+			args: args{
+				req: &http.Request{
+					Header: http.Header{
+						"X-Gitea-Event-Type": []string{"pull_request_review_rejected"},
+					},
+				},
+				payload: `{"action": "reviewed", "review": {"type": "pull_request_review_rejected"}}`,
+			},
+			isGitea:      true,
+			processEvent: true,
+		},
+		{
+			name: "bad/pull request review comment",
+			args: args{
+				req: &http.Request{
+					Header: http.Header{
+						"X-Gitea-Event-Type": []string{"pull_request_review_approved"},
+					},
+				},
+				payload: `{"action": "reviewed", "review": {"type": "pull_request_review_comment"}}`,
+			},
+			wantReason: "pull_request_review: only approved and rejected reviews are supported",
+			isGitea:    true,
+		},
 		{
 			name: "good/push",
 			args: args{
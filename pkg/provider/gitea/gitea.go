@@ -82,6 +82,12 @@ func (v *Provider) SetGiteaClient(client *forgejo.Client) {
 	v.giteaClient = client
 }
 
+// CreateCommitComment is not supported, Gitea and Forgejo don't have an API
+// to comment on a commit.
+func (v *Provider) CreateCommitComment(_ context.Context, _ *info.Event, _ string) error {
+	return fmt.Errorf("commit comments are not supported on gitea")
+}
+
 func (v *Provider) CreateComment(_ context.Context, event *info.Event, commit, updateMarker string) error {
 	if v.giteaClient == nil {
 		return fmt.Errorf("no gitea client has been initialized")
@@ -244,6 +250,18 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts
 	return v.createStatusCommit(event, v.pacInfo, statusOpts)
 }
 
+// UpdateStatusDetails replaces the description of the commit status of the
+// PipelineRun, gitea has no check runs to add text to.
+func (v *Provider) UpdateStatusDetails(_ context.Context, event *info.Event, statusOpts provider.StatusOpts, _, description string) error {
+	if v.giteaClient == nil {
+		return fmt.Errorf("cannot set status on gitea no token or url set")
+	}
+	statusOpts.Title = description
+	// no text, the status would be commented on the pull request again
+	statusOpts.Text = ""
+	return v.createStatusCommit(event, v.pacInfo, statusOpts)
+}
+
 func (v *Provider) createStatusCommit(event *info.Event, pacopts *info.PacOpts, status provider.StatusOpts) error {
 	state := forgejo.StatusState(status.Conclusion)
 	switch status.Conclusion {
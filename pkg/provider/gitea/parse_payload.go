@@ -47,6 +47,7 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.BaseURL = gitEvent.PullRequest.Base.Repository.HTMLURL
 		processedEvent.PullRequestNumber = int(gitEvent.Index)
 		processedEvent.PullRequestTitle = gitEvent.PullRequest.Title
+		processedEvent.IsDraft = gitEvent.PullRequest.Draft
 		processedEvent.Organization = gitEvent.Repository.Owner.UserName
 		processedEvent.Repository = gitEvent.Repository.Name
 		processedEvent.TriggerTarget = triggertype.PullRequest
@@ -59,6 +60,15 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		}
 		if gitEvent.Action == forgejostructs.HookIssueClosed {
 			processedEvent.TriggerTarget = triggertype.PullRequestClosed
+			processedEvent.PullRequestMerged = gitEvent.PullRequest.HasMerged
+		}
+		if gitEvent.Action == forgejostructs.HookIssueReviewed {
+			// the sender is the reviewer
+			processedEvent.PullRequestReviewState = reviewState(gitEvent)
+			if processedEvent.PullRequestReviewState == "" {
+				return nil, fmt.Errorf("pull_request_review: only approved and rejected reviews are supported")
+			}
+			processedEvent.EventType = triggertype.PullRequestReview.String()
 		}
 	case *forgejostructs.PushPayload:
 		processedEvent = info.NewEvent()
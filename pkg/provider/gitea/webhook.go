@@ -30,6 +30,9 @@ const (
 	EventTypePullRequestLabel    whEventType = "pull_request_label"
 	EventTypePullRequestComment  whEventType = "pull_request_comment"
 	EventTypePullRequestSync     whEventType = "pull_request_sync"
+
+	EventTypePullRequestReviewApproved whEventType = "pull_request_review_approved"
+	EventTypePullRequestReviewRejected whEventType = "pull_request_review_rejected"
 )
 
 func parseWebhook(eventType whEventType, payload []byte) (event any, err error) {
@@ -52,7 +55,8 @@ func parseWebhook(eventType whEventType, payload []byte) (event any, err error)
 		event = &forgejostructs.ReleasePayload{}
 	case EventTypePullRequestComment:
 		event = &forgejostructs.IssueCommentPayload{}
-	case EventTypePullRequest, EventTypePullRequestApproved, EventTypePullRequestSync, EventTypePullRequestRejected, EventTypePullRequestLabel:
+	case EventTypePullRequest, EventTypePullRequestApproved, EventTypePullRequestSync, EventTypePullRequestRejected, EventTypePullRequestLabel,
+		EventTypePullRequestReviewApproved, EventTypePullRequestReviewRejected:
 		event = &forgejostructs.PullRequestPayload{}
 	default:
 		return nil, fmt.Errorf("unexpected event type: %s", eventType)
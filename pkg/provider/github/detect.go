@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/go-github/v81/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
@@ -14,6 +15,7 @@ import (
 var (
 	pullRequestOpenSyncEvent = []string{"opened", "synchronize", "synchronized", "reopened", "ready_for_review"}
 	pullRequestLabelEvent    = []string{"labeled"}
+	pullRequestReviewStates  = []string{"approved", "changes_requested"}
 )
 
 // Detect processes event and detect if it is a github event, whether to process or reject it
@@ -72,6 +74,14 @@ func (v *Provider) detectTriggerTypeFromPayload(ghEventType string, eventInt any
 			return triggertype.PullRequest, ""
 		}
 		return "", fmt.Sprintf("pull_request: unsupported action \"%s\"", event.GetAction())
+	case *github.PullRequestReviewEvent:
+		if event.GetAction() != "submitted" {
+			return "", fmt.Sprintf("pull_request_review: unsupported action \"%s\"", event.GetAction())
+		}
+		if !provider.Valid(strings.ToLower(event.GetReview().GetState()), pullRequestReviewStates) {
+			return "", fmt.Sprintf("pull_request_review: unsupported review state \"%s\"", event.GetReview().GetState())
+		}
+		return triggertype.PullRequestReview, ""
 	case *github.IssueCommentEvent:
 		if event.GetAction() == "created" &&
 			event.GetIssue().IsPullRequest() &&
@@ -97,6 +107,11 @@ func (v *Provider) detectTriggerTypeFromPayload(ghEventType string, eventInt any
 			return triggertype.CheckRunRerequested, ""
 		}
 		return "", fmt.Sprintf("check_run: unsupported action \"%s\"", event.GetAction())
+	case *github.MergeGroupEvent:
+		if event.GetAction() == "checks_requested" || event.GetAction() == "destroyed" {
+			return triggertype.MergeGroup, ""
+		}
+		return "", fmt.Sprintf("merge_group: unsupported action \"%s\"", event.GetAction())
 	case *github.CommitCommentEvent:
 		if event.GetAction() == "created" {
 			if provider.IsTestRetestComment(event.GetComment().GetBody()) {
@@ -218,6 +218,44 @@ func TestProvider_Detect(t *testing.T) {
 			isGH:       true,
 			processReq: true,
 		},
+		{
+			name: "pull request review approved event",
+			event: github.PullRequestReviewEvent{
+				Action: github.Ptr("submitted"),
+				Review: &github.PullRequestReview{State: github.Ptr("approved")},
+			},
+			eventType:  "pull_request_review",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "pull request review commented event not supported",
+			event: github.PullRequestReviewEvent{
+				Action: github.Ptr("submitted"),
+				Review: &github.PullRequestReview{State: github.Ptr("commented")},
+			},
+			eventType:  "pull_request_review",
+			isGH:       true,
+			processReq: false,
+		},
+		{
+			name: "merge group checks requested event",
+			event: github.MergeGroupEvent{
+				Action: github.Ptr("checks_requested"),
+			},
+			eventType:  "merge_group",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "merge group destroyed event",
+			event: github.MergeGroupEvent{
+				Action: github.Ptr("destroyed"),
+			},
+			eventType:  "merge_group",
+			isGH:       true,
+			processReq: true,
+		},
 		{
 			name: "pull request event not supported action",
 			event: github.PullRequestEvent{
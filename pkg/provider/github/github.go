@@ -539,7 +539,7 @@ func (v *Provider) fetchChangedFiles(ctx context.Context, runevent *info.Event)
 	changedFiles := changedfiles.ChangedFiles{}
 
 	switch runevent.TriggerTarget {
-	case triggertype.PullRequest:
+	case triggertype.PullRequest, triggertype.PullRequestClosed:
 		opt := &github.ListOptions{PerPage: v.PaginedNumber}
 		for {
 			repoCommit, resp, err := wrapAPI(v, "list_pull_request_files", func() ([]*github.CommitFile, *github.Response, error) {
@@ -590,6 +590,33 @@ func (v *Provider) fetchChangedFiles(ctx context.Context, runevent *info.Event)
 				changedFiles.Renamed = append(changedFiles.Renamed, *rC.Files[i].Filename)
 			}
 		}
+	case triggertype.MergeGroup:
+		mergeGroupEvent, ok := runevent.Event.(*github.MergeGroupEvent)
+		if !ok {
+			return changedfiles.ChangedFiles{}, nil
+		}
+		// a merge group may contain several pull requests, compare it to
+		// the commit it has been built on.
+		comparison, _, err := wrapAPI(v, "compare_commits", func() (*github.CommitsComparison, *github.Response, error) {
+			return v.Client().Repositories.CompareCommits(ctx, runevent.Organization, runevent.Repository,
+				mergeGroupEvent.GetMergeGroup().GetBaseSHA(), mergeGroupEvent.GetMergeGroup().GetHeadSHA(), &github.ListOptions{})
+		})
+		if err != nil {
+			return changedfiles.ChangedFiles{}, err
+		}
+		for i := range comparison.Files {
+			changedFiles.All = append(changedFiles.All, comparison.Files[i].GetFilename())
+			switch comparison.Files[i].GetStatus() {
+			case "added":
+				changedFiles.Added = append(changedFiles.Added, comparison.Files[i].GetFilename())
+			case "removed":
+				changedFiles.Deleted = append(changedFiles.Deleted, comparison.Files[i].GetFilename())
+			case "modified":
+				changedFiles.Modified = append(changedFiles.Modified, comparison.Files[i].GetFilename())
+			case "renamed":
+				changedFiles.Renamed = append(changedFiles.Renamed, comparison.Files[i].GetFilename())
+			}
+		}
 	default:
 		// No action necessary
 	}
@@ -1041,3 +1068,21 @@ func (v *Provider) CreateComment(ctx context.Context, event *info.Event, commit,
 	}
 	return v.ensureSingleMarkerComment(ctx, event, matchedComments, commit, trace)
 }
+
+// CreateCommitComment posts a comment on the commit of the event.
+func (v *Provider) CreateCommitComment(ctx context.Context, event *info.Event, comment string) error {
+	if v.ghClient == nil {
+		return fmt.Errorf("no github client has been initialized")
+	}
+
+	if event.SHA == "" {
+		return fmt.Errorf("create commit comment needs a commit SHA")
+	}
+
+	_, _, err := wrapAPI(v, "create_commit_comment", func() (*github.RepositoryComment, *github.Response, error) {
+		return v.Client().Repositories.CreateComment(ctx, event.Organization, event.Repository, event.SHA, &github.RepositoryComment{
+			Body: github.Ptr(comment),
+		})
+	})
+	return err
+}
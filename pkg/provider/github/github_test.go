@@ -1012,6 +1012,36 @@ func TestGetFiles(t *testing.T) {
 			wantRenamedFilesCount:  1,
 			wantAPIRequestCount:    1,
 		},
+		{
+			name: "merge group",
+			event: &info.Event{
+				TriggerTarget: "merge_group",
+				Organization:  "mergegroupowner",
+				Repository:    "mergegrouprepository",
+				SHA:           "shamergegroup",
+				Event: &github.MergeGroupEvent{
+					MergeGroup: &github.MergeGroup{
+						HeadSHA: ptr.String("shamergegroup"),
+						BaseSHA: ptr.String("shabase"),
+					},
+				},
+			},
+			commitFiles: []*github.CommitFile{
+				{
+					Filename: ptr.String("modified.yaml"),
+					Status:   ptr.String("modified"),
+				}, {
+					Filename: ptr.String("added.doc"),
+					Status:   ptr.String("added"),
+				}, {
+					Filename: ptr.String("added.yaml"),
+					Status:   ptr.String("added"),
+				},
+			},
+			wantAddedFilesCount:    2,
+			wantModifiedFilesCount: 1,
+			wantAPIRequestCount:    1,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1042,6 +1072,14 @@ func TestGetFiles(t *testing.T) {
 				})
 			}
 
+			if tt.event.TriggerTarget == "merge_group" {
+				mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/compare/shabase...shamergegroup",
+					tt.event.Organization, tt.event.Repository), func(rw http.ResponseWriter, _ *http.Request) {
+					b, _ := json.Marshal(&github.CommitsComparison{Files: tt.commitFiles})
+					fmt.Fprint(rw, string(b))
+				})
+			}
+
 			metricsTags := map[string]string{"provider": "github", "event-type": string(tt.event.TriggerTarget)}
 			metricstest.CheckStatsNotReported(t, "pipelines_as_code_git_provider_api_request_count")
 
@@ -1063,7 +1101,7 @@ func TestGetFiles(t *testing.T) {
 			assert.Equal(t, tt.wantModifiedFilesCount, len(changedFiles.Modified))
 			assert.Equal(t, tt.wantRenamedFilesCount, len(changedFiles.Renamed))
 
-			if tt.event.TriggerTarget == "pull_request" {
+			if tt.event.TriggerTarget == "pull_request" || tt.event.TriggerTarget == "merge_group" {
 				for i := range changedFiles.All {
 					assert.Equal(t, *tt.commitFiles[i].Filename, changedFiles.All[i])
 				}
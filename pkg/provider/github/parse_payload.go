@@ -105,9 +105,12 @@ func (v *Provider) parseEventType(request *http.Request, event *info.Event) erro
 
 	event.Provider.URL = request.Header.Get("X-GitHub-Enterprise-Host")
 
-	if event.EventType == "push" {
+	switch event.EventType {
+	case "push":
 		event.TriggerTarget = triggertype.Push
-	} else {
+	case triggertype.MergeGroup.String():
+		event.TriggerTarget = triggertype.MergeGroup
+	default:
 		event.TriggerTarget = triggertype.PullRequest
 	}
 
@@ -433,10 +436,12 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 
 		if gitEvent.GetAction() == "closed" {
 			processedEvent.TriggerTarget = triggertype.PullRequestClosed
+			processedEvent.PullRequestMerged = gitEvent.GetPullRequest().GetMerged()
 		}
 
 		processedEvent.PullRequestNumber = gitEvent.GetPullRequest().GetNumber()
 		processedEvent.PullRequestTitle = gitEvent.GetPullRequest().GetTitle()
+		processedEvent.IsDraft = gitEvent.GetPullRequest().GetDraft()
 		// getting the repository ids of the base and head of the pull request
 		// to scope the token to
 		v.RepositoryIDs = []int64{
@@ -445,6 +450,71 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 		for _, label := range gitEvent.GetPullRequest().Labels {
 			processedEvent.PullRequestLabel = append(processedEvent.PullRequestLabel, label.GetName())
 		}
+	case *github.PullRequestReviewEvent:
+		if gitEvent.GetRepo() == nil {
+			return nil, errors.New("error parsing payload the repository should not be nil")
+		}
+		if tType, reason := v.detectTriggerTypeFromPayload(event.EventType, gitEvent); tType == "" {
+			return nil, errors.New(reason)
+		}
+		processedEvent.Organization = gitEvent.GetRepo().GetOwner().GetLogin()
+		processedEvent.Repository = gitEvent.GetRepo().GetName()
+		processedEvent.DefaultBranch = gitEvent.GetRepo().GetDefaultBranch()
+		processedEvent.URL = gitEvent.GetRepo().GetHTMLURL()
+		processedEvent.SHA = gitEvent.GetPullRequest().GetHead().GetSHA()
+		processedEvent.BaseBranch = gitEvent.GetPullRequest().GetBase().GetRef()
+		processedEvent.HeadBranch = gitEvent.GetPullRequest().GetHead().GetRef()
+		processedEvent.BaseURL = gitEvent.GetPullRequest().GetBase().GetRepo().GetHTMLURL()
+		processedEvent.HeadURL = gitEvent.GetPullRequest().GetHead().GetRepo().GetHTMLURL()
+		processedEvent.PullRequestNumber = gitEvent.GetPullRequest().GetNumber()
+		processedEvent.PullRequestTitle = gitEvent.GetPullRequest().GetTitle()
+		processedEvent.IsDraft = gitEvent.GetPullRequest().GetDraft()
+		for _, label := range gitEvent.GetPullRequest().Labels {
+			processedEvent.PullRequestLabel = append(processedEvent.PullRequestLabel, label.GetName())
+		}
+		// the reviewer is the one triggering the CI, the policy and ACL
+		// checks are done against them and not the pull request author.
+		processedEvent.Sender = gitEvent.GetReview().GetUser().GetLogin()
+		v.userType = gitEvent.GetReview().GetUser().GetType()
+		processedEvent.PullRequestReviewState = strings.ToLower(gitEvent.GetReview().GetState())
+		processedEvent.EventType = triggertype.PullRequestReview.String()
+		processedEvent.TriggerTarget = triggertype.PullRequest
+		v.RepositoryIDs = []int64{
+			gitEvent.GetPullRequest().GetBase().GetRepo().GetID(),
+		}
+	case *github.MergeGroupEvent:
+		if gitEvent.GetRepo() == nil {
+			return nil, errors.New("error parsing payload the repository should not be nil")
+		}
+		switch gitEvent.GetAction() {
+		case "checks_requested":
+		case "destroyed":
+			// the group has been merged, invalidated or dequeued, nothing
+			// runs for it anymore
+			v.Logger.Infof("merge group %s has been destroyed (%s), cancelling its pipelineruns",
+				gitEvent.GetMergeGroup().GetHeadRef(), gitEvent.GetReason())
+			processedEvent.CancelPipelineRuns = true
+		default:
+			return nil, fmt.Errorf("merge_group: unsupported action \"%s\"", gitEvent.GetAction())
+		}
+		processedEvent.Organization = gitEvent.GetRepo().GetOwner().GetLogin()
+		processedEvent.Repository = gitEvent.GetRepo().GetName()
+		processedEvent.DefaultBranch = gitEvent.GetRepo().GetDefaultBranch()
+		processedEvent.URL = gitEvent.GetRepo().GetHTMLURL()
+		v.RepositoryIDs = []int64{gitEvent.GetRepo().GetID()}
+		// the check runs are reported on the temporary commit of the merge
+		// group, that's what the merge queue waits for.
+		processedEvent.SHA = gitEvent.GetMergeGroup().GetHeadSHA()
+		processedEvent.SHATitle = gitEvent.GetMergeGroup().GetHeadCommit().GetMessage()
+		processedEvent.SHAURL = gitEvent.GetMergeGroup().GetHeadCommit().GetHTMLURL()
+		processedEvent.Sender = gitEvent.GetSender().GetLogin()
+		processedEvent.BaseBranch = gitEvent.GetMergeGroup().GetBaseRef()
+		processedEvent.HeadBranch = gitEvent.GetMergeGroup().GetHeadRef()
+		processedEvent.BaseURL = gitEvent.GetRepo().GetHTMLURL()
+		processedEvent.HeadURL = processedEvent.BaseURL
+		processedEvent.EventType = triggertype.MergeGroup.String()
+		processedEvent.TriggerTarget = triggertype.MergeGroup
+		v.userType = gitEvent.GetSender().GetType()
 	default:
 		return nil, errors.New("this event is not supported")
 	}
@@ -376,12 +376,54 @@ func TestIsCommitPartOfPullRequest(t *testing.T) {
 	}
 }
 
+func samplePRReviewEvent(action, state string) github.PullRequestReviewEvent {
+	return github.PullRequestReviewEvent{
+		Action:      github.Ptr(action),
+		PullRequest: samplePRevent.PullRequest,
+		Review: &github.PullRequestReview{
+			State: github.Ptr(state),
+			User:  &github.User{Login: github.Ptr("reviewer")},
+		},
+		Repo: sampleRepo,
+	}
+}
+
+func sampleMergeGroupEvent(action string) github.MergeGroupEvent {
+	return github.MergeGroupEvent{
+		Action: github.Ptr(action),
+		MergeGroup: &github.MergeGroup{
+			HeadSHA:    github.Ptr("SHAMergeGroup"),
+			HeadRef:    github.Ptr("refs/heads/gh-readonly-queue/main/pr-42-SHABase"),
+			BaseSHA:    github.Ptr("SHABase"),
+			BaseRef:    github.Ptr("refs/heads/main"),
+			HeadCommit: &github.Commit{Message: github.Ptr("Merge pull request #42")},
+		},
+		Repo: &github.Repository{
+			Owner:         &github.User{Login: github.Ptr("owner")},
+			Name:          github.Ptr("reponame"),
+			DefaultBranch: github.Ptr("main"),
+			HTMLURL:       github.Ptr("https://github.com/owner/reponame"),
+		},
+		Sender: &github.User{Login: github.Ptr("merger")},
+	}
+}
+
 func TestParsePayLoad(t *testing.T) {
 	samplePRNoRepo := samplePRevent
 	samplePRNoRepo.Repo = nil
 	samplePrEventClosed := samplePRevent
 	samplePrEventClosed.Action = github.Ptr("closed")
 
+	samplePrEventDraft := samplePRevent
+	draftPR := *samplePRevent.PullRequest
+	draftPR.Draft = github.Ptr(true)
+	samplePrEventDraft.PullRequest = &draftPR
+
+	samplePrEventMerged := samplePrEventClosed
+	mergedPR := *samplePRevent.PullRequest
+	mergedPR.Merged = github.Ptr(true)
+	samplePrEventMerged.PullRequest = &mergedPR
+
 	sampleGhPRs := []*github.PullRequest{
 		{
 			Number: github.Ptr(41),
@@ -410,6 +452,9 @@ func TestParsePayLoad(t *testing.T) {
 		isCancelPipelineRunEnabled bool
 		skipPushEventForPRCommits  bool
 		objectType                 string
+		wantedReviewState          string
+		wantedMerged               bool
+		wantedDraft                bool
 	}{
 		{
 			name:          "bad/unknown event",
@@ -602,6 +647,14 @@ func TestParsePayLoad(t *testing.T) {
 			payloadEventStruct: samplePRevent,
 			shaRet:             "sampleHeadsha",
 		},
+		{
+			name:               "good/draft pull request",
+			eventType:          "pull_request",
+			triggerTarget:      triggertype.PullRequest.String(),
+			payloadEventStruct: samplePrEventDraft,
+			shaRet:             "sampleHeadsha",
+			wantedDraft:        true,
+		},
 		{
 			name:               "good/pull request closed",
 			eventType:          "pull_request",
@@ -609,6 +662,14 @@ func TestParsePayLoad(t *testing.T) {
 			payloadEventStruct: samplePrEventClosed,
 			shaRet:             "sampleHeadsha",
 		},
+		{
+			name:               "good/pull request merged",
+			eventType:          "pull_request",
+			triggerTarget:      triggertype.PullRequestClosed.String(),
+			payloadEventStruct: samplePrEventMerged,
+			shaRet:             "sampleHeadsha",
+			wantedMerged:       true,
+		},
 		{
 			name:          "good/push",
 			eventType:     "push",
@@ -1010,6 +1071,71 @@ func TestParsePayLoad(t *testing.T) {
 			skipPushEventForPRCommits: true,
 			muxReplies:                map[string]any{"/repos/owner/pushRepo/commits/SHAPush/pulls": sampleGhPRs},
 		},
+		{
+			name:               "good/pull request review approved",
+			eventType:          "pull_request_review",
+			triggerTarget:      "pull_request",
+			payloadEventStruct: samplePRReviewEvent("submitted", "approved"),
+			shaRet:             "sampleHeadsha",
+			wantedReviewState:  "approved",
+		},
+		{
+			name:               "good/pull request review changes requested",
+			eventType:          "pull_request_review",
+			triggerTarget:      "pull_request",
+			payloadEventStruct: samplePRReviewEvent("submitted", "CHANGES_REQUESTED"),
+			shaRet:             "sampleHeadsha",
+			wantedReviewState:  "changes_requested",
+		},
+		{
+			name:               "bad/pull request review commented",
+			wantErrString:      "pull_request_review: unsupported review state \"commented\"",
+			eventType:          "pull_request_review",
+			triggerTarget:      "pull_request",
+			payloadEventStruct: samplePRReviewEvent("submitted", "commented"),
+		},
+		{
+			name:               "bad/pull request review dismissed",
+			wantErrString:      "pull_request_review: unsupported action \"dismissed\"",
+			eventType:          "pull_request_review",
+			triggerTarget:      "pull_request",
+			payloadEventStruct: samplePRReviewEvent("dismissed", "approved"),
+		},
+		{
+			name:               "good/merge group checks requested",
+			eventType:          "merge_group",
+			triggerTarget:      "merge_group",
+			githubClient:       true,
+			payloadEventStruct: sampleMergeGroupEvent("checks_requested"),
+			shaRet:             "SHAMergeGroup",
+			wantedBranchName:   "refs/heads/gh-readonly-queue/main/pr-42-SHABase",
+		},
+		{
+			name:                       "good/merge group destroyed",
+			eventType:                  "merge_group",
+			triggerTarget:              "merge_group",
+			githubClient:               true,
+			payloadEventStruct:         sampleMergeGroupEvent("destroyed"),
+			shaRet:                     "SHAMergeGroup",
+			wantedBranchName:           "refs/heads/gh-readonly-queue/main/pr-42-SHABase",
+			isCancelPipelineRunEnabled: true,
+		},
+		{
+			name:               "bad/merge group unsupported action",
+			wantErrString:      "merge_group: unsupported action \"unknown\"",
+			eventType:          "merge_group",
+			triggerTarget:      "merge_group",
+			githubClient:       true,
+			payloadEventStruct: sampleMergeGroupEvent("unknown"),
+		},
+		{
+			name:               "bad/merge group no repository",
+			wantErrString:      "error parsing payload the repository should not be nil",
+			eventType:          "merge_group",
+			triggerTarget:      "merge_group",
+			githubClient:       true,
+			payloadEventStruct: github.MergeGroupEvent{Action: github.Ptr("checks_requested")},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1111,12 +1237,26 @@ func TestParsePayLoad(t *testing.T) {
 			assert.Equal(t, tt.shaRet, ret.SHA)
 			if tt.eventType == triggertype.PullRequest.String() {
 				assert.Equal(t, "my first PR", ret.PullRequestTitle)
+				assert.Equal(t, tt.wantedMerged, ret.PullRequestMerged)
+				assert.Equal(t, tt.wantedDraft, ret.IsDraft)
 			}
 			if tt.eventType == "commit_comment" {
 				assert.Equal(t, tt.wantedBranchName, ret.HeadBranch)
 				assert.Equal(t, tt.wantedBranchName, ret.BaseBranch)
 				assert.Equal(t, tt.isCancelPipelineRunEnabled, ret.CancelPipelineRuns)
 			}
+			if tt.eventType == triggertype.PullRequestReview.String() {
+				assert.Equal(t, triggertype.PullRequestReview.String(), ret.EventType)
+				assert.Equal(t, tt.wantedReviewState, ret.PullRequestReviewState)
+				// the reviewer is the sender, not the pull request author
+				assert.Equal(t, "reviewer", ret.Sender)
+			}
+			if tt.eventType == "merge_group" {
+				assert.Equal(t, tt.wantedBranchName, ret.HeadBranch)
+				assert.Equal(t, "refs/heads/main", ret.BaseBranch)
+				assert.Equal(t, triggertype.MergeGroup.String(), ret.EventType)
+				assert.Equal(t, tt.isCancelPipelineRunEnabled, ret.CancelPipelineRuns)
+			}
 			if tt.targetPipelinerun != "" {
 				assert.Equal(t, tt.targetPipelinerun, ret.TargetTestPipelineRun)
 			}
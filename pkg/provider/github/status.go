@@ -438,3 +438,64 @@ func (v *Provider) CreateStatus(ctx context.Context, runevent *info.Event, statu
 	// Otherwise use the update status commit API
 	return v.createStatusCommit(ctx, runevent, statusOpts)
 }
+
+// UpdateStatusDetails appends text to the output of the check run of the
+// PipelineRun with a GitHub App, leaving its title, summary and conclusion
+// alone. Without a GitHub App, description replaces the description of the
+// commit status.
+func (v *Provider) UpdateStatusDetails(ctx context.Context, runevent *info.Event, statusOpts provider.StatusOpts, text, description string) error {
+	if v.ghClient == nil {
+		return fmt.Errorf("cannot set status on github no token or url set")
+	}
+
+	if runevent.InstallationID <= 0 {
+		statusOpts.Title = description
+		// no text, the status would be commented on the pull request again
+		statusOpts.Text = ""
+		return v.createStatusCommit(ctx, runevent, statusOpts)
+	}
+
+	var checkRunID *int64
+	if statusOpts.PipelineRun != nil {
+		if id, ok := statusOpts.PipelineRun.GetAnnotations()[keys.CheckRunID]; ok {
+			checkID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				return fmt.Errorf("api error: cannot convert checkrunid")
+			}
+			checkRunID = github.Ptr(checkID)
+		}
+	}
+	if checkRunID == nil {
+		var err error
+		if checkRunID, err = v.getExistingCheckRunID(ctx, runevent, statusOpts); err != nil {
+			return err
+		}
+		if checkRunID == nil {
+			return fmt.Errorf("cannot find the check run of pipelinerun %s", statusOpts.PipelineRunName)
+		}
+	}
+
+	checkRun, _, err := wrapAPI(v, "get_check_run", func() (*github.CheckRun, *github.Response, error) {
+		return v.Client().Checks.GetCheckRun(ctx, runevent.Organization, runevent.Repository, *checkRunID)
+	})
+	if err != nil {
+		return err
+	}
+
+	output := checkRun.GetOutput()
+	if output.GetText() != "" {
+		text = fmt.Sprintf("%s\n\n%s", output.GetText(), text)
+	}
+	opts := github.UpdateCheckRunOptions{
+		Name: checkRun.GetName(),
+		Output: &github.CheckRunOutput{
+			Title:   output.Title,
+			Summary: output.Summary,
+			Text:    github.Ptr(text),
+		},
+	}
+	_, _, err = wrapAPI(v, "update_check_run", func() (*github.CheckRun, *github.Response, error) {
+		return v.Client().Checks.UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, *checkRunID, opts)
+	})
+	return err
+}
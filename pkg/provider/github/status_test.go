@@ -686,3 +686,97 @@ func TestProviderGetExistingCheckRunID(t *testing.T) {
 		})
 	}
 }
+
+func TestGithubProviderUpdateStatusDetails(t *testing.T) {
+	tests := []struct {
+		name           string
+		installationID int64
+		existingText   string
+		wantText       string
+	}{
+		{
+			name:           "appended to the text of the check run",
+			installationID: 1,
+			existingText:   "the task failed",
+			wantText:       "the task failed\n\nthe analysis",
+		},
+		{
+			name:           "check run without text",
+			installationID: 1,
+			wantText:       "the analysis",
+		},
+		{
+			name: "description of the commit status",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			event := &info.Event{
+				Organization:      "owner",
+				Repository:        "repository",
+				SHA:               "sha",
+				EventType:         "pull_request",
+				PullRequestNumber: 666,
+				InstallationID:    tt.installationID,
+			}
+			pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+				Name:        "pr-build-aaaaa",
+				Annotations: map[string]string{keys.CheckRunID: "555"},
+			}}
+
+			updated, statusSet := false, false
+			mux.HandleFunc("/repos/owner/repository/check-runs/555", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					_, _ = fmt.Fprintf(w, `{"id": 555, "name": "Pipelines as Code CI / pr-build", "output": {"title": "Failed", "summary": "has failed.", "text": %q}}`, tt.existingText)
+					return
+				}
+				assert.Equal(t, r.Method, http.MethodPatch)
+				opts := github.UpdateCheckRunOptions{}
+				body, _ := io.ReadAll(r.Body)
+				assert.NilError(t, json.Unmarshal(body, &opts))
+				// only the text of the check run changes
+				assert.Equal(t, opts.Name, "Pipelines as Code CI / pr-build")
+				assert.Assert(t, opts.Status == nil && opts.Conclusion == nil && opts.CompletedAt == nil)
+				assert.Equal(t, opts.Output.GetTitle(), "Failed")
+				assert.Equal(t, opts.Output.GetSummary(), "has failed.")
+				assert.Equal(t, opts.Output.GetText(), tt.wantText)
+				updated = true
+				_, _ = fmt.Fprint(w, `{"id": 555}`)
+			})
+			mux.HandleFunc("/repos/owner/repository/statuses/sha", func(_ http.ResponseWriter, r *http.Request) {
+				status := github.RepoStatus{}
+				body, _ := io.ReadAll(r.Body)
+				assert.NilError(t, json.Unmarshal(body, &status))
+				assert.Equal(t, status.GetState(), "failure")
+				assert.Equal(t, status.GetDescription(), "AI Analysis: the analysis")
+				statusSet = true
+			})
+			mux.HandleFunc("/repos/owner/repository/issues/666/comments", func(_ http.ResponseWriter, _ *http.Request) {
+				t.Error("the status should not be commented on the pull request")
+			})
+
+			ctx, _ := rtesting.SetupFakeContext(t)
+			cnx := &Provider{
+				ghClient: fakeclient,
+				Run:      params.New(),
+				pacInfo: &info.PacOpts{
+					Settings: settings.Settings{
+						ApplicationName: settings.PACApplicationNameDefaultValue,
+					},
+				},
+			}
+			status := provider.StatusOpts{
+				PipelineRun:     pr,
+				PipelineRunName: pr.GetName(),
+				Status:          "completed",
+				Conclusion:      "failure",
+				Text:            "the task failed",
+			}
+			assert.NilError(t, cnx.UpdateStatusDetails(ctx, event, status, "the analysis", "AI Analysis: the analysis"))
+			assert.Equal(t, updated, tt.installationID > 0)
+			assert.Equal(t, statusSet, tt.installationID == 0)
+		})
+	}
+}
@@ -61,6 +61,10 @@ func (v *Provider) checkOkToTestCommentFromApprovedMember(ctx context.Context, e
 			v.Logger.Debug("RememberOKToTest is disabled, skipping MergeRequest notes check as it is not needed")
 			return false, nil
 		}
+		if gitEvent.ObjectAttributes.Action == mergeRequestApprovedAction {
+			v.Logger.Debug("Event is a MergeRequest approval, only the approver is allowed to trigger the CI")
+			return false, nil
+		}
 	case *gitlab.MergeCommentEvent:
 		if !v.pacInfo.RememberOKToTest {
 			v.Logger.Debug("Event is a MergeCommentEvent and RememberOKToTest is disabled, checking current comment only")
@@ -10,6 +10,14 @@ import (
 	"go.uber.org/zap"
 )
 
+// mergeRequestApprovedAction is the action of a Merge Request approval, it is
+// handled as a pull_request_review event.
+const mergeRequestApprovedAction = "approved"
+
+// mergeRequestMergedAction is the action of a Merge Request being merged, it
+// is handled as a pull_request_closed event like a Merge Request being closed.
+const mergeRequestMergedAction = "merge"
+
 // Detect detects events and validates if it is a valid gitlab event Pipelines as Code supports and
 // decides whether to process or reject it.
 // returns a boolean value whether to process or reject, logger with event metadata, and error if any occurred.
@@ -38,6 +46,12 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 
 	switch gitEvent := eventInt.(type) {
 	case *gitlab.MergeEvent:
+		// a draft MR marked as ready runs the pull_request PipelineRuns
+		// skipped while it was a draft.
+		if isMarkedAsReady(gitEvent) {
+			return setLoggerAndProceed(true, "", nil)
+		}
+
 		// on a MR update, react only if OldRev is empty (no new commits pushed).
 		// If OldRev is empty, it's a metadata-only update (e.g., label changes).
 		if gitEvent.ObjectAttributes.Action == "update" && gitEvent.ObjectAttributes.OldRev == "" {
@@ -50,12 +64,16 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			return setLoggerAndProceed(true, "", nil)
 		}
 
+		if gitEvent.ObjectAttributes.Action == mergeRequestApprovedAction {
+			return setLoggerAndProceed(true, "", nil)
+		}
+
 		// on a MR Update only react when there is Oldrev set, since this means
 		// there is a Push of commit in there
 		if gitEvent.ObjectAttributes.Action == "update" && gitEvent.ObjectAttributes.OldRev != "" {
 			return setLoggerAndProceed(true, "", nil)
 		}
-		if provider.Valid(gitEvent.ObjectAttributes.Action, []string{"open", "reopen", "close"}) {
+		if provider.Valid(gitEvent.ObjectAttributes.Action, []string{"open", "reopen", "close", mergeRequestMergedAction}) {
 			return setLoggerAndProceed(true, "", nil)
 		}
 
@@ -87,6 +105,13 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 
 // hasOnlyLabelsChanged checks if the only change in the merge request is to its labels.
 // This function ensures that other fields remain unchanged.
+// isMarkedAsReady returns true when the update of a Merge Request is the removal
+// of its draft status, i.e: the "Draft:" prefix removed from its title.
+func isMarkedAsReady(gitEvent *gitlab.MergeEvent) bool {
+	return gitEvent.ObjectAttributes.Action == "update" &&
+		gitEvent.Changes.Draft.Previous && !gitEvent.Changes.Draft.Current
+}
+
 func hasOnlyLabelsChanged(gitEvent *gitlab.MergeEvent) bool {
 	changes := gitEvent.Changes
 
@@ -65,6 +65,34 @@ func TestProvider_Detect(t *testing.T) {
 			isGL:       true,
 			processReq: false,
 		},
+		{
+			name:       "good/mergeRequest marked as ready Event",
+			event:      `{"object_attributes": {"action": "update"}, "changes": {"draft": {"previous": true, "current": false}}}`,
+			eventType:  gitlab.EventTypeMergeRequest,
+			isGL:       true,
+			processReq: true,
+		},
+		{
+			name:       "bad/mergeRequest marked as draft Event",
+			event:      `{"object_attributes": {"action": "update"}, "changes": {"draft": {"previous": false, "current": true}}}`,
+			eventType:  gitlab.EventTypeMergeRequest,
+			isGL:       true,
+			processReq: false,
+		},
+		{
+			name:       "good/mergeRequest merge Event",
+			event:      sample.MREventAsJSON("merge", ""),
+			eventType:  gitlab.EventTypeMergeRequest,
+			isGL:       true,
+			processReq: true,
+		},
+		{
+			name:       "good/mergeRequest approved Event",
+			event:      sample.MREventAsJSON("approved", ""),
+			eventType:  gitlab.EventTypeMergeRequest,
+			isGL:       true,
+			processReq: true,
+		},
 		{
 			name:       "good/mergeRequest update Event with commit",
 			event:      sample.MREventAsJSON("update", `"oldrev": "123"`),
@@ -145,6 +145,24 @@ func (v *Provider) CreateComment(_ context.Context, event *info.Event, commit, u
 	return nil
 }
 
+// CreateCommitComment posts a comment on the commit of the event.
+func (v *Provider) CreateCommitComment(_ context.Context, event *info.Event, comment string) error {
+	if v.gitlabClient == nil {
+		return fmt.Errorf("no gitlab client has been initialized")
+	}
+
+	if event.SHA == "" {
+		return fmt.Errorf("create commit comment needs a commit SHA")
+	}
+
+	if _, _, err := v.Client().Commits.PostCommitComment(event.SourceProjectID, event.SHA, &gitlab.PostCommitCommentOptions{
+		Note: &comment,
+	}); err != nil {
+		return fmt.Errorf("unable to create commit comment: %w", err)
+	}
+	return nil
+}
+
 // CheckPolicyAllowing TODO: Implement ME.
 func (v *Provider) CheckPolicyAllowing(_ context.Context, _ *info.Event, _ []string) (bool, string) {
 	return false, ""
@@ -276,14 +294,11 @@ func (v *Provider) SetClient(_ context.Context, run *params.Run, runevent *info.
 	return nil
 }
 
+// setCommitStatusState sets the state of the commit status of statusOpts in
+// its conclusion, and the title matching the state.
+//
 //nolint:misspell
-func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts provider.StatusOpts,
-) error {
-	var detailsURL string
-	if v.gitlabClient == nil {
-		return fmt.Errorf("no gitlab client has been initialized, " +
-			"exiting... (hint: did you forget setting a secret on your repo?)")
-	}
+func setCommitStatusState(statusOpts *provider.StatusOpts) {
 	switch statusOpts.Conclusion {
 	case "skipped":
 		statusOpts.Conclusion = "canceled"
@@ -311,6 +326,40 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts
 	if statusOpts.Status == "in_progress" {
 		statusOpts.Conclusion = "running"
 	}
+}
+
+// UpdateStatusDetails replaces the description of the commit status of the
+// PipelineRun, GitLab has no check runs to add text to. Unlike CreateStatus,
+// nothing is commented on the merge request when the status cannot be set.
+func (v *Provider) UpdateStatusDetails(_ context.Context, event *info.Event, statusOpts provider.StatusOpts, _, description string) error {
+	if v.gitlabClient == nil {
+		return fmt.Errorf("no gitlab client has been initialized, " +
+			"exiting... (hint: did you forget setting a secret on your repo?)")
+	}
+	setCommitStatusState(&statusOpts)
+	contextName := provider.GetCheckName(statusOpts, v.pacInfo)
+	opt := &gitlab.SetCommitStatusOptions{
+		State:       gitlab.BuildStateValue(statusOpts.Conclusion),
+		Name:        gitlab.Ptr(contextName),
+		TargetURL:   gitlab.Ptr(statusOpts.DetailsURL),
+		Description: gitlab.Ptr(description),
+		Context:     gitlab.Ptr(contextName),
+	}
+	if _, _, err := v.Client().Commits.SetCommitStatus(event.SourceProjectID, event.SHA, opt); err == nil {
+		return nil
+	}
+	_, _, err := v.Client().Commits.SetCommitStatus(event.TargetProjectID, event.SHA, opt)
+	return err
+}
+
+func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts provider.StatusOpts,
+) error {
+	var detailsURL string
+	if v.gitlabClient == nil {
+		return fmt.Errorf("no gitlab client has been initialized, " +
+			"exiting... (hint: did you forget setting a secret on your repo?)")
+	}
+	setCommitStatusState(&statusOpts)
 	if statusOpts.DetailsURL != "" {
 		detailsURL = statusOpts.DetailsURL
 	}
@@ -555,7 +604,7 @@ func (v *Provider) fetchChangedFiles(_ context.Context, runevent *info.Event) (c
 	changedFiles := changedfiles.ChangedFiles{}
 
 	switch runevent.TriggerTarget {
-	case triggertype.PullRequest:
+	case triggertype.PullRequest, triggertype.PullRequestClosed:
 		opt := &gitlab.ListMergeRequestDiffsOptions{
 			ListOptions: gitlab.ListOptions{
 				OrderBy:    "id",
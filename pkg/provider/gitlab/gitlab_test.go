@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
@@ -438,6 +439,77 @@ func TestCreateStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateStatusDetails(t *testing.T) {
+	tests := []struct {
+		name            string
+		sourceStatusErr bool
+		targetStatusErr bool
+		wantErr         string
+	}{
+		{
+			name: "status of the source project",
+		},
+		{
+			name:            "status of the target project",
+			sourceStatusErr: true,
+		},
+		{
+			name:            "no status",
+			sourceStatusErr: true,
+			targetStatusErr: true,
+			wantErr:         "403 Forbidden",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, tearDown := thelp.Setup(t)
+			defer tearDown()
+			v := &Provider{
+				pacInfo: &info.PacOpts{
+					Settings: settings.Settings{
+						ApplicationName: settings.PACApplicationNameDefaultValue,
+					},
+				},
+			}
+			v.SetGitLabClient(client)
+			event := &info.Event{SHA: "sha", SourceProjectID: 10, TargetProjectID: 20, PullRequestNumber: 666, EventType: "Merge Request"}
+
+			statuses := 0
+			for projectID, fail := range map[int]bool{10: tt.sourceStatusErr, 20: tt.targetStatusErr} {
+				mux.HandleFunc(fmt.Sprintf("/projects/%d/statuses/sha", projectID), func(rw http.ResponseWriter, r *http.Request) {
+					if fail {
+						rw.WriteHeader(http.StatusForbidden)
+						fmt.Fprint(rw, `{"message": "403 Forbidden"}`)
+						return
+					}
+					body, _ := io.ReadAll(r.Body)
+					opts := map[string]string{}
+					assert.NilError(t, json.Unmarshal(body, &opts))
+					assert.Equal(t, opts["state"], "failed")
+					assert.Equal(t, opts["description"], "AI Analysis: the analysis")
+					statuses++
+					rw.WriteHeader(http.StatusCreated)
+					fmt.Fprint(rw, `{}`)
+				})
+			}
+			mux.HandleFunc("/projects/20/merge_requests/666/notes", func(_ http.ResponseWriter, _ *http.Request) {
+				t.Error("the status should not be commented on the merge request")
+			})
+
+			status := provider.StatusOpts{Status: "completed", Conclusion: "failure", Text: "the task failed"}
+			err := v.UpdateStatusDetails(ctx, event, status, "the analysis", "AI Analysis: the analysis")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Equal(t, statuses, 0)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, statuses, 1)
+		})
+	}
+}
+
 func TestGetCommitInfo(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -57,6 +57,7 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 		processedEvent.BaseURL = gitEvent.ObjectAttributes.Target.WebURL
 		processedEvent.PullRequestNumber = int(gitEvent.ObjectAttributes.IID)
 		processedEvent.PullRequestTitle = gitEvent.ObjectAttributes.Title
+		processedEvent.IsDraft = gitEvent.ObjectAttributes.Draft || gitEvent.ObjectAttributes.WorkInProgress
 		v.targetProjectID = gitEvent.Project.ID
 		v.sourceProjectID = gitEvent.ObjectAttributes.SourceProjectID
 		v.userID = gitEvent.User.ID
@@ -76,8 +77,15 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 		for _, label := range gitEvent.Labels {
 			processedEvent.PullRequestLabel = append(processedEvent.PullRequestLabel, label.Title)
 		}
-		if gitEvent.ObjectAttributes.Action == "close" {
+		if provider.Valid(gitEvent.ObjectAttributes.Action, []string{"close", mergeRequestMergedAction}) {
 			processedEvent.TriggerTarget = triggertype.PullRequestClosed
+			processedEvent.PullRequestMerged = gitEvent.ObjectAttributes.Action == mergeRequestMergedAction
+		}
+		// GitLab has no review event, the approval of the Merge Request by
+		// the sender is the closest.
+		if gitEvent.ObjectAttributes.Action == mergeRequestApprovedAction {
+			processedEvent.EventType = triggertype.PullRequestReview.String()
+			processedEvent.PullRequestReviewState = "approved"
 		}
 	case *gitlab.TagEvent:
 		// GitLab sends same event for both Tag creation and deletion i.e. "Tag Push Hook".
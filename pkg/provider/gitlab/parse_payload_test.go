@@ -104,6 +104,34 @@ func TestParsePayload(t *testing.T) {
 				Repository:    "project",
 			},
 		},
+		{
+			name: "merge event merged",
+			args: args{
+				event:   gitlab.EventTypeMergeRequest,
+				payload: sample.MREventAsJSON("merge", ""),
+			},
+			want: &info.Event{
+				EventType:         "Merge Request",
+				TriggerTarget:     triggertype.PullRequestClosed,
+				Organization:      "hello/this/is/me/ze",
+				Repository:        "project",
+				PullRequestMerged: true,
+			},
+		},
+		{
+			name: "merge event approved",
+			args: args{
+				event:   gitlab.EventTypeMergeRequest,
+				payload: sample.MREventAsJSON("approved", ""),
+			},
+			want: &info.Event{
+				EventType:              triggertype.PullRequestReview.String(),
+				TriggerTarget:          "pull_request",
+				Organization:           "hello/this/is/me/ze",
+				Repository:             "project",
+				PullRequestReviewState: "approved",
+			},
+		},
 		{
 			name: "push event no commits",
 			args: args{
@@ -509,6 +537,8 @@ func TestParsePayload(t *testing.T) {
 				if tt.want.BaseBranch != "" {
 					assert.Equal(t, tt.want.BaseBranch, got.BaseBranch)
 				}
+				assert.Equal(t, tt.want.PullRequestReviewState, got.PullRequestReviewState)
+				assert.Equal(t, tt.want.PullRequestMerged, got.PullRequestMerged)
 			}
 		})
 	}
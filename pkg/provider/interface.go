@@ -47,6 +47,13 @@ type Interface interface {
 	CheckPolicyAllowing(context.Context, *info.Event, []string) (bool, string)
 	GetTemplate(CommentType) string
 	CreateComment(ctx context.Context, event *info.Event, comment, updateMarker string) error
+	CreateCommitComment(ctx context.Context, event *info.Event, comment string) error
+	// UpdateStatusDetails adds details to the status reported with statusOpts,
+	// without changing its state and without commenting on the pull request:
+	// text is appended to the text of the check run on the providers with
+	// check runs, description replaces the description of the commit status
+	// otherwise.
+	UpdateStatusDetails(ctx context.Context, event *info.Event, statusOpts StatusOpts, text, description string) error
 }
 
 const DefaultProviderAPIUser = "git"
@@ -0,0 +1,185 @@
+package plaingit
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+const (
+	headRef       = "HEAD"
+	symrefPrefix  = "ref: "
+	peeledSuffix  = "^{}"
+	branchPrefix  = "refs/heads/"
+	maskedSecret  = "*****"
+	workDirPrefix = "pac-plaingit-"
+
+	// gitTimeout is the maximum time a git command can take, a remote which
+	// hangs must not block the other repositories.
+	gitTimeout = 2 * time.Minute
+)
+
+// LsRemote lists the branches and tags of a remote repository, it returns the
+// commit every reference points to, annotated tags are peeled to their
+// commit, and the branch HEAD points to which is the default branch.
+func LsRemote(ctx context.Context, remoteURL, user, token string) (map[string]string, string, error) {
+	env, err := credentialsEnv(remoteURL, user, token)
+	if err != nil {
+		return nil, "", err
+	}
+	return lsRemote(ctx, remoteURL, env, token)
+}
+
+func lsRemote(ctx context.Context, remoteURL string, env []string, secret string) (map[string]string, string, error) {
+	out, err := runGit(ctx, "", env, secret, "ls-remote", "--symref", remoteURL)
+	if err != nil {
+		return nil, "", err
+	}
+	refs, defaultBranch := parseLsRemote(out)
+	return refs, defaultBranch, nil
+}
+
+// parseLsRemote parses the output of git ls-remote --symref, the lines which
+// are not references are skipped.
+func parseLsRemote(out string) (map[string]string, string) {
+	refs := map[string]string{}
+	peeled := map[string]string{}
+	defaultBranch := ""
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.HasPrefix(fields[0], symrefPrefix) {
+			if fields[1] == headRef {
+				defaultBranch = strings.TrimPrefix(strings.TrimPrefix(fields[0], symrefPrefix), branchPrefix)
+			}
+			continue
+		}
+		if !strings.HasPrefix(fields[1], "refs/heads/") && !strings.HasPrefix(fields[1], "refs/tags/") {
+			continue
+		}
+		if ref, ok := strings.CutSuffix(fields[1], peeledSuffix); ok {
+			peeled[ref] = fields[0]
+			continue
+		}
+		refs[fields[1]] = fields[0]
+	}
+	for ref, sha := range peeled {
+		refs[ref] = sha
+	}
+	return refs, defaultBranch
+}
+
+// credentialsEnv returns the environment passing the credentials of a http(s)
+// remote to git as an authorization header for that remote only, the
+// credentials are not in the arguments of the git commands where any process
+// could read them. Other transports get no credentials.
+func credentialsEnv(remoteURL, user, token string) ([]string, error) {
+	if token == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository url %s: %w", remoteURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, nil
+	}
+	if user == "" {
+		user = provider.DefaultProviderAPIUser
+	}
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http." + remoteURL + ".extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+token)),
+	}, nil
+}
+
+// runGit runs git in dir with the environment env and returns its standard
+// output. The secret is masked from the error in case git reports it.
+func runGit(ctx context.Context, dir string, env []string, secret string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+
+	out, err := git.RunGitContext(ctx, dir, append([]string{"GIT_TERMINAL_PROMPT=0"}, env...), args...)
+	if err != nil {
+		msg := err.Error()
+		if secret != "" {
+			escaped := strings.TrimPrefix(url.UserPassword("", secret).String(), ":")
+			msg = strings.ReplaceAll(strings.ReplaceAll(msg, escaped, maskedSecret), secret, maskedSecret)
+		}
+		return "", fmt.Errorf("%s", msg)
+	}
+	return out, nil
+}
+
+// fetch fetches a revision from the remote in the bare work directory with
+// the given depth and returns the commit it points to.
+func (v *Provider) fetch(ctx context.Context, rev string, depth int) (string, error) {
+	if v.repoURL == "" {
+		return "", fmt.Errorf("%s", noClientErrStr)
+	}
+	key := fmt.Sprintf("%s@%d", rev, depth)
+	if sha, ok := v.fetched[key]; ok {
+		return sha, nil
+	}
+	if v.workDir == "" {
+		workDir, err := os.MkdirTemp("", workDirPrefix)
+		if err != nil {
+			return "", err
+		}
+		if _, err := runGit(ctx, workDir, nil, "", "init", "--bare", "-q"); err != nil {
+			_ = os.RemoveAll(workDir)
+			return "", err
+		}
+		v.workDir = workDir
+		v.fetched = map[string]string{}
+	}
+	if _, err := runGit(ctx, v.workDir, v.gitEnv, v.token, "fetch", "-q", "--no-tags", "--depth="+strconv.Itoa(depth), v.repoURL, rev); err != nil {
+		return "", fmt.Errorf("cannot fetch %s: %w", rev, err)
+	}
+	out, err := runGit(ctx, v.workDir, nil, "", "rev-parse", "FETCH_HEAD^{commit}")
+	if err != nil {
+		return "", err
+	}
+	sha := strings.TrimSpace(out)
+	v.fetched[key] = sha
+	return sha, nil
+}
+
+// readFile reads the content of a file at a fetched commit.
+func (v *Provider) readFile(ctx context.Context, sha, path string) (string, error) {
+	out, err := runGit(ctx, v.workDir, nil, "", "ls-tree", "--name-only", sha, "--", path)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(out) == "" {
+		return "", fmt.Errorf("cannot find %s inside the %s repository", path, v.repoURL)
+	}
+	return runGit(ctx, v.workDir, nil, "", "cat-file", "blob", sha+":"+path)
+}
+
+// Cleanup removes the work directory where the revisions have been fetched.
+func (v *Provider) Cleanup() {
+	if v.workDir == "" {
+		return
+	}
+	if err := os.RemoveAll(v.workDir); err != nil && v.Logger != nil {
+		v.Logger.Warnf("cannot remove the work directory %s: %v", v.workDir, err)
+	}
+	v.workDir = ""
+	v.fetched = nil
+}
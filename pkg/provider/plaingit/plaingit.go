@@ -0,0 +1,306 @@
+package plaingit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/changedfiles"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/plaingit/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// ProviderName is the name of the provider, as set in the git-provider
+	// annotation of the PipelineRuns.
+	ProviderName = "git"
+
+	taskStatusTemplate = `{{range $taskrun := .TaskRunList }}* {{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }} {{ $taskrun.ConsoleLogURL }} ({{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.PipelineRunTaskRunStatus.Status.CompletionTime }})
+{{ end }}`
+	noClientErrStr = `no git repository has been initialized, exiting...`
+
+	// commitFormat is the git log format of the commit information, the fields
+	// are separated by a NUL character.
+	commitFormat = "%H%x00%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%cI%x00%B"
+)
+
+var _ provider.Interface = (*Provider)(nil)
+
+// Provider reads the content of a plain git repository from a shallow fetch of
+// the revisions it needs. Plain git servers have no API, the events are
+// synthesized by the poller and there is nowhere to report the status to.
+type Provider struct {
+	Logger       *zap.SugaredLogger
+	run          *params.Run
+	pacInfo      *info.PacOpts
+	repo         *v1alpha1.Repository
+	eventEmitter *events.EventEmitter
+	token        string
+	repoURL      string
+	// gitEnv is the environment passing the credentials to git.
+	gitEnv             []string
+	workDir            string
+	fetched            map[string]string
+	cachedChangedFiles *changedfiles.ChangedFiles
+}
+
+func (v *Provider) SetLogger(logger *zap.SugaredLogger) {
+	v.Logger = logger
+}
+
+func (v *Provider) SetPacInfo(pacInfo *info.PacOpts) {
+	v.pacInfo = pacInfo
+}
+
+func (v *Provider) GetTaskURI(_ context.Context, _ *info.Event, _ string) (bool, string, error) {
+	return false, "", nil
+}
+
+func (v *Provider) CreateToken(_ context.Context, _ []string, _ *info.Event) (string, error) {
+	return "", nil
+}
+
+func (v *Provider) GetTemplate(commentType provider.CommentType) string {
+	return provider.GetMarkdownTemplate(commentType)
+}
+
+func (v *Provider) GetConfig() *info.ProviderConfig {
+	return &info.ProviderConfig{
+		TaskStatusTMPL: taskStatusTemplate,
+		Name:           ProviderName,
+		SkipEmoji:      true,
+	}
+}
+
+// Validate has nothing to validate, the events are not coming from a webhook.
+func (v *Provider) Validate(_ context.Context, _ *params.Run, _ *info.Event) error {
+	return nil
+}
+
+// Detect never detects anything, plain git servers cannot send webhooks.
+func (v *Provider) Detect(_ *http.Request, _ string, logger *zap.SugaredLogger) (bool, bool, *zap.SugaredLogger, string, error) {
+	return false, false, logger, "", nil
+}
+
+func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, _ *http.Request, _ string) (*info.Event, error) {
+	return nil, fmt.Errorf("plain git repositories do not send any payload")
+}
+
+// IsAllowed allows everything, the events are pushes to a repository the
+// admin has chosen to poll and only users with write access can push.
+func (v *Provider) IsAllowed(_ context.Context, _ *info.Event) (bool, error) {
+	return true, nil
+}
+
+func (v *Provider) IsAllowedOwnersFile(_ context.Context, _ *info.Event) (bool, error) {
+	return false, nil
+}
+
+func (v *Provider) CheckPolicyAllowing(_ context.Context, _ *info.Event, _ []string) (bool, string) {
+	return false, "policies are not supported on plain git repositories"
+}
+
+// CreateStatus only logs the status, the status of the runs is available on
+// the Repository CR.
+func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts provider.StatusOpts) error {
+	if v.Logger != nil {
+		v.Logger.Debugf("plain git repository %s has no status API, status of %s: %s/%s",
+			event.URL, event.SHA, statusOpts.Status, statusOpts.Conclusion)
+	}
+	return nil
+}
+
+func (v *Provider) CreateComment(_ context.Context, _ *info.Event, _, _ string) error {
+	return nil
+}
+
+func (v *Provider) UpdateStatusDetails(_ context.Context, _ *info.Event, _ provider.StatusOpts, _, _ string) error {
+	return nil
+}
+
+func (v *Provider) CreateCommitComment(_ context.Context, _ *info.Event, _ string) error {
+	return fmt.Errorf("commit comments are not supported on a plain git repository")
+}
+
+func (v *Provider) SetClient(_ context.Context, run *params.Run, event *info.Event, repo *v1alpha1.Repository, eventsEmitter *events.EventEmitter) error {
+	gitEnv, err := credentialsEnv(event.URL, event.Provider.User, event.Provider.Token)
+	if err != nil {
+		return err
+	}
+	v.run = run
+	v.repo = repo
+	v.eventEmitter = eventsEmitter
+	v.token = event.Provider.Token
+	v.repoURL = event.URL
+	v.gitEnv = gitEnv
+	if v.Logger == nil && run != nil {
+		v.Logger = run.Clients.Log
+	}
+	return nil
+}
+
+func (v *Provider) GetTektonDir(ctx context.Context, event *info.Event, path, provenance string) (string, error) {
+	// default set provenance from the SHA
+	revision := event.SHA
+	if provenance == "default_branch" {
+		revision = event.DefaultBranch
+		v.Logger.Infof("Using PipelineRun definition from default_branch: %s", event.DefaultBranch)
+	} else {
+		v.Logger.Infof("Using PipelineRun definition from source %s commit SHA: %s", event.TriggerTarget.String(), event.SHA)
+	}
+
+	sha, err := v.fetch(ctx, revision, 1)
+	if err != nil {
+		return "", err
+	}
+	out, err := runGit(ctx, v.workDir, nil, "", "ls-tree", "-r", "--name-only", sha, "--", strings.TrimSuffix(path, "/")+"/")
+	if err != nil {
+		return "", fmt.Errorf("failed to list %s dir: %w", path, err)
+	}
+
+	var allTemplates string
+	for _, fpath := range strings.Split(out, "\n") {
+		if !strings.HasSuffix(fpath, ".yaml") && !strings.HasSuffix(fpath, ".yml") {
+			continue
+		}
+		data, err := v.readFile(ctx, sha, fpath)
+		if err != nil {
+			return "", err
+		}
+		if err := provider.ValidateYaml([]byte(data), fpath); err != nil {
+			return "", err
+		}
+		if allTemplates != "" && !strings.HasPrefix(data, "---") {
+			allTemplates += "---"
+		}
+		allTemplates += "\n" + data + "\n"
+	}
+	return allTemplates, nil
+}
+
+func (v *Provider) GetFileInsideRepo(ctx context.Context, event *info.Event, path, target string) (string, error) {
+	revision := event.SHA
+	if target != "" {
+		revision = target
+	}
+	sha, err := v.fetch(ctx, revision, 1)
+	if err != nil {
+		return "", err
+	}
+	return v.readFile(ctx, sha, path)
+}
+
+func (v *Provider) GetCommitInfo(ctx context.Context, event *info.Event) error {
+	revision := event.SHA
+	if revision == "" {
+		revision = event.HeadBranch
+	}
+	sha, err := v.fetch(ctx, revision, 1)
+	if err != nil {
+		return err
+	}
+	out, err := runGit(ctx, v.workDir, nil, "", "log", "-1", "--format="+commitFormat, sha)
+	if err != nil {
+		return err
+	}
+	fields := strings.SplitN(out, "\x00", 8)
+	if len(fields) != 8 {
+		return fmt.Errorf("cannot parse the information of commit %s", sha)
+	}
+	event.SHA = fields[0]
+	event.SHAAuthorName = fields[1]
+	event.SHAAuthorEmail = fields[2]
+	event.SHAAuthorDate, _ = time.Parse(time.RFC3339, fields[3])
+	event.SHACommitterName = fields[4]
+	event.SHACommitterEmail = fields[5]
+	event.SHACommitterDate, _ = time.Parse(time.RFC3339, fields[6])
+	event.SHAMessage = strings.TrimSpace(fields[7])
+	event.SHATitle = strings.Split(event.SHAMessage, "\n")[0]
+	event.HasSkipCommand = provider.SkipCI(event.SHAMessage)
+
+	if event.DefaultBranch == "" {
+		_, defaultBranch, err := lsRemote(ctx, v.repoURL, v.gitEnv, v.token)
+		if err != nil {
+			return err
+		}
+		event.DefaultBranch = defaultBranch
+	}
+	return nil
+}
+
+// GetFiles gets the files changed by the push, compared to the commit the
+// reference was pointing to on the previous poll, or to the parent commit
+// when the reference is new.
+func (v *Provider) GetFiles(ctx context.Context, event *info.Event) (changedfiles.ChangedFiles, error) {
+	if v.cachedChangedFiles == nil {
+		changes, err := v.fetchChangedFiles(ctx, event)
+		if err != nil {
+			return changedfiles.ChangedFiles{}, err
+		}
+		v.cachedChangedFiles = &changes
+	}
+	return *v.cachedChangedFiles, nil
+}
+
+func (v *Provider) fetchChangedFiles(ctx context.Context, event *info.Event) (changedfiles.ChangedFiles, error) {
+	before := ""
+	if pushEvent, ok := event.Event.(*types.PushEvent); ok && !provider.IsZeroSHA(pushEvent.Before) {
+		before = pushEvent.Before
+	}
+
+	var out string
+	if before != "" {
+		sha, err := v.fetch(ctx, event.SHA, 1)
+		if err != nil {
+			return changedfiles.ChangedFiles{}, err
+		}
+		if before, err = v.fetch(ctx, before, 1); err != nil {
+			return changedfiles.ChangedFiles{}, err
+		}
+		if out, err = runGit(ctx, v.workDir, nil, "", "diff", "--name-status", "-M", before, sha); err != nil {
+			return changedfiles.ChangedFiles{}, err
+		}
+	} else {
+		sha, err := v.fetch(ctx, event.SHA, 2)
+		if err != nil {
+			return changedfiles.ChangedFiles{}, err
+		}
+		// --root shows the files of a commit without parent as added
+		if out, err = runGit(ctx, v.workDir, nil, "", "diff-tree", "--no-commit-id", "--name-status", "-M", "-r", "--root", sha); err != nil {
+			return changedfiles.ChangedFiles{}, err
+		}
+	}
+	return parseNameStatus(out), nil
+}
+
+// parseNameStatus parses the output of git diff --name-status.
+func parseNameStatus(out string) changedfiles.ChangedFiles {
+	changedFiles := changedfiles.ChangedFiles{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) < 2 || fields[0] == "" {
+			continue
+		}
+		// renames and copies have the old and the new path
+		fpath := fields[len(fields)-1]
+		changedFiles.All = append(changedFiles.All, fpath)
+		switch fields[0][0] {
+		case 'A', 'C':
+			changedFiles.Added = append(changedFiles.Added, fpath)
+		case 'D':
+			changedFiles.Deleted = append(changedFiles.Deleted, fpath)
+		case 'R':
+			changedFiles.Renamed = append(changedFiles.Renamed, fpath)
+		default:
+			changedFiles.Modified = append(changedFiles.Modified, fpath)
+		}
+	}
+	return changedFiles
+}
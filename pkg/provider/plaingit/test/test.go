@@ -0,0 +1,72 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"gotest.tools/v3/assert"
+)
+
+// Repository is a local git repository standing in for a plain git server.
+type Repository struct {
+	Dir string
+	URL string
+}
+
+// NewRepository creates a local git repository with a main branch.
+func NewRepository(t *testing.T) *Repository {
+	t.Helper()
+	dir := t.TempDir()
+	repo := &Repository{Dir: dir, URL: "file://" + dir}
+	repo.Git(t, "init", "-q", "-b", "main")
+	repo.Git(t, "config", "user.name", "Author")
+	repo.Git(t, "config", "user.email", "author@example.com")
+	return repo
+}
+
+// Git runs a git command in the repository and returns its output.
+func (r *Repository) Git(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := git.RunGit(r.Dir, args...)
+	assert.NilError(t, err)
+	return strings.TrimSpace(out)
+}
+
+// Commit writes the files, removes the files with an empty content and
+// commits them on the current branch, it returns the SHA of the commit.
+func (r *Repository) Commit(t *testing.T, message string, files map[string]string) string {
+	t.Helper()
+	for name, content := range files {
+		fpath := filepath.Join(r.Dir, name)
+		if content == "" {
+			assert.NilError(t, os.Remove(fpath))
+			continue
+		}
+		assert.NilError(t, os.MkdirAll(filepath.Dir(fpath), 0o755))
+		assert.NilError(t, os.WriteFile(fpath, []byte(content), 0o600))
+	}
+	r.Git(t, "add", "-A")
+	r.Git(t, "commit", "-q", "--allow-empty", "-m", message)
+	return r.Git(t, "rev-parse", "HEAD")
+}
+
+// DirFiles reads the files of a local directory, keyed by their path inside
+// dirName, to be committed with Commit.
+func DirFiles(t *testing.T, testDir, dirName string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	entries, err := os.ReadDir(testDir)
+	assert.NilError(t, err)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(testDir, entry.Name()))
+		assert.NilError(t, err)
+		files[filepath.Join(dirName, entry.Name())] = string(content)
+	}
+	return files
+}
@@ -0,0 +1,14 @@
+//revive:disable-next-line:var-naming
+package types
+
+// PushEvent is the event synthesized by the poller when a reference of a
+// plain git repository has changed.
+type PushEvent struct {
+	// Ref is the full name of the reference, ie: refs/heads/main.
+	Ref string `json:"ref"`
+	// Before is the commit the reference pointed to on the previous poll, it
+	// is empty when the reference has been created.
+	Before string `json:"before,omitempty"`
+	// After is the commit the reference points to.
+	After string `json:"after"`
+}
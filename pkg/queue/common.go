@@ -7,11 +7,12 @@ import (
 type Semaphore interface {
 	acquire(string) bool
 	acquireLatest() string
+	nextPending() string
 	tryAcquire(string) (bool, string)
 	release(string) bool
 	resize(int) bool
-	addToQueue(string, time.Time) bool
-	addToPendingQueue(string, time.Time) bool
+	addToQueue(string, int, time.Time) bool
+	addToPendingQueue(string, int, time.Time) bool
 	removeFromQueue(string)
 	getName() string
 	getLimit() int
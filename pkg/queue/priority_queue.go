@@ -8,8 +8,11 @@ type (
 	key = string
 )
 
+// item is a pending key, the items with the highest class come first and
+// then the ones with the lowest priority, the creation time.
 type item struct {
 	key      string
+	class    int
 	priority int64
 	index    int
 }
@@ -26,11 +29,17 @@ func (pq *priorityQueue) isPending(key key) bool {
 	return false
 }
 
-func (pq *priorityQueue) add(key key, priority int64) {
-	if _, ok := pq.itemByKey[key]; ok {
+// add adds the key to the queue, the class of a key already pending is
+// updated as its priority may have been changed.
+func (pq *priorityQueue) add(key key, class int, priority int64) {
+	if item, ok := pq.itemByKey[key]; ok {
+		if item.class != class {
+			item.class = class
+			heap.Fix(pq, item.index)
+		}
 		return
 	}
-	heap.Push(pq, &item{key: key, priority: priority})
+	heap.Push(pq, &item{key: key, class: class, priority: priority})
 }
 
 func (pq *priorityQueue) remove(key key) {
@@ -52,6 +61,9 @@ func (pq *priorityQueue) peek() *item {
 func (pq priorityQueue) Len() int { return len(pq.items) }
 
 func (pq priorityQueue) Less(i, j int) bool {
+	if pq.items[i].class != pq.items[j].class {
+		return pq.items[i].class > pq.items[j].class
+	}
 	return pq.items[i].priority < pq.items[j].priority
 }
 
@@ -18,10 +18,10 @@ func TestPriorityQueue(t *testing.T) {
 	// adding items with random priorities
 	// priority is creation time hence the item with less priority number
 	// will be on top of Queue
-	pq.add("item-a", 13)
-	pq.add("item-b", 3)
-	pq.add("item-c", 7)
-	pq.add("item-d", 2)
+	pq.add("item-a", 0, 13)
+	pq.add("item-b", 0, 3)
+	pq.add("item-c", 0, 7)
+	pq.add("item-d", 0, 2)
 
 	// number of items
 	assert.Equal(t, pq.Len(), 4)
@@ -46,8 +46,44 @@ func TestPriorityQueue(t *testing.T) {
 	assert.Equal(t, pq.Len(), 2)
 
 	// changing priority
-	pq.add("item-a", 1)
+	pq.add("item-a", 0, 1)
 
 	// check the top most
 	assert.Equal(t, pq.peek().key, "item-c")
 }
+
+func TestPriorityQueueClass(t *testing.T) {
+	pq := &priorityQueue{itemByKey: make(map[string]*item)}
+
+	// the items of the highest class come first whatever their creation
+	// time, then the oldest ones
+	pq.add("dependabot-1", 0, 1)
+	pq.add("dependabot-2", 0, 2)
+	pq.add("hotfix", 100, 5)
+	pq.add("release", 10, 3)
+	pq.add("hotfix-2", 100, 4)
+
+	order := []string{}
+	for pq.Len() > 0 {
+		order = append(order, pq.pop().key)
+	}
+	assert.DeepEqual(t, order, []string{"hotfix-2", "hotfix", "release", "dependabot-1", "dependabot-2"})
+}
+
+func TestPriorityQueueUpdateClass(t *testing.T) {
+	pq := &priorityQueue{itemByKey: make(map[string]*item)}
+
+	pq.add("first", 0, 1)
+	pq.add("second", 0, 2)
+	pq.add("third", 0, 3)
+
+	// adding a pending key again updates its class, not its creation time
+	pq.add("third", 1, 10)
+	pq.add("first", 0, 10)
+
+	order := []string{}
+	for pq.Len() > 0 {
+		order = append(order, pq.pop().key)
+	}
+	assert.DeepEqual(t, order, []string{"third", "first", "second"})
+}
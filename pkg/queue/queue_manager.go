@@ -3,6 +3,9 @@ package queue
 import (
 	"context"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -17,52 +20,176 @@ import (
 	"go.uber.org/zap"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
 	creationTimestamp = "{.metadata.creationTimestamp}"
+	// unlimited is the size of the semaphore of a queue without limit, its
+	// pipelineRuns are only limited by the namespace and global limits.
+	unlimited = math.MaxInt32
 )
 
 type Manager struct {
 	queueMap map[string]Semaphore
 	lock     *sync.Mutex
 	logger   *zap.SugaredLogger
+
+	// globalLimit and namespaceLimits are the maximum number of running
+	// pipelineRuns of all the queues and of the queues of a namespace.
+	globalLimit     int
+	namespaceLimits map[string]int
+	// lastQueue is the queue which started the last pipelineRun when a
+	// namespace or global limit is set, the queues are visited in a
+	// round-robin order from it.
+	lastQueue string
 }
 
 func NewManager(logger *zap.SugaredLogger) *Manager {
 	return &Manager{
-		queueMap: make(map[string]Semaphore),
-		lock:     &sync.Mutex{},
-		logger:   logger,
+		queueMap:        make(map[string]Semaphore),
+		lock:            &sync.Mutex{},
+		logger:          logger,
+		namespaceLimits: make(map[string]int),
 	}
 }
 
-// getSemaphore returns existing semaphore created for repository or create
-// a new one with limit provided in repository
-// Semaphore: nothing but a waiting and a running queue for a repository
-// with limit deciding how many should be running at a time.
-func (qm *Manager) getSemaphore(repo *v1alpha1.Repository) (Semaphore, error) {
-	repoKey := RepoKey(repo)
+// SetGlobalLimit sets the maximum number of running pipelineRuns of all the
+// queues, 0 means no limit.
+func (qm *Manager) SetGlobalLimit(limit int) {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	qm.globalLimit = limit
+}
+
+// SetNamespaceLimit sets the maximum number of running pipelineRuns of the
+// queues of the namespace, 0 means no limit.
+func (qm *Manager) SetNamespaceLimit(namespace string, limit int) {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	if limit == 0 {
+		delete(qm.namespaceLimits, namespace)
+		return
+	}
+	qm.namespaceLimits[namespace] = limit
+}
+
+// NamespaceLimit returns the maximum number of running pipelineRuns of the
+// queues of the namespace, 0 means no limit.
+func (qm *Manager) NamespaceLimit(namespace string) int {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	return qm.namespaceLimits[namespace]
+}
+
+// InitNamespaceLimits sets the limits of all the namespaces with a
+// pipelinesascode.tekton.dev/concurrency-limit annotation, the queues must
+// know them before they start any pipelineRun.
+func (qm *Manager) InitNamespaceLimits(ctx context.Context, kube kubernetes.Interface) error {
+	namespaces, err := kube.CoreV1().Namespaces().List(ctx, v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range namespaces.Items {
+		limit, err := NamespaceLimit(&namespaces.Items[i])
+		if err != nil {
+			qm.logger.Warn(err)
+		}
+		qm.SetNamespaceLimit(namespaces.Items[i].GetName(), limit)
+	}
+	return nil
+}
+
+// hasLimits returns true when a namespace or the global limit is set.
+func (qm *Manager) hasLimits() bool {
+	return qm.globalLimit > 0 || len(qm.namespaceLimits) > 0
+}
+
+// hasCapacity returns true when the namespace and the global limits allow one
+// more running pipelineRun for prKey.
+func (qm *Manager) hasCapacity(prKey string) bool {
+	namespace := strings.Split(prKey, "/")[0]
+	namespaceLimit := qm.namespaceLimits[namespace]
+	if qm.globalLimit == 0 && namespaceLimit == 0 {
+		return true
+	}
+
+	running, runningInNamespace := 0, 0
+	for _, sema := range qm.queueMap {
+		for _, key := range sema.getCurrentRunning() {
+			running++
+			if strings.HasPrefix(key, namespace+"/") {
+				runningInNamespace++
+			}
+		}
+	}
+	if qm.globalLimit > 0 && running >= qm.globalLimit {
+		return false
+	}
+	return namespaceLimit == 0 || runningInNamespace < namespaceLimit
+}
 
-	if sema, found := qm.queueMap[repoKey]; found {
-		if err := qm.checkAndUpdateSemaphoreSize(repo, sema); err != nil {
+// acquireFrom moves the next pipelineRun of the queue to running when the
+// queue, the namespace and the global limits allow it.
+func (qm *Manager) acquireFrom(queueKey string, sema Semaphore) string {
+	next := sema.nextPending()
+	if next == "" || !qm.hasCapacity(next) {
+		return ""
+	}
+	acquired := sema.acquireLatest()
+	if acquired != "" {
+		qm.lastQueue = queueKey
+		qm.logger.Infof("moved (%s) to running for (%s)", acquired, queueKey)
+	}
+	return acquired
+}
+
+// acquireNext moves the next pipelineRun of any queue to running, the queues
+// are visited in a round-robin order starting after the last queue which
+// started a pipelineRun so a busy repository does not starve the others.
+func (qm *Manager) acquireNext() string {
+	queueKeys := slices.Sorted(maps.Keys(qm.queueMap))
+	start, found := slices.BinarySearch(queueKeys, qm.lastQueue)
+	if found {
+		start++
+	}
+	for i := range queueKeys {
+		queueKey := queueKeys[(start+i)%len(queueKeys)]
+		if next := qm.acquireFrom(queueKey, qm.queueMap[queueKey]); next != "" {
+			return next
+		}
+	}
+	return ""
+}
+
+// getSemaphore returns existing semaphore created for the queue key or create
+// a new one with the limit of the queue
+// Semaphore: nothing but a waiting and a running queue for a repository or a
+// concurrency group with limit deciding how many should be running at a time.
+func (qm *Manager) getSemaphore(queueKey string, limit *int) (Semaphore, error) {
+	// can't assume callers have checked that the limit is set, a queue
+	// without limit is only limited by the namespace and global limits
+	size := unlimited
+	if limit != nil && *limit != 0 {
+		size = *limit
+	}
+
+	if sema, found := qm.queueMap[queueKey]; found {
+		if err := qm.checkAndUpdateSemaphoreSize(size, sema); err != nil {
 			return nil, err
 		}
 		return sema, nil
 	}
 
-	// create a new semaphore; can't assume callers have checked that ConcurrencyLimit is set
-	limit := 0
-	if repo.Spec.ConcurrencyLimit != nil {
-		limit = *repo.Spec.ConcurrencyLimit
-	}
-	qm.queueMap[repoKey] = newSemaphore(repoKey, limit)
+	qm.queueMap[queueKey] = newSemaphore(queueKey, size)
 
-	return qm.queueMap[repoKey], nil
+	return qm.queueMap[queueKey], nil
 }
 
-func (qm *Manager) checkAndUpdateSemaphoreSize(repo *v1alpha1.Repository, semaphore Semaphore) error {
-	limit := *repo.Spec.ConcurrencyLimit
+func (qm *Manager) checkAndUpdateSemaphoreSize(limit int, semaphore Semaphore) error {
 	if limit != semaphore.getLimit() {
 		if semaphore.resize(limit) {
 			return nil
@@ -72,92 +199,127 @@ func (qm *Manager) checkAndUpdateSemaphoreSize(repo *v1alpha1.Repository, semaph
 	return nil
 }
 
-// AddListToRunningQueue adds the pipelineRun to the waiting queue of the repository
-// and if it is at the top and ready to run which means currently running pipelineRun < limit
+// AddListToRunningQueue adds the pipelineRun to the waiting queue of the repository,
+// or of the concurrency group of run when it has one, and if it is at the top
+// and ready to run which means currently running pipelineRun < limit
 // then move it to running queue
-// This adds the pipelineRuns in the same order as in the list.
-func (qm *Manager) AddListToRunningQueue(repo *v1alpha1.Repository, list []string) ([]string, error) {
+// This adds the pipelineRuns in the same order as in the list, the
+// pipelineRuns with a higher priority are moved to running first.
+func (qm *Manager) AddListToRunningQueue(repo *v1alpha1.Repository, run *tektonv1.PipelineRun, list []*tektonv1.PipelineRun) ([]string, error) {
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
 
-	sema, err := qm.getSemaphore(repo)
+	queueKey, limit := QueueKey(repo, run)
+	sema, err := qm.getSemaphore(queueKey, limit)
 	if err != nil {
 		return []string{}, err
 	}
 
 	for _, pr := range list {
-		if sema.addToQueue(pr, time.Now()) {
-			qm.logger.Infof("added pipelineRun (%s) to running queue for repository (%s)", pr, RepoKey(repo))
+		if sema.addToQueue(PrKey(pr), Priority(pr), time.Now()) {
+			qm.logger.Infof("added pipelineRun (%s) to running queue for (%s)", PrKey(pr), queueKey)
 		}
 	}
 
 	// it is possible something besides PAC set the PipelineRun to Pending; if concurrency limit has not
-	// been set, return all the pending PipelineRuns; also, if the limit is zero, that also means do not throttle,
-	// so we return all the PipelinesRuns, the for loop below skips that case as well
-	if repo.Spec.ConcurrencyLimit == nil || *repo.Spec.ConcurrencyLimit == 0 {
-		return sema.getCurrentPending(), nil
-	}
-
+	// been set or is zero, the queue is unlimited and all the pending PipelinesRuns are moved to running
+	// unless a namespace or global limit is reached
 	acquiredList := []string{}
-	for i := 0; i < *repo.Spec.ConcurrencyLimit; i++ {
-		acquired := sema.acquireLatest()
-		if acquired != "" {
-			qm.logger.Infof("moved (%s) to running for repository (%s)", acquired, RepoKey(repo))
-			acquiredList = append(acquiredList, acquired)
+	for {
+		acquired := qm.acquireFrom(queueKey, sema)
+		if acquired == "" {
+			break
 		}
+		acquiredList = append(acquiredList, acquired)
 	}
 
 	return acquiredList, nil
 }
 
-func (qm *Manager) AddToPendingQueue(repo *v1alpha1.Repository, list []string) error {
+func (qm *Manager) AddToPendingQueue(repo *v1alpha1.Repository, run *tektonv1.PipelineRun, list []*tektonv1.PipelineRun) error {
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
 
-	sema, err := qm.getSemaphore(repo)
+	queueKey, limit := QueueKey(repo, run)
+	sema, err := qm.getSemaphore(queueKey, limit)
 	if err != nil {
 		return err
 	}
 
 	for _, pr := range list {
-		if sema.addToPendingQueue(pr, time.Now()) {
-			qm.logger.Infof("added pipelineRun (%s) to pending queue for repository (%s)", pr, RepoKey(repo))
+		if sema.addToPendingQueue(PrKey(pr), Priority(pr), time.Now()) {
+			qm.logger.Infof("added pipelineRun (%s) to pending queue for (%s)", PrKey(pr), queueKey)
 		}
 	}
 	return nil
 }
 
-func (qm *Manager) RemoveFromQueue(repoKey, prKey string) bool {
+// RemoveFromQueue removes the pipelineRun from the queue with the key
+// returned by QueueKey.
+func (qm *Manager) RemoveFromQueue(queueKey, prKey string) bool {
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
+	return qm.removeFromQueue(queueKey, prKey)
+}
 
-	sema, found := qm.queueMap[repoKey]
+// removeFromQueue is RemoveFromQueue with the lock held.
+func (qm *Manager) removeFromQueue(queueKey, prKey string) bool {
+	sema, found := qm.queueMap[queueKey]
 	if !found {
 		return false
 	}
 
 	sema.release(prKey)
 	sema.removeFromQueue(prKey)
-	qm.logger.Infof("removed (%s) for repository (%s)", prKey, repoKey)
+	qm.logger.Infof("removed (%s) for (%s)", prKey, queueKey)
 	return true
 }
 
+// RemovePipelineRun removes the pipelineRun from all the queues holding it,
+// for when its queue key cannot be computed because the pipelineRun or its
+// repository is gone.
+func (qm *Manager) RemovePipelineRun(prKey string) bool {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	removed := false
+	for queueKey, sema := range qm.queueMap {
+		if !slices.Contains(sema.getCurrentRunning(), prKey) && !slices.Contains(sema.getCurrentPending(), prKey) {
+			continue
+		}
+		sema.release(prKey)
+		sema.removeFromQueue(prKey)
+		qm.logger.Infof("removed (%s) for (%s)", prKey, queueKey)
+		removed = true
+	}
+	return removed
+}
+
+// RemoveAndTakeItemFromQueue removes run from its queue and returns the next
+// pipelineRun moved to running, the next pipelineRun of a concurrency group
+// can belong to another repository. When a namespace or global limit is set,
+// the next pipelineRun is taken from the queues in a round-robin order.
 func (qm *Manager) RemoveAndTakeItemFromQueue(repo *v1alpha1.Repository, run *tektonv1.PipelineRun) string {
-	repoKey := RepoKey(repo)
-	prKey := PrKey(run)
-	if !qm.RemoveFromQueue(repoKey, prKey) {
+	queueKey, _ := QueueKey(repo, run)
+
+	// the removal and the acquisition are done under the same lock, another
+	// pipelineRun must not take the place released in between.
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	if !qm.removeFromQueue(queueKey, PrKey(run)) {
 		return ""
 	}
-	sema, found := qm.queueMap[repoKey]
-	if !found {
-		return ""
+
+	if qm.hasLimits() {
+		return qm.acquireNext()
 	}
 
-	if next := sema.acquireLatest(); next != "" {
-		qm.logger.Infof("moved (%s) to running for repository (%s)", next, repoKey)
-		return next
+	sema, found := qm.queueMap[queueKey]
+	if !found {
+		return ""
 	}
-	return ""
+	return qm.acquireFrom(queueKey, sema)
 }
 
 // FilterPipelineRunByInProgress filters the given list of PipelineRun names to only include those
@@ -167,6 +329,16 @@ func (qm *Manager) RemoveAndTakeItemFromQueue(repo *v1alpha1.Repository, run *te
 // Returns A list of PipelineRun names that are in a "queued" state and have a pending status.
 func FilterPipelineRunByState(ctx context.Context, tekton versioned2.Interface, orderList []string, wantedStatus, wantedState string) []string {
 	orderedList := []string{}
+	for _, pr := range FilterPipelineRunsByState(ctx, tekton, orderList, wantedStatus, wantedState) {
+		orderedList = append(orderedList, PrKey(pr))
+	}
+	return orderedList
+}
+
+// FilterPipelineRunsByState is FilterPipelineRunByState returning the
+// PipelineRun objects.
+func FilterPipelineRunsByState(ctx context.Context, tekton versioned2.Interface, orderList []string, wantedStatus, wantedState string) []*tektonv1.PipelineRun {
+	orderedList := []*tektonv1.PipelineRun{}
 	for _, prName := range orderList {
 		prKey := strings.Split(prName, "/")
 		pr, err := tekton.TektonV1().PipelineRuns(prKey[0]).Get(ctx, prKey[1], v1.GetOptions{})
@@ -183,82 +355,96 @@ func FilterPipelineRunByState(ctx context.Context, tekton versioned2.Interface,
 			if wantedStatus != "" && pr.Spec.Status != tektonv1.PipelineRunSpecStatus(wantedStatus) {
 				continue
 			}
-			orderedList = append(orderedList, prName)
+			orderedList = append(orderedList, pr)
 		}
 	}
 	return orderedList
 }
 
 // InitQueues rebuild all the queues for all repository if concurrency is defined before
-// reconciler started reconciling them.
-func (qm *Manager) InitQueues(ctx context.Context, tekton versioned2.Interface, pac versioned.Interface) error {
+// reconciler started reconciling them. The concurrency groups of the global
+// repository are merged in every repository.
+func (qm *Manager) InitQueues(ctx context.Context, tekton versioned2.Interface, pac versioned.Interface, globalRepo *v1alpha1.Repository) error {
 	// fetch all repos
-	repos, err := pac.PipelinesascodeV1alpha1().Repositories("").List(ctx, v1.ListOptions{})
+	repoList, err := pac.PipelinesascodeV1alpha1().Repositories("").List(ctx, v1.ListOptions{})
 	if err != nil {
 		return err
 	}
 
-	// pipelineRuns from the namespace where repository is present
-	// those are required for creating queues
-	for _, repo := range repos.Items {
-		if repo.Spec.ConcurrencyLimit == nil || *repo.Spec.ConcurrencyLimit == 0 {
-			continue
+	// the repositories without limit are kept as their running pipelineRuns
+	// count in the namespace and global limits
+	repos := map[string]*v1alpha1.Repository{}
+	for i := range repoList.Items {
+		repo := &repoList.Items[i]
+		if globalRepo != nil {
+			repo.Spec.Merge(globalRepo.Spec)
 		}
+		repos[RepoKey(repo)] = repo
+	}
 
-		// add all pipelineRuns in started state to pending queue
-		prs, err := tekton.TektonV1().PipelineRuns(repo.Namespace).
-			List(ctx, v1.ListOptions{
-				LabelSelector: fmt.Sprintf("%s=%s", keys.State, kubeinteraction.StateStarted),
-			})
-		if err != nil {
-			return err
+	// the started pipelineRuns of all the repositories are added before the
+	// queued ones, a concurrency group is shared between repositories and a
+	// queued pipelineRun must not take the place of a started one.
+	sortedPRs, err := listPipelineRunsByState(ctx, tekton, kubeinteraction.StateStarted)
+	if err != nil {
+		return err
+	}
+	for _, pr := range sortedPRs {
+		repo, found := repos[fmt.Sprintf("%s/%s", pr.GetNamespace(), pr.GetAnnotations()[keys.Repository])]
+		if !found {
+			continue
 		}
-
-		// sort the pipelinerun by creation time before adding to queue
-		sortedPRs := sortPipelineRunsByCreationTimestamp(prs.Items)
-
-		for _, pr := range sortedPRs {
-			order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
-			if !exist {
-				// if the pipelineRun doesn't have order label then wait
-				return nil
-			}
-			orderedList := FilterPipelineRunByState(ctx, tekton, strings.Split(order, ","), "", kubeinteraction.StateStarted)
-
-			_, err = qm.AddListToRunningQueue(&repo, orderedList)
-			if err != nil {
-				qm.logger.Error("failed to init queue for repo: ", repo.GetName())
-			}
+		order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
+		if !exist {
+			// if the pipelineRun doesn't have order label then wait
+			continue
 		}
+		orderedList := FilterPipelineRunsByState(ctx, tekton, strings.Split(order, ","), "", kubeinteraction.StateStarted)
 
-		// now fetch all queued pipelineRun
-		prs, err = tekton.TektonV1().PipelineRuns(repo.Namespace).
-			List(ctx, v1.ListOptions{
-				LabelSelector: fmt.Sprintf("%s=%s", keys.State, kubeinteraction.StateQueued),
-			})
+		_, err = qm.AddListToRunningQueue(repo, pr, orderedList)
 		if err != nil {
-			return err
+			qm.logger.Error("failed to init queue for repo: ", repo.GetName())
 		}
+	}
 
-		// sort the pipelinerun by creation time before adding to queue
-		sortedPRs = sortPipelineRunsByCreationTimestamp(prs.Items)
-
-		for _, pr := range sortedPRs {
-			order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
-			if !exist {
-				// if the pipelineRun doesn't have order label then wait
-				return nil
-			}
-			orderedList := FilterPipelineRunByState(ctx, tekton, strings.Split(order, ","), tektonv1.PipelineRunSpecStatusPending, kubeinteraction.StateQueued)
-			if err := qm.AddToPendingQueue(&repo, orderedList); err != nil {
-				qm.logger.Error("failed to init queue for repo: ", repo.GetName())
-			}
+	// now fetch all queued pipelineRun
+	sortedPRs, err = listPipelineRunsByState(ctx, tekton, kubeinteraction.StateQueued)
+	if err != nil {
+		return err
+	}
+	for _, pr := range sortedPRs {
+		repo, found := repos[fmt.Sprintf("%s/%s", pr.GetNamespace(), pr.GetAnnotations()[keys.Repository])]
+		if !found {
+			continue
+		}
+		order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
+		if !exist {
+			// if the pipelineRun doesn't have order label then wait
+			continue
+		}
+		orderedList := FilterPipelineRunsByState(ctx, tekton, strings.Split(order, ","), tektonv1.PipelineRunSpecStatusPending, kubeinteraction.StateQueued)
+		if err := qm.AddToPendingQueue(repo, pr, orderedList); err != nil {
+			qm.logger.Error("failed to init queue for repo: ", repo.GetName())
 		}
 	}
 
 	return nil
 }
 
+// listPipelineRunsByState returns the pipelineRuns of all the namespaces in
+// state sorted by creation time.
+func listPipelineRunsByState(ctx context.Context, tekton versioned2.Interface, state string) ([]*tektonv1.PipelineRun, error) {
+	prs, err := tekton.TektonV1().PipelineRuns("").
+		List(ctx, v1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", keys.State, state),
+		})
+	if err != nil {
+		return nil, err
+	}
+	// sort the pipelinerun by creation time before adding to queue
+	return sortPipelineRunsByCreationTimestamp(prs.Items), nil
+}
+
 func (qm *Manager) RemoveRepository(repo *v1alpha1.Repository) {
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
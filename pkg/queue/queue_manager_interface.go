@@ -1,24 +1,33 @@
 package queue
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	tektonVersionedClient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type ManagerInterface interface {
-	InitQueues(ctx context.Context, tekton tektonVersionedClient.Interface, pac versioned.Interface) error
+	InitQueues(ctx context.Context, tekton tektonVersionedClient.Interface, pac versioned.Interface, globalRepo *v1alpha1.Repository) error
 	RemoveRepository(repo *v1alpha1.Repository)
 	QueuedPipelineRuns(repo *v1alpha1.Repository) []string
 	RunningPipelineRuns(repo *v1alpha1.Repository) []string
-	AddListToRunningQueue(repo *v1alpha1.Repository, list []string) ([]string, error)
-	AddToPendingQueue(repo *v1alpha1.Repository, list []string) error
-	RemoveFromQueue(repoKey, prKey string) bool
+	AddListToRunningQueue(repo *v1alpha1.Repository, run *tektonv1.PipelineRun, list []*tektonv1.PipelineRun) ([]string, error)
+	AddToPendingQueue(repo *v1alpha1.Repository, run *tektonv1.PipelineRun, list []*tektonv1.PipelineRun) error
+	RemoveFromQueue(queueKey, prKey string) bool
+	RemovePipelineRun(prKey string) bool
 	RemoveAndTakeItemFromQueue(repo *v1alpha1.Repository, run *tektonv1.PipelineRun) string
+	SetGlobalLimit(limit int)
+	SetNamespaceLimit(namespace string, limit int)
+	NamespaceLimit(namespace string) int
 }
 
 func RepoKey(repo *v1alpha1.Repository) string {
@@ -28,3 +37,65 @@ func RepoKey(repo *v1alpha1.Repository) string {
 func PrKey(run *tektonv1.PipelineRun) string {
 	return fmt.Sprintf("%s/%s", run.Namespace, run.Name)
 }
+
+// GroupKey returns the key of the queue of the concurrency group name, the
+// namespace of the Repository is part of the key unless the group is cluster
+// scoped.
+func GroupKey(repo *v1alpha1.Repository, scope, name string) string {
+	if scope == v1alpha1.ConcurrencyGroupScopeCluster {
+		return fmt.Sprintf("group/cluster/%s", name)
+	}
+	return fmt.Sprintf("group/namespace/%s/%s", repo.Namespace, name)
+}
+
+// QueueKey returns the key and the limit of the queue of the PipelineRun: the
+// queue of its concurrency group when the group is defined in the Repository,
+// the queue of the Repository otherwise.
+func QueueKey(repo *v1alpha1.Repository, run *tektonv1.PipelineRun) (string, *int) {
+	if run != nil {
+		name := run.GetAnnotations()[keys.ConcurrencyGroup]
+		if group := repo.Spec.GetConcurrencyGroup(name); group != nil {
+			limit := group.Limit
+			return GroupKey(repo, group.Scope, name), &limit
+		}
+	}
+	return RepoKey(repo), repo.Spec.ConcurrencyLimit
+}
+
+// SortPending sorts the pending PipelineRuns of a queue in the order they are
+// started: the highest priority first and then by order of creation.
+func SortPending(runs []*tektonv1.PipelineRun) {
+	slices.SortStableFunc(runs, func(a, b *tektonv1.PipelineRun) int {
+		if c := cmp.Compare(Priority(b), Priority(a)); c != 0 {
+			return c
+		}
+		return a.GetCreationTimestamp().Compare(b.GetCreationTimestamp().Time)
+	})
+}
+
+// Priority returns the priority of the PipelineRun in its queue as set in the
+// pipelinesascode.tekton.dev/priority annotation, 0 when it is not set or
+// not a number. The PipelineRuns with the highest priority leave the queue
+// first.
+func Priority(run *tektonv1.PipelineRun) int {
+	priority, err := strconv.Atoi(run.GetAnnotations()[keys.Priority])
+	if err != nil {
+		return 0
+	}
+	return priority
+}
+
+// NamespaceLimit returns the maximum number of running PipelineRuns of the
+// Repositories of ns as set in its pipelinesascode.tekton.dev/concurrency-limit
+// annotation, 0 when it is not set.
+func NamespaceLimit(ns *corev1.Namespace) (int, error) {
+	value, ok := ns.GetAnnotations()[keys.ConcurrencyLimit]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid %s annotation on namespace %s: %q must be a positive number", keys.ConcurrencyLimit, ns.GetName(), value)
+	}
+	return limit, nil
+}
@@ -3,6 +3,7 @@ package queue
 import (
 	"fmt"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
@@ -20,6 +22,7 @@ import (
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
@@ -50,7 +53,7 @@ func TestSomeoneElseSetPendingWithNoConcurrencyLimit(t *testing.T) {
 			Reason: v1beta1.PipelineRunReasonPending.String(),
 		},
 	}
-	started, err := qm.AddListToRunningQueue(repo, []string{PrKey(pr)})
+	started, err := qm.AddListToRunningQueue(repo, nil, []*tektonv1.PipelineRun{pr})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 1)
 }
@@ -72,7 +75,7 @@ func TestAddToPendingQueueDirectly(t *testing.T) {
 			Reason: v1beta1.PipelineRunReasonPending.String(),
 		},
 	}
-	err := qm.AddToPendingQueue(repo, []string{PrKey(pr)})
+	err := qm.AddToPendingQueue(repo, nil, []*tektonv1.PipelineRun{pr})
 	assert.NilError(t, err)
 
 	sema := qm.queueMap[RepoKey(repo)]
@@ -94,7 +97,7 @@ func TestNewManagerForList(t *testing.T) {
 	prFirst := newTestPR("first", time.Now(), nil, nil, tektonv1.PipelineRunSpec{})
 
 	// added to queue, as there is only one should start
-	started, err := qm.AddListToRunningQueue(repo, []string{PrKey(prFirst)})
+	started, err := qm.AddListToRunningQueue(repo, nil, []*tektonv1.PipelineRun{prFirst})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 1)
 
@@ -106,7 +109,7 @@ func TestNewManagerForList(t *testing.T) {
 	prSecond := newTestPR("second", time.Now().Add(1*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 	prThird := newTestPR("third", time.Now().Add(7*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prSecond), PrKey(prThird)})
+	started, err = qm.AddListToRunningQueue(repo, nil, []*tektonv1.PipelineRun{prSecond, prThird})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 1)
 	// as per the list, 2nd must be started
@@ -116,7 +119,7 @@ func TestNewManagerForList(t *testing.T) {
 	prFourth := newTestPR("fourth", time.Now().Add(5*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 	prFifth := newTestPR("fifth", time.Now().Add(4*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prFourth), PrKey(prFifth)})
+	started, err = qm.AddListToRunningQueue(repo, nil, []*tektonv1.PipelineRun{prFourth, prFifth})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 0)
 
@@ -130,7 +133,7 @@ func TestNewManagerForList(t *testing.T) {
 	prSeventh := newTestPR("seventh", time.Now().Add(5*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 	prEight := newTestPR("eight", time.Now().Add(4*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prSixth), PrKey(prSeventh), PrKey(prEight)})
+	started, err = qm.AddListToRunningQueue(repo, nil, []*tektonv1.PipelineRun{prSixth, prSeventh, prEight})
 	assert.NilError(t, err)
 	// third is running, but limit is changed now, so one more should be moved to running
 	assert.Equal(t, len(started), 1)
@@ -151,18 +154,18 @@ func TestNewManagerReListing(t *testing.T) {
 	prThird := newTestPR("third", time.Now().Add(7*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 
 	// added to queue, as there is only one should start
-	started, err := qm.AddListToRunningQueue(repo, []string{PrKey(prFirst), PrKey(prSecond), PrKey(prThird)})
+	started, err := qm.AddListToRunningQueue(repo, nil, []*tektonv1.PipelineRun{prFirst, prSecond, prThird})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 2)
 
 	// if first is running and other pipelineRuns are reconciling
 	// then adding again shouldn't have any effect
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prFirst), PrKey(prSecond), PrKey(prThird)})
+	started, err = qm.AddListToRunningQueue(repo, nil, []*tektonv1.PipelineRun{prFirst, prSecond, prThird})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 0)
 
 	// again
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prFirst), PrKey(prSecond), PrKey(prThird)})
+	started, err = qm.AddListToRunningQueue(repo, nil, []*tektonv1.PipelineRun{prFirst, prSecond, prThird})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 0)
 
@@ -176,7 +179,7 @@ func TestNewManagerReListing(t *testing.T) {
 	prFifth := newTestPR("fifth", time.Now().Add(1*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 	prSixths := newTestPR("sixth", time.Now().Add(7*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prFourth), PrKey(prFifth), PrKey(prSixths)})
+	started, err = qm.AddListToRunningQueue(repo, nil, []*tektonv1.PipelineRun{prFourth, prFifth, prSixths})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 0)
 
@@ -220,10 +223,12 @@ func TestQueueManager_InitQueues(t *testing.T) {
 	cw := clockwork.NewFakeClock()
 
 	startedLabel := map[string]string{
-		keys.State: kubeinteraction.StateStarted,
+		keys.State:      kubeinteraction.StateStarted,
+		keys.Repository: "test",
 	}
 	queuedLabel := map[string]string{
-		keys.State: kubeinteraction.StateQueued,
+		keys.State:      kubeinteraction.StateQueued,
+		keys.Repository: "test",
 	}
 
 	repo := newTestRepo(1)
@@ -231,10 +236,12 @@ func TestQueueManager_InitQueues(t *testing.T) {
 	queuedAnnotations := map[string]string{
 		keys.ExecutionOrder: "test-ns/first,test-ns/second,test-ns/third",
 		keys.State:          kubeinteraction.StateQueued,
+		keys.Repository:     "test",
 	}
 	startedAnnotations := map[string]string{
 		keys.ExecutionOrder: "test-ns/first,test-ns/second,test-ns/third",
 		keys.State:          kubeinteraction.StateStarted,
+		keys.Repository:     "test",
 	}
 	firstPR := newTestPR("first", cw.Now(), startedLabel, startedAnnotations, tektonv1.PipelineRunSpec{})
 	secondPR := newTestPR("second", cw.Now().Add(5*time.Second), queuedLabel, queuedAnnotations, tektonv1.PipelineRunSpec{
@@ -252,7 +259,7 @@ func TestQueueManager_InitQueues(t *testing.T) {
 
 	qm := NewManager(logger)
 
-	err := qm.InitQueues(ctx, stdata.Pipeline, stdata.PipelineAsCode)
+	err := qm.InitQueues(ctx, stdata.Pipeline, stdata.PipelineAsCode, nil)
 	assert.NilError(t, err)
 
 	// queues are built
@@ -274,6 +281,37 @@ func TestQueueManager_InitQueues(t *testing.T) {
 	assert.Equal(t, len(runs), 1)
 }
 
+func TestQueueManager_InitQueuesLongRepositoryName(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	cw := clockwork.NewFakeClock()
+
+	// the label of the repository is truncated, the annotation is not
+	repo := newTestRepo(1)
+	repo.Name = strings.Repeat("a", 70)
+	labels := map[string]string{
+		keys.State:      kubeinteraction.StateStarted,
+		keys.Repository: formatting.CleanValueKubernetes(repo.GetName()),
+	}
+	annotations := map[string]string{
+		keys.ExecutionOrder: "test-ns/first",
+		keys.State:          kubeinteraction.StateStarted,
+		keys.Repository:     repo.GetName(),
+	}
+	pr := newTestPR("first", cw.Now(), labels, annotations, tektonv1.PipelineRunSpec{})
+
+	tdata := testclient.Data{
+		Repositories: []*v1alpha1.Repository{repo},
+		PipelineRuns: []*tektonv1.PipelineRun{pr},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+
+	qm := NewManager(logger)
+	assert.NilError(t, qm.InitQueues(ctx, stdata.Pipeline, stdata.PipelineAsCode, nil))
+	assert.DeepEqual(t, qm.RunningPipelineRuns(repo), []string{PrKey(pr)})
+}
+
 func TestFilterPipelineRunByInProgress(t *testing.T) {
 	ctx, _ := rtesting.SetupFakeContext(t)
 	ns := "test-ns"
@@ -338,3 +376,308 @@ func TestFilterPipelineRunByInProgress(t *testing.T) {
 	expected := []string{"test-ns/pr1"}
 	assert.DeepEqual(t, filtered, expected)
 }
+
+func TestConcurrencyGroupSharedBetweenRepositories(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	qm := NewManager(logger)
+	cw := clockwork.NewFakeClock()
+
+	groups := []v1alpha1.ConcurrencyGroup{{Name: "deploy-*", Limit: 1, Scope: v1alpha1.ConcurrencyGroupScopeCluster}}
+	repoA := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns-a"},
+		Spec:       v1alpha1.RepositorySpec{ConcurrencyGroups: groups},
+	}
+	repoB := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns-b"},
+		Spec:       v1alpha1.RepositorySpec{ConcurrencyLimit: intPtr(5), ConcurrencyGroups: groups},
+	}
+	groupAnnotations := map[string]string{keys.ConcurrencyGroup: "deploy-prod"}
+
+	prA := newTestPR("deploy", cw.Now(), nil, groupAnnotations, tektonv1.PipelineRunSpec{})
+	prA.Namespace = repoA.Namespace
+	prB := newTestPR("deploy", cw.Now().Add(time.Second), nil, groupAnnotations, tektonv1.PipelineRunSpec{})
+	prB.Namespace = repoB.Namespace
+	// not in a group, it uses the queue of the repository
+	prBTest := newTestPR("test", cw.Now().Add(time.Second), nil, nil, tektonv1.PipelineRunSpec{})
+	prBTest.Namespace = repoB.Namespace
+
+	queueKey, limit := QueueKey(repoA, prA)
+	assert.Equal(t, queueKey, "group/cluster/deploy-prod")
+	assert.Equal(t, *limit, 1)
+	queueKey, _ = QueueKey(repoB, prBTest)
+	assert.Equal(t, queueKey, RepoKey(repoB))
+
+	started, err := qm.AddListToRunningQueue(repoA, prA, []*tektonv1.PipelineRun{prA})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{PrKey(prA)})
+
+	// the group of the other repository is full
+	started, err = qm.AddListToRunningQueue(repoB, prB, []*tektonv1.PipelineRun{prB})
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 0)
+
+	// the queue of the repository is not limited by the group
+	started, err = qm.AddListToRunningQueue(repoB, prBTest, []*tektonv1.PipelineRun{prBTest})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{PrKey(prBTest)})
+
+	// releasing the group starts the PipelineRun of the other repository
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repoA, prA), PrKey(prB))
+}
+
+func TestQueueManager_InitQueuesConcurrencyGroup(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	cw := clockwork.NewFakeClock()
+
+	globalRepo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "global", Namespace: "pac"},
+		Spec: v1alpha1.RepositorySpec{
+			ConcurrencyGroups: []v1alpha1.ConcurrencyGroup{{Name: "deploy", Limit: 1, Scope: v1alpha1.ConcurrencyGroupScopeCluster}},
+		},
+	}
+	repoA := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns-a"}}
+	repoB := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns-b"}}
+
+	newGroupPR := func(repo *v1alpha1.Repository, name, state string, created time.Time) *tektonv1.PipelineRun {
+		spec := tektonv1.PipelineRunSpec{}
+		if state == kubeinteraction.StateQueued {
+			spec.Status = tektonv1.PipelineRunSpecStatusPending
+		}
+		pr := newTestPR(name, created, map[string]string{
+			keys.State:      state,
+			keys.Repository: repo.GetName(),
+		}, map[string]string{
+			keys.State:            state,
+			keys.Repository:       repo.GetName(),
+			keys.ConcurrencyGroup: "deploy",
+			keys.ExecutionOrder:   repo.GetNamespace() + "/" + name,
+		}, spec)
+		pr.Namespace = repo.GetNamespace()
+		return pr
+	}
+	// the queued PipelineRun of the first repository is older than the
+	// started one of the second repository
+	queuedPR := newGroupPR(repoA, "queued", kubeinteraction.StateQueued, cw.Now())
+	startedPR := newGroupPR(repoB, "started", kubeinteraction.StateStarted, cw.Now().Add(time.Minute))
+
+	tdata := testclient.Data{
+		Repositories: []*v1alpha1.Repository{repoA, repoB, globalRepo},
+		PipelineRuns: []*tektonv1.PipelineRun{queuedPR, startedPR},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+
+	qm := NewManager(logger)
+	assert.NilError(t, qm.InitQueues(ctx, stdata.Pipeline, stdata.PipelineAsCode, globalRepo))
+
+	sema := qm.queueMap[GroupKey(repoA, v1alpha1.ConcurrencyGroupScopeCluster, "deploy")]
+	assert.Assert(t, sema != nil)
+	assert.DeepEqual(t, sema.getCurrentRunning(), []string{PrKey(startedPR)})
+	assert.DeepEqual(t, sema.getCurrentPending(), []string{PrKey(queuedPR)})
+}
+
+func TestQueueManagerPriority(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	qm := NewManager(logger)
+	cw := clockwork.NewFakeClock()
+	repo := newTestRepo(1)
+
+	running := newTestPR("running", cw.Now(), nil, nil, tektonv1.PipelineRunSpec{})
+	started, err := qm.AddListToRunningQueue(repo, running, []*tektonv1.PipelineRun{running})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{PrKey(running)})
+
+	dependabot := []*tektonv1.PipelineRun{}
+	for i := range 3 {
+		pr := newTestPR(fmt.Sprintf("dependabot-%d", i), cw.Now(), nil, nil, tektonv1.PipelineRunSpec{})
+		dependabot = append(dependabot, pr)
+	}
+	started, err = qm.AddListToRunningQueue(repo, dependabot[0], dependabot)
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 0)
+
+	hotfix := newTestPR("hotfix", cw.Now(), nil, map[string]string{keys.Priority: "100"}, tektonv1.PipelineRunSpec{})
+	started, err = qm.AddListToRunningQueue(repo, hotfix, []*tektonv1.PipelineRun{hotfix})
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 0)
+
+	// the hotfix does not wait behind the dependabot PipelineRuns
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, running), PrKey(hotfix))
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, hotfix), PrKey(dependabot[0]))
+}
+
+func TestPriority(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        int
+	}{
+		{name: "no annotation", want: 0},
+		{name: "priority", annotations: map[string]string{keys.Priority: "10"}, want: 10},
+		{name: "negative priority", annotations: map[string]string{keys.Priority: "-5"}, want: -5},
+		{name: "invalid priority", annotations: map[string]string{keys.Priority: "high"}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := newTestPR("pr", time.Now(), nil, tt.annotations, tektonv1.PipelineRunSpec{})
+			assert.Equal(t, Priority(pr), tt.want)
+		})
+	}
+}
+
+func newTestPRs(namespace string, names ...string) []*tektonv1.PipelineRun {
+	prs := []*tektonv1.PipelineRun{}
+	for i, name := range names {
+		pr := newTestPR(name, time.Now().Add(time.Duration(i)*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
+		pr.Namespace = namespace
+		prs = append(prs, pr)
+	}
+	return prs
+}
+
+func TestQueueManagerGlobalLimit(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	qm := NewManager(logger)
+	qm.SetGlobalLimit(2)
+
+	// the repositories have no limit of their own
+	repoA := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns-a"}}
+	repoB := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns-b"}}
+	prsA := newTestPRs(repoA.Namespace, "a1", "a2", "a3")
+	prsB := newTestPRs(repoB.Namespace, "b1")
+
+	started, err := qm.AddListToRunningQueue(repoA, prsA[0], prsA)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{PrKey(prsA[0]), PrKey(prsA[1])})
+
+	started, err = qm.AddListToRunningQueue(repoB, prsB[0], prsB)
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 0)
+	assert.DeepEqual(t, qm.QueuedPipelineRuns(repoB), []string{PrKey(prsB[0])})
+
+	// the queues are visited in a round-robin order, the other repository
+	// starts before the next PipelineRun of the busy one
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repoA, prsA[0]), PrKey(prsB[0]))
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repoA, prsA[1]), PrKey(prsA[2]))
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repoB, prsB[0]), "")
+
+	// removing the limit starts everything
+	qm.SetGlobalLimit(0)
+	prsB = newTestPRs(repoB.Namespace, "b2", "b3", "b4")
+	started, err = qm.AddListToRunningQueue(repoB, prsB[0], prsB)
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 3)
+}
+
+func TestQueueManagerNamespaceLimit(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	qm := NewManager(logger)
+	qm.SetNamespaceLimit("ns-a", 1)
+
+	repoA := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns-a"}}
+	repoOther := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns-a"},
+		Spec:       v1alpha1.RepositorySpec{ConcurrencyLimit: intPtr(5)},
+	}
+	repoB := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns-b"}}
+	prsA := newTestPRs(repoA.Namespace, "a1", "a2")
+	prsOther := newTestPRs(repoOther.Namespace, "other1")
+	prsB := newTestPRs(repoB.Namespace, "b1", "b2")
+
+	started, err := qm.AddListToRunningQueue(repoA, prsA[0], prsA)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{PrKey(prsA[0])})
+
+	// every level must have capacity, the limit of the repository is not
+	// enough
+	started, err = qm.AddListToRunningQueue(repoOther, prsOther[0], prsOther)
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 0)
+
+	// the other namespaces are not limited
+	started, err = qm.AddListToRunningQueue(repoB, prsB[0], prsB)
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 2)
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repoB, prsB[0]), "")
+
+	// the round-robin continues after the queue of ns-b/b
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repoA, prsA[0]), PrKey(prsA[1]))
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repoA, prsA[1]), PrKey(prsOther[0]))
+}
+
+func TestNamespaceLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        int
+		wantErr     string
+	}{
+		{name: "no annotation", want: 0},
+		{name: "limit", annotations: map[string]string{keys.ConcurrencyLimit: "3"}, want: 3},
+		{name: "empty limit", annotations: map[string]string{keys.ConcurrencyLimit: ""}, want: 0},
+		{name: "invalid limit", annotations: map[string]string{keys.ConcurrencyLimit: "many"}, wantErr: "must be a positive number"},
+		{name: "negative limit", annotations: map[string]string{keys.ConcurrencyLimit: "-1"}, wantErr: "must be a positive number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, err := NamespaceLimit(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ns", Annotations: tt.annotations},
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, limit, tt.want)
+		})
+	}
+}
+
+func TestInitNamespaceLimits(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	kube := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "limited", Annotations: map[string]string{keys.ConcurrencyLimit: "2"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "invalid", Annotations: map[string]string{keys.ConcurrencyLimit: "many"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unlimited"}},
+	)
+	qm := NewManager(logger)
+	assert.NilError(t, qm.InitNamespaceLimits(ctx, kube))
+	assert.Equal(t, qm.NamespaceLimit("limited"), 2)
+	assert.Equal(t, qm.NamespaceLimit("invalid"), 0)
+	assert.Equal(t, qm.NamespaceLimit("unlimited"), 0)
+
+	// the limit applies before any repository of the namespace is reconciled
+	repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "limited"}}
+	prs := newTestPRs(repo.Namespace, "first", "second", "third")
+	started, err := qm.AddListToRunningQueue(repo, prs[0], prs)
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 2)
+}
+
+func TestRemovePipelineRun(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	qm := NewManager(logger)
+
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{ConcurrencyLimit: intPtr(1)},
+	}
+	prs := newTestPRs(repo.Namespace, "first", "second")
+	started, err := qm.AddListToRunningQueue(repo, prs[0], prs)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{PrKey(prs[0])})
+
+	assert.Assert(t, qm.RemovePipelineRun(PrKey(prs[0])))
+	assert.Equal(t, len(qm.RunningPipelineRuns(repo)), 0)
+	assert.Assert(t, qm.RemovePipelineRun(PrKey(prs[1])))
+	assert.Equal(t, len(qm.QueuedPipelineRuns(repo)), 0)
+	assert.Assert(t, !qm.RemovePipelineRun("ns/unknown"))
+}
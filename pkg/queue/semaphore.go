@@ -80,7 +80,7 @@ func (s *prioritySemaphore) removeFromQueue(key string) {
 	s.pending.remove(key)
 }
 
-func (s *prioritySemaphore) addToPendingQueue(key string, creationTime time.Time) bool {
+func (s *prioritySemaphore) addToPendingQueue(key string, priority int, creationTime time.Time) bool {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -88,9 +88,11 @@ func (s *prioritySemaphore) addToPendingQueue(key string, creationTime time.Time
 		return false
 	}
 	if s.pending.isPending(key) {
+		// the priority of a pending key can be changed, ie: with tkn pac queue promote
+		s.pending.add(key, priority, creationTime.UnixNano())
 		return false
 	}
-	s.pending.add(key, creationTime.UnixNano())
+	s.pending.add(key, priority, creationTime.UnixNano())
 	return true
 }
 
@@ -112,6 +114,17 @@ func (s *prioritySemaphore) acquireLatest() string {
 	return ""
 }
 
+// nextPending returns the pending key acquired by the next acquireLatest.
+func (s *prioritySemaphore) nextPending() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.pending.Len() == 0 {
+		return ""
+	}
+	return s.pending.peek().key
+}
+
 func (s *prioritySemaphore) release(key string) bool {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -130,7 +143,7 @@ func (s *prioritySemaphore) release(key string) bool {
 	return true
 }
 
-func (s *prioritySemaphore) addToQueue(key string, creationTime time.Time) bool {
+func (s *prioritySemaphore) addToQueue(key string, priority int, creationTime time.Time) bool {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -138,9 +151,11 @@ func (s *prioritySemaphore) addToQueue(key string, creationTime time.Time) bool
 		return false
 	}
 	if s.pending.isPending(key) {
+		// the priority of a pending key can be changed, ie: with tkn pac queue promote
+		s.pending.add(key, priority, creationTime.UnixNano())
 		return false
 	}
-	s.pending.add(key, creationTime.UnixNano())
+	s.pending.add(key, priority, creationTime.UnixNano())
 	return true
 }
 
@@ -18,9 +18,9 @@ func TestNewSemaphore(t *testing.T) {
 	// add elements
 	// randomly adding elements, the element with the less priority
 	// must execute first
-	assert.Equal(t, repo.addToQueue("C", cw.Now().Add(5*time.Second)), true)
-	assert.Equal(t, repo.addToQueue("A", cw.Now()), true)
-	assert.Equal(t, repo.addToQueue("B", cw.Now().Add(1*time.Second)), true)
+	assert.Equal(t, repo.addToQueue("C", 0, cw.Now().Add(5*time.Second)), true)
+	assert.Equal(t, repo.addToQueue("A", 0, cw.Now()), true)
+	assert.Equal(t, repo.addToQueue("B", 0, cw.Now().Add(1*time.Second)), true)
 
 	// start the topmost, which would be A
 	acquired, msg := repo.tryAcquire("A")
@@ -44,7 +44,7 @@ func TestNewSemaphore(t *testing.T) {
 
 	// adding element to Queue which is running
 	// nothing should happen
-	assert.Equal(t, repo.addToQueue("A", cw.Now().Add(5*time.Second)), false)
+	assert.Equal(t, repo.addToQueue("A", 0, cw.Now().Add(5*time.Second)), false)
 
 	// A is done
 	repo.release("A")
@@ -67,9 +67,9 @@ func TestNewSemaphore(t *testing.T) {
 	repo.resize(2)
 
 	// now add new elements
-	assert.Equal(t, repo.addToQueue("D", cw.Now().Add(8*time.Second)), true)
-	assert.Equal(t, repo.addToQueue("E", cw.Now().Add(6*time.Second)), true)
-	assert.Equal(t, repo.addToQueue("F", cw.Now().Add(7*time.Second)), true)
+	assert.Equal(t, repo.addToQueue("D", 0, cw.Now().Add(8*time.Second)), true)
+	assert.Equal(t, repo.addToQueue("E", 0, cw.Now().Add(6*time.Second)), true)
+	assert.Equal(t, repo.addToQueue("F", 0, cw.Now().Add(7*time.Second)), true)
 
 	// queue already have C in it
 	// now the queue must have C > E > F > D
@@ -115,7 +115,7 @@ func TestTryAcquireDeadlockScenario(t *testing.T) {
 	cw := clockwork.NewFakeClock()
 
 	// Add an item to the queue
-	assert.Equal(t, repo.addToQueue("key1", cw.Now()), true)
+	assert.Equal(t, repo.addToQueue("key1", 0, cw.Now()), true)
 
 	// Create channels for synchronization
 	firstStarted := make(chan bool)
@@ -164,7 +164,7 @@ func TestTryAcquireDeadlockTimeout(t *testing.T) {
 	cw := clockwork.NewFakeClock()
 
 	// Add an item to the queue
-	assert.Equal(t, repo.addToQueue("key1", cw.Now()), true)
+	assert.Equal(t, repo.addToQueue("key1", 0, cw.Now()), true)
 
 	done := make(chan struct{})
 	go func() {
@@ -188,7 +188,7 @@ func TestDeadlockDetectionRecursiveMutex(t *testing.T) {
 	cw := clockwork.NewFakeClock()
 
 	// Add an item to the queue
-	assert.Equal(t, repo.addToQueue("key1", cw.Now()), true)
+	assert.Equal(t, repo.addToQueue("key1", 0, cw.Now()), true)
 
 	// Channel to signal completion
 	done := make(chan bool, 1)
@@ -222,8 +222,8 @@ func TestDeadlockDetectionConcurrentTryAcquire(t *testing.T) {
 	cw := clockwork.NewFakeClock()
 
 	// Add items to the queue
-	assert.Equal(t, repo.addToQueue("key1", cw.Now()), true)
-	assert.Equal(t, repo.addToQueue("key2", cw.Now().Add(1*time.Second)), true)
+	assert.Equal(t, repo.addToQueue("key1", 0, cw.Now()), true)
+	assert.Equal(t, repo.addToQueue("key2", 0, cw.Now().Add(1*time.Second)), true)
 
 	// Channels for synchronization
 	goroutine1Done := make(chan bool, 1)
@@ -271,9 +271,9 @@ func TestTryAcquireConcurrentAccess(t *testing.T) {
 	cw := clockwork.NewFakeClock()
 
 	// Add multiple items to the queue
-	assert.Equal(t, repo.addToQueue("key1", cw.Now()), true)
-	assert.Equal(t, repo.addToQueue("key2", cw.Now().Add(1*time.Second)), true)
-	assert.Equal(t, repo.addToQueue("key3", cw.Now().Add(2*time.Second)), true)
+	assert.Equal(t, repo.addToQueue("key1", 0, cw.Now()), true)
+	assert.Equal(t, repo.addToQueue("key2", 0, cw.Now().Add(1*time.Second)), true)
+	assert.Equal(t, repo.addToQueue("key3", 0, cw.Now().Add(2*time.Second)), true)
 
 	// Try to acquire each key in order, simulating concurrent but ordered access
 	acquired1, _ := repo.tryAcquire("key1")
@@ -2,42 +2,151 @@ package reconciler
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 )
 
-func (r *Reconciler) cleanupPipelineRuns(ctx context.Context, logger *zap.SugaredLogger, pacInfo *info.PacOpts, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun) error {
-	keepMaxPipeline, ok := pr.Annotations[keys.MaxKeepRuns]
-	if ok {
-		maxVal, err := strconv.Atoi(keepMaxPipeline)
+// cleanupInterval is how often the completed PipelineRuns of all the
+// Repositories are cleaned up, for the Repositories without new PipelineRuns.
+const cleanupInterval = 10 * time.Minute
+
+// retention returns the retention of the completed PipelineRuns of the
+// pipeline of the PipelineRun, as set in its annotations or, when none of the
+// retention annotations is set, in the global settings.
+func retention(logger *zap.SugaredLogger, pacInfo *info.PacOpts, pr *tektonv1.PipelineRun) (kubeinteraction.Retention, error) {
+	annotations := pr.GetAnnotations()
+	_, hasMaxKeep := annotations[keys.MaxKeepRuns]
+	_, hasMaxKeepFailed := annotations[keys.MaxKeepFailedRuns]
+	_, hasMaxKeepSuccessful := annotations[keys.MaxKeepSuccessfulRuns]
+	_, hasKeepFor := annotations[keys.KeepFor]
+	if !hasMaxKeep && !hasMaxKeepFailed && !hasMaxKeepSuccessful && !hasKeepFor {
+		ret := kubeinteraction.Retention{
+			MaxKeep:           pacInfo.DefaultMaxKeepRuns,
+			MaxKeepFailed:     pacInfo.DefaultMaxKeepFailedRuns,
+			MaxKeepSuccessful: pacInfo.DefaultMaxKeepSuccessfulRuns,
+		}
+		if pacInfo.DefaultKeepFor != "" {
+			keepFor, err := time.ParseDuration(pacInfo.DefaultKeepFor)
+			if err != nil {
+				return ret, fmt.Errorf("invalid default-keep-for setting: %w", err)
+			}
+			ret.KeepFor = keepFor
+		}
+		return ret, nil
+	}
+
+	ret := kubeinteraction.Retention{}
+	for key, value := range map[string]*int{
+		keys.MaxKeepRuns:           &ret.MaxKeep,
+		keys.MaxKeepFailedRuns:     &ret.MaxKeepFailed,
+		keys.MaxKeepSuccessfulRuns: &ret.MaxKeepSuccessful,
+	} {
+		annotation, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		maxVal, err := strconv.Atoi(annotation)
 		if err != nil {
-			return err
+			return ret, err
 		}
 		// if annotation value is more than max limit defined in config then use from config
 		if pacInfo.MaxKeepRunsUpperLimit > 0 && maxVal > pacInfo.MaxKeepRunsUpperLimit {
-			logger.Infof("max-keep-run value in annotation (%v) is more than max-keep-run-upper-limit (%v), so using upper-limit", maxVal, pacInfo.MaxKeepRunsUpperLimit)
+			logger.Infof("%s value in annotation (%v) is more than max-keep-run-upper-limit (%v), so using upper-limit", key, maxVal, pacInfo.MaxKeepRunsUpperLimit)
 			maxVal = pacInfo.MaxKeepRunsUpperLimit
 		}
-		err = r.kinteract.CleanupPipelines(ctx, logger, repo, pr, maxVal)
+		*value = maxVal
+	}
+	// an explicit max-keep-runs of 0 deletes all the completed PipelineRuns
+	if hasMaxKeep && ret.MaxKeep == 0 {
+		ret.KeepNone = true
+	}
+	if hasKeepFor {
+		keepFor, err := time.ParseDuration(annotations[keys.KeepFor])
 		if err != nil {
-			return err
+			return ret, fmt.Errorf("invalid %s annotation: %w", keys.KeepFor, err)
 		}
+		ret.KeepFor = keepFor
+	}
+	return ret, nil
+}
+
+func (r *Reconciler) cleanupPipelineRuns(ctx context.Context, logger *zap.SugaredLogger, pacInfo *info.PacOpts, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun) error {
+	ret, err := retention(logger, pacInfo, pr)
+	if err != nil {
+		return err
+	}
+	if ret.IsZero() {
 		return nil
 	}
+	return r.kinteract.CleanupPipelines(ctx, logger, repo, pr, ret)
+}
 
-	// if annotation is not defined but default max-keep-run value is defined then use that
-	if pacInfo.DefaultMaxKeepRuns > 0 {
-		maxVal := pacInfo.DefaultMaxKeepRuns
+// startPeriodicCleanup cleans up the completed PipelineRuns of the
+// Repositories this replica leads every cleanupInterval until the context is
+// done.
+func (r *Reconciler) startPeriodicCleanup(ctx context.Context, isLeader func(types.NamespacedName) bool) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.cleanupRepositories(ctx, isLeader)
+		}
+	}
+}
 
-		err := r.kinteract.CleanupPipelines(ctx, logger, repo, pr, maxVal)
+// cleanupRepositories applies the retention of each pipeline of the
+// Repositories, as set on its latest completed PipelineRun, to its completed
+// PipelineRuns. Only the leader of a Repository cleans it up.
+func (r *Reconciler) cleanupRepositories(ctx context.Context, isLeader func(types.NamespacedName) bool) {
+	logger := r.run.Clients.Log
+	repos, err := r.repoLister.List(labels.Everything())
+	if err != nil {
+		logger.Errorf("cannot list repositories to clean up: %v", err)
+		return
+	}
+	pacInfo := r.run.Info.GetPacOpts()
+	for _, repo := range repos {
+		if !isLeader(types.NamespacedName{Namespace: repo.GetNamespace(), Name: repo.GetName()}) {
+			continue
+		}
+		selector := labels.SelectorFromSet(labels.Set{
+			keys.Repository: formatting.CleanValueKubernetes(repo.GetName()),
+			keys.State:      kubeinteraction.StateCompleted,
+		})
+		prs, err := r.pipelineRunLister.PipelineRuns(repo.GetNamespace()).List(selector)
 		if err != nil {
-			return err
+			logger.Errorf("cannot list pipelineruns of repository %s/%s to clean up: %v", repo.GetNamespace(), repo.GetName(), err)
+			continue
+		}
+
+		latest := map[string]*tektonv1.PipelineRun{}
+		for _, pr := range prs {
+			name, ok := pr.GetLabels()[keys.OriginalPRName]
+			if !ok {
+				continue
+			}
+			if prev, ok := latest[name]; !ok || prev.CreationTimestamp.Before(&pr.CreationTimestamp) {
+				latest[name] = pr
+			}
+		}
+		for _, pr := range latest {
+			if err := r.cleanupPipelineRuns(ctx, logger, &pacInfo, repo, pr); err != nil {
+				logger.Errorf("cannot clean up pipelineruns of repository %s/%s: %v", repo.GetNamespace(), repo.GetName(), err)
+			}
 		}
 	}
-	return nil
 }
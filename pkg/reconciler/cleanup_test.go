@@ -3,6 +3,7 @@ package reconciler
 import (
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
@@ -20,6 +21,7 @@ import (
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
@@ -47,15 +49,18 @@ func TestCleanupPipelineRuns(t *testing.T) {
 	}
 
 	clock := clockwork.NewFakeClock()
+	recentClock := clockwork.NewFakeClockAt(time.Now())
 	tests := []struct {
 		name               string
 		pruns              []*tektonv1.PipelineRun
 		currentpr          *tektonv1.PipelineRun
 		maxkeepruns        int
 		defaultmaxkeepruns int
+		defaultkeepfor     string
 		repoNs             string
 		repoName           string
 		afterCleanup       int
+		wantErr            string
 	}{
 		{
 			name: "using from annotation",
@@ -120,6 +125,97 @@ func TestCleanupPipelineRuns(t *testing.T) {
 			afterCleanup:       2,
 			defaultmaxkeepruns: 2,
 		},
+		{
+			name: "max-keep-runs of 0 deletes all the completed pipelineruns",
+			pruns: []*tektonv1.PipelineRun{
+				tektontest.MakePRCompletion(clock, "pipeline-newest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 10),
+				tektontest.MakePRCompletion(clock, "pipeline-oldest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 20),
+			},
+			repoNs:   ns,
+			repoName: cleanupRepoName,
+			currentpr: &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: cleanupLabels, Annotations: maxRunsAnno(0),
+				},
+			},
+			afterCleanup:       0,
+			defaultmaxkeepruns: 5,
+		},
+		{
+			name: "using status annotations",
+			pruns: []*tektonv1.PipelineRun{
+				tektontest.MakePRCompletion(clock, "pipeline-newest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 10),
+				tektontest.MakePRCompletion(clock, "pipeline-failed", ns, tektonv1.PipelineRunReasonFailed.String(), nil, cleanupLabels, 20),
+				tektontest.MakePRCompletion(clock, "pipeline-oldest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 30),
+			},
+			repoNs:   ns,
+			repoName: cleanupRepoName,
+			currentpr: &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: cleanupLabels,
+					Annotations: map[string]string{
+						keys.MaxKeepFailedRuns:     "5",
+						keys.MaxKeepSuccessfulRuns: "1",
+						keys.OriginalPRName:        cleanupPRName,
+						keys.Repository:            cleanupRepoName,
+					},
+				},
+			},
+			afterCleanup:       2,
+			defaultmaxkeepruns: 1,
+		},
+		{
+			name: "using keep-for annotation",
+			pruns: []*tektonv1.PipelineRun{
+				tektontest.MakePRCompletion(recentClock, "pipeline-newest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 10),
+				tektontest.MakePRCompletion(recentClock, "pipeline-oldest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 120),
+			},
+			repoNs:   ns,
+			repoName: cleanupRepoName,
+			currentpr: &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: cleanupLabels,
+					Annotations: map[string]string{
+						keys.KeepFor:        "1h",
+						keys.OriginalPRName: cleanupPRName,
+						keys.Repository:     cleanupRepoName,
+					},
+				},
+			},
+			afterCleanup: 1,
+		},
+		{
+			name: "using default keep-for from config",
+			pruns: []*tektonv1.PipelineRun{
+				tektontest.MakePRCompletion(recentClock, "pipeline-newest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 10),
+				tektontest.MakePRCompletion(recentClock, "pipeline-oldest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 120),
+			},
+			repoNs:         ns,
+			repoName:       cleanupRepoName,
+			currentpr:      &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Labels: cleanupLabels, Annotations: cleanupAnnotation}},
+			afterCleanup:   1,
+			defaultkeepfor: "1h",
+		},
+		{
+			name: "invalid keep-for annotation",
+			pruns: []*tektonv1.PipelineRun{
+				tektontest.MakePRCompletion(clock, "pipeline-newest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 10),
+			},
+			repoNs:   ns,
+			repoName: cleanupRepoName,
+			currentpr: &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: cleanupLabels,
+					Annotations: map[string]string{
+						keys.KeepFor:        "3 days",
+						keys.OriginalPRName: cleanupPRName,
+						keys.Repository:     cleanupRepoName,
+					},
+				},
+			},
+			afterCleanup: 1,
+			wantErr:      `invalid pipelinesascode.tekton.dev/keep-for annotation: time: unknown unit " days" in duration "3 days"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -163,11 +259,16 @@ func TestCleanupPipelineRuns(t *testing.T) {
 				Settings: settings.Settings{
 					MaxKeepRunsUpperLimit: tt.maxkeepruns,
 					DefaultMaxKeepRuns:    tt.defaultmaxkeepruns,
+					DefaultKeepFor:        tt.defaultkeepfor,
 				},
 			}
 
 			err := r.cleanupPipelineRuns(ctx, fakelogger, pacInfo, repo, tt.currentpr)
-			assert.NilError(t, err)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+			}
 
 			plist, err := kint.Run.Clients.Tekton.TektonV1().PipelineRuns(tt.repoNs).List(
 				ctx, metav1.ListOptions{})
@@ -176,3 +277,76 @@ func TestCleanupPipelineRuns(t *testing.T) {
 		})
 	}
 }
+
+func TestCleanupRepositories(t *testing.T) {
+	ns := "namespace"
+	repoName := "quiet-repo"
+	labelsFor := func(pipeline string) map[string]string {
+		return map[string]string{
+			keys.OriginalPRName: pipeline,
+			keys.Repository:     repoName,
+			keys.State:          kubeinteraction.StateCompleted,
+		}
+	}
+	annotationsFor := func(pipeline, maxKeep string) map[string]string {
+		annotations := map[string]string{
+			keys.OriginalPRName: pipeline,
+			keys.Repository:     repoName,
+		}
+		if maxKeep != "" {
+			annotations[keys.MaxKeepRuns] = maxKeep
+		}
+		return annotations
+	}
+
+	clock := clockwork.NewFakeClock()
+	pruns := []*tektonv1.PipelineRun{
+		tektontest.MakePRCompletion(clock, "push-newest", ns, "", annotationsFor("push", "1"), labelsFor("push"), 10),
+		tektontest.MakePRCompletion(clock, "push-oldest", ns, "", annotationsFor("push", "1"), labelsFor("push"), 20),
+		tektontest.MakePRCompletion(clock, "pull-request-newest", ns, "", annotationsFor("pull-request", ""), labelsFor("pull-request"), 10),
+		tektontest.MakePRCompletion(clock, "pull-request-middest", ns, "", annotationsFor("pull-request", ""), labelsFor("pull-request"), 20),
+		tektontest.MakePRCompletion(clock, "pull-request-oldest", ns, "", annotationsFor("pull-request", ""), labelsFor("pull-request"), 30),
+	}
+	for i, pr := range pruns {
+		pr.CreationTimestamp = metav1.NewTime(clock.Now().Add(-time.Duration(i) * time.Minute))
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, informers := testclient.SeedTestData(t, ctx, testclient.Data{
+		PipelineRuns: pruns,
+		Repositories: []*v1alpha1.Repository{
+			{ObjectMeta: metav1.ObjectMeta{Name: repoName, Namespace: ns}},
+		},
+	})
+	run := &params.Run{
+		Clients: clients.Clients{
+			Kube:   stdata.Kube,
+			Tekton: stdata.Pipeline,
+			Log:    zap.NewNop().Sugar(),
+		},
+		Info: info.Info{Pac: info.NewPacOpts()},
+	}
+	run.Info.Pac.DefaultMaxKeepRuns = 2
+	r := &Reconciler{
+		run:               run,
+		kinteract:         kubeinteraction.Interaction{Run: run},
+		repoLister:        informers.Repository.Lister(),
+		pipelineRunLister: stdata.PipelineLister,
+	}
+
+	// another replica leads the repository
+	r.cleanupRepositories(ctx, func(types.NamespacedName) bool { return false })
+	plist, err := stdata.Pipeline.TektonV1().PipelineRuns(ns).List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(plist.Items), len(pruns))
+
+	r.cleanupRepositories(ctx, func(types.NamespacedName) bool { return true })
+
+	plist, err = stdata.Pipeline.TektonV1().PipelineRuns(ns).List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	names := []string{}
+	for _, pr := range plist.Items {
+		names = append(names, pr.GetName())
+	}
+	assert.DeepEqual(t, names, []string{"pull-request-middest", "pull-request-newest", "push-newest"})
+}
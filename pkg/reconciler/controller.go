@@ -4,19 +4,32 @@ import (
 	"context"
 	"path"
 
+	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/generated/injection/informers/pipelinesascode/v1alpha1/repository"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kueue"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	prmetrics "github.com/openshift-pipelines/pipelines-as-code/pkg/pipelinerunmetrics"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/poller"
 	queuepkg "github.com/openshift-pipelines/pipelines-as-code/pkg/queue"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/scheduler"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	tektonPipelineRunInformerv1 "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1/pipelinerun"
+	tektonTaskRunInformerv1 "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1/taskrun"
 	tektonPipelineRunReconcilerv1 "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1/pipelinerun"
+	tektonv1lister "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmeta"
@@ -49,21 +62,63 @@ func NewController() func(context.Context, configmap.Watcher) *controller.Impl {
 			log.Fatalf("Failed to create pipeline as code metrics recorder %v", err)
 		}
 
+		// the poller and the scheduler read the settings of the namespaces
+		// from the informer cache
+		run.Clients.NamespaceLister = namespaceinformer.Get(ctx).Lister()
+
+		qm := queuepkg.NewManager(run.Clients.Log)
 		r := &Reconciler{
 			run:               run,
 			kinteract:         kinteract,
 			pipelineRunLister: pipelineRunInformer.Lister(),
+			taskRunLister:     tektonTaskRunInformerv1.Get(ctx).Lister(),
 			repoLister:        repository.Get(ctx).Lister(),
-			qm:                queuepkg.NewManager(run.Clients.Log),
+			qm:                qm,
 			metrics:           metrics,
+			llmCache:          llm.NewAnalysisCache(clockwork.NewRealClock()),
 			eventEmitter:      events.NewEventEmitter(run.Clients.Kube, run.Clients.Log),
 		}
 		impl := tektonPipelineRunReconcilerv1.NewImpl(ctx, r, ctrlOpts())
 
-		if err := r.qm.InitQueues(ctx, run.Clients.Tekton, run.Clients.PipelineAsCode); err != nil {
+		// the concurrency groups can be defined in the global repository
+		globalRepo, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(system.Namespace()).Get(
+			ctx, info.GetControllerInfoFromEnvOrDefault().GlobalRepository, metav1.GetOptions{})
+		if err != nil {
+			globalRepo = nil
+		}
+		// the namespace limits must be known before the queues start any
+		// PipelineRun, they are then kept up to date by the namespace informer
+		if err := qm.InitNamespaceLimits(ctx, run.Clients.Kube); err != nil {
+			log.Fatal("failed to init the namespace concurrency limits", err)
+		}
+		if _, err := namespaceinformer.Get(ctx).Informer().AddEventHandler(namespaceLimitHandler(qm, enqueueQueued(impl, r.pipelineRunLister), log)); err != nil {
+			log.Panicf("Couldn't register Namespace informer event handler: %w", err)
+		}
+		qm.SetGlobalLimit(run.Info.GetPacOpts().ConcurrencyLimit)
+		if err := r.qm.InitQueues(ctx, run.Clients.Tekton, run.Clients.PipelineAsCode, globalRepo); err != nil {
 			log.Fatal("failed to init queues", err)
 		}
 
+		// the PipelineRuns admitted by kueue are reconciled again when their
+		// workload changes
+		if kueue.IsInstalled(run.Clients.Kube) {
+			workloadInformer := kueue.NewWorkloadInformer(ctx, run.Clients.Dynamic)
+			if _, err := workloadInformer.AddEventHandler(controller.HandleAll(enqueueWorkloadOwner(impl))); err != nil {
+				log.Panicf("Couldn't register Kueue Workload informer event handler: %w", err)
+			}
+			r.workloads = workloadInformer.GetIndexer()
+			go workloadInformer.Run(ctx.Done())
+		}
+
+		// Start polling the plain git repositories
+		go poller.New(run, kinteract, r.repoLister, isLeaderFor(impl), run.Clients.Log).Start(ctx)
+
+		// Run the PipelineRuns with an on-schedule annotation
+		go scheduler.New(run, kinteract, r.repoLister, isLeaderFor(impl), run.Clients.Log).Start(ctx)
+
+		// Clean up the completed PipelineRuns of the quiet repositories
+		go r.startPeriodicCleanup(ctx, isLeaderFor(impl))
+
 		if _, err := pipelineRunInformer.Informer().AddEventHandler(controller.HandleAll(checkStateAndEnqueue(impl))); err != nil {
 			logging.FromContext(ctx).Panicf("Couldn't register PipelineRun informer event handler: %w", err)
 		}
@@ -72,6 +127,72 @@ func NewController() func(context.Context, configmap.Watcher) *controller.Impl {
 	}
 }
 
+// isLeaderFor returns whether this replica is the leader for a key, like the
+// reconciler the loops outside of it only act on the keys they lead.
+func isLeaderFor(impl *controller.Impl) func(types.NamespacedName) bool {
+	if leaderAware, ok := impl.Reconciler.(interface {
+		IsLeaderFor(types.NamespacedName) bool
+	}); ok {
+		return leaderAware.IsLeaderFor
+	}
+	return func(types.NamespacedName) bool { return true }
+}
+
+// namespaceLimitHandler keeps the namespace limits of the queue manager in sync
+// with the pipelinesascode.tekton.dev/concurrency-limit annotation of the
+// namespaces. A finished PipelineRun only starts the next one, the queued
+// PipelineRuns of a namespace are enqueued when its limit is raised or removed
+// so they start up to the new limit.
+func namespaceLimitHandler(qm queuepkg.ManagerInterface, enqueueQueued func(namespace string), logger *zap.SugaredLogger) cache.ResourceEventHandler {
+	setLimit := func(obj any) {
+		ns, ok := obj.(*corev1.Namespace)
+		if !ok {
+			return
+		}
+		limit, err := queuepkg.NamespaceLimit(ns)
+		if err != nil {
+			logger.Warn(err)
+		}
+		previous := qm.NamespaceLimit(ns.GetName())
+		qm.SetNamespaceLimit(ns.GetName(), limit)
+		if previous > 0 && (limit == 0 || limit > previous) {
+			enqueueQueued(ns.GetName())
+		}
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    setLimit,
+		UpdateFunc: func(_, obj any) { setLimit(obj) },
+		DeleteFunc: func(obj any) {
+			if object, err := kmeta.DeletionHandlingAccessor(obj); err == nil {
+				qm.SetNamespaceLimit(object.GetName(), 0)
+			}
+		},
+	}
+}
+
+// enqueueQueued enqueues the queued PipelineRuns of a namespace, the
+// reconciler starts them when their queue has room.
+func enqueueQueued(impl *controller.Impl, lister tektonv1lister.PipelineRunLister) func(namespace string) {
+	return func(namespace string) {
+		prs, err := lister.PipelineRuns(namespace).List(labels.SelectorFromSet(labels.Set{keys.State: kubeinteraction.StateQueued}))
+		if err != nil {
+			return
+		}
+		for _, pr := range prs {
+			impl.EnqueueKey(types.NamespacedName{Namespace: pr.GetNamespace(), Name: pr.GetName()})
+		}
+	}
+}
+
+// enqueueWorkloadOwner enqueues the PipelineRun admitted by a Kueue Workload.
+func enqueueWorkloadOwner(impl *controller.Impl) func(obj any) {
+	return func(obj any) {
+		if key, ok := kueue.OwnerPipelineRun(obj); ok {
+			impl.EnqueueKey(key)
+		}
+	}
+}
+
 // enqueue only the pipelineruns which are in `started` state
 // pipelinerun will have a label `pipelinesascode.tekton.dev/state` to describe the state.
 func checkStateAndEnqueue(impl *controller.Impl) func(obj any) {
@@ -7,11 +7,13 @@ import (
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	queuepkg "github.com/openshift-pipelines/pipelines-as-code/pkg/queue"
 	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
 	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"go.uber.org/zap"
 	zapobserver "go.uber.org/zap/zaptest/observer"
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/controller"
 )
@@ -81,3 +83,42 @@ func TestCtrlOpts(t *testing.T) {
 	// Assert that the promote filter function returns true.
 	assert.Assert(t, promote)
 }
+
+func TestNamespaceLimitHandler(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	qm := queuepkg.NewManager(logger)
+	enqueued := []string{}
+	handler := namespaceLimitHandler(qm, func(namespace string) { enqueued = append(enqueued, namespace) }, logger)
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns", Annotations: map[string]string{keys.ConcurrencyLimit: "3"}}}
+	handler.OnAdd(ns, false)
+	assert.Equal(t, qm.NamespaceLimit("ns"), 3)
+	assert.Equal(t, len(enqueued), 0)
+
+	updated := ns.DeepCopy()
+	updated.Annotations[keys.ConcurrencyLimit] = "1"
+	handler.OnUpdate(ns, updated)
+	assert.Equal(t, qm.NamespaceLimit("ns"), 1)
+	assert.Equal(t, len(enqueued), 0)
+
+	// the queued PipelineRuns start up to the raised limit
+	raised := updated.DeepCopy()
+	raised.Annotations[keys.ConcurrencyLimit] = "2"
+	handler.OnUpdate(updated, raised)
+	assert.Equal(t, qm.NamespaceLimit("ns"), 2)
+	assert.DeepEqual(t, enqueued, []string{"ns"})
+
+	// and all start when the limit is removed
+	removed := raised.DeepCopy()
+	delete(removed.Annotations, keys.ConcurrencyLimit)
+	handler.OnUpdate(raised, removed)
+	assert.Equal(t, qm.NamespaceLimit("ns"), 0)
+	assert.DeepEqual(t, enqueued, []string{"ns", "ns"})
+
+	handler.OnUpdate(removed, updated)
+	assert.Equal(t, qm.NamespaceLimit("ns"), 1)
+
+	handler.OnDelete(updated)
+	assert.Equal(t, qm.NamespaceLimit("ns"), 0)
+}
@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/flaky"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"knative.dev/pkg/apis"
 )
 
@@ -34,8 +36,10 @@ func (r *Reconciler) countPipelineRun(pr *tektonv1.PipelineRun) error {
 		} else {
 			gitProvider += "-webhook"
 		}
-	case "gitlab", "gitea", "bitbucket-cloud", "bitbucket-datacenter":
+	case "gitlab", "gitea", "bitbucket-cloud", "bitbucket-datacenter", "azure-devops", "gerrit":
 		gitProvider += "-webhook"
+	case "git":
+		gitProvider += "-poll"
 	default:
 		return fmt.Errorf("no supported Git provider")
 	}
@@ -65,3 +69,82 @@ func (r *Reconciler) calculatePRDuration(pr *tektonv1.PipelineRun) error {
 
 	return r.metrics.CountPRDuration(pr.GetNamespace(), repository, status, reason, duration)
 }
+
+// emitTaskMetrics counts the completed tasks of the PipelineRun and the flaky
+// ones, the tasks whose result differs from their result in the previous
+// attempt of the same pipeline on the same commit. The PipelineRuns and their
+// TaskRuns are read from the informer caches.
+func (r *Reconciler) emitTaskMetrics(pr *tektonv1.PipelineRun) error {
+	run, ok := flaky.NewRun(pr, r.taskRunStatuses(pr))
+	if !ok {
+		return nil
+	}
+	repository := pr.GetAnnotations()[keys.Repository]
+	for task, succeeded := range run.Tasks {
+		status := "success"
+		if !succeeded {
+			status = "failed"
+		}
+		if err := r.metrics.CountTask(pr.GetNamespace(), repository, run.Pipeline, task, status); err != nil {
+			return err
+		}
+	}
+
+	sha, originalPRName := pr.GetLabels()[keys.SHA], pr.GetLabels()[keys.OriginalPRName]
+	if sha == "" || originalPRName == "" {
+		return nil
+	}
+	prs, err := r.pipelineRunLister.PipelineRuns(pr.GetNamespace()).List(labels.SelectorFromSet(labels.Set{
+		keys.SHA:            sha,
+		keys.OriginalPRName: originalPRName,
+	}))
+	if err != nil {
+		return err
+	}
+	// only the most recent attempt before this one is compared, an older
+	// result of a task is not a flip of the current one
+	var previous *tektonv1.PipelineRun
+	for _, attempt := range prs {
+		if attempt.GetName() == pr.GetName() || !attempt.IsDone() || attempt.IsCancelled() ||
+			!attempt.CreationTimestamp.Before(&pr.CreationTimestamp) {
+			continue
+		}
+		if previous == nil || previous.CreationTimestamp.Before(&attempt.CreationTimestamp) {
+			previous = attempt
+		}
+	}
+	if previous == nil {
+		return nil
+	}
+	prev, ok := flaky.NewRun(previous, r.taskRunStatuses(previous))
+	if !ok {
+		return nil
+	}
+	for _, task := range flaky.Flips(run, []flaky.Run{prev}) {
+		if err := r.metrics.CountFlakyTask(pr.GetNamespace(), repository, run.Pipeline, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskRunStatuses returns the statuses of the TaskRuns of the PipelineRun
+// found in the TaskRun informer cache.
+func (r *Reconciler) taskRunStatuses(pr *tektonv1.PipelineRun) map[string]*tektonv1.PipelineRunTaskRunStatus {
+	trStatus := map[string]*tektonv1.PipelineRunTaskRunStatus{}
+	for _, cr := range pr.Status.ChildReferences {
+		if cr.Kind != "TaskRun" {
+			continue
+		}
+		tr, err := r.taskRunLister.TaskRuns(pr.GetNamespace()).Get(cr.Name)
+		if err != nil {
+			r.run.Clients.Log.Debugf("cannot get taskrun %s of pipelinerun %s/%s: %v", cr.Name, pr.GetNamespace(), pr.GetName(), err)
+			continue
+		}
+		trStatus[cr.Name] = &tektonv1.PipelineRunTaskRunStatus{
+			PipelineTaskName: cr.PipelineTaskName,
+			Status:           &tr.Status,
+		}
+	}
+	return trStatus
+}
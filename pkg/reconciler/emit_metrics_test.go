@@ -5,16 +5,22 @@ import (
 	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	prmetrics "github.com/openshift-pipelines/pipelines-as-code/pkg/pipelinerunmetrics"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
 	metricsutils "github.com/openshift-pipelines/pipelines-as-code/pkg/test/metricstest"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/metrics/metricstest"
 	_ "knative.dev/pkg/metrics/testing"
+	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
 // TestCountPipelineRun tests pipelinerun count metric.
@@ -75,6 +81,18 @@ func TestCountPipelineRun(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "provider is a polled git repository",
+			annotations: map[string]string{
+				keys.GitProvider: "git",
+				keys.EventType:   "push",
+			},
+			tags: map[string]string{
+				"provider":   "git-poll",
+				"event-type": "push",
+			},
+			wantErr: false,
+		},
 		{
 			name: "unsupported provider",
 			annotations: map[string]string{
@@ -307,3 +325,159 @@ func TestCountRunningPRs(t *testing.T) {
 	}
 	metricstest.CheckLastValueData(t, "pipelines_as_code_running_pipelineruns_count", tags, float64(numberOfRunningPRs))
 }
+
+func TestQueuedPRsMetrics(t *testing.T) {
+	now := time.Now()
+	queuedPR := func(name string, age time.Duration, status tektonv1.PipelineRunSpecStatus) *tektonv1.PipelineRun {
+		return &tektonv1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "pac-ns",
+				CreationTimestamp: metav1.Time{Time: now.Add(-age)},
+				Annotations:       map[string]string{keys.Repository: "pac-repo"},
+				Labels:            map[string]string{keys.State: "queued"},
+			},
+			Spec: tektonv1.PipelineRunSpec{Status: status},
+		}
+	}
+	prl := []*tektonv1.PipelineRun{
+		queuedPR("oldest", 10*time.Minute, tektonv1.PipelineRunSpecStatusPending),
+		queuedPR("newest", time.Minute, tektonv1.PipelineRunSpecStatusPending),
+		queuedPR("cancelled", time.Hour, tektonv1.PipelineRunSpecStatusCancelled),
+	}
+
+	metricsutils.ResetMetrics()
+	m, err := prmetrics.NewRecorder()
+	assert.NilError(t, err)
+
+	err = m.EmitQueuedPRsMetrics(prl, now)
+	assert.NilError(t, err)
+	tags := map[string]string{
+		"namespace":  "pac-ns",
+		"repository": "pac-repo",
+	}
+	metricstest.CheckLastValueData(t, "pipelines_as_code_queued_pipelineruns_count", tags, 2)
+	metricstest.CheckLastValueData(t, "pipelines_as_code_queued_pipelineruns_max_wait_seconds", tags, (10 * time.Minute).Seconds())
+
+	err = m.CountQueueTimeout("pac-ns", "pac-repo")
+	assert.NilError(t, err)
+	metricstest.CheckCountData(t, "pipelines_as_code_queue_timeout_count", tags, 1)
+}
+
+func newTaskMetricsPR(now time.Time, name string, age time.Duration, status corev1.ConditionStatus, tasks map[string]corev1.ConditionStatus) (*tektonv1.PipelineRun, []*tektonv1.TaskRun) {
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "pac-ns",
+			CreationTimestamp: metav1.Time{Time: now.Add(-age)},
+			Labels: map[string]string{
+				keys.SHA:            "sha1",
+				keys.OriginalPRName: "pull-request",
+			},
+			Annotations: map[string]string{
+				keys.Repository:     "pac-repo",
+				keys.SHA:            "sha1",
+				keys.OriginalPRName: "pull-request",
+			},
+		},
+		Status: tektonv1.PipelineRunStatus{
+			Status: duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status}}},
+		},
+	}
+	taskruns := []*tektonv1.TaskRun{}
+	for task, taskStatus := range tasks {
+		trName := name + "-" + task
+		pr.Status.ChildReferences = append(pr.Status.ChildReferences, tektonv1.ChildStatusReference{
+			TypeMeta:         runtime.TypeMeta{Kind: "TaskRun"},
+			Name:             trName,
+			PipelineTaskName: task,
+		})
+		taskruns = append(taskruns, &tektonv1.TaskRun{
+			ObjectMeta: metav1.ObjectMeta{Name: trName, Namespace: "pac-ns"},
+			Status: tektonv1.TaskRunStatus{
+				Status: duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: taskStatus}}},
+			},
+		})
+	}
+	return pr, taskruns
+}
+
+func TestEmitTaskMetrics(t *testing.T) {
+	now := time.Now()
+	previous, previousTRs := newTaskMetricsPR(now, "pull-request-aaaaa", time.Hour, corev1.ConditionFalse,
+		map[string]corev1.ConditionStatus{"lint": corev1.ConditionTrue, "test": corev1.ConditionFalse})
+	current, currentTRs := newTaskMetricsPR(now, "pull-request-bbbbb", time.Minute, corev1.ConditionTrue,
+		map[string]corev1.ConditionStatus{"test": corev1.ConditionTrue})
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		PipelineRuns: []*tektonv1.PipelineRun{previous, current},
+		TaskRuns:     append(previousTRs, currentTRs...),
+	})
+
+	metricsutils.ResetMetrics()
+	m, err := prmetrics.NewRecorder()
+	assert.NilError(t, err)
+	r := &Reconciler{
+		metrics:           m,
+		pipelineRunLister: stdata.PipelineLister,
+		taskRunLister:     stdata.TaskRunLister,
+		run: &params.Run{
+			Clients: clients.Clients{Tekton: stdata.Pipeline, Log: zap.NewNop().Sugar()},
+		},
+	}
+
+	assert.NilError(t, r.emitTaskMetrics(current))
+	metricstest.CheckCountData(t, "pipelines_as_code_task_count", map[string]string{
+		"namespace":  "pac-ns",
+		"repository": "pac-repo",
+		"pipeline":   "pull-request",
+		"task":       "test",
+		"status":     "success",
+	}, 1)
+	metricstest.CheckCountData(t, "pipelines_as_code_flaky_task_count", map[string]string{
+		"namespace":  "pac-ns",
+		"repository": "pac-repo",
+		"pipeline":   "pull-request",
+		"task":       "test",
+	}, 1)
+}
+
+func TestEmitTaskMetricsMostRecentAttempt(t *testing.T) {
+	now := time.Now()
+	// lint flipped since the first attempt but did not run in the second one
+	first, firstTRs := newTaskMetricsPR(now, "pull-request-aaaaa", 2*time.Hour, corev1.ConditionFalse,
+		map[string]corev1.ConditionStatus{"lint": corev1.ConditionFalse})
+	second, secondTRs := newTaskMetricsPR(now, "pull-request-bbbbb", time.Hour, corev1.ConditionFalse,
+		map[string]corev1.ConditionStatus{"test": corev1.ConditionFalse})
+	current, currentTRs := newTaskMetricsPR(now, "pull-request-ccccc", time.Minute, corev1.ConditionTrue,
+		map[string]corev1.ConditionStatus{"lint": corev1.ConditionTrue})
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		PipelineRuns: []*tektonv1.PipelineRun{first, second, current},
+		TaskRuns:     append(append(firstTRs, secondTRs...), currentTRs...),
+	})
+
+	metricsutils.ResetMetrics()
+	m, err := prmetrics.NewRecorder()
+	assert.NilError(t, err)
+	r := &Reconciler{
+		metrics:           m,
+		pipelineRunLister: stdata.PipelineLister,
+		taskRunLister:     stdata.TaskRunLister,
+		run: &params.Run{
+			Clients: clients.Clients{Tekton: stdata.Pipeline, Log: zap.NewNop().Sugar()},
+		},
+	}
+
+	assert.NilError(t, r.emitTaskMetrics(current))
+	metricstest.CheckCountData(t, "pipelines_as_code_task_count", map[string]string{
+		"namespace":  "pac-ns",
+		"repository": "pac-repo",
+		"pipeline":   "pull-request",
+		"task":       "lint",
+		"status":     "success",
+	}, 1)
+	metricstest.AssertNoMetric(t, "pipelines_as_code_flaky_task_count")
+}
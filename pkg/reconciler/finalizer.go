@@ -54,6 +54,7 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, pr *tektonv1.PipelineRun)
 			repo.Spec.Merge(r.globalRepo.Spec)
 		}
 		logger = logger.With("namespace", repo.Namespace)
+		_ = r.setConcurrencyLimits(pr.GetNamespace())
 		next := r.qm.RemoveAndTakeItemFromQueue(repo, pr)
 		if next != "" {
 			key := strings.Split(next, "/")
@@ -61,8 +62,12 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, pr *tektonv1.PipelineRun)
 			if err != nil {
 				return err
 			}
+			nextRepo, err := r.getPipelineRunRepository(repo, pr)
+			if err != nil {
+				return err
+			}
 			if err := r.
-				updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
+				updatePipelineRunToInProgress(ctx, logger, nextRepo, pr); err != nil {
 				logger.Errorf("failed to update status: %w", err)
 				return err
 			}
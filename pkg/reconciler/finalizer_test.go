@@ -148,6 +148,7 @@ func TestReconciler_FinalizeKind(t *testing.T) {
 			cs := &params.Run{
 				Clients: clients.Clients{
 					PipelineAsCode: stdata.PipelineAsCode,
+					Kube:           stdata.Kube,
 					Log:            fakelogger,
 				},
 				Info: info.Info{
@@ -166,7 +167,7 @@ func TestReconciler_FinalizeKind(t *testing.T) {
 
 			if len(tt.addToQueue) != 0 {
 				for _, pr := range tt.addToQueue {
-					_, err := r.qm.AddListToRunningQueue(finalizeTestRepo, []string{pr.GetNamespace() + "/" + pr.GetName()})
+					_, err := r.qm.AddListToRunningQueue(finalizeTestRepo, pr, []*tektonv1.PipelineRun{pr})
 					assert.NilError(t, err)
 				}
 			}
@@ -0,0 +1,50 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kueue"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// admitPipelineRun reports the PipelineRun as started on the git provider once
+// its Kueue Workload is admitted, the PipelineRun stays queued until then and
+// is reconciled again when its Workload changes. The PipelineRuns admitted by
+// Kueue are not queued by the queue manager.
+func (r *Reconciler) admitPipelineRun(ctx context.Context, logger *zap.SugaredLogger, pr *tektonv1.PipelineRun) error {
+	if r.workloads == nil {
+		logger.Warnf("pipelineRun %s/%s is waiting for admission by kueue queue %s but kueue is not installed", pr.GetNamespace(), pr.GetName(), kueue.QueueName(pr))
+		return nil
+	}
+	admission, err := kueue.GetAdmission(r.workloads, pr)
+	if err != nil {
+		return err
+	}
+	if !admission.Admitted {
+		if admission.Found {
+			logger.Infof("pipelineRun %s/%s is waiting for admission by kueue queue %s: %s", pr.GetNamespace(), pr.GetName(), kueue.QueueName(pr), admission.Message)
+		} else {
+			logger.Infof("pipelineRun %s/%s is waiting for its kueue workload in queue %s", pr.GetNamespace(), pr.GetName(), kueue.QueueName(pr))
+		}
+		return nil
+	}
+
+	repoName := pr.GetAnnotations()[keys.Repository]
+	repo, err := r.repoLister.Repositories(pr.GetNamespace()).Get(repoName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get repository CR: %w", err)
+	}
+	repo = repo.DeepCopy()
+	if r.globalRepo, err = r.repoLister.Repositories(r.run.Info.Kube.Namespace).Get(r.run.Info.Controller.GlobalRepository); err == nil && r.globalRepo != nil {
+		repo.Spec.Merge(r.globalRepo.Spec)
+	}
+	logger.Infof("pipelineRun %s/%s has been admitted by kueue queue %s", pr.GetNamespace(), pr.GetName(), kueue.QueueName(pr))
+	return r.updatePipelineRunToInProgress(ctx, logger, repo, pr)
+}
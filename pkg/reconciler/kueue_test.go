@@ -0,0 +1,94 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kueue"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestAdmitPipelineRunWaitsForKueue(t *testing.T) {
+	observer, logs := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	ctx, _ := rtesting.SetupFakeContext(t)
+
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pr",
+			Namespace: "ns",
+			UID:       types.UID("uid"),
+			Labels:    map[string]string{kueue.QueueNameLabel: "team-queue"},
+			Annotations: map[string]string{
+				keys.Repository: "repo",
+				keys.State:      kubeinteraction.StateQueued,
+			},
+		},
+		Spec: tektonv1.PipelineRunSpec{Status: tektonv1.PipelineRunSpecStatusPending},
+	}
+	workload := &unstructured.Unstructured{}
+	workload.SetUnstructuredContent(map[string]any{
+		"apiVersion": "kueue.x-k8s.io/v1beta1",
+		"kind":       "Workload",
+		"metadata": map[string]any{
+			"name":      "pipelinerun-pr",
+			"namespace": "ns",
+			"labels":    map[string]any{kueue.JobUIDLabel: "uid"},
+		},
+		"status": map[string]any{
+			"conditions": []any{
+				map[string]any{"type": "QuotaReserved", "status": "False", "message": "insufficient quota"},
+			},
+		},
+	})
+	workload.SetOwnerReferences([]metav1.OwnerReference{{Kind: "PipelineRun", Name: "pr", UID: types.UID("uid")}})
+	kdyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{kueue.WorkloadGVR: "WorkloadList"}, workload)
+	informer := kueue.NewWorkloadInformer(ctx, kdyn)
+	go informer.Run(ctx.Done())
+	assert.Assert(t, cache.WaitForCacheSync(ctx.Done(), informer.HasSynced))
+
+	r := &Reconciler{
+		run: &params.Run{
+			Clients: clients.Clients{Dynamic: kdyn},
+			Info:    info.Info{Kube: &info.KubeOpts{}, Controller: &info.ControllerInfo{}},
+		},
+		workloads: informer.GetIndexer(),
+	}
+	// the PipelineRun is reconciled again when its workload changes, it is
+	// not requeued
+	assert.NilError(t, r.admitPipelineRun(ctx, logger, pr))
+	assert.Equal(t, logs.FilterMessageSnippet("insufficient quota").Len(), 1)
+}
+
+func TestAdmitPipelineRunWithoutKueue(t *testing.T) {
+	observer, logs := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	ctx, _ := rtesting.SetupFakeContext(t)
+
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pr",
+			Namespace: "ns",
+			Labels:    map[string]string{kueue.QueueNameLabel: "team-queue"},
+		},
+	}
+	r := &Reconciler{run: &params.Run{}}
+	assert.NilError(t, r.admitPipelineRun(ctx, logger, pr))
+	assert.Equal(t, logs.FilterMessageSnippet("kueue is not installed").Len(), 1)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	pacAPIv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
@@ -13,6 +14,7 @@ import (
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/controller"
 )
 
 func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLogger, pr *tektonv1.PipelineRun) error {
@@ -52,9 +54,23 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 		repo.Spec.Merge(r.globalRepo.Spec)
 	}
 
+	// cancel the PipelineRun when it waited in the queue for too long, or
+	// check it again when it would have
+	var requeueAfter time.Duration
+	if maxWait := r.maxQueueWait(repo); maxWait > 0 {
+		waited := time.Since(pr.GetCreationTimestamp().Time)
+		if waited >= maxWait {
+			return r.timeoutQueuedPipelineRun(ctx, logger, repo, pr, maxWait)
+		}
+		requeueAfter = maxWait - waited
+	}
+
 	// if concurrency was set and later removed or changed to zero
 	// then remove pipelineRun from Queue and update pending state to running
-	if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit == 0 {
+	// unless a namespace or global limit still applies
+	hasLimits := r.setConcurrencyLimits(pr.GetNamespace())
+	_, limit := queuepkg.QueueKey(repo, pr)
+	if limit != nil && *limit == 0 && !hasLimits {
 		_ = r.qm.RemoveAndTakeItemFromQueue(repo, pr)
 		if err := r.updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
 			return fmt.Errorf("failed to update PipelineRun to in_progress: %w", err)
@@ -66,9 +82,9 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 	var itered int
 	maxIterations := 5
 
-	orderedList := queuepkg.FilterPipelineRunByState(ctx, r.run.Clients.Tekton, strings.Split(order, ","), tektonv1.PipelineRunSpecStatusPending, kubeinteraction.StateQueued)
+	orderedList := queuepkg.FilterPipelineRunsByState(ctx, r.run.Clients.Tekton, strings.Split(order, ","), tektonv1.PipelineRunSpecStatusPending, kubeinteraction.StateQueued)
 	for {
-		acquired, err := r.qm.AddListToRunningQueue(repo, orderedList)
+		acquired, err := r.qm.AddListToRunningQueue(repo, pr, orderedList)
 		if err != nil {
 			return fmt.Errorf("failed to add to queue: %s: %w", pr.GetName(), err)
 		}
@@ -77,17 +93,25 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 			break
 		}
 
+		// the acquired PipelineRuns can come from the queue of another
+		// Repository, they are removed from their own queue on failure
 		for _, prKeys := range acquired {
 			nsName := strings.Split(prKeys, "/")
-			repoKey := queuepkg.RepoKey(repo)
-			pr, err = r.run.Clients.Tekton.TektonV1().PipelineRuns(nsName[0]).Get(ctx, nsName[1], metav1.GetOptions{})
+			acquiredPR, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(nsName[0]).Get(ctx, nsName[1], metav1.GetOptions{})
 			if err != nil {
 				logger.Info("failed to get pr with namespace and name: ", nsName[0], nsName[1])
-				_ = r.qm.RemoveFromQueue(repoKey, prKeys)
+				_ = r.qm.RemovePipelineRun(prKeys)
+				continue
+			}
+			prRepo, err := r.getPipelineRunRepository(repo, acquiredPR)
+			if err != nil {
+				logger.Info("failed to get repository of pr with namespace and name: ", nsName[0], nsName[1])
+				_ = r.qm.RemovePipelineRun(prKeys)
 			} else {
-				if err := r.updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
+				if err := r.updatePipelineRunToInProgress(ctx, logger, prRepo, acquiredPR); err != nil {
 					logger.Errorf("failed to update pipelineRun to in_progress: %w", err)
-					_ = r.qm.RemoveFromQueue(repoKey, prKeys)
+					prQueueKey, _ := queuepkg.QueueKey(prRepo, acquiredPR)
+					_ = r.qm.RemoveFromQueue(prQueueKey, prKeys)
 				} else {
 					processed = true
 				}
@@ -101,5 +125,37 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 		}
 		itered++
 	}
+	if requeueAfter > 0 {
+		return controller.NewRequeueAfter(requeueAfter)
+	}
 	return nil
 }
+
+// setConcurrencyLimits updates the global limit of the queue manager from
+// the pipelines-as-code configmap, it returns true when the global limit or
+// the limit of the namespace applies. The namespace limits are kept up to date
+// by the namespace informer.
+func (r *Reconciler) setConcurrencyLimits(namespace string) bool {
+	globalLimit := r.run.Info.GetPacOpts().ConcurrencyLimit
+	r.qm.SetGlobalLimit(globalLimit)
+	return globalLimit > 0 || r.qm.NamespaceLimit(namespace) > 0
+}
+
+// getPipelineRunRepository returns the Repository of pr, merged with the global
+// repository, or repo when pr belongs to it. The PipelineRuns of a concurrency
+// group can belong to another Repository than the one releasing the queue.
+func (r *Reconciler) getPipelineRunRepository(repo *pacAPIv1alpha1.Repository, pr *tektonv1.PipelineRun) (*pacAPIv1alpha1.Repository, error) {
+	repoName := pr.GetAnnotations()[keys.Repository]
+	if repoName == "" || (repoName == repo.GetName() && pr.GetNamespace() == repo.GetNamespace()) {
+		return repo, nil
+	}
+	prRepo, err := r.repoLister.Repositories(pr.GetNamespace()).Get(repoName)
+	if err != nil {
+		return nil, err
+	}
+	prRepo = prRepo.DeepCopy()
+	if r.globalRepo != nil {
+		prRepo.Spec.Merge(r.globalRepo.Spec)
+	}
+	return prRepo, nil
+}
@@ -183,6 +183,7 @@ func TestQueuePipelineRun(t *testing.T) {
 							Namespace: "global",
 						},
 						Controller: &info.ControllerInfo{},
+						Pac:        info.NewPacOpts(),
 					},
 					Clients: clients.Clients{
 						PipelineAsCode: stdata.PipelineAsCode,
@@ -0,0 +1,77 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxQueueWait returns the maximum duration the PipelineRuns of the repository
+// wait in the queue, the max_queue_wait setting of the repository takes
+// precedence over the max-queue-wait setting of the configmap. It returns 0
+// when there is no maximum.
+func (r *Reconciler) maxQueueWait(repo *v1alpha1.Repository) time.Duration {
+	value := r.run.Info.GetPacOpts().MaxQueueWait
+	if repo.Spec.Settings != nil && repo.Spec.Settings.MaxQueueWait != "" {
+		value = repo.Spec.Settings.MaxQueueWait
+	}
+	key := repo.GetNamespace() + "/" + repo.GetName()
+	if value == "" {
+		r.invalidQueueWaits.Delete(key)
+		return 0
+	}
+	wait, err := time.ParseDuration(value)
+	if err != nil || wait < 0 {
+		// the repository is reconciled with each of its PipelineRuns, only
+		// warn once about the same invalid value
+		if previous, warned := r.invalidQueueWaits.Swap(key, value); !warned || previous != value {
+			r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryMaxQueueWait",
+				fmt.Sprintf("invalid max queue wait %q, the PipelineRuns wait in the queue without limit", value))
+		}
+		return 0
+	}
+	r.invalidQueueWaits.Delete(key)
+	return wait
+}
+
+func queueTimeoutPatch(wait time.Duration) ([]byte, error) {
+	return json.Marshal([]map[string]any{
+		{"op": "test", "path": "/spec/status", "value": tektonv1.PipelineRunSpecStatusPending},
+		{"op": "replace", "path": "/spec/status", "value": tektonv1.PipelineRunSpecStatusCancelled},
+		{"op": "add", "path": "/metadata/annotations/" + strings.ReplaceAll(keys.QueueTimeout, "/", "~1"), "value": wait.String()},
+	})
+}
+
+// timeoutQueuedPipelineRun cancels a PipelineRun which waited in the queue
+// longer than the maximum queue wait, the PipelineRun is then reported as
+// failed to the git provider and removed from the queue like any cancelled
+// PipelineRun.
+func (r *Reconciler) timeoutQueuedPipelineRun(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun, wait time.Duration) error {
+	patch, err := queueTimeoutPatch(wait)
+	if err != nil {
+		return err
+	}
+	if _, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).Patch(ctx, pr.GetName(),
+		types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to cancel pipelineRun %s/%s timed out in queue: %w", pr.GetNamespace(), pr.GetName(), err)
+	}
+
+	msg := fmt.Sprintf("PipelineRun %s has been cancelled after waiting in the queue for more than %s", pr.GetName(), wait)
+	logger.Info(msg)
+	r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "QueuedPipelineRunTimeout", msg)
+	if r.metrics != nil {
+		if err := r.metrics.CountQueueTimeout(pr.GetNamespace(), repo.GetName()); err != nil {
+			logger.Warnf("failed to count the queue timeout of pipelineRun %s: %v", pr.GetName(), err)
+		}
+	}
+	return nil
+}
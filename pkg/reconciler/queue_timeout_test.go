@@ -0,0 +1,185 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testconcurrency "github.com/openshift-pipelines/pipelines-as-code/pkg/test/concurrency"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/controller"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestQueuePipelineRunMaxQueueWait(t *testing.T) {
+	tests := []struct {
+		name          string
+		globalWait    string
+		repoWait      string
+		age           time.Duration
+		wantTimeout   bool
+		wantRequeue   bool
+		wantEventText string
+	}{
+		{
+			name: "no max queue wait",
+			age:  24 * time.Hour,
+		},
+		{
+			name:        "waiting less than the repository max queue wait",
+			repoWait:    "1h",
+			age:         10 * time.Minute,
+			wantRequeue: true,
+		},
+		{
+			name:          "waiting more than the repository max queue wait",
+			repoWait:      "1h",
+			age:           2 * time.Hour,
+			wantTimeout:   true,
+			wantEventText: "PipelineRun test has been cancelled after waiting in the queue for more than 1h0m0s",
+		},
+		{
+			name:          "waiting more than the global max queue wait",
+			globalWait:    "30m",
+			age:           time.Hour,
+			wantTimeout:   true,
+			wantEventText: "PipelineRun test has been cancelled after waiting in the queue for more than 30m0s",
+		},
+		{
+			name:        "repository max queue wait takes precedence",
+			globalWait:  "30m",
+			repoWait:    "2h",
+			age:         time.Hour,
+			wantRequeue: true,
+		},
+		{
+			name:          "invalid repository max queue wait",
+			repoWait:      "forever",
+			age:           24 * time.Hour,
+			wantEventText: `invalid max queue wait "forever", the PipelineRuns wait in the queue without limit`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			fakelogger := zap.New(observer).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			pr := &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test",
+					Namespace:         "test",
+					CreationTimestamp: metav1.Time{Time: time.Now().Add(-tt.age)},
+					Annotations: map[string]string{
+						keys.ExecutionOrder: "test/test",
+						keys.Repository:     "test",
+					},
+				},
+				Spec: tektonv1.PipelineRunSpec{Status: tektonv1.PipelineRunSpecStatusPending},
+			}
+			repo := &pacv1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec: pacv1alpha1.RepositorySpec{
+					URL:      randomURL,
+					Settings: &pacv1alpha1.Settings{MaxQueueWait: tt.repoWait},
+				},
+			}
+			stdata, informers := testclient.SeedTestData(t, ctx, testclient.Data{
+				Repositories: []*pacv1alpha1.Repository{repo},
+				PipelineRuns: []*tektonv1.PipelineRun{pr},
+			})
+			pacOpts := info.NewPacOpts()
+			pacOpts.MaxQueueWait = tt.globalWait
+			r := &Reconciler{
+				qm:           testconcurrency.TestQMI{},
+				repoLister:   informers.Repository.Lister(),
+				eventEmitter: events.NewEventEmitter(stdata.Kube, fakelogger),
+				run: &params.Run{
+					Info: info.Info{
+						Kube:       &info.KubeOpts{Namespace: "global"},
+						Controller: &info.ControllerInfo{},
+						Pac:        pacOpts,
+					},
+					Clients: clients.Clients{
+						PipelineAsCode: stdata.PipelineAsCode,
+						Tekton:         stdata.Pipeline,
+						Kube:           stdata.Kube,
+						Log:            fakelogger,
+					},
+				},
+			}
+
+			err := r.queuePipelineRun(ctx, fakelogger, pr)
+			requeue, _ := controller.IsRequeueKey(err)
+			assert.Equal(t, requeue, tt.wantRequeue, "unexpected result %v", err)
+			if !tt.wantRequeue {
+				assert.NilError(t, err)
+			}
+
+			got, err := stdata.Pipeline.TektonV1().PipelineRuns("test").Get(ctx, "test", metav1.GetOptions{})
+			assert.NilError(t, err)
+			if tt.wantTimeout {
+				assert.Equal(t, got.Spec.Status, tektonv1.PipelineRunSpecStatus(tektonv1.PipelineRunSpecStatusCancelled))
+				assert.Assert(t, got.GetAnnotations()[keys.QueueTimeout] != "")
+			} else {
+				assert.Equal(t, got.Spec.Status, tektonv1.PipelineRunSpecStatus(tektonv1.PipelineRunSpecStatusPending))
+			}
+
+			repoEvents, err := stdata.Kube.CoreV1().Events("test").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			if tt.wantEventText == "" {
+				assert.Equal(t, len(repoEvents.Items), 0)
+				return
+			}
+			assert.Equal(t, len(repoEvents.Items), 1)
+			assert.Equal(t, repoEvents.Items[0].Message, tt.wantEventText)
+		})
+	}
+}
+
+func TestMaxQueueWaitWarnsOnce(t *testing.T) {
+	observer, logs := zapobserver.New(zap.InfoLevel)
+	fakelogger := zap.New(observer).Sugar()
+	repo := &pacv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: pacv1alpha1.RepositorySpec{
+			URL:      randomURL,
+			Settings: &pacv1alpha1.Settings{MaxQueueWait: "forever"},
+		},
+	}
+	r := &Reconciler{
+		eventEmitter: events.NewEventEmitter(nil, fakelogger),
+		run: &params.Run{
+			Info: info.Info{Pac: info.NewPacOpts()},
+		},
+	}
+
+	countEvents := func() int {
+		return logs.FilterMessageSnippet("invalid max queue wait").Len()
+	}
+
+	assert.Equal(t, r.maxQueueWait(repo), time.Duration(0))
+	assert.Equal(t, r.maxQueueWait(repo), time.Duration(0))
+	assert.Equal(t, countEvents(), 1)
+
+	// another invalid value is reported again
+	repo.Spec.Settings.MaxQueueWait = "-1h"
+	assert.Equal(t, r.maxQueueWait(repo), time.Duration(0))
+	assert.Equal(t, countEvents(), 2)
+
+	// and so is the same invalid value once it has been fixed in between
+	repo.Spec.Settings.MaxQueueWait = "1h"
+	assert.Equal(t, r.maxQueueWait(repo), time.Hour)
+	repo.Spec.Settings.MaxQueueWait = "-1h"
+	assert.Equal(t, r.maxQueueWait(repo), time.Duration(0))
+	assert.Equal(t, countEvents(), 3)
+}
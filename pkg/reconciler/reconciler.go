@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	pipelinerunreconciler "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1/pipelinerun"
@@ -12,6 +13,7 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/system"
@@ -24,6 +26,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	pacapi "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kueue"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/llm"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
@@ -39,12 +42,20 @@ type Reconciler struct {
 	run               *params.Run
 	repoLister        pacapi.RepositoryLister
 	pipelineRunLister tektonv1lister.PipelineRunLister
+	taskRunLister     tektonv1lister.TaskRunLister
 	kinteract         kubeinteraction.Interface
 	qm                queuepkg.ManagerInterface
 	metrics           *prmetrics.Recorder
+	llmCache          *llm.AnalysisCache
 	eventEmitter      *events.EventEmitter
 	globalRepo        *v1alpha1.Repository
 	secretNS          string
+	// workloads is the indexer of the Kueue Workloads, nil when Kueue is
+	// not installed
+	workloads cache.Indexer
+	// invalidQueueWaits holds the invalid max queue wait already reported
+	// for each repository
+	invalidQueueWaits sync.Map
 }
 
 var (
@@ -109,7 +120,11 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, pr *tektonv1.PipelineRun
 
 	// queue pipelines which are in queued state and pending status
 	// if status is not pending, it could be cancelled so let it be reported, even if state is queued
+	// the pipelines admitted by kueue are started by kueue, not queued
 	if state == kubeinteraction.StateQueued && pr.Spec.Status == tektonv1.PipelineRunSpecStatusPending {
+		if kueue.QueueName(pr) != "" {
+			return r.admitPipelineRun(ctx, logger, pr)
+		}
 		return r.queuePipelineRun(ctx, logger, pr)
 	}
 
@@ -231,25 +246,47 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 		return repo, fmt.Errorf("cannot set client: %w", err)
 	}
 
-	finalState := kubeinteraction.StateCompleted
-	newPr, err := r.postFinalStatus(ctx, logger, pacInfo, provider, event, pr)
-	if err != nil {
-		logger.Errorf("failed to post final status, moving on: %v", err)
-		finalState = kubeinteraction.StateFailed
+	// a failed PipelineRun with retries left is run again, it is reported as
+	// still in progress until its last attempt is done
+	var retry *tektonv1.PipelineRun
+	if shouldRetry, err := r.shouldRetry(ctx, pr); err != nil {
+		r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "PipelineRunRetry", fmt.Sprintf("cannot retry pipelineRun %s: %v", pr.GetName(), err))
+	} else if shouldRetry {
+		if retry, err = r.retryPipelineRun(ctx, logger, repo, event, pr); err != nil {
+			r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "PipelineRunRetry", fmt.Sprintf("cannot retry pipelineRun %s: %v", pr.GetName(), err))
+		}
 	}
 
-	// Perform LLM analysis only for failed pipeline runs (best-effort, non-blocking)
-	// Users can use CEL expressions in role configurations for more fine-grained control
-	if len(newPr.Status.Conditions) > 0 && newPr.Status.Conditions[0].Status == corev1.ConditionFalse {
-		if err := r.performLLMAnalysis(ctx, logger, repo, newPr, event, provider); err != nil {
-			logger.Warnf("LLM analysis failed (non-blocking): %v", err)
-			r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "LLMAnalysisFailed",
-				fmt.Sprintf("AI/LLM analysis failed for repository %s/%s and pipeline run %s: %v", repo.Namespace, repo.Name, newPr.Name, err))
+	finalState := kubeinteraction.StateCompleted
+	if retry != nil {
+		if err := r.postRetryStatus(ctx, logger, provider, event, pr, retry); err != nil {
+			logger.Errorf("failed to post retry status, moving on: %v", err)
+			finalState = kubeinteraction.StateFailed
+		}
+	} else {
+		newPr, finalStatus, err := r.postFinalStatus(ctx, logger, pacInfo, provider, event, pr)
+		if err != nil {
+			logger.Errorf("failed to post final status, moving on: %v", err)
+			finalState = kubeinteraction.StateFailed
+		}
+
+		// the run status is updated before the LLM analysis so the analysis
+		// can be stored in it
+		statusErr := r.updateRepoRunStatus(ctx, logger, newPr, repo, event)
+
+		// Perform LLM analysis only for failed pipeline runs (best-effort, non-blocking)
+		// Users can use CEL expressions in role configurations for more fine-grained control
+		if len(newPr.Status.Conditions) > 0 && newPr.Status.Conditions[0].Status == corev1.ConditionFalse {
+			if err := r.performLLMAnalysis(ctx, logger, repo, newPr, event, provider, finalStatus); err != nil {
+				logger.Warnf("LLM analysis failed (non-blocking): %v", err)
+				r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "LLMAnalysisFailed",
+					fmt.Sprintf("AI/LLM analysis failed for repository %s/%s and pipeline run %s: %v", repo.Namespace, repo.Name, newPr.Name, err))
+			}
 		}
-	}
 
-	if err := r.updateRepoRunStatus(ctx, logger, newPr, repo, event); err != nil {
-		return repo, fmt.Errorf("cannot update run status: %w", err)
+		if statusErr != nil {
+			return repo, fmt.Errorf("cannot update run status: %w", statusErr)
+		}
 	}
 
 	if _, err := r.updatePipelineRunState(ctx, logger, pr, finalState); err != nil {
@@ -259,33 +296,53 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 	if err := r.emitMetrics(pr); err != nil {
 		logger.Error("failed to emit metrics: ", err)
 	}
+	if err := r.emitTaskMetrics(pr); err != nil {
+		logger.Error("failed to emit task metrics: ", err)
+	}
 
 	// remove pipelineRun from Queue and start the next one
+	r.startNextPipelineRun(ctx, logger, repo, pr)
+
+	if err := r.cleanupPipelineRuns(ctx, logger, pacInfo, repo, pr); err != nil {
+		return repo, fmt.Errorf("error cleaning pipelineruns: %w", err)
+	}
+
+	return repo, nil
+}
+
+// startNextPipelineRun removes pr from its queue and starts the next one, the
+// next one can be in the queue of another repository when a concurrency
+// group, a namespace or global limit is set. The next one is removed from
+// every queue holding it when it cannot be started so it does not keep a
+// running slot.
+func (r *Reconciler) startNextPipelineRun(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun) {
+	_ = r.setConcurrencyLimits(pr.GetNamespace())
 	for {
 		next := r.qm.RemoveAndTakeItemFromQueue(repo, pr)
 		if next == "" {
-			break
+			return
 		}
 		key := strings.Split(next, "/")
-		pr, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(key[0]).Get(ctx, key[1], metav1.GetOptions{})
+		nextPR, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(key[0]).Get(ctx, key[1], metav1.GetOptions{})
 		if err != nil {
-			logger.Errorf("cannot get pipeline for next in queue: %w", err)
+			logger.Errorf("cannot get pipeline for next in queue: %v", err)
+			_ = r.qm.RemovePipelineRun(next)
 			continue
 		}
 
-		if err := r.updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
-			logger.Errorf("failed to update status: %w", err)
-			_ = r.qm.RemoveFromQueue(queuepkg.RepoKey(repo), queuepkg.PrKey(pr))
+		nextRepo, err := r.getPipelineRunRepository(repo, nextPR)
+		if err != nil {
+			logger.Errorf("cannot get repository for next in queue: %v", err)
+			_ = r.qm.RemovePipelineRun(next)
 			continue
 		}
-		break
-	}
-
-	if err := r.cleanupPipelineRuns(ctx, logger, pacInfo, repo, pr); err != nil {
-		return repo, fmt.Errorf("error cleaning pipelineruns: %w", err)
+		if err := r.updatePipelineRunToInProgress(ctx, logger, nextRepo, nextPR); err != nil {
+			logger.Errorf("failed to update status: %v", err)
+			_ = r.qm.RemovePipelineRun(next)
+			continue
+		}
+		return
 	}
-
-	return repo, nil
 }
 
 func (r *Reconciler) updatePipelineRunToInProgress(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun) error {
@@ -415,7 +472,8 @@ func (r *Reconciler) performLLMAnalysis(
 	pr *tektonv1.PipelineRun,
 	event *info.Event,
 	provider provider.Interface,
+	finalStatus *provider.StatusOpts,
 ) error {
-	orchestrator := llm.NewOrchestrator(r.run, r.kinteract, logger)
-	return orchestrator.ExecuteAnalysis(ctx, repo, pr, event, provider)
+	orchestrator := llm.NewOrchestrator(r.run, r.kinteract, r.llmCache, logger)
+	return orchestrator.ExecuteAnalysis(ctx, repo, pr, event, provider, finalStatus)
 }
@@ -201,9 +201,11 @@ func TestReconciler_ReconcileKind(t *testing.T) {
 						Controller: &info.ControllerInfo{
 							Secret: secretName,
 						},
+						Pac: info.NewPacOpts(),
 					},
 				},
 				pipelineRunLister: stdata.PipelineLister,
+				taskRunLister:     stdata.TaskRunLister,
 				kinteract: &kubeinteraction.Interaction{
 					Run: &params.Run{
 						Clients: clients.Clients{
@@ -238,6 +240,75 @@ func TestReconciler_ReconcileKind(t *testing.T) {
 	}
 }
 
+func TestStartNextPipelineRunRepositoryNotFound(t *testing.T) {
+	observer, logcatch := zapobserver.New(zap.InfoLevel)
+	fakelogger := zap.New(observer).Sugar()
+	ctx, _ := rtesting.SetupFakeContext(t)
+
+	repoA := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo-a", Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{URL: randomURL},
+	}
+	// repo-b was deleted while its PipelineRun was queued, the next
+	// PipelineRun is taken from its queue because of the namespace limit
+	repoB := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo-b", Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{URL: randomURL},
+	}
+	newPR := func(name, repoName string) *tektonv1.PipelineRun {
+		return &tektonv1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ns",
+				Annotations: map[string]string{
+					keys.Repository: repoName,
+					keys.State:      kubeinteraction.StateQueued,
+				},
+			},
+			Spec: tektonv1.PipelineRunSpec{Status: tektonv1.PipelineRunSpecStatusPending},
+		}
+	}
+	finished := newPR("finished", repoA.GetName())
+	next := newPR("next", repoB.GetName())
+
+	stdata, informers := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{repoA},
+		PipelineRuns: []*tektonv1.PipelineRun{finished, next},
+	})
+
+	qm := queuepkg.NewManager(fakelogger)
+	qm.SetNamespaceLimit("ns", 1)
+	acquired, err := qm.AddListToRunningQueue(repoA, finished, []*tektonv1.PipelineRun{finished})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, acquired, []string{"ns/finished"})
+	assert.NilError(t, qm.AddToPendingQueue(repoB, next, []*tektonv1.PipelineRun{next}))
+
+	r := Reconciler{
+		repoLister: informers.Repository.Lister(),
+		qm:         qm,
+		run: &params.Run{
+			Clients: clients.Clients{
+				PipelineAsCode: stdata.PipelineAsCode,
+				Tekton:         stdata.Pipeline,
+				Kube:           stdata.Kube,
+			},
+			Info: info.Info{
+				Kube:       &info.KubeOpts{},
+				Controller: &info.ControllerInfo{},
+				Pac:        info.NewPacOpts(),
+			},
+		},
+	}
+	r.startNextPipelineRun(ctx, fakelogger, repoA, finished)
+	assert.Assert(t, logcatch.FilterMessageSnippet("cannot get repository for next in queue").Len() == 1, logcatch.All())
+
+	// the running slot of the namespace is free again
+	another := newPR("another", repoA.GetName())
+	acquired, err = qm.AddListToRunningQueue(repoA, another, []*tektonv1.PipelineRun{another})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, acquired, []string{"ns/another"})
+}
+
 func TestUpdatePipelineRunState(t *testing.T) {
 	observer, _ := zapobserver.New(zap.InfoLevel)
 	fakelogger := zap.New(observer).Sugar()
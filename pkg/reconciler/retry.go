@@ -0,0 +1,273 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	pacCel "github.com/openshift-pipelines/pipelines-as-code/pkg/cel"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kueue"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	queuepkg "github.com/openshift-pipelines/pipelines-as-code/pkg/queue"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+)
+
+// retryAttempts returns the names of the previous attempts of a retried
+// PipelineRun, from the first one to the last one.
+func retryAttempts(pr *tektonv1.PipelineRun) []string {
+	value := pr.GetAnnotations()[keys.RetryAttempts]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// maxRetries returns the number of times a failed PipelineRun is retried as
+// set in its max-retries annotation, 0 when it is not retried.
+func maxRetries(pr *tektonv1.PipelineRun) (int, error) {
+	value, ok := pr.GetAnnotations()[keys.MaxRetries]
+	if !ok {
+		return 0, nil
+	}
+	retries, err := strconv.Atoi(value)
+	if err != nil || retries < 0 {
+		return 0, fmt.Errorf("invalid value %q for annotation %s, it must be a positive integer", value, keys.MaxRetries)
+	}
+	return retries, nil
+}
+
+// shouldRetry returns true when the PipelineRun failed, has not been retried
+// max-retries times yet and its failure matches the retry-on expression when
+// there is one. Cancelled PipelineRuns are never retried.
+func (r *Reconciler) shouldRetry(ctx context.Context, pr *tektonv1.PipelineRun) (bool, error) {
+	retries, err := maxRetries(pr)
+	if err != nil || retries == 0 || len(retryAttempts(pr)) >= retries {
+		return false, err
+	}
+
+	cond := pr.Status.GetCondition(apis.ConditionSucceeded)
+	if cond == nil || cond.Status != corev1.ConditionFalse {
+		return false, nil
+	}
+	if pr.IsCancelled() || strings.HasPrefix(cond.Reason, tektonv1.PipelineRunReasonCancelled.String()) {
+		return false, nil
+	}
+
+	expr := pr.GetAnnotations()[keys.RetryOn]
+	if expr == "" {
+		return true, nil
+	}
+	lines := int64(r.run.Info.GetPacOpts().ErrorDetectionNumberOfLines)
+	taskInfos := kstatus.CollectFailedTasksLogSnippet(ctx, r.run, r.kinteract, pr, lines)
+	failedTasks := []pacCel.FailedTask{}
+	for _, name := range slices.Sorted(maps.Keys(taskInfos)) {
+		failedTasks = append(failedTasks, pacCel.FailedTask{
+			Name:       taskInfos[name].Name,
+			Reason:     taskInfos[name].Reason,
+			Message:    taskInfos[name].Message,
+			LogSnippet: taskInfos[name].LogSnippet,
+		})
+	}
+	return pacCel.RetryOn(expr, cond.Reason, failedTasks)
+}
+
+// retryName returns the name of the next attempt of a failed PipelineRun, it
+// is derived from the name of the first attempt so the same attempt is never
+// created twice.
+func retryName(pr *tektonv1.PipelineRun) string {
+	attempts := append(retryAttempts(pr), pr.GetName())
+	return kmeta.ChildName(attempts[0], fmt.Sprintf("-attempt-%d", len(attempts)+1))
+}
+
+// newRetryPipelineRun returns a copy of a failed PipelineRun to run it again,
+// the copy keeps the labels and annotations of the event and links the
+// previous attempts.
+func newRetryPipelineRun(pr *tektonv1.PipelineRun, name string, queued bool) *tektonv1.PipelineRun {
+	retry := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:         name,
+			GenerateName: pr.GetGenerateName(),
+			Namespace:    pr.GetNamespace(),
+			Labels:       maps.Clone(pr.GetLabels()),
+			Annotations:  maps.Clone(pr.GetAnnotations()),
+		},
+		Spec: *pr.Spec.DeepCopy(),
+	}
+	if retry.Labels == nil {
+		retry.Labels = map[string]string{}
+	}
+	if retry.Annotations == nil {
+		retry.Annotations = map[string]string{}
+	}
+	for key := range retry.Annotations {
+		// the records of tekton results belong to the previous attempt
+		if strings.HasPrefix(key, "results.tekton.dev/") && key != keys.ResultsRecordSummary {
+			delete(retry.Annotations, key)
+		}
+	}
+	delete(retry.Annotations, keys.LogURL)
+	delete(retry.Annotations, keys.ExecutionOrder)
+	delete(retry.Annotations, keys.RetriedAs)
+	retry.Annotations[keys.RetryAttempts] = strings.Join(append(retryAttempts(pr), pr.GetName()), ",")
+
+	state := kubeinteraction.StateStarted
+	retry.Spec.Status = ""
+	if queued {
+		state = kubeinteraction.StateQueued
+		retry.Spec.Status = tektonv1.PipelineRunSpecStatusPending
+		delete(retry.Annotations, keys.SCMReportingPLRStarted)
+	} else {
+		retry.Annotations[keys.SCMReportingPLRStarted] = "true"
+	}
+	retry.Labels[keys.State] = state
+	retry.Annotations[keys.State] = state
+	return retry
+}
+
+// retryPipelineRun creates a new attempt of a failed PipelineRun, the new
+// attempt is queued when a concurrency limit or kueue applies to it, it is
+// started right away otherwise. The name of the new attempt is recorded in
+// the retried-as annotation of the failed PipelineRun before it is created,
+// a reconciliation of the failed PipelineRun which is retried returns the
+// attempt already created.
+func (r *Reconciler) retryPipelineRun(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, event *info.Event, pr *tektonv1.PipelineRun) (*tektonv1.PipelineRun, error) {
+	hasLimits := r.setConcurrencyLimits(pr.GetNamespace())
+	_, limit := queuepkg.QueueKey(repo, pr)
+	queued := hasLimits || (limit != nil && *limit > 0) || kueue.QueueName(pr) != ""
+
+	name := pr.GetAnnotations()[keys.RetriedAs]
+	if name == "" {
+		name = retryName(pr)
+		mergePatch := map[string]any{
+			"metadata": map[string]any{
+				"annotations": map[string]string{keys.RetriedAs: name},
+			},
+		}
+		if _, err := action.PatchPipelineRun(ctx, logger, "retried-as", r.run.Clients.Tekton, pr, mergePatch); err != nil {
+			return nil, fmt.Errorf("cannot record the new attempt of pipelineRun %s: %w", pr.GetName(), err)
+		}
+	}
+
+	retry, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).Create(ctx,
+		newRetryPipelineRun(pr, name, queued), metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		logger.Infof("pipelineRun %s/%s has already been retried as %s", pr.GetNamespace(), pr.GetName(), name)
+		retry, err = r.run.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot create a new attempt of pipelineRun %s: %w", pr.GetName(), err)
+	}
+	if err := r.handOverGitAuthSecret(ctx, logger, event, pr, retry); err != nil {
+		r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "PipelineRunRetry",
+			fmt.Sprintf("cannot hand over the git auth secret of pipelineRun %s to %s: %v", pr.GetName(), retry.GetName(), err))
+	}
+	if queued && kueue.QueueName(pr) == "" {
+		// the queue manager starts the PipelineRuns in the execution order
+		patch := map[string]any{
+			"metadata": map[string]any{
+				"annotations": map[string]string{
+					keys.ExecutionOrder: queuepkg.PrKey(retry),
+				},
+			},
+		}
+		data, err := json.Marshal(patch)
+		if err != nil {
+			return nil, err
+		}
+		if retry, err = r.run.Clients.Tekton.TektonV1().PipelineRuns(retry.GetNamespace()).Patch(ctx, retry.GetName(),
+			types.MergePatchType, data, metav1.PatchOptions{}); err != nil {
+			return nil, fmt.Errorf("cannot queue the new attempt of pipelineRun %s: %w", pr.GetName(), err)
+		}
+	}
+	logger.Infof("pipelineRun %s/%s failed, it is retried as %s", pr.GetNamespace(), pr.GetName(), retry.GetName())
+	return retry, nil
+}
+
+// handOverGitAuthSecret gives the git auth secret of a failed PipelineRun to
+// its new attempt, the secret is owned by the new attempt so it is not garbage
+// collected with the failed PipelineRun, and its token is refreshed since the
+// token of the failed PipelineRun may have expired.
+func (r *Reconciler) handOverGitAuthSecret(ctx context.Context, logger *zap.SugaredLogger, event *info.Event, pr, retry *tektonv1.PipelineRun) error {
+	secretName := pr.GetAnnotations()[keys.GitAuthSecret]
+	if secretName == "" || !r.run.Info.GetPacOpts().SecretAutoCreation {
+		return nil
+	}
+	if event.Provider.Token != "" {
+		authSecret, err := secrets.MakeBasicAuthSecret(event, secretName)
+		if err != nil {
+			return err
+		}
+		secret, err := r.run.Clients.Kube.CoreV1().Secrets(pr.GetNamespace()).Get(ctx, secretName, metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+			if err := r.kinteract.CreateSecret(ctx, pr.GetNamespace(), authSecret); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			secret.Data = nil
+			secret.StringData = authSecret.StringData
+			if _, err := r.run.Clients.Kube.CoreV1().Secrets(pr.GetNamespace()).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+	}
+	return r.kinteract.UpdateSecretWithOwnerRef(ctx, logger, pr.GetNamespace(), secretName, retry)
+}
+
+// postRetryStatus reports a failed PipelineRun as still in progress on the
+// git provider with a link to its new attempt.
+func (r *Reconciler) postRetryStatus(ctx context.Context, logger *zap.SugaredLogger, vcx provider.Interface, event *info.Event, pr, retry *tektonv1.PipelineRun) error {
+	retries, _ := maxRetries(pr)
+	attempt := len(retryAttempts(retry)) + 1
+	text := fmt.Sprintf("The PipelineRun [%s](%s) failed, it is retried as [%s](%s) (attempt %d of %d).",
+		pr.GetName(), r.run.Clients.ConsoleUI().DetailURL(pr),
+		retry.GetName(), r.run.Clients.ConsoleUI().DetailURL(retry),
+		attempt, retries+1)
+	status := provider.StatusOpts{
+		Status:                  "in_progress",
+		Conclusion:              "pending",
+		Text:                    text,
+		DetailsURL:              r.run.Clients.ConsoleUI().DetailURL(retry),
+		PipelineRunName:         retry.GetName(),
+		PipelineRun:             retry,
+		OriginalPipelineRunName: retry.GetAnnotations()[keys.OriginalPRName],
+	}
+	return createStatusWithRetry(ctx, logger, vcx, event, status)
+}
+
+// retryAttemptsText links the previous attempts of a retried PipelineRun in
+// its final status.
+func (r *Reconciler) retryAttemptsText(pr *tektonv1.PipelineRun) string {
+	attempts := retryAttempts(pr)
+	if len(attempts) == 0 {
+		return ""
+	}
+	links := make([]string, 0, len(attempts))
+	for _, name := range attempts {
+		previous := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: pr.GetNamespace()}}
+		links = append(links, fmt.Sprintf("[%s](%s)", name, r.run.Clients.ConsoleUI().DetailURL(previous)))
+	}
+	retries, _ := maxRetries(pr)
+	return fmt.Sprintf("This is the attempt %d of %d, the previous attempts failed: %s.",
+		len(attempts)+1, retries+1, strings.Join(links, ", "))
+}
@@ -0,0 +1,308 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testconcurrency "github.com/openshift-pipelines/pipelines-as-code/pkg/test/concurrency"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func failedPipelineRun(name, reason string, annotations map[string]string) *tektonv1.PipelineRun {
+	return &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:         name,
+			GenerateName: "pr-build-",
+			Namespace:    "ns",
+			Labels: map[string]string{
+				keys.State:      kubeinteraction.StateCompleted,
+				keys.Repository: "repo",
+			},
+			Annotations: annotations,
+		},
+		Status: tektonv1.PipelineRunStatus{
+			Status: duckv1.Status{Conditions: []apis.Condition{
+				{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: reason},
+			}},
+		},
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		reason      string
+		annotations map[string]string
+		succeeded   bool
+		want        bool
+		wantError   string
+	}{
+		{
+			name:   "no max retries",
+			reason: tektonv1.PipelineRunReasonFailed.String(),
+		},
+		{
+			name:        "failed with retries left",
+			reason:      tektonv1.PipelineRunReasonFailed.String(),
+			annotations: map[string]string{keys.MaxRetries: "2", keys.RetryAttempts: "pr-build-aaaaa"},
+			want:        true,
+		},
+		{
+			name:        "retries exhausted",
+			reason:      tektonv1.PipelineRunReasonFailed.String(),
+			annotations: map[string]string{keys.MaxRetries: "2", keys.RetryAttempts: "pr-build-aaaaa,pr-build-bbbbb"},
+		},
+		{
+			name:        "succeeded",
+			succeeded:   true,
+			annotations: map[string]string{keys.MaxRetries: "2"},
+		},
+		{
+			name:        "cancelled",
+			reason:      tektonv1.PipelineRunReasonCancelled.String(),
+			annotations: map[string]string{keys.MaxRetries: "2"},
+		},
+		{
+			name:        "retry on matching",
+			reason:      tektonv1.PipelineRunReasonTimedOut.String(),
+			annotations: map[string]string{keys.MaxRetries: "1", keys.RetryOn: `reason == "PipelineRunTimeout"`},
+			want:        true,
+		},
+		{
+			name:        "retry on not matching",
+			reason:      tektonv1.PipelineRunReasonFailed.String(),
+			annotations: map[string]string{keys.MaxRetries: "1", keys.RetryOn: `reason == "PipelineRunTimeout"`},
+		},
+		{
+			name:        "invalid max retries",
+			reason:      tektonv1.PipelineRunReasonFailed.String(),
+			annotations: map[string]string{keys.MaxRetries: "many"},
+			wantError:   `invalid value "many" for annotation pipelinesascode.tekton.dev/max-retries, it must be a positive integer`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			pr := failedPipelineRun("pr-build-ccccc", tt.reason, tt.annotations)
+			if tt.succeeded {
+				pr.Status.Conditions[0].Status = corev1.ConditionTrue
+			}
+			r := &Reconciler{
+				run: &params.Run{Info: info.Info{Pac: info.NewPacOpts()}},
+			}
+			got, err := r.shouldRetry(ctx, pr)
+			if tt.wantError != "" {
+				assert.Error(t, err, tt.wantError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestRetryPipelineRun(t *testing.T) {
+	limit := 1
+	tests := []struct {
+		name       string
+		repo       *pacv1alpha1.Repository
+		wantState  string
+		wantStatus tektonv1.PipelineRunSpecStatus
+	}{
+		{
+			name:      "started right away",
+			repo:      &pacv1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}},
+			wantState: kubeinteraction.StateStarted,
+		},
+		{
+			name: "queued with a concurrency limit",
+			repo: &pacv1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec:       pacv1alpha1.RepositorySpec{ConcurrencyLimit: &limit},
+			},
+			wantState:  kubeinteraction.StateQueued,
+			wantStatus: tektonv1.PipelineRunSpecStatusPending,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			fakelogger := zap.New(observer).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			pr := failedPipelineRun("pr-build-aaaaa", tektonv1.PipelineRunReasonFailed.String(), map[string]string{
+				keys.MaxRetries:             "2",
+				keys.State:                  kubeinteraction.StateCompleted,
+				keys.CheckRunID:             "1234",
+				keys.LogURL:                 "https://console/pr-build-aaaaa",
+				keys.SCMReportingPLRStarted: "true",
+				keys.ExecutionOrder:         "ns/pr-build-aaaaa",
+				"results.tekton.dev/record": "ns/results/1/records/1",
+			})
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces:   []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}},
+				PipelineRuns: []*tektonv1.PipelineRun{pr},
+			})
+			r := &Reconciler{
+				qm: testconcurrency.TestQMI{},
+				run: &params.Run{
+					Info: info.Info{Pac: info.NewPacOpts()},
+					Clients: clients.Clients{
+						Tekton: stdata.Pipeline,
+						Kube:   stdata.Kube,
+					},
+				},
+			}
+
+			retry, err := r.retryPipelineRun(ctx, fakelogger, tt.repo, info.NewEvent(), pr)
+			assert.NilError(t, err)
+			assert.Equal(t, retry.GetName(), "pr-build-aaaaa-attempt-2")
+			assert.Equal(t, retry.Spec.Status, tt.wantStatus)
+			assert.Equal(t, retry.GetLabels()[keys.State], tt.wantState)
+			assert.Equal(t, retry.GetAnnotations()[keys.State], tt.wantState)
+			assert.Equal(t, retry.GetAnnotations()[keys.RetryAttempts], "pr-build-aaaaa")
+			assert.Equal(t, retry.GetAnnotations()[keys.CheckRunID], "1234")
+			assert.Equal(t, retry.GetAnnotations()[keys.LogURL], "")
+			assert.Equal(t, retry.GetAnnotations()["results.tekton.dev/record"], "")
+			if tt.wantState == kubeinteraction.StateQueued {
+				assert.Equal(t, retry.GetAnnotations()[keys.ExecutionOrder], "ns/pr-build-aaaaa-attempt-2")
+				assert.Equal(t, retry.GetAnnotations()[keys.SCMReportingPLRStarted], "")
+			} else {
+				assert.Equal(t, retry.GetAnnotations()[keys.ExecutionOrder], "")
+				assert.Equal(t, retry.GetAnnotations()[keys.SCMReportingPLRStarted], "true")
+			}
+		})
+	}
+}
+
+func TestRetryPipelineRunIdempotent(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	fakelogger := zap.New(observer).Sugar()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	pr := failedPipelineRun("pr-build-aaaaa", tektonv1.PipelineRunReasonFailed.String(), map[string]string{
+		keys.MaxRetries:    "2",
+		keys.GitAuthSecret: "pac-gitauth-aaaaa",
+	})
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "pac-gitauth-aaaaa",
+			Namespace:       "ns",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "PipelineRun", Name: "pr-build-aaaaa"}},
+		},
+		StringData: map[string]string{"git-provider-token": "expired"},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Namespaces:   []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}},
+		PipelineRuns: []*tektonv1.PipelineRun{pr},
+		Secret:       []*corev1.Secret{secret},
+	})
+	run := &params.Run{
+		Info: info.Info{Pac: info.NewPacOpts()},
+		Clients: clients.Clients{
+			Tekton: stdata.Pipeline,
+			Kube:   stdata.Kube,
+		},
+	}
+	run.Info.Pac.SecretAutoCreation = true
+	r := &Reconciler{
+		qm:        testconcurrency.TestQMI{},
+		run:       run,
+		kinteract: kubeinteraction.Interaction{Run: run},
+	}
+	repo := &pacv1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}}
+	event := info.NewEvent()
+	event.URL = "https://forge.example.com/org/repo"
+	event.Provider.Token = "fresh"
+
+	retry, err := r.retryPipelineRun(ctx, fakelogger, repo, event, pr)
+	assert.NilError(t, err)
+	assert.Equal(t, retry.GetName(), "pr-build-aaaaa-attempt-2")
+
+	failed, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").Get(ctx, "pr-build-aaaaa", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, failed.GetAnnotations()[keys.RetriedAs], "pr-build-aaaaa-attempt-2")
+	assert.Equal(t, retry.GetAnnotations()[keys.RetriedAs], "")
+
+	// the secret is owned by the new attempt with a fresh token
+	gotSecret, err := stdata.Kube.CoreV1().Secrets("ns").Get(ctx, "pac-gitauth-aaaaa", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(gotSecret.OwnerReferences), 1)
+	assert.Equal(t, gotSecret.OwnerReferences[0].Name, "pr-build-aaaaa-attempt-2")
+	assert.Equal(t, gotSecret.StringData["git-provider-token"], "fresh")
+
+	// reconciling the failed PipelineRun again returns the same attempt
+	again, err := r.retryPipelineRun(ctx, fakelogger, repo, event, failed)
+	assert.NilError(t, err)
+	assert.Equal(t, again.GetName(), "pr-build-aaaaa-attempt-2")
+	prs, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(prs.Items), 2)
+}
+
+func TestRetryName(t *testing.T) {
+	pr := failedPipelineRun("pr-build-aaaaa", tektonv1.PipelineRunReasonFailed.String(), nil)
+	assert.Equal(t, retryName(pr), "pr-build-aaaaa-attempt-2")
+	pr = failedPipelineRun("pr-build-aaaaa-attempt-2", tektonv1.PipelineRunReasonFailed.String(), map[string]string{
+		keys.RetryAttempts: "pr-build-aaaaa",
+	})
+	assert.Equal(t, retryName(pr), "pr-build-aaaaa-attempt-3")
+}
+
+func TestRetryAttemptsText(t *testing.T) {
+	run := params.New()
+	run.Clients.SetConsoleUI(&consoleui.TektonDashboard{BaseURL: "https://dashboard"})
+	r := &Reconciler{run: run}
+
+	pr := failedPipelineRun("pr-build-ccccc", tektonv1.PipelineRunReasonFailed.String(), map[string]string{
+		keys.MaxRetries:    "3",
+		keys.RetryAttempts: "pr-build-aaaaa,pr-build-bbbbb",
+	})
+	assert.Equal(t, r.retryAttemptsText(pr),
+		"This is the attempt 3 of 4, the previous attempts failed: "+
+			"[pr-build-aaaaa](https://dashboard/#/namespaces/ns/pipelineruns/pr-build-aaaaa), "+
+			"[pr-build-bbbbb](https://dashboard/#/namespaces/ns/pipelineruns/pr-build-bbbbb).")
+
+	pr.Annotations = map[string]string{keys.MaxRetries: "3"}
+	assert.Equal(t, r.retryAttemptsText(pr), "")
+}
+
+func TestUpdateRepoRunStatusRetried(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	fakelogger := zap.New(observer).Sugar()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	repo := &pacv1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}}
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*pacv1alpha1.Repository{repo},
+	})
+	run := params.New()
+	run.Clients.PipelineAsCode = stdata.PipelineAsCode
+	run.Clients.SetConsoleUI(consoleui.FallBackConsole{})
+	r := &Reconciler{run: run}
+
+	pr := failedPipelineRun("pr-build-ccccc", tektonv1.PipelineRunReasonSuccessful.String(), map[string]string{
+		keys.MaxRetries:    "3",
+		keys.RetryAttempts: "pr-build-aaaaa,pr-build-bbbbb",
+	})
+	pr.Status.Conditions[0].Status = corev1.ConditionTrue
+	assert.NilError(t, r.updateRepoRunStatus(ctx, fakelogger, pr, repo, info.NewEvent()))
+
+	got, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(got.Status), 1)
+	assert.Equal(t, got.Status[0].Attempts, 3)
+	assert.Assert(t, got.Status[0].Flaky)
+}
@@ -47,6 +47,13 @@ func (r *Reconciler) updateRepoRunStatus(ctx context.Context, logger *zap.Sugare
 		EventType:       &event.EventType,
 		TargetBranch:    &refsanitized,
 	}
+	if group := pr.GetAnnotations()[apipac.ConcurrencyGroup]; group != "" {
+		repoStatus.ConcurrencyGroup = &group
+	}
+	if attempts := len(retryAttempts(pr)) + 1; attempts > 1 {
+		repoStatus.Attempts = attempts
+		repoStatus.Flaky = pr.Status.GetCondition(apis.ConditionSucceeded).IsTrue()
+	}
 
 	// Get repository again in case it was updated while we were running the CI
 	// we try multiple time until we get right in case of conflicts.
@@ -101,12 +108,14 @@ func (r *Reconciler) getFailureSnippet(ctx context.Context, pr *tektonv1.Pipelin
 	return fmt.Sprintf("task <b>%s</b> has the status <b>\"%s\"</b>:\n<pre>%s</pre>", name, sortedTaskInfos[0].Reason, text)
 }
 
-func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLogger, pacInfo *info.PacOpts, vcx provider.Interface, event *info.Event, createdPR *tektonv1.PipelineRun) (*tektonv1.PipelineRun, error) {
+// postFinalStatus reports the final status of the PipelineRun on the git
+// provider, it returns the status when it was reported.
+func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLogger, pacInfo *info.PacOpts, vcx provider.Interface, event *info.Event, createdPR *tektonv1.PipelineRun) (*tektonv1.PipelineRun, *provider.StatusOpts, error) {
 	pr, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(createdPR.GetNamespace()).Get(
 		ctx, createdPR.GetName(), metav1.GetOptions{},
 	)
 	if err != nil {
-		return pr, err
+		return pr, nil, err
 	}
 
 	trStatus := kstatus.GetStatusFromTaskStatusOrFromAsking(ctx, pr, r.run)
@@ -115,11 +124,22 @@ func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLog
 		var err error
 		taskStatusText, err = sort.TaskStatusTmpl(pr, trStatus, r.run, vcx.GetConfig())
 		if err != nil {
-			return pr, err
+			return pr, nil, err
 		}
 	} else {
 		taskStatusText = pr.Status.GetCondition(apis.ConditionSucceeded).Message
 	}
+	if sha := pr.GetAnnotations()[apipac.SupersededBy]; sha != "" {
+		taskStatusText = fmt.Sprintf("The PipelineRun has been cancelled before it started, it is superseded by the PipelineRun of the commit %s.", sha)
+	}
+	if text := r.retryAttemptsText(pr); text != "" {
+		taskStatusText = fmt.Sprintf("%s\n\n%s", taskStatusText, text)
+	}
+	conclusion := formatting.PipelineRunStatus(pr)
+	if wait := pr.GetAnnotations()[apipac.QueueTimeout]; wait != "" {
+		taskStatusText = fmt.Sprintf("The PipelineRun has timed out in queue, it has been cancelled after waiting for more than %s to start.", wait)
+		conclusion = "failure"
+	}
 
 	namespaceURL := r.run.Clients.ConsoleUI().NamespaceURL(pr)
 	consoleURL := r.run.Clients.ConsoleUI().DetailURL(pr)
@@ -143,22 +163,24 @@ func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLog
 	}
 	var tmplStatusText string
 	if tmplStatusText, err = mt.MakeTemplate(vcx.GetTemplate(provider.PipelineRunStatusType)); err != nil {
-		return nil, fmt.Errorf("cannot create message template: %w", err)
+		return nil, nil, fmt.Errorf("cannot create message template: %w", err)
 	}
 
 	status := provider.StatusOpts{
 		Status:                  pipelineascode.CompletedStatus,
 		PipelineRun:             pr,
-		Conclusion:              formatting.PipelineRunStatus(pr),
+		Conclusion:              conclusion,
 		Text:                    tmplStatusText,
 		PipelineRunName:         pr.Name,
 		DetailsURL:              r.run.Clients.ConsoleUI().DetailURL(pr),
 		OriginalPipelineRunName: pr.GetAnnotations()[apipac.OriginalPRName],
 	}
 
-	err = createStatusWithRetry(ctx, logger, vcx, event, status)
+	if err := createStatusWithRetry(ctx, logger, vcx, event, status); err != nil {
+		return pr, nil, err
+	}
 	logger.Infof("pipelinerun %s has a status of '%s'", pr.Name, status.Conclusion)
-	return pr, err
+	return pr, &status, nil
 }
 
 func createStatusWithRetry(ctx context.Context, logger *zap.SugaredLogger, vcx provider.Interface, event *info.Event, status provider.StatusOpts) error {
@@ -2,10 +2,12 @@ package reconciler
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
@@ -77,6 +79,35 @@ func TestPostFinalStatus(t *testing.T) {
 		},
 	}
 
-	_, err := r.postFinalStatus(ctx, fakelogger, pacInfo, vcx, info.NewEvent(), pr1)
+	_, _, err := r.postFinalStatus(ctx, fakelogger, pacInfo, vcx, info.NewEvent(), pr1)
 	assert.NilError(t, err)
 }
+
+func TestPostFinalStatusQueueTimeout(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	fakelogger := zap.New(observer).Sugar()
+	vcx := &tprovider.TestProviderImp{}
+
+	ns := "namespace"
+	clock := clockwork.NewFakeClock()
+	pr := tektontest.MakePRCompletion(clock, "pipeline-queued", ns, tektonv1.PipelineRunReasonCancelled.String(),
+		map[string]string{keys.QueueTimeout: "1h0m0s"}, map[string]string{}, 10)
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: []*tektonv1.PipelineRun{pr}})
+
+	run := params.New()
+	run.Clients = clients.Clients{
+		Kube:   stdata.Kube,
+		Tekton: stdata.Pipeline,
+	}
+	run.Clients.SetConsoleUI(consoleui.FallBackConsole{})
+	r := &Reconciler{
+		run: run,
+	}
+
+	_, _, err := r.postFinalStatus(ctx, fakelogger, &info.PacOpts{}, vcx, info.NewEvent(), pr)
+	assert.NilError(t, err)
+	assert.Assert(t, vcx.CreatedStatus != nil)
+	assert.Equal(t, vcx.CreatedStatus.Conclusion, "failure")
+	assert.Assert(t, strings.Contains(vcx.CreatedStatus.Text, "The PipelineRun has timed out in queue, it has been cancelled after waiting for more than 1h0m0s to start."), vcx.CreatedStatus.Text)
+}
@@ -0,0 +1,101 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/robfig/cron/v3"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxMissedSchedules is how many missed schedules we look at to find the
+// last one, a schedule missed for longer is run as soon as possible.
+const maxMissedSchedules = 1000
+
+// Parse parses a standard cron schedule, with a time zone as understood by
+// time.LoadLocation, UTC when empty.
+func Parse(spec, timeZone string) (cron.Schedule, error) {
+	if timeZone != "" {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %s: %w", timeZone, err)
+		}
+		spec = fmt.Sprintf("CRON_TZ=%s %s", timeZone, spec)
+	}
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+	}
+	return sched, nil
+}
+
+// FromPipelineRuns returns the schedules of the PipelineRuns with an
+// on-schedule annotation. The last schedule time is kept from the previous
+// schedules when the schedule has not changed, or starts now otherwise so we
+// don't run the PipelineRun for the times before it was scheduled.
+func FromPipelineRuns(prs []*tektonv1.PipelineRun, branch string, previous []v1alpha1.ScheduleStatus, now time.Time) ([]v1alpha1.ScheduleStatus, []error) {
+	schedules := []v1alpha1.ScheduleStatus{}
+	errs := []error{}
+	for _, pr := range prs {
+		spec, ok := pr.GetAnnotations()[keys.OnSchedule]
+		if !ok {
+			continue
+		}
+		name := pr.GetAnnotations()[keys.OriginalPRName]
+		if name == "" {
+			name = pr.GetName()
+		}
+		name = strings.TrimSuffix(name, "-")
+		timeZone := pr.GetAnnotations()[keys.OnScheduleTimeZone]
+		if _, err := Parse(spec, timeZone); err != nil {
+			errs = append(errs, fmt.Errorf("pipelinerun %s: %w", name, err))
+			continue
+		}
+		status := v1alpha1.ScheduleStatus{
+			PipelineRunName:  name,
+			Schedule:         spec,
+			TimeZone:         timeZone,
+			Branch:           branch,
+			LastScheduleTime: &metav1.Time{Time: now},
+		}
+		for _, prev := range previous {
+			if prev.PipelineRunName == name && prev.Schedule == spec && prev.TimeZone == timeZone && prev.Branch == branch && prev.LastScheduleTime != nil {
+				status.LastScheduleTime = prev.LastScheduleTime.DeepCopy()
+			}
+		}
+		schedules = append(schedules, status)
+	}
+	return schedules, errs
+}
+
+// Equal returns true if the schedules are the same.
+func Equal(a, b []v1alpha1.ScheduleStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].PipelineRunName != b[i].PipelineRunName || a[i].Schedule != b[i].Schedule ||
+			a[i].TimeZone != b[i].TimeZone || a[i].Branch != b[i].Branch ||
+			!a[i].LastScheduleTime.Equal(b[i].LastScheduleTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// LastDue returns the last time the schedule was due after last and until
+// now, or a zero time when it wasn't due. Several missed schedules are
+// collapsed into the last one.
+func LastDue(sched cron.Schedule, last, now time.Time) time.Time {
+	due := time.Time{}
+	for next, i := sched.Next(last), 0; !next.IsZero() && !next.After(now); next, i = sched.Next(next), i+1 {
+		if i == maxMissedSchedules {
+			return now
+		}
+		due = next
+	}
+	return due
+}
@@ -0,0 +1,134 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParse(t *testing.T) {
+	last := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		spec     string
+		timeZone string
+		wantNext time.Time
+		wantErr  string
+	}{
+		{
+			name:     "every night",
+			spec:     "0 2 * * *",
+			wantNext: time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "descriptor",
+			spec:     "@weekly",
+			wantNext: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "with a time zone",
+			spec:     "0 2 * * *",
+			timeZone: "Asia/Tokyo",
+			// 02:00 in Tokyo is 17:00 UTC the day before
+			wantNext: time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "invalid time zone",
+			spec:     "0 2 * * *",
+			timeZone: "Mars/Olympus",
+			wantErr:  "invalid time zone Mars/Olympus",
+		},
+		{
+			name:    "invalid schedule",
+			spec:    "every night",
+			wantErr: `invalid schedule "every night"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, err := Parse(tt.spec, tt.timeZone)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, sched.Next(last).Equal(tt.wantNext), "next is %s, wanted %s", sched.Next(last), tt.wantNext)
+		})
+	}
+}
+
+func TestFromPipelineRuns(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	before := &metav1.Time{Time: now.Add(-time.Hour)}
+	makePR := func(name string, annotations map[string]string) *tektonv1.PipelineRun {
+		return &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+	}
+	prs := []*tektonv1.PipelineRun{
+		makePR("push", map[string]string{keys.OnEvent: "[push]"}),
+		makePR("nightly", map[string]string{keys.OnSchedule: "0 2 * * *"}),
+		makePR("", map[string]string{
+			keys.OriginalPRName:     "weekly-",
+			keys.OnSchedule:         "@weekly",
+			keys.OnScheduleTimeZone: "Europe/Paris",
+		}),
+		makePR("changed", map[string]string{keys.OnSchedule: "0 3 * * *"}),
+		makePR("invalid", map[string]string{keys.OnSchedule: "0 2 * *"}),
+	}
+	previous := []v1alpha1.ScheduleStatus{
+		{PipelineRunName: "nightly", Schedule: "0 2 * * *", Branch: "main", LastScheduleTime: before},
+		{PipelineRunName: "changed", Schedule: "0 2 * * *", Branch: "main", LastScheduleTime: before},
+		{PipelineRunName: "removed", Schedule: "0 2 * * *", Branch: "main", LastScheduleTime: before},
+	}
+
+	got, errs := FromPipelineRuns(prs, "main", previous, now)
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorContains(t, errs[0], "pipelinerun invalid: invalid schedule")
+	assert.DeepEqual(t, got, []v1alpha1.ScheduleStatus{
+		{PipelineRunName: "nightly", Schedule: "0 2 * * *", Branch: "main", LastScheduleTime: before},
+		{PipelineRunName: "weekly", Schedule: "@weekly", TimeZone: "Europe/Paris", Branch: "main", LastScheduleTime: &metav1.Time{Time: now}},
+		{PipelineRunName: "changed", Schedule: "0 3 * * *", Branch: "main", LastScheduleTime: &metav1.Time{Time: now}},
+	})
+	assert.Assert(t, Equal(got, got))
+	assert.Assert(t, !Equal(got, previous))
+}
+
+func TestLastDue(t *testing.T) {
+	sched, err := Parse("0 2 * * *", "")
+	assert.NilError(t, err)
+	last := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "not due yet",
+			now:  time.Date(2024, 1, 2, 1, 59, 0, 0, time.UTC),
+		},
+		{
+			name: "due",
+			now:  time.Date(2024, 1, 2, 2, 0, 30, 0, time.UTC),
+			want: time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "missed schedules are collapsed",
+			now:  time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 5, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "missed for too long",
+			now:  time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Assert(t, LastDue(sched, last, tt.now).Equal(tt.want), "got %s, wanted %s", LastDue(sched, last, tt.now), tt.want)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	listers "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/azuredevops"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gerrit"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github/app"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/plaingit"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/schedule"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+const tickInterval = 30 * time.Second
+
+// errAlreadyScheduled is returned when the PipelineRun has already been run
+// for this time, or is not scheduled anymore.
+var errAlreadyScheduled = errors.New("already scheduled")
+
+// Scheduler runs the PipelineRuns of the default branch with an on-schedule
+// annotation, as recorded in the Repository status by the controller.
+type Scheduler struct {
+	run          *params.Run
+	kint         kubeinteraction.Interface
+	repoLister   listers.RepositoryLister
+	logger       *zap.SugaredLogger
+	eventEmitter *events.EventEmitter
+	// isLeader tells if this replica of the watcher runs the schedules of the
+	// repository, only the leader of its bucket does.
+	isLeader func(ktypes.NamespacedName) bool
+	// trigger runs the PipelineRuns of the event, replaced by the tests.
+	trigger func(ctx context.Context, repo *v1alpha1.Repository, event *info.Event) error
+}
+
+func New(run *params.Run, kint kubeinteraction.Interface, repoLister listers.RepositoryLister, isLeader func(ktypes.NamespacedName) bool, logger *zap.SugaredLogger) *Scheduler {
+	s := &Scheduler{
+		run:          run,
+		kint:         kint,
+		repoLister:   repoLister,
+		logger:       logger,
+		eventEmitter: events.NewEventEmitter(run.Clients.Kube, logger),
+		isLeader:     isLeader,
+	}
+	s.trigger = s.runPac
+	return s
+}
+
+// Start runs the scheduled PipelineRuns until the context is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	for {
+		s.schedule(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// schedule runs the PipelineRuns which are due.
+func (s *Scheduler) schedule(ctx context.Context, now time.Time) {
+	repos, err := s.repoLister.List(labels.Everything())
+	if err != nil {
+		s.logger.Errorf("cannot list repositories to schedule: %v", err)
+		return
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].GetNamespace()+"/"+repos[i].GetName() < repos[j].GetNamespace()+"/"+repos[j].GetName()
+	})
+
+	for _, repo := range repos {
+		if len(repo.ScheduleStatus) == 0 || !s.isLeader(ktypes.NamespacedName{Namespace: repo.GetNamespace(), Name: repo.GetName()}) {
+			continue
+		}
+		for _, sched := range repo.ScheduleStatus {
+			if sched.LastScheduleTime == nil {
+				// a schedule which has never run starts now
+				if err := s.recordScheduleTime(ctx, repo, sched, now); err != nil && !errors.Is(err, errAlreadyScheduled) {
+					s.logger.Errorf("cannot record the schedule time of pipelinerun %s: %v", sched.PipelineRunName, err)
+				}
+				continue
+			}
+			due, err := dueTime(sched, now)
+			if err != nil {
+				s.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryInvalidSchedule", fmt.Sprintf("pipelinerun %s: %v", sched.PipelineRunName, err))
+				continue
+			}
+			if due.IsZero() {
+				continue
+			}
+			s.runSchedule(ctx, repo, sched, due)
+		}
+	}
+}
+
+// dueTime returns the time the PipelineRun is due, or a zero time if it
+// isn't.
+func dueTime(sched v1alpha1.ScheduleStatus, now time.Time) (time.Time, error) {
+	cronSched, err := schedule.Parse(sched.Schedule, sched.TimeZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.LastDue(cronSched, sched.LastScheduleTime.Time, now), nil
+}
+
+// runSchedule records the schedule time before running the PipelineRun, a
+// restart of the watcher or another replica will not run it again.
+func (s *Scheduler) runSchedule(ctx context.Context, repo *v1alpha1.Repository, sched v1alpha1.ScheduleStatus, due time.Time) {
+	logger := s.logger.With("namespace", repo.GetNamespace(), "repository", repo.GetName(), "pipelinerun", sched.PipelineRunName)
+	if err := s.recordScheduleTime(ctx, repo, sched, due); err != nil {
+		if !errors.Is(err, errAlreadyScheduled) {
+			s.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryScheduleFailed",
+				fmt.Sprintf("cannot record the schedule time of pipelinerun %s: %v", sched.PipelineRunName, err))
+		}
+		return
+	}
+
+	logger.Infof("running pipelinerun %s scheduled at %s on branch %s", sched.PipelineRunName, due.Format(time.RFC3339), sched.Branch)
+	event, err := makeEvent(repo, sched)
+	if err == nil {
+		err = s.trigger(ctx, repo, event)
+	}
+	if err != nil {
+		s.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryScheduleFailed",
+			fmt.Sprintf("cannot run the scheduled pipelinerun %s: %v", sched.PipelineRunName, err))
+	}
+}
+
+// recordScheduleTime sets the last schedule time of the PipelineRun in the
+// Repository status.
+func (s *Scheduler) recordScheduleTime(ctx context.Context, repo *v1alpha1.Repository, sched v1alpha1.ScheduleStatus, due time.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lastrepo, err := s.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Get(ctx, repo.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		idx := -1
+		for j, current := range lastrepo.ScheduleStatus {
+			if current.PipelineRunName == sched.PipelineRunName && current.Schedule == sched.Schedule && current.TimeZone == sched.TimeZone {
+				idx = j
+				break
+			}
+		}
+		if idx == -1 {
+			return errAlreadyScheduled
+		}
+		if last := lastrepo.ScheduleStatus[idx].LastScheduleTime; last != nil && !last.Time.Before(due) {
+			return errAlreadyScheduled
+		}
+		lastrepo.ScheduleStatus[idx].LastScheduleTime = &metav1.Time{Time: due}
+		_, err = s.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(lastrepo.GetNamespace()).Update(ctx, lastrepo, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// makeEvent synthesizes the event of a scheduled PipelineRun, it is matched
+// like an incoming webhook on the PipelineRun name.
+func makeEvent(repo *v1alpha1.Repository, sched v1alpha1.ScheduleStatus) (*info.Event, error) {
+	event := info.NewEvent()
+	org, repoName, err := formatting.GetRepoOwnerSplitted(repo.Spec.URL)
+	if err != nil {
+		return nil, err
+	}
+	event.Organization = org
+	event.Repository = repoName
+	event.Request = &info.Request{Header: http.Header{}}
+	event.URL = repo.Spec.URL
+	event.EventType = triggertype.Schedule.String()
+	event.TriggerTarget = triggertype.Push
+	event.TargetPipelineRun = sched.PipelineRunName
+	event.HeadBranch = sched.Branch
+	event.BaseBranch = sched.Branch
+	event.DefaultBranch = sched.Branch
+	event.Sender = triggertype.Schedule.String()
+	return event, nil
+}
+
+// providerFor returns the provider of the repository.
+func (s *Scheduler) providerFor(ctx context.Context, repo *v1alpha1.Repository, event *info.Event) (provider.Interface, error) {
+	if repo.Spec.GitProvider != nil && repo.Spec.GitProvider.Type != "" {
+		switch repo.Spec.GitProvider.Type {
+		case "github":
+			gh := github.New()
+			gh.Run = s.run
+			return gh, nil
+		case "gitlab":
+			return &gitlab.Provider{}, nil
+		case "gitea":
+			return &gitea.Provider{}, nil
+		case "bitbucket-cloud":
+			return &bitbucketcloud.Provider{}, nil
+		case "bitbucket-datacenter":
+			return &bitbucketdatacenter.Provider{}, nil
+		case "azure-devops":
+			return &azuredevops.Provider{}, nil
+		case "gerrit":
+			return &gerrit.Provider{}, nil
+		default:
+			return nil, fmt.Errorf("no supported Git provider has been detected")
+		}
+	}
+	if repo.Spec.Poll != nil {
+		return &plaingit.Provider{}, nil
+	}
+
+	// a GitHub App installation
+	gh := github.New()
+	gh.Run = s.run
+	ip := app.NewInstallation(nil, s.run, repo, gh, s.run.Info.Kube.Namespace)
+	enterpriseURL, token, installationID, err := ip.GetAndUpdateInstallationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if installationID == 0 {
+		return nil, fmt.Errorf("GithubApp is not installed for the repository url %s", repo.Spec.URL)
+	}
+	event.Provider.URL = enterpriseURL
+	event.Provider.Token = token
+	event.InstallationID = installationID
+	return gh, nil
+}
+
+// runPac runs the scheduled PipelineRun like the controller does for the
+// incoming webhooks.
+func (s *Scheduler) runPac(ctx context.Context, repo *v1alpha1.Repository, event *info.Event) error {
+	logger := s.logger.With("namespace", repo.GetNamespace(), "event-type", event.EventType,
+		"source-repo-url", event.URL, "target-branch", event.BaseBranch)
+	pacInfo := s.run.Info.GetPacOpts()
+
+	globalRepo, err := s.repoLister.Repositories(s.run.Info.Kube.Namespace).Get(s.run.Info.Controller.GlobalRepository)
+	if err != nil || globalRepo == nil {
+		globalRepo = &v1alpha1.Repository{}
+	}
+
+	vcx, err := s.providerFor(ctx, repo, event)
+	if err != nil {
+		return err
+	}
+	if gitProvider, ok := vcx.(*plaingit.Provider); ok {
+		defer gitProvider.Cleanup()
+	}
+	vcx.SetLogger(logger)
+	vcx.SetPacInfo(&pacInfo)
+
+	pac := pipelineascode.NewPacs(event, vcx, s.run, &pacInfo, s.kint, logger, globalRepo.DeepCopy())
+	return pac.Run(ctx)
+}
@@ -0,0 +1,214 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+type fixture struct {
+	scheduler *Scheduler
+	stdata    testclient.Clients
+	informers testclient.Informers
+	triggered []*info.Event
+	logs      *zapobserver.ObservedLogs
+}
+
+func newFixture(t *testing.T, ctx context.Context, repos ...*v1alpha1.Repository) *fixture {
+	t.Helper()
+	observer, logs := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	stdata, informers := testclient.SeedTestData(t, ctx, testclient.Data{Repositories: repos})
+	f := &fixture{stdata: stdata, informers: informers, logs: logs}
+	f.scheduler = &Scheduler{
+		run: &params.Run{
+			Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Kube: stdata.Kube, Log: logger},
+		},
+		repoLister:   stdata.RepositoryLister,
+		logger:       logger,
+		eventEmitter: events.NewEventEmitter(nil, logger),
+		isLeader:     func(ktypes.NamespacedName) bool { return true },
+	}
+	f.scheduler.trigger = func(_ context.Context, _ *v1alpha1.Repository, event *info.Event) error {
+		f.triggered = append(f.triggered, event)
+		return nil
+	}
+	return f
+}
+
+// sync copies the repositories as updated by the scheduler to the lister, like
+// the informer would do.
+func (f *fixture) sync(t *testing.T, ctx context.Context) {
+	t.Helper()
+	repos, err := f.stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("").List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	for i := range repos.Items {
+		assert.NilError(t, f.informers.Repository.Informer().GetIndexer().Update(&repos.Items[i]))
+	}
+}
+
+func (f *fixture) schedules(t *testing.T, ctx context.Context) []v1alpha1.ScheduleStatus {
+	t.Helper()
+	repo, err := f.stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	return repo.ScheduleStatus
+}
+
+func makeRepository(schedules ...v1alpha1.ScheduleStatus) *v1alpha1.Repository {
+	return &v1alpha1.Repository{
+		ObjectMeta:     metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec:           v1alpha1.RepositorySpec{URL: "https://forge.example.com/org/repo"},
+		ScheduleStatus: schedules,
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	last := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	f := newFixture(t, ctx, makeRepository(
+		v1alpha1.ScheduleStatus{PipelineRunName: "nightly", Schedule: "0 2 * * *", Branch: "main", LastScheduleTime: &metav1.Time{Time: last}},
+		v1alpha1.ScheduleStatus{PipelineRunName: "weekly", Schedule: "@weekly", Branch: "main", LastScheduleTime: &metav1.Time{Time: last}},
+	))
+
+	// not due yet
+	f.scheduler.schedule(ctx, last.Add(time.Hour))
+	assert.Equal(t, len(f.triggered), 0)
+
+	now := last.Add(24*time.Hour + 30*time.Second)
+	f.scheduler.schedule(ctx, now)
+	assert.Equal(t, len(f.triggered), 1)
+	event := f.triggered[0]
+	assert.Equal(t, event.EventType, triggertype.Schedule.String())
+	assert.Equal(t, event.TriggerTarget, triggertype.Push)
+	assert.Equal(t, event.TargetPipelineRun, "nightly")
+	assert.Equal(t, event.HeadBranch, "main")
+	assert.Equal(t, event.BaseBranch, "main")
+	assert.Equal(t, event.URL, "https://forge.example.com/org/repo")
+	assert.Equal(t, event.Organization, "org")
+	assert.Equal(t, event.Repository, "repo")
+	assert.Assert(t, event.Request.Header != nil)
+
+	schedules := f.schedules(t, ctx)
+	assert.Assert(t, schedules[0].LastScheduleTime.Time.Equal(last.Add(24*time.Hour)))
+	assert.Assert(t, schedules[1].LastScheduleTime.Time.Equal(last))
+
+	// the lister has not seen the update yet, it's not run twice
+	f.scheduler.schedule(ctx, now.Add(30*time.Second))
+	assert.Equal(t, len(f.triggered), 1)
+
+	// nor after a restart
+	f.sync(t, ctx)
+	restarted := newFixture(t, ctx)
+	restarted.scheduler.repoLister = f.scheduler.repoLister
+	restarted.scheduler.schedule(ctx, now.Add(time.Minute))
+	assert.Equal(t, len(restarted.triggered), 0)
+}
+
+func TestScheduleNotLeader(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	last := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	f := newFixture(t, ctx, makeRepository(
+		v1alpha1.ScheduleStatus{PipelineRunName: "nightly", Schedule: "0 2 * * *", Branch: "main", LastScheduleTime: &metav1.Time{Time: last}},
+	))
+	f.scheduler.isLeader = func(ktypes.NamespacedName) bool { return false }
+	f.scheduler.schedule(ctx, last.Add(24*time.Hour))
+	assert.Equal(t, len(f.triggered), 0)
+	assert.Assert(t, f.schedules(t, ctx)[0].LastScheduleTime.Time.Equal(last))
+}
+
+func TestScheduleStartsNow(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	f := newFixture(t, ctx, makeRepository(
+		v1alpha1.ScheduleStatus{PipelineRunName: "nightly", Schedule: "0 2 * * *", Branch: "main"},
+	))
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	f.scheduler.schedule(ctx, now)
+	assert.Equal(t, len(f.triggered), 0)
+	assert.Assert(t, f.schedules(t, ctx)[0].LastScheduleTime.Time.Equal(now))
+}
+
+func TestScheduleInvalid(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	last := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	f := newFixture(t, ctx, makeRepository(
+		v1alpha1.ScheduleStatus{PipelineRunName: "nightly", Schedule: "0 2 * *", Branch: "main", LastScheduleTime: &metav1.Time{Time: last}},
+	))
+	f.scheduler.schedule(ctx, last.Add(48*time.Hour))
+	assert.Equal(t, len(f.triggered), 0)
+	assert.Equal(t, f.logs.FilterMessageSnippet("pipelinerun nightly: invalid schedule").Len(), 1)
+}
+
+func TestScheduleTriggerFailure(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	last := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	f := newFixture(t, ctx, makeRepository(
+		v1alpha1.ScheduleStatus{PipelineRunName: "nightly", Schedule: "0 2 * * *", Branch: "main", LastScheduleTime: &metav1.Time{Time: last}},
+	))
+	f.scheduler.trigger = func(context.Context, *v1alpha1.Repository, *info.Event) error {
+		return fmt.Errorf("no luck")
+	}
+	f.scheduler.schedule(ctx, last.Add(24*time.Hour))
+	assert.Equal(t, f.logs.FilterMessageSnippet("cannot run the scheduled pipelinerun nightly: no luck").Len(), 1)
+	// failed runs are not retried
+	assert.Assert(t, f.schedules(t, ctx)[0].LastScheduleTime.Time.Equal(last.Add(24*time.Hour)))
+}
+
+func TestScheduleUpdateRetries(t *testing.T) {
+	tests := []struct {
+		name          string
+		updateErr     error
+		wantUpdates   int
+		wantTriggered int
+	}{
+		{
+			name:          "retry on conflict",
+			updateErr:     errors.NewConflict(v1alpha1.Resource("repositories"), "repo", fmt.Errorf("modified")),
+			wantUpdates:   2,
+			wantTriggered: 1,
+		},
+		{
+			name:        "no retry on other errors",
+			updateErr:   errors.NewForbidden(v1alpha1.Resource("repositories"), "repo", fmt.Errorf("denied")),
+			wantUpdates: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			last := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+			f := newFixture(t, ctx, makeRepository(
+				v1alpha1.ScheduleStatus{PipelineRunName: "nightly", Schedule: "0 2 * * *", Branch: "main", LastScheduleTime: &metav1.Time{Time: last}},
+			))
+			updates := 0
+			f.stdata.PipelineAsCode.PrependReactor("update", "repositories", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+				updates++
+				if updates == 1 {
+					return true, nil, tt.updateErr
+				}
+				return false, nil, nil
+			})
+
+			f.scheduler.schedule(ctx, last.Add(24*time.Hour))
+			assert.Equal(t, updates, tt.wantUpdates)
+			assert.Equal(t, len(f.triggered), tt.wantTriggered)
+		})
+	}
+}
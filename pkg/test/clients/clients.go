@@ -15,6 +15,7 @@ import (
 	pipelineinformerv1 "github.com/tektoncd/pipeline/pkg/client/informers/externalversions/pipeline/v1"
 	fakepipelineclient "github.com/tektoncd/pipeline/pkg/client/injection/client/fake"
 	fakepipelineruninformer "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1/pipelinerun/fake"
+	faketaskruninformer "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1/taskrun/fake"
 	pipelinelisterv1 "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +29,7 @@ type Clients struct {
 	PipelineAsCode   *fakepacclientset.Clientset
 	Kube             *fakekubeclientset.Clientset
 	PipelineLister   pipelinelisterv1.PipelineRunLister
+	TaskRunLister    pipelinelisterv1.TaskRunLister
 	RepositoryLister fakepaclister.RepositoryLister
 }
 
@@ -61,8 +63,10 @@ func SeedTestData(t *testing.T, ctx context.Context, d Data) (Clients, Informers
 	i := Informers{
 		Repository:  fakerepositoryinformers.Get(ctx),
 		PipelineRun: fakepipelineruninformer.Get(ctx),
+		TaskRun:     faketaskruninformer.Get(ctx),
 	}
 	c.PipelineLister = i.PipelineRun.Lister()
+	c.TaskRunLister = i.TaskRun.Lister()
 	c.RepositoryLister = i.Repository.Lister()
 
 	for _, pr := range d.PipelineRuns {
@@ -76,6 +80,9 @@ func SeedTestData(t *testing.T, ctx context.Context, d Data) (Clients, Informers
 	}
 
 	for _, tr := range d.TaskRuns {
+		if err := i.TaskRun.Informer().GetIndexer().Add(tr); err != nil {
+			t.Fatal(err)
+		}
 		if _, err := c.Pipeline.TektonV1().TaskRuns(tr.Namespace).Create(ctx, tr, metav1.CreateOptions{}); err != nil {
 			t.Fatal(err)
 		}
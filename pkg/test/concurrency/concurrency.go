@@ -14,7 +14,7 @@ type TestQMI struct {
 	RunningQueue []string
 }
 
-func (TestQMI) InitQueues(_ context.Context, _ tektonVersionedClient.Interface, _ pacVersionedClient.Interface) error {
+func (TestQMI) InitQueues(_ context.Context, _ tektonVersionedClient.Interface, _ pacVersionedClient.Interface, _ *pacv1alpha1.Repository) error {
 	// TODO implement me
 	panic("implement me")
 }
@@ -31,11 +31,11 @@ func (TestQMI) RunningPipelineRuns(_ *pacv1alpha1.Repository) []string {
 	panic("implement me")
 }
 
-func (t TestQMI) AddListToRunningQueue(_ *pacv1alpha1.Repository, _ []string) ([]string, error) {
+func (t TestQMI) AddListToRunningQueue(_ *pacv1alpha1.Repository, _ *tektonv1.PipelineRun, _ []*tektonv1.PipelineRun) ([]string, error) {
 	return t.RunningQueue, nil
 }
 
-func (TestQMI) AddToPendingQueue(_ *pacv1alpha1.Repository, _ []string) error {
+func (TestQMI) AddToPendingQueue(_ *pacv1alpha1.Repository, _ *tektonv1.PipelineRun, _ []*tektonv1.PipelineRun) error {
 	// TODO implement me
 	panic("implement me")
 }
@@ -44,7 +44,21 @@ func (t TestQMI) RemoveFromQueue(_, _ string) bool {
 	return false
 }
 
+func (t TestQMI) RemovePipelineRun(_ string) bool {
+	return false
+}
+
 func (TestQMI) RemoveAndTakeItemFromQueue(_ *pacv1alpha1.Repository, _ *tektonv1.PipelineRun) string {
 	// TODO implement me
 	panic("implement me")
 }
+
+func (TestQMI) SetGlobalLimit(_ int) {
+}
+
+func (TestQMI) SetNamespaceLimit(_ string, _ int) {
+}
+
+func (TestQMI) NamespaceLimit(_ string) int {
+	return 0
+}
@@ -52,10 +52,10 @@ func (k *KinterfaceTest) GetSecret(_ context.Context, secret ktypes.GetSecretOpt
 }
 
 func (k *KinterfaceTest) CleanupPipelines(_ context.Context, _ *zap.SugaredLogger, _ *v1alpha1.Repository,
-	_ *tektonv1.PipelineRun, limitnumber int,
+	_ *tektonv1.PipelineRun, retention kubeinteraction.Retention,
 ) error {
-	if k.ExpectedNumberofCleanups != limitnumber {
-		return fmt.Errorf("we wanted %d and we got %d", k.ExpectedNumberofCleanups, limitnumber)
+	if k.ExpectedNumberofCleanups != retention.MaxKeep {
+		return fmt.Errorf("we wanted %d and we got %d", k.ExpectedNumberofCleanups, retention.MaxKeep)
 	}
 	return nil
 }
@@ -10,6 +10,15 @@ func ResetMetrics() {
 		"pipelines_as_code_pipelinerun_count",
 		"pipelines_as_code_pipelinerun_duration_seconds_sum",
 		"pipelines_as_code_running_pipelineruns_count",
+		"pipelines_as_code_queued_pipelineruns_count",
+		"pipelines_as_code_queued_pipelineruns_max_wait_seconds",
+		"pipelines_as_code_queue_timeout_count",
+		"pipelines_as_code_task_count",
+		"pipelines_as_code_flaky_task_count",
+		"pipelines_as_code_llm_analysis_cache_count",
+		"pipelines_as_code_llm_analysis_cache_size",
+		"pipelines_as_code_llm_analysis_cache_ttl_seconds",
+		"pipelines_as_code_llm_analysis_token_count",
 		"pipelines_as_code_git_provider_api_request_count",
 	)
 
@@ -101,6 +101,61 @@ type geminiError struct {
 	} `json:"error"`
 }
 
+// Anthropic request/response structures.
+type anthropicRequest struct {
+	Model     string          `json:"model"`
+	MaxTokens int             `json:"max_tokens"`
+	Messages  []openaiMessage `json:"messages"`
+}
+
+type anthropicResponse struct {
+	ID         string             `json:"id"`
+	Type       string             `json:"type"`
+	Role       string             `json:"role"`
+	Model      string             `json:"model"`
+	Content    []anthropicContent `json:"content"`
+	StopReason string             `json:"stop_reason"`
+	Usage      anthropicUsage     `json:"usage"`
+}
+
+type anthropicContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicError struct {
+	Type  string `json:"type"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Ollama request/response structures.
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []openaiMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+}
+
+type ollamaResponse struct {
+	Model           string        `json:"model"`
+	CreatedAt       string        `json:"created_at"`
+	Message         openaiMessage `json:"message"`
+	Done            bool          `json:"done"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+}
+
+type ollamaError struct {
+	Error string `json:"error"`
+}
+
 // Canned responses structure.
 type cannedResponses struct {
 	Default    string            `json:"default"`
@@ -146,6 +201,8 @@ func main() {
 	http.HandleFunc("/", rootHandler)
 	http.HandleFunc("/v1/chat/completions", openaiHandler)
 	http.HandleFunc("/v1beta/models/", geminiHandler)
+	http.HandleFunc("/v1/messages", anthropicHandler)
+	http.HandleFunc("/api/chat", ollamaHandler)
 	http.HandleFunc("/health", healthHandler)
 
 	addr := fmt.Sprintf(":%d", *port)
@@ -153,6 +210,8 @@ func main() {
 	log.Printf("📡 Listening on: http://localhost%s", addr)
 	log.Printf("🤖 OpenAI endpoint: http://localhost%s/v1/chat/completions", addr)
 	log.Printf("🧠 Gemini endpoint: http://localhost%s/v1beta/models/{model}:generateContent", addr)
+	log.Printf("📜 Anthropic endpoint: http://localhost%s/v1/messages", addr)
+	log.Printf("🦙 Ollama endpoint: http://localhost%s/api/chat", addr)
 	log.Printf("⚙️  Latency: %dms, Failure rate: %.1f%%", *simulateLatency, *failureRate*100)
 
 	server := &http.Server{
@@ -193,9 +252,11 @@ func rootHandler(w http.ResponseWriter, _ *http.Request) {
 		"message": "NoNo Fake AI Server for Testing",
 		"version": "1.0.0",
 		"endpoints": map[string]string{
-			"openai": "/v1/chat/completions",
-			"gemini": "/v1beta/models/{model}:generateContent",
-			"health": "/health",
+			"openai":    "/v1/chat/completions",
+			"gemini":    "/v1beta/models/{model}:generateContent",
+			"anthropic": "/v1/messages",
+			"ollama":    "/api/chat",
+			"health":    "/health",
 		},
 	}
 	if err := json.NewEncoder(w).Encode(errResp); err != nil {
@@ -391,6 +452,156 @@ func geminiHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func anthropicHandler(w http.ResponseWriter, r *http.Request) {
+	if *verbose {
+		log.Printf("📨 Anthropic request from %s", r.RemoteAddr)
+	}
+
+	// Check API key header
+	if r.Header.Get("x-api-key") == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		errResp := anthropicError{Type: "error"}
+		errResp.Error.Type = "authentication_error"
+		errResp.Error.Message = "Missing x-api-key header"
+		if err := json.NewEncoder(w).Encode(errResp); err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Simulate latency
+	if *simulateLatency > 0 {
+		time.Sleep(time.Duration(*simulateLatency) * time.Millisecond)
+	}
+
+	// Simulate failures
+	if shouldFail() {
+		w.WriteHeader(http.StatusTooManyRequests)
+		errResp := anthropicError{Type: "error"}
+		errResp.Error.Type = "rate_limit_error"
+		errResp.Error.Message = "Rate limit exceeded. Please try again later."
+		if err := json.NewEncoder(w).Encode(errResp); err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if *verbose {
+			log.Printf("❌ Simulated rate limit error")
+		}
+		return
+	}
+
+	// Parse request
+	var req anthropicRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		errResp := anthropicError{Type: "error"}
+		errResp.Error.Type = "invalid_request_error"
+		errResp.Error.Message = fmt.Sprintf("Invalid request body: %v", err)
+		if err := json.NewEncoder(w).Encode(errResp); err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		return
+	}
+
+	if *verbose {
+		log.Printf("📝 Model: %s, Messages: %d", req.Model, len(req.Messages))
+	}
+
+	// Get appropriate response
+	responseText := getResponse("anthropic", req.Messages)
+
+	// Build response
+	response := anthropicResponse{
+		ID:    fmt.Sprintf("msg_fake_%d", time.Now().Unix()),
+		Type:  "message",
+		Role:  "assistant",
+		Model: req.Model,
+		Content: []anthropicContent{
+			{Type: "text", Text: responseText},
+		},
+		StopReason: "end_turn",
+		Usage: anthropicUsage{
+			InputTokens:  countTokens(req.Messages),
+			OutputTokens: countTokens([]openaiMessage{{Content: responseText}}),
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+
+	if *verbose {
+		log.Printf("✅ Anthropic response sent (%d tokens)", response.Usage.InputTokens+response.Usage.OutputTokens)
+	}
+}
+
+func ollamaHandler(w http.ResponseWriter, r *http.Request) {
+	if *verbose {
+		log.Printf("📨 Ollama request from %s", r.RemoteAddr)
+	}
+
+	// Simulate latency
+	if *simulateLatency > 0 {
+		time.Sleep(time.Duration(*simulateLatency) * time.Millisecond)
+	}
+
+	// Simulate failures
+	if shouldFail() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		if err := json.NewEncoder(w).Encode(ollamaError{Error: "server busy, please try again"}); err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if *verbose {
+			log.Printf("❌ Simulated server busy error")
+		}
+		return
+	}
+
+	// Parse request
+	var req ollamaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(ollamaError{Error: fmt.Sprintf("invalid request body: %v", err)}); err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		return
+	}
+
+	if *verbose {
+		log.Printf("📝 Model: %s, Messages: %d", req.Model, len(req.Messages))
+	}
+
+	// Get appropriate response
+	responseText := getResponse("ollama", req.Messages)
+
+	// Build response, streaming is not supported and the whole answer is
+	// always returned at once
+	response := ollamaResponse{
+		Model:     req.Model,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Message: openaiMessage{
+			Role:    "assistant",
+			Content: responseText,
+		},
+		Done:            true,
+		PromptEvalCount: countTokens(req.Messages),
+		EvalCount:       countTokens([]openaiMessage{{Content: responseText}}),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+
+	if *verbose {
+		log.Printf("✅ Ollama response sent (%d tokens)", response.PromptEvalCount+response.EvalCount)
+	}
+}
+
 func getResponse(provider string, messages []openaiMessage) string {
 	// Check provider-specific response first
 	if resp, ok := responses.ByProvider[provider]; ok {
@@ -32,6 +32,12 @@ type TestProviderImp struct {
 	WantRenamedFiles       []string
 	FailGetCommitInfo      bool
 	CommitInfoErrorMsg     string
+	CreatedStatus          *provider.StatusOpts
+	CreatedComments        []string
+	CreatedCommitComments  []string
+	UpdatedStatus          *provider.StatusOpts
+	StatusTexts            []string
+	StatusDescription      string
 	pacInfo                *info.PacOpts
 }
 
@@ -50,7 +56,13 @@ func (v *TestProviderImp) IsAllowedOwnersFile(_ context.Context, _ *info.Event)
 	return v.AllowedInOwnersFile, nil
 }
 
-func (v *TestProviderImp) CreateComment(_ context.Context, _ *info.Event, _, _ string) error {
+func (v *TestProviderImp) CreateComment(_ context.Context, _ *info.Event, comment, _ string) error {
+	v.CreatedComments = append(v.CreatedComments, comment)
+	return nil
+}
+
+func (v *TestProviderImp) CreateCommitComment(_ context.Context, _ *info.Event, comment string) error {
+	v.CreatedCommitComments = append(v.CreatedCommitComments, comment)
 	return nil
 }
 
@@ -103,10 +115,21 @@ func (v *TestProviderImp) GetTaskURI(_ context.Context, _ *info.Event, _ string)
 	return v.WantProviderRemoteTask, "", nil
 }
 
-func (v *TestProviderImp) CreateStatus(_ context.Context, _ *info.Event, _ provider.StatusOpts) error {
+func (v *TestProviderImp) CreateStatus(_ context.Context, _ *info.Event, status provider.StatusOpts) error {
+	if v.CreateStatusErorring {
+		return fmt.Errorf("some provider error occurred while reporting status")
+	}
+	v.CreatedStatus = &status
+	return nil
+}
+
+func (v *TestProviderImp) UpdateStatusDetails(_ context.Context, _ *info.Event, status provider.StatusOpts, text, description string) error {
 	if v.CreateStatusErorring {
 		return fmt.Errorf("some provider error occurred while reporting status")
 	}
+	v.UpdatedStatus = &status
+	v.StatusTexts = append(v.StatusTexts, text)
+	v.StatusDescription = description
 	return nil
 }
 
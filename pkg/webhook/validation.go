@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/url"
 	"os"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	pac "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
@@ -61,6 +62,26 @@ func (ac *reconciler) Admit(_ context.Context, request *v1.AdmissionRequest) *v1
 		return webhook.MakeErrorStatus("concurrency limit must be greater than 0")
 	}
 
+	// the limit of a cluster scoped concurrency group is shared by all the
+	// Repositories, it can only be set by the administrator
+	if repo.GetNamespace() != os.Getenv("SYSTEM_NAMESPACE") {
+		for _, group := range repo.Spec.ConcurrencyGroups {
+			if group.Scope == v1alpha1.ConcurrencyGroupScopeCluster {
+				return webhook.MakeErrorStatus("concurrency group %s: the cluster scoped groups can only be defined in the global repository", group.Name)
+			}
+		}
+	}
+
+	if repo.Spec.Settings != nil && repo.Spec.Settings.MaxQueueWait != "" {
+		wait, err := time.ParseDuration(repo.Spec.Settings.MaxQueueWait)
+		if err != nil {
+			return webhook.MakeErrorStatus("invalid max_queue_wait: %v", err)
+		}
+		if wait < 0 {
+			return webhook.MakeErrorStatus("invalid max_queue_wait: %s is negative", repo.Spec.Settings.MaxQueueWait)
+		}
+	}
+
 	if repo.Spec.Settings != nil && repo.Spec.Settings.Gitlab != nil {
 		if !allowedGitlabDisableCommentStrategyOnMr.Has(repo.Spec.Settings.Gitlab.CommentStrategy) {
 			return webhook.MakeErrorStatus("comment strategy '%s' is not supported for Gitlab MRs", repo.Spec.Settings.Gitlab.CommentStrategy)
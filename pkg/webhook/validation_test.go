@@ -103,6 +103,64 @@ func TestReconciler_Admit(t *testing.T) {
 			allowed: false,
 			result:  "repository already exists with URL: https://pac.test/already/installed",
 		},
+		{
+			name: "reject cluster scoped concurrency group",
+			repo: withConcurrencyGroup(testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+				Name:             "test-run",
+				InstallNamespace: "namespace",
+				URL:              "https://github.com/openshift-pipelines/pipelines-as-code",
+			}), v1alpha1.ConcurrencyGroupScopeCluster),
+			allowed: false,
+			result:  "concurrency group deploy: the cluster scoped groups can only be defined in the global repository",
+		},
+		{
+			name: "allow namespace scoped concurrency group",
+			repo: withConcurrencyGroup(testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+				Name:             "test-run",
+				InstallNamespace: "namespace",
+				URL:              "https://github.com/openshift-pipelines/pipelines-as-code",
+			}), v1alpha1.ConcurrencyGroupScopeNamespace),
+			allowed: true,
+		},
+		{
+			name: "allow cluster scoped concurrency group for global namespace",
+			repo: withConcurrencyGroup(testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+				Name:             "test-run",
+				InstallNamespace: globalNamespace,
+			}), v1alpha1.ConcurrencyGroupScopeCluster),
+			allowed: true,
+		},	{
+			name: "allow max queue wait",
+			repo: testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+				Name:             "test-run",
+				InstallNamespace: "namespace",
+				URL:              "https://github.com/openshift-pipelines/pipelines-as-code",
+				Settings:         &v1alpha1.Settings{MaxQueueWait: "2h"},
+			}),
+			allowed: true,
+		},
+		{
+			name: "reject invalid max queue wait",
+			repo: testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+				Name:             "test-run",
+				InstallNamespace: "namespace",
+				URL:              "https://github.com/openshift-pipelines/pipelines-as-code",
+				Settings:         &v1alpha1.Settings{MaxQueueWait: "forever"},
+			}),
+			allowed: false,
+			result:  `invalid max_queue_wait: time: invalid duration "forever"`,
+		},
+		{
+			name: "reject negative max queue wait",
+			repo: testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+				Name:             "test-run",
+				InstallNamespace: "namespace",
+				URL:              "https://github.com/openshift-pipelines/pipelines-as-code",
+				Settings:         &v1alpha1.Settings{MaxQueueWait: "-1h"},
+			}),
+			allowed: false,
+			result:  "invalid max_queue_wait: -1h is negative",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -132,3 +190,8 @@ func TestReconciler_Admit(t *testing.T) {
 		})
 	}
 }
+
+func withConcurrencyGroup(repo *v1alpha1.Repository, scope string) *v1alpha1.Repository {
+	repo.Spec.ConcurrencyGroups = []v1alpha1.ConcurrencyGroup{{Name: "deploy", Limit: 1, Scope: scope}}
+	return repo
+}